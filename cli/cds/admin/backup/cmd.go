@@ -0,0 +1,55 @@
+package backup
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	rootCmd = &cobra.Command{
+		Use:   "backup",
+		Short: "cds admin backup -o <filename> [--artifacts] (admin only)",
+		Long: `Download a backup archive of the instance: users, groups, projects with their applications, pipelines, environments and workflows,
+worker models, actions, plugins and templates. The secrets are saved encrypted with the cipher key of the instance.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if ok, err := sdk.IsAdmin(); !ok {
+				if err != nil {
+					fmt.Printf("Error : %v\n", err)
+				}
+				sdk.Exit("You are not allowed to run this command")
+			}
+
+			var w io.Writer = os.Stdout
+			if output != "" {
+				f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(0600))
+				if err != nil {
+					sdk.Exit("Error: %s\n", err)
+				}
+				defer f.Close()
+				w = f
+			}
+
+			if err := sdk.Backup(withArtifacts, w); err != nil {
+				sdk.Exit("Error: %s\n", err)
+			}
+		},
+	}
+
+	output        string
+	withArtifacts bool
+)
+
+func init() {
+	rootCmd.Flags().StringVarP(&output, "output", "o", "", "cds admin backup -o <filename>")
+	rootCmd.Flags().BoolVarP(&withArtifacts, "artifacts", "", false, "Also save the artifacts")
+}
+
+//Cmd returns the root command
+func Cmd() *cobra.Command {
+	return rootCmd
+}
@@ -3,11 +3,13 @@ package admin
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/ovh/cds/cli/cds/admin/backup"
 	"github.com/ovh/cds/cli/cds/admin/export"
 	"github.com/ovh/cds/cli/cds/admin/importer"
 	"github.com/ovh/cds/cli/cds/admin/maintenance"
 	"github.com/ovh/cds/cli/cds/admin/plugin"
 	"github.com/ovh/cds/cli/cds/admin/repositoriesmanager"
+	"github.com/ovh/cds/cli/cds/admin/restore"
 	"github.com/ovh/cds/cli/cds/admin/template"
 	"github.com/ovh/cds/cli/cds/admin/user"
 	"github.com/ovh/cds/cli/cds/admin/warning"
@@ -21,11 +23,13 @@ var (
 )
 
 func init() {
+	rootCmd.AddCommand(backup.Cmd())
 	rootCmd.AddCommand(export.Cmd())
 	rootCmd.AddCommand(importer.Cmd())
 	rootCmd.AddCommand(maintenance.Cmd())
 	rootCmd.AddCommand(plugin.Cmd())
 	rootCmd.AddCommand(repositoriesmanager.Cmd())
+	rootCmd.AddCommand(restore.Cmd())
 	rootCmd.AddCommand(template.Cmd())
 	rootCmd.AddCommand(user.Cmd())
 	rootCmd.AddCommand(warning.Cmd())
@@ -2,11 +2,20 @@ package plugin
 
 import (
 	"fmt"
+	"os"
 
+	"github.com/olekukonko/tablewriter"
 	"github.com/ovh/cds/sdk"
 	"github.com/spf13/cobra"
 )
 
+var (
+	pluginName    string
+	pluginVersion string
+	pluginOS      string
+	pluginArch    string
+)
+
 //Cmd returns the root cobra command for plugin management
 func Cmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -19,12 +28,22 @@ func Cmd() *cobra.Command {
 	cmd.AddCommand(updatePluginCmd)
 	cmd.AddCommand(deletePluginCmd)
 	cmd.AddCommand(downloadPluginCmd)
+	cmd.AddCommand(listPluginCmd)
+	cmd.AddCommand(promotePluginCmd)
+	cmd.AddCommand(deprecatePluginCmd)
+
+	for _, c := range []*cobra.Command{addPluginCmd, updatePluginCmd} {
+		c.Flags().StringVarP(&pluginName, "name", "", "", "Name of the plugin, mandatory if the binary is not built for the platform of the API")
+		c.Flags().StringVarP(&pluginVersion, "version", "", "", "Version of the plugin, mandatory if the binary is not built for the platform of the API")
+		c.Flags().StringVarP(&pluginOS, "os", "", "", "GOOS of the binary (linux, darwin, windows...). Binaries without os are used on every platform")
+		c.Flags().StringVarP(&pluginArch, "arch", "", "", "GOARCH of the binary (amd64, 386, arm...)")
+	}
 	return cmd
 }
 
 var addPluginCmd = &cobra.Command{
 	Use:   "add",
-	Short: "cds plugin add <file>",
+	Short: "cds plugin add <file> [--version <version>] [--os <os> --arch <arch>] [--name <name>]",
 	Run: func(cmd *cobra.Command, args []string) {
 		if ok, err := sdk.IsAdmin(); !ok {
 			if err != nil {
@@ -38,7 +57,7 @@ var addPluginCmd = &cobra.Command{
 		}
 		var err error
 		for i := 0; i < 5; i++ {
-			_, err = sdk.UploadPlugin(args[0], false)
+			_, err = sdk.UploadPluginBinary(args[0], false, pluginName, pluginVersion, pluginOS, pluginArch)
 			if err == nil {
 				break
 			}
@@ -52,7 +71,7 @@ var addPluginCmd = &cobra.Command{
 
 var updatePluginCmd = &cobra.Command{
 	Use:   "update",
-	Short: "cds plugin update <file>",
+	Short: "cds plugin update <file> [--version <version>] [--os <os> --arch <arch>] [--name <name>]",
 	Run: func(cmd *cobra.Command, args []string) {
 		if ok, err := sdk.IsAdmin(); !ok {
 			if err != nil {
@@ -66,7 +85,7 @@ var updatePluginCmd = &cobra.Command{
 		}
 		var err error
 		for i := 0; i < 5; i++ {
-			_, err = sdk.UploadPlugin(args[0], true)
+			_, err = sdk.UploadPluginBinary(args[0], true, pluginName, pluginVersion, pluginOS, pluginArch)
 			if err == nil {
 				break
 			}
@@ -119,3 +138,96 @@ var downloadPluginCmd = &cobra.Command{
 		fmt.Printf("OK\n")
 	},
 }
+
+var listPluginCmd = &cobra.Command{
+	Use:   "list",
+	Short: "cds plugin list [<name>]",
+	Run: func(cmd *cobra.Command, args []string) {
+		if ok, err := sdk.IsAdmin(); !ok {
+			if err != nil {
+				fmt.Printf("Error : %v\n", err)
+			}
+			sdk.Exit("You are not allowed to run this command")
+		}
+
+		if len(args) > 1 {
+			sdk.Exit("Wrong usage: %s\n", cmd.Short)
+		}
+
+		var plugins []sdk.ActionPlugin
+		var err error
+		if len(args) == 1 {
+			plugins, err = sdk.ListPluginVersions(args[0])
+		} else {
+			plugins, err = sdk.ListPlugins()
+		}
+		if err != nil {
+			sdk.Exit("Error: cannot list plugins (%s)\n", err)
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "Version", "Platform", "Status", "SHA256", "Created"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+		table.SetCenterSeparator("|")
+
+		for _, p := range plugins {
+			platform := "any"
+			if p.OS != "" {
+				platform = p.OS + "/" + p.Arch
+			}
+			status := ""
+			if p.Default {
+				status = "default"
+			} else if p.Deprecated {
+				status = "deprecated"
+			}
+			table.Append([]string{p.Name, p.Version, platform, status, p.SHA256sum, p.Created.Format("2006-01-02 15:04:05")})
+		}
+
+		table.Render()
+	},
+}
+
+var promotePluginCmd = &cobra.Command{
+	Use:   "promote",
+	Short: "cds plugin promote <name> <version>",
+	Long:  "Make a version the default version of a plugin, used by requirements which are not pinned to a version",
+	Run: func(cmd *cobra.Command, args []string) {
+		if ok, err := sdk.IsAdmin(); !ok {
+			if err != nil {
+				fmt.Printf("Error : %v\n", err)
+			}
+			sdk.Exit("You are not allowed to run this command")
+		}
+
+		if len(args) != 2 {
+			sdk.Exit("Wrong usage: %s\n", cmd.Short)
+		}
+		if err := sdk.PromotePluginVersion(args[0], args[1]); err != nil {
+			sdk.Exit("Error: cannot promote plugin %s@%s (%s)\n", args[0], args[1], err)
+		}
+		fmt.Printf("OK\n")
+	},
+}
+
+var deprecatePluginCmd = &cobra.Command{
+	Use:   "deprecate",
+	Short: "cds plugin deprecate <name> <version>",
+	Long:  "Deprecate a version of a plugin, which is then only used by requirements pinned to this exact version",
+	Run: func(cmd *cobra.Command, args []string) {
+		if ok, err := sdk.IsAdmin(); !ok {
+			if err != nil {
+				fmt.Printf("Error : %v\n", err)
+			}
+			sdk.Exit("You are not allowed to run this command")
+		}
+
+		if len(args) != 2 {
+			sdk.Exit("Wrong usage: %s\n", cmd.Short)
+		}
+		if err := sdk.DeprecatePluginVersion(args[0], args[1]); err != nil {
+			sdk.Exit("Error: cannot deprecate plugin %s@%s (%s)\n", args[0], args[1], err)
+		}
+		fmt.Printf("OK\n")
+	},
+}
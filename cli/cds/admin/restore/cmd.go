@@ -0,0 +1,67 @@
+package restore
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	rootCmd = &cobra.Command{
+		Use:   "restore <filename>",
+		Short: "cds admin restore <filename> [--previous-cipher-key <key>] (admin only)",
+		Long: `Restore a backup archive made with "cds admin backup" on a fresh instance, without any project. The database of the instance
+must be at the same migration as the database the backup has been made on. If the instance does not use the same cipher key,
+give the cipher key of the previous instance so that the secrets are re-encrypted.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				sdk.Exit("Wrong usage: %s\n", cmd.Short)
+			}
+
+			if ok, err := sdk.IsAdmin(); !ok {
+				if err != nil {
+					fmt.Printf("Error : %v\n", err)
+				}
+				sdk.Exit("You are not allowed to run this command")
+			}
+
+			if !confirm && !cli.AskForConfirmation("The users, groups and worker models of the instance will be replaced. Do you really want to restore the backup ?") {
+				fmt.Println("Aborted")
+				return
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				sdk.Exit("Error: %s\n", err)
+			}
+
+			m, err := sdk.Restore(f, previousCipherKey)
+			if err != nil {
+				sdk.Exit("Error: %s\n", err)
+			}
+
+			fmt.Printf("Backup made on %s with CDS %s restored\n", m.Created.Format("2006-01-02 15:04:05"), m.Version)
+			for _, t := range m.Tables {
+				fmt.Printf("%-40s %d rows\n", t.Name, t.Rows)
+			}
+			fmt.Printf("%d objects\n", m.Objects)
+		},
+	}
+
+	previousCipherKey string
+	confirm           bool
+)
+
+func init() {
+	rootCmd.Flags().StringVarP(&previousCipherKey, "previous-cipher-key", "", "", "Cipher key of the instance the backup has been made on")
+	rootCmd.Flags().BoolVarP(&confirm, "yes", "y", false, "Automatic yes to prompt")
+}
+
+//Cmd returns the root command
+func Cmd() *cobra.Command {
+	return rootCmd
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var (
+	approvalCmd = cli.Command{
+		Name:  "approval",
+		Short: "Manage CDS approvals of paused runs",
+	}
+
+	approval = cli.NewCommand(approvalCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(approvalListCmd, approvalListRun, nil),
+			cli.NewGetCommand(approvalShowCmd, approvalShowRun, nil),
+			cli.NewGetCommand(approvalApproveCmd, approvalApproveRun, nil),
+			cli.NewGetCommand(approvalRejectCmd, approvalRejectRun, nil),
+		})
+)
+
+var approvalListCmd = cli.Command{
+	Name:  "list",
+	Short: "List the approvals of a CDS project",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "status",
+			Usage: "Filter on the status of the approvals: Waiting, Success or Fail",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+func approvalListRun(v cli.Values) (cli.ListResult, error) {
+	approvals, err := client.ApprovalList(v["project-key"], v.GetString("status"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(approvals), nil
+}
+
+var approvalShowCmd = cli.Command{
+	Name:  "show",
+	Short: "Show a CDS approval",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "id"},
+	},
+}
+
+func approvalShowRun(v cli.Values) (interface{}, error) {
+	id, err := strconv.ParseInt(v["id"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid approval id %s", v["id"])
+	}
+	a, err := client.ApprovalGet(v["project-key"], id)
+	if err != nil {
+		return nil, err
+	}
+	return *a, nil
+}
+
+var approvalApproveCmd = cli.Command{
+	Name:  "approve",
+	Short: "Approve a paused run",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "id"},
+	},
+	OptionnalArgs: []cli.Arg{
+		{Name: "comment"},
+	},
+}
+
+func approvalApproveRun(v cli.Values) (interface{}, error) {
+	return approvalVote(v, true)
+}
+
+var approvalRejectCmd = cli.Command{
+	Name:  "reject",
+	Short: "Reject a paused run",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "id"},
+	},
+	OptionnalArgs: []cli.Arg{
+		{Name: "comment"},
+	},
+}
+
+func approvalRejectRun(v cli.Values) (interface{}, error) {
+	return approvalVote(v, false)
+}
+
+func approvalVote(v cli.Values, approved bool) (interface{}, error) {
+	id, err := strconv.ParseInt(v["id"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid approval id %s", v["id"])
+	}
+	a, err := client.ApprovalVote(v["project-key"], id, approved, v["comment"])
+	if err != nil {
+		return nil, err
+	}
+	return *a, nil
+}
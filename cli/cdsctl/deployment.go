@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var (
+	deploymentCmd = cli.Command{
+		Name:  "deployment",
+		Short: "Manage CDS deployments of workflows",
+	}
+
+	deployment = cli.NewCommand(deploymentCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(deploymentListCmd, deploymentListRun, nil),
+			cli.NewGetCommand(deploymentRollbackCmd, deploymentRollbackRun, nil),
+		})
+)
+
+var deploymentListCmd = cli.Command{
+	Name:  "list",
+	Short: "List the last deployments, across all your projects",
+	Flags: []cli.Flag{
+		{
+			Name:  "project",
+			Usage: "Filter on a project key",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "environment",
+			Usage: "Filter on an environment name",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "application",
+			Usage: "Filter on an application name",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+func deploymentListRun(v cli.Values) (cli.ListResult, error) {
+	deployments, err := client.DeploymentList(v.GetString("project"), v.GetString("environment"), v.GetString("application"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(deployments), nil
+}
+
+var deploymentRollbackCmd = cli.Command{
+	Name:  "rollback",
+	Short: "Run again a previous successful deployment",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+		{Name: "id"},
+	},
+}
+
+func deploymentRollbackRun(v cli.Values) (interface{}, error) {
+	id, err := strconv.ParseInt(v["id"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deployment id %s", v["id"])
+	}
+	wr, err := client.DeploymentRollback(v["project-key"], v["workflow-name"], id)
+	if err != nil {
+		return nil, err
+	}
+	return *wr, nil
+}
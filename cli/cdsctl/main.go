@@ -29,6 +29,9 @@ func main() {
 			signup,
 			project,
 			workflow,
+			release,
+			approval,
+			deployment,
 			usr,
 			healt,
 		},
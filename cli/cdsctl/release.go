@@ -0,0 +1,180 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	releaseCmd = cli.Command{
+		Name:  "release",
+		Short: "Manage CDS application releases",
+	}
+
+	release = cli.NewCommand(releaseCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(releaseListCmd, releaseListRun, nil),
+			cli.NewGetCommand(releaseShowCmd, releaseShowRun, nil),
+			cli.NewGetCommand(releasePromoteCmd, releasePromoteRun, nil),
+			cli.NewCommand(releaseDownloadCmd, releaseDownloadRun, nil),
+		})
+)
+
+var releaseListCmd = cli.Command{
+	Name:  "list",
+	Short: "List the releases of a CDS application",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "application"},
+	},
+}
+
+func releaseListRun(v cli.Values) (cli.ListResult, error) {
+	rels, err := client.ApplicationReleaseList(v["project-key"], v["application"])
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(rels), nil
+}
+
+var releaseShowCmd = cli.Command{
+	Name:  "show",
+	Short: "Show a release of a CDS application",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "application"},
+		{Name: "version"},
+	},
+}
+
+func releaseShowRun(v cli.Values) (interface{}, error) {
+	rel, err := client.ApplicationReleaseGet(v["project-key"], v["application"], v["version"])
+	if err != nil {
+		return nil, err
+	}
+	return *rel, nil
+}
+
+var releasePromoteCmd = cli.Command{
+	Name:  "promote",
+	Short: "Promote the artifacts of a workflow run to a new release of a CDS application",
+	Long:  "Only the artifacts uploaded by the pipelines linked to the application are promoted. A version can't be promoted twice.",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "application"},
+		{Name: "version"},
+		{Name: "workflow"},
+		{Name: "run-number"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "artifacts",
+			Usage: "Comma-separated names of the artifacts to promote. All the artifacts are promoted if empty",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+func releasePromoteRun(v cli.Values) (interface{}, error) {
+	number, err := strconv.ParseInt(v["run-number"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid run number %s", v["run-number"])
+	}
+
+	promotion := sdk.ReleasePromotion{
+		Version:           v["version"],
+		WorkflowName:      v["workflow"],
+		WorkflowRunNumber: number,
+	}
+	if arts := v.GetString("artifacts"); arts != "" {
+		for _, a := range strings.Split(arts, ",") {
+			promotion.Artifacts = append(promotion.Artifacts, strings.TrimSpace(a))
+		}
+	}
+
+	rel, err := client.ApplicationReleasePromote(v["project-key"], v["application"], promotion)
+	if err != nil {
+		return nil, err
+	}
+	return *rel, nil
+}
+
+var releaseDownloadCmd = cli.Command{
+	Name:  "download",
+	Short: "Download the artifacts of a release of a CDS application",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "application"},
+		{Name: "version"},
+	},
+	OptionnalArgs: []cli.Arg{
+		{Name: "artifact"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:    "directory",
+			Usage:   "Directory where the artifacts are downloaded",
+			Default: ".",
+			Kind:    reflect.String,
+		},
+	},
+}
+
+func releaseDownloadRun(v cli.Values) error {
+	rel, err := client.ApplicationReleaseGet(v["project-key"], v["application"], v["version"])
+	if err != nil {
+		return err
+	}
+
+	dir := v.GetString("directory")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for _, a := range rel.Artifacts {
+		if v["artifact"] != "" && v["artifact"] != a.Name {
+			continue
+		}
+		if err := releaseDownloadArtifact(v["project-key"], v["application"], rel.Version, a, filepath.Join(dir, a.Name)); err != nil {
+			return fmt.Errorf("cannot download artifact %s: %s", a.Name, err)
+		}
+		fmt.Printf("%s downloaded\n", a.Name)
+	}
+	return nil
+}
+
+func releaseDownloadArtifact(projectKey, appName, version string, a sdk.ReleaseArtifact, destPath string) error {
+	mode := os.FileMode(0644)
+	if a.Perm != 0 {
+		mode = os.FileMode(a.Perm)
+	}
+	f, err := os.OpenFile(destPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	h := sha256.New()
+	err = client.ApplicationReleaseArtifactDownload(projectKey, appName, version, a.Name, io.MultiWriter(f, h))
+	if errC := f.Close(); err == nil {
+		err = errC
+	}
+	if err == nil {
+		err = sdk.CheckSHA256(a.SHA256Sum, h.Sum(nil))
+	}
+	if err != nil {
+		os.Remove(destPath)
+		return err
+	}
+	return nil
+}
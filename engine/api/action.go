@@ -165,6 +165,10 @@ func updateActionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap,
 
 	a.ID = actionDB.ID
 
+	if err = action.ResolveChildren(tx, 0, &a); err != nil {
+		return sdk.WrapError(err, "updateAction: Cannot resolve steps")
+	}
+
 	if err = action.UpdateActionDB(tx, &a, c.User.ID); err != nil {
 		return sdk.WrapError(err, "updateAction: Cannot update action")
 	}
@@ -200,6 +204,9 @@ func addActionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 	defer tx.Rollback()
 
 	a.Type = sdk.DefaultAction
+	if err := action.ResolveChildren(tx, 0, &a); err != nil {
+		return sdk.WrapError(err, "Action: Cannot resolve steps")
+	}
 	if err := action.InsertAction(tx, &a, true); err != nil {
 		return sdk.WrapError(err, "Action: Cannot insert action")
 	}
@@ -291,6 +298,10 @@ func importActionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap,
 		a.ID = existingAction.ID
 	}
 
+	if err := action.ResolveChildren(tx, 0, a); err != nil {
+		return sdk.WrapError(err, "importActionHandler> Cannot resolve steps")
+	}
+
 	//http code status
 	var code int
 
@@ -38,8 +38,14 @@ func InsertAction(tx gorp.SqlExecutor, a *sdk.Action, public bool) error {
 		return sdk.ErrActionLoop
 	}
 
-	query := `INSERT INTO action (name, description, type, enabled, public) VALUES($1, $2, $3, $4, $5) RETURNING id`
-	if err := tx.QueryRow(query, a.Name, a.Description, a.Type, a.Enabled, public).Scan(&a.ID); err != nil {
+	var projectID sql.NullInt64
+	if a.ProjectID != 0 {
+		projectID.Valid = true
+		projectID.Int64 = a.ProjectID
+	}
+
+	query := `INSERT INTO action (name, description, type, enabled, public, project_id) VALUES($1, $2, $3, $4, $5, $6) RETURNING id`
+	if err := tx.QueryRow(query, a.Name, a.Description, a.Type, a.Enabled, public, projectID).Scan(&a.ID); err != nil {
 		return err
 	}
 
@@ -107,13 +113,16 @@ func InsertAction(tx gorp.SqlExecutor, a *sdk.Action, public bool) error {
 		}
 	}
 
-	return nil
+	if a.Type == sdk.JoinedAction {
+		return nil
+	}
+	return recordVersion(tx, a.ID, 0)
 }
 
 // LoadPipelineActionByID retrieves and action by its id but check project and pipeline
 func LoadPipelineActionByID(db gorp.SqlExecutor, project, pip string, actionID int64) (*sdk.Action, error) {
 	query := `
-	SELECT action.id, action.name, action.description, action.type, action.last_modified, action.enabled
+	SELECT action.id, action.name, action.description, action.type, action.last_modified, action.enabled, action.project_id
 	FROM action
 	JOIN pipeline_action ON pipeline_action.action_id = $1
 	JOIN pipeline_stage ON pipeline_stage.id = pipeline_action.pipeline_stage_id
@@ -129,7 +138,7 @@ func LoadPipelineActionByID(db gorp.SqlExecutor, project, pip string, actionID i
 
 // LoadPublicAction load an action from database
 func LoadPublicAction(db gorp.SqlExecutor, name string) (*sdk.Action, error) {
-	query := `SELECT id, name, description, type, last_modified, enabled FROM action WHERE lower(action.name) = lower($1) AND public = true`
+	query := `SELECT id, name, description, type, last_modified, enabled, project_id FROM action WHERE lower(action.name) = lower($1) AND public = true`
 	a, err := loadActions(db, query, name)
 	if err != nil {
 		return nil, err
@@ -137,9 +146,61 @@ func LoadPublicAction(db gorp.SqlExecutor, name string) (*sdk.Action, error) {
 	return &a[0], nil
 }
 
+// LoadProjectAction loads an action private to a project
+func LoadProjectAction(db gorp.SqlExecutor, projectID int64, name string) (*sdk.Action, error) {
+	query := `SELECT id, name, description, type, last_modified, enabled, project_id FROM action WHERE lower(action.name) = lower($1) AND project_id = $2 AND type <> $3`
+	a, err := loadActions(db, query, name, projectID, sdk.JoinedAction)
+	if err != nil {
+		return nil, err
+	}
+	return &a[0], nil
+}
+
+// LoadProjectActions loads all the actions private to a project
+func LoadProjectActions(db gorp.SqlExecutor, projectID int64) ([]sdk.Action, error) {
+	query := `SELECT id, name, description, type, last_modified, enabled, project_id FROM action WHERE project_id = $1 AND type <> $2 ORDER BY name`
+	acts, err := loadActions(db, query, projectID, sdk.JoinedAction)
+	if err == sdk.ErrNoAction {
+		return []sdk.Action{}, nil
+	}
+	return acts, err
+}
+
+// LoadActionForProject loads an action private to a project or, if the project has no action with this name,
+// a public action
+func LoadActionForProject(db gorp.SqlExecutor, projectID int64, name string) (*sdk.Action, error) {
+	if projectID != 0 {
+		a, err := LoadProjectAction(db, projectID, name)
+		if err != sdk.ErrNoAction {
+			return a, err
+		}
+	}
+	return LoadPublicAction(db, name)
+}
+
+// DeleteProjectActions deletes all the actions private to a project. The steps of all the actions are deleted first,
+// since a project action can be a step of another one
+func DeleteProjectActions(db gorp.SqlExecutor, projectID int64) error {
+	var ids []int64
+	if _, err := db.Select(&ids, `SELECT id FROM action WHERE project_id = $1 AND type <> $2`, projectID, sdk.JoinedAction); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := deleteActionChildren(db, id); err != nil {
+			return err
+		}
+	}
+	for _, id := range ids {
+		if err := DeleteAction(db, id, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // LoadActionByID retrieves in database the action with given id
 func LoadActionByID(db gorp.SqlExecutor, actionID int64) (*sdk.Action, error) {
-	query := `SELECT id, name, description, type, last_modified, enabled FROM action WHERE action.id = $1`
+	query := `SELECT id, name, description, type, last_modified, enabled, project_id FROM action WHERE action.id = $1`
 	a, err := loadActions(db, query, actionID)
 	if err != nil {
 		return nil, err
@@ -149,7 +210,7 @@ func LoadActionByID(db gorp.SqlExecutor, actionID int64) (*sdk.Action, error) {
 
 // LoadActionByPipelineActionID load an action from database
 func LoadActionByPipelineActionID(db gorp.SqlExecutor, pipelineActionID int64) (*sdk.Action, error) {
-	query := `SELECT action.id, action.name, action.description, action.type, action.last_modified, action.enabled
+	query := `SELECT action.id, action.name, action.description, action.type, action.last_modified, action.enabled, action.project_id
 	          FROM action
 	          JOIN pipeline_action ON pipeline_action.action_id = action.id
 	          WHERE pipeline_action.id = $1`
@@ -162,7 +223,7 @@ func LoadActionByPipelineActionID(db gorp.SqlExecutor, pipelineActionID int64) (
 
 // LoadActions load all actions from database
 func LoadActions(db gorp.SqlExecutor) ([]sdk.Action, error) {
-	query := `SELECT id, name, description, type, last_modified, enabled FROM action WHERE public = true ORDER BY name`
+	query := `SELECT id, name, description, type, last_modified, enabled, project_id FROM action WHERE public = true ORDER BY name`
 	return loadActions(db, query)
 }
 
@@ -180,13 +241,15 @@ func loadActions(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.
 	for rows.Next() {
 		a := sdk.Action{}
 		var lastModified time.Time
-		if err := rows.Scan(&a.ID, &a.Name, &a.Description, &a.Type, &lastModified, &a.Enabled); err != nil {
+		var projectID sql.NullInt64
+		if err := rows.Scan(&a.ID, &a.Name, &a.Description, &a.Type, &lastModified, &a.Enabled, &projectID); err != nil {
 			if err == sql.ErrNoRows {
 				return nil, sdk.ErrNoAction
 			}
 			return nil, fmt.Errorf("cannot Scan> %s", err)
 		}
 		a.LastModified = lastModified.Unix()
+		a.ProjectID = projectID.Int64
 		acts = append(acts, a)
 	}
 
@@ -216,6 +279,22 @@ func loadActionDependencies(db gorp.SqlExecutor, a *sdk.Action) error {
 		return fmt.Errorf("cannot LoadActionParameters> %s", err)
 	}
 
+	if a.Type != sdk.JoinedAction {
+		a.Version, err = currentVersion(db, a.ID)
+		if err != nil {
+			return fmt.Errorf("cannot load current version> %s", err)
+		}
+		deprecated, err := LoadDeprecatedVersions(db, a.ID)
+		if err != nil {
+			return err
+		}
+		a.Deprecated = deprecated[a.Version]
+
+		if err := loadSource(db, a); err != nil {
+			return err
+		}
+	}
+
 	// Don't try to load children is action is builtin
 	if a.Type == sdk.BuiltinAction {
 		return nil
@@ -261,6 +340,17 @@ func UpdateActionDB(db gorp.SqlExecutor, a *sdk.Action, userID int64) error {
 		return err
 	}
 
+	// Actions created before they were versioned get their first version before being updated
+	version, errV := currentVersion(db, a.ID)
+	if errV != nil {
+		return errV
+	}
+	if version == 0 {
+		if err := recordVersion(db, a.ID, 0); err != nil {
+			return err
+		}
+	}
+
 	if err := deleteActionChildren(db, a.ID); err != nil {
 		return err
 	}
@@ -327,8 +417,10 @@ func UpdateActionDB(db gorp.SqlExecutor, a *sdk.Action, userID int64) error {
 	}
 
 	query := `UPDATE action SET name=$1,description=$2, type=$3, enabled=$4 WHERE id=$5`
-	_, errdb := db.Exec(query, a.Name, a.Description, string(a.Type), a.Enabled, a.ID)
-	return errdb
+	if _, err := db.Exec(query, a.Name, a.Description, string(a.Type), a.Enabled, a.ID); err != nil {
+		return err
+	}
+	return recordVersion(db, a.ID, userID)
 }
 
 // DeleteAction remove action from database
@@ -31,15 +31,38 @@ the pre-requisites of action`,
 	junit := sdk.NewAction(sdk.JUnitAction)
 	junit.Type = sdk.BuiltinAction
 	junit.Description = `CDS Builtin Action.
-Parse given file to extract Unit Test results.`
+Parse given file to extract Unit Test results.
+Supported formats are JUnit XML, go test -json, TAP, xUnit.net, NUnit and TRX.`
 	junit.Parameter(sdk.Parameter{
 		Name:        "path",
-		Description: `Path to junit xml file.`,
+		Description: `Path to junit xml file. Glob patterns (including **) are allowed, several patterns can be separated by commas.`,
 		Type:        sdk.TextParameter})
+	junit.Parameter(sdk.Parameter{
+		Name:        "format",
+		Description: `Format of the reports: junit, gotest, tap, xunit, nunit or trx. Let it empty to detect it from the content of the files.`,
+		Type:        sdk.StringParameter})
 	if err := checkBuiltinAction(db, junit); err != nil {
 		return err
 	}
 
+	// ----------------------------------- Static analysis ---------------------
+	analysis := sdk.NewAction(sdk.StaticAnalysisAction)
+	analysis.Type = sdk.BuiltinAction
+	analysis.Description = `CDS Builtin Action.
+Parse given static or security analysis reports (SARIF or Checkstyle) and upload their findings.
+Findings counters are available as build variables: cds.analysis.high, cds.analysis.new.high...`
+	analysis.Parameter(sdk.Parameter{
+		Name:        "path",
+		Description: `Path to the report files, glob patterns are allowed.`,
+		Type:        sdk.StringParameter})
+	analysis.Parameter(sdk.Parameter{
+		Name:        "format",
+		Description: `Format of the reports: sarif or checkstyle. Let it empty to detect it from the content of the files.`,
+		Type:        sdk.StringParameter})
+	if err := checkBuiltinAction(db, analysis); err != nil {
+		return err
+	}
+
 	// ----------------------------------- Git clone    -----------------------
 	gitclone := sdk.NewAction(sdk.GitCloneAction)
 	gitclone.Type = sdk.BuiltinAction
@@ -167,12 +190,16 @@ func createBuiltinArtifactUploadAction(db *gorp.DbMap) error {
 	upload.Parameter(sdk.Parameter{
 		Name:        "path",
 		Type:        sdk.StringParameter,
-		Description: "Path of file to upload, example: ./src/yourFile.json"})
+		Description: "Path of file to upload, example: ./src/yourFile.json. Glob patterns (including **) and directories are allowed, several patterns can be separated by commas"})
 	upload.Parameter(sdk.Parameter{
 		Name:        "tag",
 		Type:        sdk.StringParameter,
 		Description: "Artifact will be uploaded with a tag, generally {{.cds.version}}",
 		Value:       "{{.cds.version}}"})
+	upload.Parameter(sdk.Parameter{
+		Name:        "archive",
+		Type:        sdk.StringParameter,
+		Description: "Name of a tar.gz archive in which all the files are uploaded, example: dist.tar.gz. If empty, files are uploaded one by one"})
 	upload.Parameter(sdk.Parameter{
 		Name:        "enabled",
 		Type:        sdk.BooleanParameter,
@@ -213,6 +240,18 @@ func createBuiltinArtifactDownloadAction(db *gorp.DbMap) error {
 		Name:        "application",
 		Description: "Application from where artifacts will be downloaded, generally {{.cds.application}}",
 		Type:        sdk.StringParameter})
+	dl.Parameter(sdk.Parameter{
+		Name:        "workflow",
+		Description: "Workflow from where artifacts will be downloaded. Artifacts are downloaded from the last successful run of this workflow",
+		Type:        sdk.StringParameter})
+	dl.Parameter(sdk.Parameter{
+		Name:        "branch",
+		Description: "Only the runs built on this git branch or tag are considered to find the last successful run, example: master",
+		Type:        sdk.StringParameter})
+	dl.Parameter(sdk.Parameter{
+		Name:        "release",
+		Description: "Version of the release of the application from where artifacts will be downloaded",
+		Type:        sdk.StringParameter})
 	dl.Parameter(sdk.Parameter{
 		Name:        "enabled",
 		Type:        sdk.BooleanParameter,
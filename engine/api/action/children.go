@@ -10,11 +10,11 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-func insertEdge(db gorp.SqlExecutor, parentID, childID int64, execOrder int, optional, alwaysExecuted, enabled bool) (int64, error) {
-	query := `INSERT INTO action_edge (parent_id, child_id, exec_order, optional, always_executed, enabled) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+func insertEdge(db gorp.SqlExecutor, parentID, childID, childVersion int64, execOrder int, optional, alwaysExecuted, enabled bool) (int64, error) {
+	query := `INSERT INTO action_edge (parent_id, child_id, child_version, exec_order, optional, always_executed, enabled) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
 
 	var id int64
-	err := db.QueryRow(query, parentID, childID, execOrder, optional, alwaysExecuted, enabled).Scan(&id)
+	err := db.QueryRow(query, parentID, childID, childVersion, execOrder, optional, alwaysExecuted, enabled).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -27,7 +27,7 @@ func insertActionChild(db gorp.SqlExecutor, actionID int64, child sdk.Action, ex
 		return fmt.Errorf("insertActionChild: child action has no id")
 	}
 
-	id, err := insertEdge(db, actionID, child.ID, execOrder, child.Optional, child.AlwaysExecuted, child.Enabled)
+	id, err := insertEdge(db, actionID, child.ID, child.Version, execOrder, child.Optional, child.AlwaysExecuted, child.Enabled)
 	if err != nil {
 		return err
 	}
@@ -67,7 +67,8 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 	var children []sdk.Action
 	var edgeIDs []int64
 	var childrenIDs []int64
-	query := `SELECT id, child_id, exec_order, optional, always_executed, enabled FROM action_edge WHERE parent_id = $1 ORDER BY exec_order ASC`
+	var childrenVersions []int64
+	query := `SELECT id, child_id, child_version, exec_order, optional, always_executed, enabled FROM action_edge WHERE parent_id = $1 ORDER BY exec_order ASC`
 
 	rows, err := db.Query(query, actionID)
 	if err != nil {
@@ -75,7 +76,7 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 	}
 	defer rows.Close()
 
-	var edgeID, childID int64
+	var edgeID, childID, childVersion int64
 	var execOrder int
 	var optional, alwaysExecuted, enabled bool
 	var mapOptional = make(map[int64]bool)
@@ -83,23 +84,35 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 	var mapEnabled = make(map[int64]bool)
 
 	for rows.Next() {
-		err = rows.Scan(&edgeID, &childID, &execOrder, &optional, &alwaysExecuted, &enabled)
+		err = rows.Scan(&edgeID, &childID, &childVersion, &execOrder, &optional, &alwaysExecuted, &enabled)
 		if err != nil {
 			return nil, err
 		}
 		edgeIDs = append(edgeIDs, edgeID)
 		childrenIDs = append(childrenIDs, childID)
+		childrenVersions = append(childrenVersions, childVersion)
 		mapOptional[edgeID] = optional
 		mapAlwaysExecuted[edgeID] = alwaysExecuted
 		mapEnabled[edgeID] = enabled
 	}
 	rows.Close()
 
-	for _, childID := range childrenIDs {
+	for i, childID := range childrenIDs {
 		a, err := LoadActionByID(db, childID)
 		if err != nil {
 			return nil, fmt.Errorf("cannot LoadActionByID> %s", err)
 		}
+		latest := a.Version
+
+		// The step uses the version of the action it pinned, or follows its latest version
+		if childrenVersions[i] != 0 {
+			a, err = LoadVersion(db, childID, childrenVersions[i])
+			if err != nil {
+				return nil, fmt.Errorf("cannot LoadVersion %d of action %d> %s", childrenVersions[i], childID, err)
+			}
+		}
+		a.Version = childrenVersions[i]
+		a.LatestVersion = latest
 		children = append(children, *a)
 	}
 
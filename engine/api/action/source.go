@@ -0,0 +1,22 @@
+package action
+
+import (
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// loadSource sets the name of the source of an action synchronized from a git repository, and the commit the
+// action comes from
+func loadSource(db gorp.SqlExecutor, a *sdk.Action) error {
+	query := `SELECT action_source.name, action_source_action.commit
+	FROM action_source_action
+	JOIN action_source ON action_source.id = action_source_action.source_id
+	WHERE action_source_action.action_id = $1`
+	if err := db.QueryRow(query, a.ID).Scan(&a.Source, &a.SourceCommit); err != nil && err != sql.ErrNoRows {
+		return sdk.WrapError(err, "loadSource> Cannot load source of action %d", a.ID)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package action
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/history"
+	"github.com/ovh/cds/sdk"
+)
+
+// recordVersion stores the definition of the action as a new version in the history, unless it does not differ
+// from the last version. Jobs are not versioned.
+func recordVersion(db gorp.SqlExecutor, actionID, userID int64) error {
+	a, err := LoadActionByID(db, actionID)
+	if err != nil {
+		return sdk.WrapError(err, "recordVersion> Cannot load action %d", actionID)
+	}
+	if a.Type == sdk.JoinedAction {
+		return nil
+	}
+
+	var u *sdk.User
+	if userID != 0 {
+		u = &sdk.User{ID: userID}
+		if err := db.QueryRow(`SELECT username FROM "user" WHERE id = $1`, userID).Scan(&u.Username); err != nil && err != sql.ErrNoRows {
+			return sdk.WrapError(err, "recordVersion> Cannot load user %d", userID)
+		}
+	}
+
+	s := snapshot(*a)
+	return history.Record(db, sdk.HistoryAction, a.ProjectID, a.ID, a.Name, s, u)
+}
+
+// snapshot returns the definition of an action without the version and source information, which are not part
+// of a version
+func snapshot(a sdk.Action) sdk.Action {
+	a.Version = 0
+	a.LatestVersion = 0
+	a.Deprecated = false
+	a.Source = ""
+	a.SourceCommit = ""
+	children := make([]sdk.Action, len(a.Actions))
+	for i := range a.Actions {
+		pinned := a.Actions[i].Version
+		children[i] = snapshot(a.Actions[i])
+		children[i].Version = pinned
+	}
+	a.Actions = children
+	return a
+}
+
+// currentVersion returns the last version of an action, 0 if it has never been versioned
+func currentVersion(db gorp.SqlExecutor, actionID int64) (int64, error) {
+	query := `SELECT COALESCE(MAX(version), 0) FROM object_history WHERE object_type = $1 AND object_id = $2`
+	return db.SelectInt(query, sdk.HistoryAction, actionID)
+}
+
+// LoadVersion loads a version of an action from the history
+func LoadVersion(db gorp.SqlExecutor, actionID, version int64) (*sdk.Action, error) {
+	h, err := history.LoadVersion(db, sdk.HistoryAction, actionID, version)
+	if err != nil {
+		if err == sdk.ErrHistoryNotFound {
+			return nil, sdk.ErrActionVersionNotFound
+		}
+		return nil, sdk.WrapError(err, "LoadVersion> Cannot load version %d of action %d", version, actionID)
+	}
+
+	a := &sdk.Action{}
+	if err := json.Unmarshal(h.Snapshot, a); err != nil {
+		return nil, sdk.WrapError(err, "LoadVersion> Cannot read version %d of action %d", version, actionID)
+	}
+	a.ID = actionID
+	a.Version = version
+
+	deprecated, err := LoadDeprecatedVersions(db, actionID)
+	if err != nil {
+		return nil, err
+	}
+	a.Deprecated = deprecated[version]
+	return a, nil
+}
+
+// LoadDeprecatedVersions returns the deprecated versions of an action
+func LoadDeprecatedVersions(db gorp.SqlExecutor, actionID int64) (map[int64]bool, error) {
+	var versions []int64
+	if _, err := db.Select(&versions, `SELECT version FROM action_version_deprecated WHERE action_id = $1`, actionID); err != nil {
+		return nil, sdk.WrapError(err, "LoadDeprecatedVersions> Cannot load deprecated versions of action %d", actionID)
+	}
+	deprecated := make(map[int64]bool, len(versions))
+	for _, v := range versions {
+		deprecated[v] = true
+	}
+	return deprecated, nil
+}
+
+// DeprecateVersion deprecates a version of an action. Steps can still use it, but the sanity checker warns them.
+func DeprecateVersion(db gorp.SqlExecutor, actionID, version int64) error {
+	if _, err := history.LoadVersion(db, sdk.HistoryAction, actionID, version); err != nil {
+		if err == sdk.ErrHistoryNotFound {
+			return sdk.ErrActionVersionNotFound
+		}
+		return sdk.WrapError(err, "DeprecateVersion> Cannot load version %d of action %d", version, actionID)
+	}
+
+	query := `INSERT INTO action_version_deprecated (action_id, version)
+	SELECT $1, $2 WHERE NOT EXISTS (SELECT 1 FROM action_version_deprecated WHERE action_id = $1 AND version = $2)`
+	if _, err := db.Exec(query, actionID, version); err != nil {
+		return sdk.WrapError(err, "DeprecateVersion> Cannot deprecate version %d of action %d", version, actionID)
+	}
+	return nil
+}
+
+// ResolveChildren sets the id of the children given by name, looking for the actions of the project first,
+// and checks that the project can use the children and their pinned versions
+func ResolveChildren(db gorp.SqlExecutor, projectID int64, a *sdk.Action) error {
+	for i := range a.Actions {
+		child := &a.Actions[i]
+
+		var ch *sdk.Action
+		var err error
+		if child.ID == 0 {
+			ch, err = LoadActionForProject(db, projectID, child.Name)
+		} else {
+			ch, err = LoadActionByID(db, child.ID)
+		}
+		if err != nil {
+			return sdk.WrapError(err, "ResolveChildren> Cannot load action %s", child.Name)
+		}
+		if ch.Type == sdk.JoinedAction || (ch.ProjectID != 0 && ch.ProjectID != projectID) {
+			return sdk.WrapError(sdk.ErrNoAction, "ResolveChildren> Action %s cannot be used in project %d", ch.Name, projectID)
+		}
+		child.ID = ch.ID
+
+		if child.Version == 0 {
+			continue
+		}
+		if _, err := history.LoadVersion(db, sdk.HistoryAction, ch.ID, child.Version); err != nil {
+			if err == sdk.ErrHistoryNotFound {
+				return sdk.WrapError(sdk.ErrActionVersionNotFound, "ResolveChildren> Action %s has no version %d", ch.Name, child.Version)
+			}
+			return sdk.WrapError(err, "ResolveChildren> Cannot load version %d of action %s", child.Version, ch.Name)
+		}
+	}
+	return nil
+}
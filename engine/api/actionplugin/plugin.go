@@ -3,12 +3,15 @@ package actionplugin
 import (
 	"context"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/go-gorp/gorp"
+	"github.com/golang/protobuf/ptypes/empty"
 
 	"github.com/ovh/cds/engine/api/action"
 	"github.com/ovh/cds/sdk"
@@ -20,49 +23,91 @@ import (
 func Get(name, path string) (*sdk.ActionPlugin, *plugin.Parameters, error) {
 	//FIXME: run this in a jail with apparmor
 	log.Debug("actionplugin.Get> Getting info from '%s' (%s)", name, path)
-	client := plugin.NewClient(context.Background(), name, path, "ID", "http://127.0.0.1:8081", true)
-	defer func() {
-		log.Debug("actionplugin.Get> kill rpc-server")
-		client.Kill()
-	}()
-	log.Debug("actionplugin.Get> Client '%s'", name)
-	_plugin, err := client.Instance()
+	conn, err := plugin.Dial(context.Background(), name, path, "ID", "http://127.0.0.1:8081", true)
 	if err != nil {
 		return nil, nil, sdk.WrapError(err, "actionplugin.Get> ")
 	}
+	defer func() {
+		log.Debug("actionplugin.Get> kill plugin")
+		conn.Close()
+	}()
+	log.Debug("actionplugin.Get> Client '%s' (protocol %d)", name, conn.ProtocolVersion)
+
+	var ap sdk.ActionPlugin
+	var params plugin.Parameters
+	if conn.ProtocolVersion == plugin.GRPCProtocolVersion {
+		m, err := conn.GetManifest(context.Background(), &empty.Empty{})
+		if err != nil {
+			return nil, nil, sdk.WrapError(err, "actionplugin.Get> Unable to get manifest")
+		}
+		ap = sdk.ActionPlugin{Name: m.Name, Author: m.Author, Description: m.Description, Version: m.Version}
+		params = manifestParameters(m)
+	} else {
+		_plugin, err := conn.Client.Instance()
+		if err != nil {
+			return nil, nil, sdk.WrapError(err, "actionplugin.Get> ")
+		}
+		ap = sdk.ActionPlugin{Name: _plugin.Name(), Author: _plugin.Author(), Description: _plugin.Description()}
+		params = _plugin.Parameters()
+	}
+
+	ap.Filename = name
+	if err := Stat(&ap, path); err != nil {
+		return nil, nil, err
+	}
+
+	return &ap, &params, nil
+}
 
+//Stat sets the size, the permissions and the checksums of the binary file of a plugin
+func Stat(ap *sdk.ActionPlugin, path string) error {
 	fi, err := os.Open(path)
 	if err != nil {
-		return nil, nil, err
+		return err
 	}
 	defer fi.Close()
 	stat, err := fi.Stat()
 	if err != nil {
-		return nil, nil, err
+		return err
 	}
 
-	//Compute md5sum
+	//Compute md5sum and sha256sum
 	hash := md5.New()
-	if _, err := io.Copy(hash, fi); err != nil {
-		return nil, nil, err
+	hash256 := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(hash, hash256), fi); err != nil {
+		return err
 	}
 	hashInBytes := hash.Sum(nil)[:16]
-	md5sumStr := hex.EncodeToString(hashInBytes)
 
-	ap := sdk.ActionPlugin{
-		Filename:    name,
-		Name:        _plugin.Name(),
-		Author:      _plugin.Author(),
-		Description: _plugin.Description(),
-		Path:        path,
-		Size:        stat.Size(),
-		Perm:        uint32(stat.Mode().Perm()),
-		MD5sum:      md5sumStr,
-	}
-
-	params := _plugin.Parameters()
+	ap.Path = path
+	ap.Size = stat.Size()
+	ap.Perm = uint32(stat.Mode().Perm())
+	ap.MD5sum = hex.EncodeToString(hashInBytes)
+	ap.SHA256sum = hex.EncodeToString(hash256.Sum(nil))
+	return nil
+}
 
-	return &ap, &params, nil
+//manifestParameters returns the parameters of the manifest of a plugin served with the gRPC protocol.
+//Values of list parameters are separated by semicolons, the default value first.
+func manifestParameters(m *plugin.Manifest) plugin.Parameters {
+	params := plugin.NewParameters()
+	for _, p := range m.Parameters {
+		value := p.Default
+		if plugin.ParameterType(p.Type) == plugin.ListParameter && len(p.Values) > 0 {
+			values := []string{}
+			if p.Default != "" {
+				values = append(values, p.Default)
+			}
+			for _, v := range p.Values {
+				if v != p.Default {
+					values = append(values, v)
+				}
+			}
+			value = strings.Join(values, ";")
+		}
+		params.Add(p.Name, plugin.ParameterType(p.Type), p.Description, value)
+	}
+	return params
 }
 
 func actionPluginToAction(ap *sdk.ActionPlugin, params *plugin.Parameters) (*sdk.Action, error) {
@@ -129,38 +174,64 @@ func Insert(db gorp.SqlExecutor, ap *sdk.ActionPlugin, params *plugin.Parameters
 		return nil, err
 	}
 
-	query := `INSERT INTO plugin (name, size, perm, md5sum, object_path) VALUES ($1, $2, $3, $4, $5) RETURNING id`
-	if err = db.QueryRow(query, ap.Name, ap.Size, ap.Perm, ap.MD5sum, ap.ObjectPath).Scan(&ap.ID); err != nil {
+	ap.Default = true
+	if err := InsertBinary(db, ap); err != nil {
 		return nil, err
 	}
 	return a, nil
 }
 
-//Update action in database
+//Update replaces the binary of the plugin for its version and its platform. The action is updated if the binary
+//is the one of the default version and the parameters of the plugin are known.
 func Update(db gorp.SqlExecutor, ap *sdk.ActionPlugin, params *plugin.Parameters, userID int64) (*sdk.Action, error) {
-	a, err := actionPluginToAction(ap, params)
+	//oldA, err := action.LoadPublicAction(db, a.Name, action.WithClearPasswords())
+	oldA, err := action.LoadPublicAction(db, ap.Name)
 	if err != nil {
 		return nil, err
 	}
 
-	//oldA, err := action.LoadPublicAction(db, a.Name, action.WithClearPasswords())
-	oldA, err := action.LoadPublicAction(db, a.Name)
-	if err != nil {
+	old, err := LoadBinary(db, ap.Name, ap.Version, ap.OS, ap.Arch)
+	if err != nil && err != sdk.ErrPluginNotFound {
 		return nil, err
 	}
-	a.ID = oldA.ID
+	if old != nil {
+		ap.Default = old.Default
+		ap.Deprecated = old.Deprecated
+	}
 
-	if err := action.UpdateActionDB(db, a, userID); err != nil {
+	a := oldA
+	if params != nil && ap.Default {
+		a, err = UpdateAction(db, ap, params, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	query := "DELETE FROM plugin WHERE name = $1 AND version = $2 AND os = $3 AND arch = $4"
+	if _, err := db.Exec(query, ap.Name, ap.Version, ap.OS, ap.Arch); err != nil {
 		return nil, err
 	}
 
-	query := "DELETE FROM plugin WHERE name = $1"
-	if _, err := db.Exec(query, a.Name); err != nil {
+	if err := InsertBinary(db, ap); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+//UpdateAction updates the action of the plugin with the parameters of a binary
+func UpdateAction(db gorp.SqlExecutor, ap *sdk.ActionPlugin, params *plugin.Parameters, userID int64) (*sdk.Action, error) {
+	a, err := actionPluginToAction(ap, params)
+	if err != nil {
 		return nil, err
 	}
 
-	query = `INSERT INTO plugin (name, size, perm, md5sum, object_path) VALUES ($1, $2, $3, $4, $5) RETURNING id`
-	if err = db.QueryRow(query, ap.Name, ap.Size, ap.Perm, ap.MD5sum, ap.ObjectPath).Scan(&ap.ID); err != nil {
+	oldA, err := action.LoadPublicAction(db, a.Name)
+	if err != nil {
+		return nil, err
+	}
+	a.ID = oldA.ID
+
+	if err := action.UpdateActionDB(db, a, userID); err != nil {
 		return nil, err
 	}
 	return a, nil
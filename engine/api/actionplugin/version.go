@@ -0,0 +1,228 @@
+package actionplugin
+
+import (
+	"database/sql"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const binaryColumns = "id, name, version, os, arch, size, perm, md5sum, sha256sum, object_path, is_default, deprecated, created"
+
+//InsertBinary inserts the binary of a plugin for its version and its platform
+func InsertBinary(db gorp.SqlExecutor, ap *sdk.ActionPlugin) error {
+	query := `INSERT INTO plugin (name, version, os, arch, size, perm, md5sum, sha256sum, object_path, is_default, deprecated, created)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, current_timestamp) RETURNING id, created`
+	return db.QueryRow(query, ap.Name, ap.Version, ap.OS, ap.Arch, ap.Size, ap.Perm, ap.MD5sum, ap.SHA256sum, ap.ObjectPath, ap.Default, ap.Deprecated).Scan(&ap.ID, &ap.Created)
+}
+
+//AddBinary inserts the binary of an existing plugin for a new version or a new platform. The binary is the default
+//one if its version is the default version of the plugin, as set by Promote for all the platforms
+func AddBinary(db gorp.SqlExecutor, ap *sdk.ActionPlugin) error {
+	aps, err := LoadByName(db, ap.Name)
+	if err != nil {
+		return sdk.WrapError(err, "AddBinary> Unable to load plugin %s", ap.Name)
+	}
+	ap.Default = isDefaultVersion(aps, ap.Version)
+	return InsertBinary(db, ap)
+}
+
+//isDefaultVersion returns true if the binaries of the version are the default ones
+func isDefaultVersion(aps []sdk.ActionPlugin, version string) bool {
+	for _, ap := range aps {
+		if ap.Version == version && ap.Default {
+			return true
+		}
+	}
+	return false
+}
+
+//LoadAll returns the binaries of all plugins
+func LoadAll(db gorp.SqlExecutor) ([]sdk.ActionPlugin, error) {
+	query := "SELECT " + binaryColumns + " FROM plugin ORDER BY name, created DESC"
+	return loadBinaries(db, query)
+}
+
+//LoadByName returns all the binaries of a plugin
+func LoadByName(db gorp.SqlExecutor, name string) ([]sdk.ActionPlugin, error) {
+	query := "SELECT " + binaryColumns + " FROM plugin WHERE name = $1 ORDER BY created DESC"
+	return loadBinaries(db, query, name)
+}
+
+//LoadBinary returns the binary of a plugin for a version and a platform
+func LoadBinary(db gorp.SqlExecutor, name, version, goos, goarch string) (*sdk.ActionPlugin, error) {
+	query := "SELECT " + binaryColumns + " FROM plugin WHERE name = $1 AND version = $2 AND os = $3 AND arch = $4"
+	aps, err := loadBinaries(db, query, name, version, goos, goarch)
+	if err != nil {
+		return nil, err
+	}
+	if len(aps) == 0 {
+		return nil, sdk.ErrPluginNotFound
+	}
+	return &aps[0], nil
+}
+
+func loadBinaries(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.ActionPlugin, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aps := []sdk.ActionPlugin{}
+	for rows.Next() {
+		var ap sdk.ActionPlugin
+		var objectPath sql.NullString
+		if err := rows.Scan(&ap.ID, &ap.Name, &ap.Version, &ap.OS, &ap.Arch, &ap.Size, &ap.Perm, &ap.MD5sum, &ap.SHA256sum, &objectPath, &ap.Default, &ap.Deprecated, &ap.Created); err != nil {
+			return nil, err
+		}
+		ap.ObjectPath = objectPath.String
+		aps = append(aps, ap)
+	}
+	return aps, rows.Err()
+}
+
+//Resolve returns the binary of a plugin matching a version and a platform. If the version is empty, the default
+//version is used. Otherwise the version is exactly matched, or the latest version which is not deprecated starting
+//with it: 1.2 matches 1.2.3 but not 1.20. Binaries uploaded without platform match every platform. If the
+//platform is empty, the one of the API is used.
+func Resolve(db gorp.SqlExecutor, name, version, goos, goarch string) (*sdk.ActionPlugin, error) {
+	if goos == "" {
+		goos, goarch = runtime.GOOS, runtime.GOARCH
+	}
+
+	aps, err := LoadByName(db, name)
+	if err != nil {
+		return nil, sdk.WrapError(err, "Resolve> Unable to load plugin %s", name)
+	}
+
+	res := resolve(aps, version, goos, goarch)
+	if res == nil {
+		return nil, sdk.ErrPluginNotFound
+	}
+	return res, nil
+}
+
+func resolve(aps []sdk.ActionPlugin, version, goos, goarch string) *sdk.ActionPlugin {
+	var res *sdk.ActionPlugin
+	for i := range aps {
+		ap := &aps[i]
+		if ap.OS != "" && (ap.OS != goos || ap.Arch != goarch) {
+			continue
+		}
+
+		switch {
+		case version == "":
+			if !ap.Default {
+				continue
+			}
+		case ap.Version == version:
+		case strings.HasPrefix(ap.Version, version+".") && !ap.Deprecated:
+		default:
+			continue
+		}
+
+		if res == nil {
+			res = ap
+			continue
+		}
+		//Prefer the latest version, and the binary built for the platform
+		if c := CompareVersions(ap.Version, res.Version); c > 0 || (c == 0 && ap.OS != "" && res.OS == "") {
+			res = ap
+		}
+	}
+	return res
+}
+
+//CompareVersions compares two versions such as 1.2.10 and 1.2.9 number by number. It returns 1 if a is greater
+//than b, -1 if a is lower than b, and 0 if they are equal
+func CompareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		if x == y {
+			continue
+		}
+
+		xi, errx := strconv.Atoi(x)
+		yi, erry := strconv.Atoi(y)
+		switch {
+		case errx == nil && erry == nil && xi > yi:
+			return 1
+		case errx == nil && erry == nil && xi < yi:
+			return -1
+		case x > y:
+			return 1
+		default:
+			return -1
+		}
+	}
+	return 0
+}
+
+//Promote makes a version the default version of a plugin
+func Promote(db gorp.SqlExecutor, name, version string) error {
+	aps, err := LoadByName(db, name)
+	if err != nil {
+		return sdk.WrapError(err, "Promote> Unable to load plugin %s", name)
+	}
+
+	var found bool
+	for _, ap := range aps {
+		if ap.Version != version {
+			continue
+		}
+		if ap.Deprecated {
+			return sdk.ErrPluginVersionDeprecated
+		}
+		found = true
+	}
+	if !found {
+		return sdk.ErrPluginNotFound
+	}
+
+	query := "UPDATE plugin SET is_default = (version = $2) WHERE name = $1"
+	if _, err := db.Exec(query, name, version); err != nil {
+		return sdk.WrapError(err, "Promote> Unable to promote plugin %s@%s", name, version)
+	}
+	return nil
+}
+
+//Deprecate deprecates a version of a plugin, which is not resolved anymore unless it's exactly pinned
+func Deprecate(db gorp.SqlExecutor, name, version string) error {
+	aps, err := LoadByName(db, name)
+	if err != nil {
+		return sdk.WrapError(err, "Deprecate> Unable to load plugin %s", name)
+	}
+
+	var found bool
+	for _, ap := range aps {
+		if ap.Version != version {
+			continue
+		}
+		if ap.Default {
+			return sdk.ErrPluginVersionDefault
+		}
+		found = true
+	}
+	if !found {
+		return sdk.ErrPluginNotFound
+	}
+
+	query := "UPDATE plugin SET deprecated = true WHERE name = $1 AND version = $2"
+	if _, err := db.Exec(query, name, version); err != nil {
+		return sdk.WrapError(err, "Deprecate> Unable to deprecate plugin %s@%s", name, version)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package actionplugin
+
+import (
+	"testing"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.10", "1.2.9", 1},
+		{"1.2", "1.2.0", -1},
+		{"v1.3", "1.2.9", 1},
+		{"1.2.3", "1.2.3", 0},
+		{"", "1.0", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	aps := []sdk.ActionPlugin{
+		{ID: 1, Version: "1.1.0", Default: true},
+		{ID: 2, Version: "1.2.0", OS: "linux", Arch: "amd64"},
+		{ID: 3, Version: "1.2.1", OS: "linux", Arch: "amd64"},
+		{ID: 4, Version: "1.2.2", OS: "linux", Arch: "amd64", Deprecated: true},
+		{ID: 5, Version: "1.2.1", OS: "darwin", Arch: "amd64"},
+		{ID: 6, Version: "1.20.0", OS: "linux", Arch: "amd64"},
+	}
+	// Binary added for another platform of the default version
+	aps = append(aps, sdk.ActionPlugin{ID: 7, Version: "1.1.0", OS: "darwin", Arch: "arm64", Default: isDefaultVersion(aps, "1.1.0")})
+
+	tests := []struct {
+		name                string
+		version, goos, arch string
+		want                int64
+	}{
+		{"default version", "", "linux", "amd64", 1},
+		{"latest of pinned minor", "1.2", "linux", "amd64", 3},
+		{"deprecated exact version", "1.2.2", "linux", "amd64", 4},
+		{"other platform", "1.2", "darwin", "amd64", 5},
+		{"no binary for platform", "1.2", "windows", "amd64", 0},
+		{"pinned major", "1", "linux", "amd64", 6},
+		{"default version of added platform", "", "darwin", "arm64", 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got int64
+			if ap := resolve(aps, tt.version, tt.goos, tt.arch); ap != nil {
+				got = ap.ID
+			}
+			if got != tt.want {
+				t.Errorf("resolve(%s, %s/%s) = %d, want %d", tt.version, tt.goos, tt.arch, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package actionsource
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadAll loads the action sources of a project
+func LoadAll(db gorp.SqlExecutor, projectID int64) ([]sdk.ActionSource, error) {
+	return loadAll(db, `SELECT * FROM action_source WHERE project_id = $1 ORDER BY name`, projectID)
+}
+
+// LoadToSynchronize loads the action sources which have not been synchronized since the given delay
+func LoadToSynchronize(db gorp.SqlExecutor, delay time.Duration) ([]sdk.ActionSource, error) {
+	return loadAll(db, `SELECT * FROM action_source WHERE last_sync < $1 ORDER BY last_sync`, time.Now().Add(-delay))
+}
+
+func loadAll(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.ActionSource, error) {
+	var dbSources []dbActionSource
+	if _, err := db.Select(&dbSources, query, args...); err != nil {
+		return nil, sdk.WrapError(err, "loadAll> Cannot load action sources")
+	}
+
+	sources := make([]sdk.ActionSource, len(dbSources))
+	for i := range dbSources {
+		sources[i] = sdk.ActionSource(dbSources[i])
+	}
+	return sources, nil
+}
+
+// Load loads an action source of a project given its name
+func Load(db gorp.SqlExecutor, projectID int64, name string) (*sdk.ActionSource, error) {
+	var dbSource dbActionSource
+	if err := db.SelectOne(&dbSource, `SELECT * FROM action_source WHERE project_id = $1 AND name = $2`, projectID, name); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrActionSourceNotFound
+		}
+		return nil, sdk.WrapError(err, "Load> Cannot load action source %s", name)
+	}
+	s := sdk.ActionSource(dbSource)
+	return &s, nil
+}
+
+// Insert inserts an action source
+func Insert(db gorp.SqlExecutor, s *sdk.ActionSource) error {
+	if err := s.IsValid(); err != nil {
+		return err
+	}
+	if _, err := Load(db, s.ProjectID, s.Name); err == nil {
+		return sdk.ErrActionSourceExists
+	} else if err != sdk.ErrActionSourceNotFound {
+		return err
+	}
+
+	s.LastCommit = ""
+	s.LastSync = time.Time{}
+	s.SyncErrors = nil
+	dbSource := dbActionSource(*s)
+	if err := db.Insert(&dbSource); err != nil {
+		return sdk.WrapError(err, "Insert> Cannot insert action source %s", s.Name)
+	}
+	*s = sdk.ActionSource(dbSource)
+	return nil
+}
+
+// Update updates an action source
+func Update(db gorp.SqlExecutor, s *sdk.ActionSource) error {
+	if err := s.IsValid(); err != nil {
+		return err
+	}
+	dbSource := dbActionSource(*s)
+	if _, err := db.Update(&dbSource); err != nil {
+		return sdk.WrapError(err, "Update> Cannot update action source %s", s.Name)
+	}
+	return nil
+}
+
+// Delete deletes an action source. The actions synchronized from the source are kept, but they are not
+// synchronized anymore.
+func Delete(db gorp.SqlExecutor, s *sdk.ActionSource) error {
+	if _, err := db.Exec(`DELETE FROM action_source WHERE id = $1`, s.ID); err != nil {
+		return sdk.WrapError(err, "Delete> Cannot delete action source %s", s.Name)
+	}
+	return nil
+}
+
+// lock marks the source as being synchronized. It returns false if another synchronization has started since the
+// given date, so that the API instances do not synchronize the same source at the same time.
+func lock(db gorp.SqlExecutor, s *sdk.ActionSource, since time.Time) (bool, error) {
+	res, err := db.Exec(`UPDATE action_source SET last_sync = $2 WHERE id = $1 AND last_sync < $3`, s.ID, time.Now(), since)
+	if err != nil {
+		return false, sdk.WrapError(err, "lock> Cannot lock action source %s", s.Name)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, sdk.WrapError(err, "lock> Cannot lock action source %s", s.Name)
+	}
+	return n == 1, nil
+}
+
+// link records that an action is synchronized from a file of a source, at a commit
+type link struct {
+	SourceID int64
+	Path     string
+	Commit   string
+	Hash     string
+}
+
+// loadLink returns the source an action is synchronized from, nil if the action is not synchronized
+func loadLink(db gorp.SqlExecutor, actionID int64) (*link, error) {
+	l := &link{}
+	query := `SELECT source_id, path, commit, hash FROM action_source_action WHERE action_id = $1`
+	if err := db.QueryRow(query, actionID).Scan(&l.SourceID, &l.Path, &l.Commit, &l.Hash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, sdk.WrapError(err, "loadLink> Cannot load source of action %d", actionID)
+	}
+	return l, nil
+}
+
+// saveLink records the source an action is synchronized from
+func saveLink(db gorp.SqlExecutor, actionID int64, l link) error {
+	if _, err := db.Exec(`DELETE FROM action_source_action WHERE action_id = $1`, actionID); err != nil {
+		return sdk.WrapError(err, "saveLink> Cannot delete source of action %d", actionID)
+	}
+	query := `INSERT INTO action_source_action (action_id, source_id, path, commit, hash) VALUES ($1, $2, $3, $4, $5)`
+	if _, err := db.Exec(query, actionID, l.SourceID, l.Path, l.Commit, l.Hash); err != nil {
+		return sdk.WrapError(err, "saveLink> Cannot save source of action %d", actionID)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package actionsource
+
+import (
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbActionSource sdk.ActionSource
+
+// PostInsert is a DB Hook, it stores the errors of the last synchronization
+func (s *dbActionSource) PostInsert(db gorp.SqlExecutor) error {
+	errs, err := gorpmapping.JSONToNullString(s.SyncErrors)
+	if err != nil {
+		return sdk.WrapError(err, "PostInsert> Unable to get json from sync errors")
+	}
+	if _, err := db.Exec("UPDATE action_source SET sync_errors = $2 WHERE id = $1", s.ID, errs); err != nil {
+		return sdk.WrapError(err, "PostInsert> Unable to update sync errors of action source %d", s.ID)
+	}
+	return nil
+}
+
+// PostUpdate is a DB Hook, it stores the errors of the last synchronization
+func (s *dbActionSource) PostUpdate(db gorp.SqlExecutor) error {
+	return s.PostInsert(db)
+}
+
+// PostGet is a DB Hook, it loads the errors of the last synchronization
+func (s *dbActionSource) PostGet(db gorp.SqlExecutor) error {
+	var errs sql.NullString
+	if err := db.QueryRow("SELECT sync_errors FROM action_source WHERE id = $1", s.ID).Scan(&errs); err != nil {
+		return sdk.WrapError(err, "PostGet> Unable to load sync errors of action source %d", s.ID)
+	}
+	if err := gorpmapping.JSONNullString(errs, &s.SyncErrors); err != nil {
+		return sdk.WrapError(err, "PostGet> Unable to unmarshal sync errors of action source %d", s.ID)
+	}
+	return nil
+}
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbActionSource{}, "action_source", true, "id"))
+}
@@ -0,0 +1,356 @@
+package actionsource
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/action"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/vcs"
+	"github.com/ovh/cds/sdk/vcs/git"
+)
+
+// syncLockDelay is the time during which an action source cannot be synchronized again on demand
+const syncLockDelay = time.Minute
+
+// Synchronizer is the goroutine which periodically synchronizes the actions of the action sources
+func Synchronizer(c context.Context, DBFunc func() *gorp.DbMap, delay time.Duration) {
+	tick := time.NewTicker(time.Minute).C
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting actionsource.Synchronizer: %v", c.Err())
+				return
+			}
+		case <-tick:
+			db := DBFunc()
+			sources, err := LoadToSynchronize(db, delay)
+			if err != nil {
+				log.Warning("actionsource.Synchronizer> %s", err)
+				continue
+			}
+			for i := range sources {
+				locked, err := lock(db, &sources[i], time.Now().Add(-delay))
+				if err != nil {
+					log.Warning("actionsource.Synchronizer> %s", err)
+					continue
+				}
+				if !locked {
+					continue
+				}
+				if err := synchronize(db, &sources[i], 0); err != nil {
+					log.Warning("actionsource.Synchronizer> Cannot synchronize action source %s of project %d: %s", sources[i].Name, sources[i].ProjectID, err)
+				}
+			}
+		}
+	}
+}
+
+// Synchronize pulls the repository of an action source, and imports the actions it contains. The errors raised by
+// the repository and its files are reported in the source. A source which is being synchronized, or which has just
+// been, is not synchronized again.
+func Synchronize(db *gorp.DbMap, s *sdk.ActionSource, userID int64) error {
+	locked, err := lock(db, s, time.Now().Add(-syncLockDelay))
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return sdk.ErrActionSourceSyncing
+	}
+	return synchronize(db, s, userID)
+}
+
+func synchronize(db *gorp.DbMap, s *sdk.ActionSource, userID int64) error {
+	log.Debug("actionsource.synchronize> Synchronizing action source %s of project %d", s.Name, s.ProjectID)
+
+	proj, err := project.LoadByID(db, s.ProjectID, nil, project.LoadOptions.WithKeys)
+	if err != nil {
+		return sdk.WrapError(err, "synchronize> Cannot load project %d", s.ProjectID)
+	}
+
+	tmpDir, err := ioutil.TempDir("", "cds-action-source")
+	if err != nil {
+		return sdk.WrapError(err, "synchronize> Cannot create temporary directory")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	repoDir := filepath.Join(tmpDir, "repository")
+	s.LastSync = time.Now()
+	if err := clone(db, proj, s, tmpDir, repoDir); err != nil {
+		log.Warning("actionsource.synchronize> Cannot clone repository of action source %s of project %d: %s", s.Name, s.ProjectID, err)
+		s.SyncErrors = []sdk.ActionSourceError{{Message: err.Error()}}
+		return Update(db, s)
+	}
+
+	commit, err := git.LatestCommit(repoDir)
+	if err != nil {
+		return sdk.WrapError(err, "synchronize> Cannot get the commit of action source %s", s.Name)
+	}
+
+	dir, err := sourceDir(repoDir, s.Directory)
+	if err != nil {
+		s.LastCommit = commit
+		s.SyncErrors = []sdk.ActionSourceError{{Message: err.Error()}}
+		return Update(db, s)
+	}
+
+	files, errs := readActions(dir)
+	errs = append(errs, importActions(db, s, commit, files, userID)...)
+
+	s.LastCommit = commit
+	s.SyncErrors = errs
+	return Update(db, s)
+}
+
+// clone clones the repository of an action source. The ssh key of the source is written in keyDir.
+func clone(db gorp.SqlExecutor, proj *sdk.Project, s *sdk.ActionSource, keyDir, repoDir string) error {
+	url := s.URL
+	if s.RepositoriesManager != "" {
+		client, err := repositoriesmanager.AuthorizedClient(db, proj.Key, s.RepositoriesManager)
+		if err != nil {
+			return fmt.Errorf("cannot get client of repositories manager %s: %s", s.RepositoriesManager, err)
+		}
+		repo, err := client.RepoByFullname(s.RepositoryFullname)
+		if err != nil {
+			return fmt.Errorf("cannot get repository %s: %s", s.RepositoryFullname, err)
+		}
+		url = repo.HTTPCloneURL
+		if s.KeyName != "" {
+			url = repo.SSHCloneURL
+		}
+	} else if err := checkURL(url); err != nil {
+		return err
+	}
+
+	var auth *git.AuthOpts
+	if s.KeyName != "" {
+		var key *sdk.ProjectKey
+		for i := range proj.Keys {
+			if proj.Keys[i].Name == s.KeyName && proj.Keys[i].Type == sdk.KeyTypeSsh {
+				key = &proj.Keys[i]
+				break
+			}
+		}
+		if key == nil {
+			return fmt.Errorf("ssh key %s not found in project %s", s.KeyName, proj.Key)
+		}
+
+		keyFile := filepath.Join(keyDir, key.Name)
+		if err := ioutil.WriteFile(keyFile, []byte(key.Private), os.FileMode(0600)); err != nil {
+			return fmt.Errorf("cannot write ssh key %s: %s", s.KeyName, err)
+		}
+		auth = &git.AuthOpts{PrivateKey: vcs.SSHKey{Filename: keyFile, Content: []byte(key.Private)}}
+	}
+
+	opts := &git.CloneOpts{
+		Depth:                   1,
+		Branch:                  s.Branch,
+		Quiet:                   true,
+		NoStrictHostKeyChecking: true,
+	}
+	var stderr bytes.Buffer
+	if err := git.Clone(url, repoDir, auth, opts, &git.OutputOpts{Stdout: ioutil.Discard, Stderr: &stderr}); err != nil {
+		return fmt.Errorf("cannot clone %s: %s %s", url, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// checkURL checks that the git URL of an action source is a remote one, whose host does not resolve to a loopback,
+// link-local or private address
+func checkURL(rawurl string) error {
+	host, err := sdk.GitURLHost(rawurl)
+	if err != nil {
+		return fmt.Errorf("invalid git url %s", rawurl)
+	}
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return fmt.Errorf("cannot resolve git host %s: %s", host, err)
+	}
+	for _, ip := range ips {
+		if !sdk.IsPublicIP(ip) {
+			return fmt.Errorf("git host %s resolves to the non public address %s", host, ip)
+		}
+	}
+	return nil
+}
+
+// sourceDir returns the directory of the repository containing the actions, which must not lead out of the
+// repository through symbolic links
+func sourceDir(repoDir, directory string) (string, error) {
+	root, err := filepath.EvalSymlinks(repoDir)
+	if err != nil {
+		return "", err
+	}
+	dir, err := filepath.EvalSymlinks(filepath.Join(root, filepath.Clean("/"+directory)))
+	if err != nil {
+		return "", fmt.Errorf("cannot read directory %s: %s", directory, err)
+	}
+	if dir != root && !strings.HasPrefix(dir, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("directory %s is out of the repository", directory)
+	}
+	return dir, nil
+}
+
+// actionFile is an action read from a file of an action source
+type actionFile struct {
+	path   string
+	hash   string
+	action *sdk.Action
+}
+
+// readActions parses the action files of a directory and of its subdirectories
+func readActions(dir string) ([]actionFile, []sdk.ActionSourceError) {
+	files := []actionFile{}
+	errs := []sdk.ActionSourceError{}
+	names := map[string]string{}
+
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		rel, errR := filepath.Rel(dir, path)
+		if errR != nil {
+			rel = path
+		}
+		if err != nil {
+			errs = append(errs, sdk.ActionSourceError{File: rel, Message: err.Error()})
+			return nil
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !sdk.IsActionFile(path) {
+			return nil
+		}
+		// Symbolic links are not followed, they could lead out of the repository
+		fi, errL := os.Lstat(path)
+		if errL != nil {
+			errs = append(errs, sdk.ActionSourceError{File: rel, Message: errL.Error()})
+			return nil
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+
+		btes, err := ioutil.ReadFile(path)
+		if err != nil {
+			errs = append(errs, sdk.ActionSourceError{File: rel, Message: err.Error()})
+			return nil
+		}
+
+		var a *sdk.Action
+		if strings.ToLower(filepath.Ext(path)) == ".hcl" {
+			a, err = sdk.NewActionFromScript(btes)
+		} else {
+			a, err = sdk.NewActionFromYAMLScript(btes)
+		}
+		if err == nil && a.Name == "" {
+			err = fmt.Errorf("the name of the action is missing")
+		}
+		if err == nil && names[strings.ToLower(a.Name)] != "" {
+			err = fmt.Errorf("action %s is already defined in %s", a.Name, names[strings.ToLower(a.Name)])
+		}
+		if err != nil {
+			errs = append(errs, sdk.ActionSourceError{File: rel, Message: err.Error()})
+			return nil
+		}
+
+		names[strings.ToLower(a.Name)] = rel
+		sum := sha256.Sum256(btes)
+		files = append(files, actionFile{path: rel, hash: hex.EncodeToString(sum[:]), action: a})
+		return nil
+	})
+
+	return files, errs
+}
+
+// importActions inserts or updates the actions read from the files of a source. As actions can use other actions
+// of the same source, the files which cannot be imported are retried as long as the others succeed.
+func importActions(db *gorp.DbMap, s *sdk.ActionSource, commit string, files []actionFile, userID int64) []sdk.ActionSourceError {
+	pending := files
+	for len(pending) > 0 {
+		failed := []actionFile{}
+		errs := []sdk.ActionSourceError{}
+		for _, f := range pending {
+			if err := importAction(db, s, commit, f, userID); err != nil {
+				failed = append(failed, f)
+				errs = append(errs, sdk.ActionSourceError{File: f.path, Message: err.Error()})
+			}
+		}
+		if len(failed) == len(pending) {
+			return errs
+		}
+		pending = failed
+	}
+	return nil
+}
+
+// importAction inserts or updates an action read from a file of a source. The action is not updated if the file
+// has not changed since the last synchronization.
+func importAction(db *gorp.DbMap, s *sdk.ActionSource, commit string, f actionFile, userID int64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WrapError(err, "importAction> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	a := f.action
+	existing, err := action.LoadProjectAction(tx, s.ProjectID, a.Name)
+	if err != nil && err != sdk.ErrNoAction {
+		return sdk.WrapError(err, "importAction> Cannot load action %s", a.Name)
+	}
+
+	l := link{SourceID: s.ID, Path: f.path, Commit: commit, Hash: f.hash}
+	if existing != nil {
+		current, err := loadLink(tx, existing.ID)
+		if err != nil {
+			return err
+		}
+		if current == nil || current.SourceID != s.ID {
+			return fmt.Errorf("action %s already exists in the project and is not synchronized from this source", a.Name)
+		}
+		if current.Hash == f.hash {
+			if err := saveLink(tx, existing.ID, l); err != nil {
+				return err
+			}
+			return tx.Commit()
+		}
+	}
+
+	a.Type = sdk.DefaultAction
+	a.ProjectID = s.ProjectID
+	if err := action.ResolveChildren(tx, s.ProjectID, a); err != nil {
+		return err
+	}
+
+	if existing == nil {
+		if err := action.InsertAction(tx, a, false); err != nil {
+			return sdk.WrapError(err, "importAction> Cannot insert action %s", a.Name)
+		}
+	} else {
+		a.ID = existing.ID
+		if err := action.UpdateActionDB(tx, a, userID); err != nil {
+			return sdk.WrapError(err, "importAction> Cannot update action %s", a.Name)
+		}
+	}
+
+	if err := saveLink(tx, a.ID, l); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
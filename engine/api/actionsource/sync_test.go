@@ -0,0 +1,89 @@
+package actionsource
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_readActions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cds-action-source-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"build.hcl": `name = "Build"
+steps = [{
+	script = "make"
+}]`,
+		"deploy/deploy.yml": `name: Deploy
+steps:
+- script: make deploy
+`,
+		"deploy/copy.yaml": `name: build
+steps:
+- script: make
+`,
+		"broken.hcl":   `name = `,
+		"README.md":    `# Actions`,
+		".git/foo.yml": `name: Foo`,
+	}
+	for path, content := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), os.FileMode(0755)))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, path), []byte(content), os.FileMode(0644)))
+	}
+
+	// Symbolic links are ignored
+	outside, err := ioutil.TempDir("", "cds-action-source-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(outside)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(outside, "secret.yml"), []byte("name: Secret"), os.FileMode(0644)))
+	assert.NoError(t, os.Symlink(filepath.Join(outside, "secret.yml"), filepath.Join(dir, "secret.yml")))
+	assert.NoError(t, os.Symlink(outside, filepath.Join(dir, "outside")))
+
+	actions, errs := readActions(dir)
+	assert.Equal(t, 2, len(actions))
+	assert.Equal(t, "build.hcl", actions[0].path)
+	assert.Equal(t, "Build", actions[0].action.Name)
+	assert.Equal(t, 64, len(actions[0].hash))
+	assert.Equal(t, "deploy/deploy.yml", actions[1].path)
+	assert.Equal(t, "Deploy", actions[1].action.Name)
+
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, "broken.hcl", errs[0].File)
+	assert.Equal(t, "deploy/copy.yaml", errs[1].File)
+	assert.Equal(t, "action build is already defined in build.hcl", errs[1].Message)
+}
+
+func Test_sourceDir(t *testing.T) {
+	repo, err := ioutil.TempDir("", "cds-action-source-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(repo)
+	root, err := filepath.EvalSymlinks(repo)
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(repo, "actions"), os.FileMode(0755)))
+	assert.NoError(t, os.Symlink(os.TempDir(), filepath.Join(repo, "outside")))
+	assert.NoError(t, os.Symlink(filepath.Join(repo, "actions"), filepath.Join(repo, "inside")))
+
+	dir, err := sourceDir(repo, "")
+	assert.NoError(t, err)
+	assert.Equal(t, root, dir)
+
+	dir, err = sourceDir(repo, "../../actions")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "actions"), dir)
+
+	dir, err = sourceDir(repo, "inside")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "actions"), dir)
+
+	_, err = sourceDir(repo, "outside")
+	assert.Error(t, err)
+
+	_, err = sourceDir(repo, "missing")
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-gorp/gorp"
@@ -19,7 +20,10 @@ func adminTruncateWarningsHandler(w http.ResponseWriter, r *http.Request, db *go
 }
 
 func postAdminMaintenanceHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	var m bool
+	cache.Get("maintenance", &m)
 	cache.SetWithTTL("maintenance", true, -1)
+	c.AuditBefore, c.AuditAfter = fmt.Sprintf("maintenance=%t", m), "maintenance=true"
 	return nil
 }
 
@@ -30,6 +34,9 @@ func getAdminMaintenanceHandler(w http.ResponseWriter, r *http.Request, db *gorp
 }
 
 func deleteAdminMaintenanceHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	var m bool
+	cache.Get("maintenance", &m)
 	cache.Delete("maintenance")
+	c.AuditBefore, c.AuditAfter = fmt.Sprintf("maintenance=%t", m), "maintenance=false"
 	return nil
 }
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/backup"
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/sdk"
+)
+
+// getAdminBackupHandler downloads a backup archive of the instance
+func getAdminBackupHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	withArtifacts := FormBool(r, "artifacts")
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment;filename=\"cds-backup-%s.tar.gz\"", time.Now().Format("20060102-150405")))
+	if err := backup.Write(db, w, withArtifacts); err != nil {
+		return sdk.WrapError(err, "getAdminBackupHandler> Cannot write backup")
+	}
+	return nil
+}
+
+// postAdminRestoreHandler restores a backup archive on an instance without any project
+func postAdminRestoreHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	defer r.Body.Close()
+
+	m, err := backup.Restore(db, r.Body, r.Header.Get(sdk.RestoreCipherKeyHeader))
+	if err != nil {
+		return sdk.WrapError(err, "postAdminRestoreHandler> Cannot restore backup")
+	}
+
+	c.AuditAfter = fmt.Sprintf("version=%s tables=%d objects=%d", m.Version, len(m.Tables), m.Objects)
+	return WriteJSON(w, r, m, http.StatusOK)
+}
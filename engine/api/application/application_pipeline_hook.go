@@ -1,8 +1,6 @@
 package application
 
 import (
-	"regexp"
-
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/pipeline"
@@ -11,8 +9,15 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-// TriggerPipeline linked to received hook
-func TriggerPipeline(tx gorp.SqlExecutor, h sdk.Hook, branch string, hash string, author string, p *sdk.Pipeline, projectData *sdk.Project) (*sdk.PipelineBuild, error) {
+// TriggerPipeline linked to received hook. from is the previous head of the branch, it is empty for the hooks
+// created before it was sent by Stash
+func TriggerPipeline(tx gorp.SqlExecutor, h sdk.Hook, branch string, from string, hash string, author string, p *sdk.Pipeline, projectData *sdk.Project) (*sdk.PipelineBuild, error) {
+	// Check the branch or the tag against the hook filter
+	if !h.Filter.MatchRef(branch) {
+		log.Info("hook> Skipping build of %s/%s for %s: filtered", projectData.Key, p.Name, branch)
+		return nil, nil
+	}
+	branch, tag := sdk.GitRef(branch)
 
 	// Create pipeline args
 	var args []sdk.Parameter
@@ -20,6 +25,12 @@ func TriggerPipeline(tx gorp.SqlExecutor, h sdk.Hook, branch string, hash string
 		Name:  "git.branch",
 		Value: branch,
 	})
+	if tag {
+		args = append(args, sdk.Parameter{
+			Name:  "git.tag",
+			Value: branch,
+		})
+	}
 	args = append(args, sdk.Parameter{
 		Name:  "git.hash",
 		Value: hash,
@@ -71,14 +82,21 @@ func TriggerPipeline(tx gorp.SqlExecutor, h sdk.Hook, branch string, hash string
 				if err != nil {
 					log.Warning("hook> can't get commit %s from %s on %s : %s", hash, a.RepositoryFullname, a.RepositoriesManager.Name, err)
 				}
-				match, err := regexp.Match(".*\\[ci skip\\].*|.*\\[cd skip\\].*", []byte(commit.Message))
-				if err != nil {
-					log.Warning("hook> Cannot check %s/%s for commit %s by %s : %s (%s)", projectData.Key, a.Name, hash, author, commit.Message, err)
-				}
-				if match {
+				if h.Filter.Skip(commit.Message) {
 					log.Info("hook> Skipping build of %s/%s for commit %s by %s", projectData.Key, a.Name, hash, author)
 					return nil, nil
 				}
+
+				// Check the files changed by the push against the hook filter
+				if len(h.Filter.Paths) > 0 {
+					files, err := client.ChangedFiles(a.RepositoryFullname, from, hash)
+					if err != nil {
+						log.Warning("hook> can't get files changed by %s..%s from %s on %s : %s", from, hash, a.RepositoryFullname, a.RepositoriesManager.Name, err)
+					} else if !h.Filter.MatchPaths(files) {
+						log.Info("hook> Skipping build of %s/%s for %s..%s: no change in %v", projectData.Key, a.Name, from, hash, h.Filter.Paths)
+						return nil, nil
+					}
+				}
 			}
 		} else {
 			log.Debug("Application is not attached (%s %s %s)", a.RepositoriesManager.Name, projectData.Key, a.Name)
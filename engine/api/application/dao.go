@@ -11,6 +11,7 @@ import (
 
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/history"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -229,7 +230,20 @@ func UpdateLastModified(db gorp.SqlExecutor, app *sdk.Application, u *sdk.User)
 		}
 	}
 
-	return sdk.WrapError(err, "application.UpdateLastModified %s(%d)", app.Name, app.ID)
+	if err != nil {
+		return sdk.WrapError(err, "application.UpdateLastModified %s(%d)", app.Name, app.ID)
+	}
+	return recordHistory(db, app.ID, u)
+}
+
+// recordHistory stores the current settings of the application as a new version in its history.
+// Variables are not part of the snapshot, they have their own audit.
+func recordHistory(db gorp.SqlExecutor, appID int64, u *sdk.User) error {
+	snapshot, err := LoadByID(db, appID, nil)
+	if err != nil {
+		return sdk.WrapError(err, "application.recordHistory> Unable to load application %d", appID)
+	}
+	return history.Record(db, sdk.HistoryApplication, snapshot.ProjectID, snapshot.ID, snapshot.Name, snapshot, u)
 }
 
 // LoadAll returns all applications
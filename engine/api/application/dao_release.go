@@ -0,0 +1,73 @@
+package application
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertRelease inserts a release and its artifacts. A version can be released only once for an application
+func InsertRelease(db gorp.SqlExecutor, rel *sdk.Release) error {
+	rel.Created = time.Now()
+	dbRel := dbRelease(*rel)
+	if err := db.Insert(&dbRel); err != nil {
+		if errPG, ok := err.(*pq.Error); ok && errPG.Code == "23505" {
+			err = sdk.ErrReleaseAlreadyExists
+		}
+		return sdk.WrapError(err, "InsertRelease> Unable to insert release %s", rel.Version)
+	}
+	rel.ID = dbRel.ID
+
+	for i := range rel.Artifacts {
+		a := &rel.Artifacts[i]
+		a.ReleaseID = rel.ID
+		dbArt := dbReleaseArtifact(*a)
+		if err := db.Insert(&dbArt); err != nil {
+			return sdk.WrapError(err, "InsertRelease> Unable to insert artifact %s", a.Name)
+		}
+		a.ID = dbArt.ID
+	}
+	return nil
+}
+
+// LoadReleases loads all the releases of an application, without their artifacts
+func LoadReleases(db gorp.SqlExecutor, appID int64) ([]sdk.Release, error) {
+	dbRels := []dbRelease{}
+	query := "select * from application_release where application_id = $1 order by created desc"
+	if _, err := db.Select(&dbRels, query, appID); err != nil {
+		return nil, sdk.WrapError(err, "LoadReleases> Unable to load releases")
+	}
+
+	rels := make([]sdk.Release, len(dbRels))
+	for i := range dbRels {
+		rels[i] = sdk.Release(dbRels[i])
+	}
+	return rels, nil
+}
+
+// LoadRelease loads a release of an application and its artifacts
+func LoadRelease(db gorp.SqlExecutor, appID int64, version string) (*sdk.Release, error) {
+	dbRel := dbRelease{}
+	query := "select * from application_release where application_id = $1 and version = $2"
+	if err := db.SelectOne(&dbRel, query, appID, version); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrReleaseNotFound
+		}
+		return nil, sdk.WrapError(err, "LoadRelease> Unable to load release %s", version)
+	}
+	rel := sdk.Release(dbRel)
+
+	dbArts := []dbReleaseArtifact{}
+	if _, err := db.Select(&dbArts, "select * from application_release_artifact where release_id = $1 order by name", rel.ID); err != nil {
+		return nil, sdk.WrapError(err, "LoadRelease> Unable to load artifacts of release %s", version)
+	}
+	rel.Artifacts = make([]sdk.ReleaseArtifact, len(dbArts))
+	for i := range dbArts {
+		rel.Artifacts[i] = sdk.ReleaseArtifact(dbArts[i])
+	}
+	return &rel, nil
+}
@@ -15,10 +15,14 @@ import (
 type dbApplication sdk.Application
 type dbVariable sdk.Variable
 type dbApplicationVariableAudit sdk.ApplicationVariableAudit
+type dbRelease sdk.Release
+type dbReleaseArtifact sdk.ReleaseArtifact
 
 func init() {
 	gorpmapping.Register(gorpmapping.New(dbApplication{}, "application", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbApplicationVariableAudit{}, "application_variable_audit", true, "id"))
+	gorpmapping.Register(gorpmapping.New(dbRelease{}, "application_release", true, "id"))
+	gorpmapping.Register(gorpmapping.New(dbReleaseArtifact{}, "application_release_artifact", true, "id"))
 }
 
 // PostGet is a db hook
@@ -7,6 +7,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/businesscontext"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/group"
@@ -31,6 +32,7 @@ func updateGroupRoleOnApplicationHandler(w http.ResponseWriter, r *http.Request,
 	if errload != nil {
 		return sdk.WrapError(errload, "updateGroupRoleOnApplicationHandler: Cannot load application %s", appName)
 	}
+	c.AuditBefore = audit.Permissions(db, "application", app.ID)
 
 	g, errLoadGroup := group.LoadGroup(db, groupName)
 	if errLoadGroup != nil {
@@ -65,6 +67,7 @@ func updateGroupRoleOnApplicationHandler(w http.ResponseWriter, r *http.Request,
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "updateGroupRoleOnApplicationHandler: Cannot commit transaction")
 	}
+	c.AuditAfter = audit.Permissions(db, "application", app.ID)
 
 	cache.DeleteAll(cache.Key("application", key, "*"+appName+"*"))
 
@@ -111,6 +114,7 @@ func updateGroupsInApplicationHandler(w http.ResponseWriter, r *http.Request, db
 	if errLoadName != nil {
 		return sdk.WrapError(errLoadName, "updateGroupsInApplicationHandler: Cannot load application %s: %s", appName)
 	}
+	c.AuditBefore = audit.Permissions(db, "application", app.ID)
 
 	tx, err := db.Begin()
 	if err != nil {
@@ -137,6 +141,7 @@ func updateGroupsInApplicationHandler(w http.ResponseWriter, r *http.Request, db
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "updateGroupsInApplicationHandler: Cannot commit transaction")
 	}
+	c.AuditAfter = audit.Permissions(db, "application", app.ID)
 
 	cache.DeleteAll(cache.Key("application", key, "*"+appName+"*"))
 	return WriteJSON(w, r, app, http.StatusOK)
@@ -162,6 +167,7 @@ func addGroupInApplicationHandler(w http.ResponseWriter, r *http.Request, db *go
 	if err != nil {
 		return sdk.WrapError(err, "addGroupInApplicationHandler> Cannot load %s", appName)
 	}
+	c.AuditBefore = audit.Permissions(db, "application", app.ID)
 
 	g, err := group.LoadGroup(db, groupPermission.Group.Name)
 	if err != nil {
@@ -185,6 +191,7 @@ func addGroupInApplicationHandler(w http.ResponseWriter, r *http.Request, db *go
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "addGroupInApplicationHandler> Cannot commit transaction")
 	}
+	c.AuditAfter = audit.Permissions(db, "application", app.ID)
 
 	cache.DeleteAll(cache.Key("application", key, "*"+appName+"*"))
 
@@ -206,6 +213,7 @@ func deleteGroupFromApplicationHandler(w http.ResponseWriter, r *http.Request, d
 	if err != nil {
 		return sdk.WrapError(err, "deleteGroupFromApplicationHandler: Cannot load application %s", appName)
 	}
+	c.AuditBefore = audit.Permissions(db, "application", app.ID)
 
 	tx, err := db.Begin()
 	if err != nil {
@@ -224,6 +232,7 @@ func deleteGroupFromApplicationHandler(w http.ResponseWriter, r *http.Request, d
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "deleteGroupFromApplicationHandler: Cannot commit transaction")
 	}
+	c.AuditAfter = audit.Permissions(db, "application", app.ID)
 
 	cache.DeleteAll(cache.Key("application", key, "*"+appName+"*"))
 
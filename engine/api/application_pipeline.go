@@ -290,7 +290,7 @@ func removePipelineFromApplicationHandler(w http.ResponseWriter, r *http.Request
 }
 
 func getUserNotificationTypeHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
-	var types = []sdk.UserNotificationSettingsType{sdk.EmailUserNotification, sdk.JabberUserNotification}
+	var types = []sdk.UserNotificationSettingsType{sdk.EmailUserNotification, sdk.JabberUserNotification, sdk.SlackUserNotification}
 	return WriteJSON(w, r, types, http.StatusOK)
 }
 
@@ -589,3 +589,45 @@ func updateUserNotificationApplicationPipelineHandler(w http.ResponseWriter, r *
 	return WriteJSON(w, r, applicationData, http.StatusOK)
 
 }
+
+// postSlackNotificationTestHandler sends a test message with the saved slack notification settings of the application,
+// pipeline and environment. The webhook is never taken from the request, so that the API cannot be used to post anywhere
+func postSlackNotificationTestHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["key"]
+	appName := vars["permApplicationName"]
+	pipelineName := vars["permPipelineKey"]
+
+	if err := r.ParseForm(); err != nil {
+		return sdk.WrapError(sdk.ErrWrongRequest, "postSlackNotificationTestHandler> Cannot parse form: %s", err)
+	}
+	envName := r.Form.Get("envName")
+
+	app, err := application.LoadByName(db, key, appName, c.User)
+	if err != nil {
+		return sdk.WrapError(err, "postSlackNotificationTestHandler> Cannot load application %s", appName)
+	}
+
+	pip, err := pipeline.LoadPipeline(db, key, pipelineName, false)
+	if err != nil {
+		return sdk.WrapError(err, "postSlackNotificationTestHandler> Cannot load pipeline %s", pipelineName)
+	}
+
+	env := &sdk.DefaultEnv
+	if envName != "" {
+		env, err = environment.LoadEnvironmentByName(db, key, envName)
+		if err != nil {
+			return sdk.WrapError(err, "postSlackNotificationTestHandler> Cannot load environment %s", envName)
+		}
+	}
+
+	if !permission.AccessToEnvironment(env.ID, c.User, permission.PermissionReadWriteExecute) {
+		return sdk.WrapError(sdk.ErrForbidden, "postSlackNotificationTestHandler> Cannot access to environment %s", env.Name)
+	}
+
+	if err := notification.SendSlackTestNotif(db, app.ID, pip.ID, env.ID, c.User); err != nil {
+		return sdk.WrapError(err, "postSlackNotificationTestHandler> Cannot send test notification on %s/%s/%s", appName, pipelineName, env.Name)
+	}
+
+	return WriteJSON(w, r, nil, http.StatusOK)
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/artifact"
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func getApplicationReleasesHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["key"]
+	appName := vars["permApplicationName"]
+
+	app, errA := application.LoadByName(db, key, appName, c.User)
+	if errA != nil {
+		return sdk.WrapError(errA, "getApplicationReleasesHandler> Cannot load application %s", appName)
+	}
+
+	rels, errR := application.LoadReleases(db, app.ID)
+	if errR != nil {
+		return sdk.WrapError(errR, "getApplicationReleasesHandler> Cannot load releases")
+	}
+
+	return WriteJSON(w, r, rels, http.StatusOK)
+}
+
+func getApplicationReleaseHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["key"]
+	appName := vars["permApplicationName"]
+	version := vars["version"]
+
+	app, errA := application.LoadByName(db, key, appName, c.User)
+	if errA != nil {
+		return sdk.WrapError(errA, "getApplicationReleaseHandler> Cannot load application %s", appName)
+	}
+
+	rel, errR := application.LoadRelease(db, app.ID, version)
+	if errR != nil {
+		return sdk.WrapError(errR, "getApplicationReleaseHandler> Cannot load release %s", version)
+	}
+
+	return WriteJSON(w, r, rel, http.StatusOK)
+}
+
+func getApplicationReleaseArtifactHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["key"]
+	appName := vars["permApplicationName"]
+	version := vars["version"]
+	name := vars["name"]
+
+	app, errA := application.LoadByName(db, key, appName, c.User)
+	if errA != nil {
+		return sdk.WrapError(errA, "getApplicationReleaseArtifactHandler> Cannot load application %s", appName)
+	}
+
+	rel, errR := application.LoadRelease(db, app.ID, version)
+	if errR != nil {
+		return sdk.WrapError(errR, "getApplicationReleaseArtifactHandler> Cannot load release %s", version)
+	}
+
+	art, errArt := rel.Artifact(name)
+	if errArt != nil {
+		return errArt
+	}
+
+	w.Header().Add("Content-Type", "application/octet-stream")
+	w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", art.Name))
+
+	if err := artifact.StreamReleaseFile(w, rel, art); err != nil {
+		return sdk.WrapError(err, "getApplicationReleaseArtifactHandler> Cannot stream artifact %s", art.Name)
+	}
+	return nil
+}
+
+// postApplicationReleaseHandler promotes the artifacts of a workflow run to a new release of the application.
+// Only the artifacts of the nodes linked to the application are promoted
+func postApplicationReleaseHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["key"]
+	appName := vars["permApplicationName"]
+
+	var promotion sdk.ReleasePromotion
+	if err := UnmarshalBody(r, &promotion); err != nil {
+		return err
+	}
+	if err := promotion.IsValid(); err != nil {
+		return err
+	}
+
+	app, errA := application.LoadByName(db, key, appName, c.User)
+	if errA != nil {
+		return sdk.WrapError(errA, "postApplicationReleaseHandler> Cannot load application %s", appName)
+	}
+
+	if _, err := application.LoadRelease(db, app.ID, promotion.Version); err == nil {
+		return sdk.WrapError(sdk.ErrReleaseAlreadyExists, "postApplicationReleaseHandler> Release %s already exists", promotion.Version)
+	} else if err != sdk.ErrReleaseNotFound {
+		return sdk.WrapError(err, "postApplicationReleaseHandler> Cannot load release %s", promotion.Version)
+	}
+
+	if _, err := workflow.Load(db, key, promotion.WorkflowName, c.User); err != nil {
+		return sdk.WrapError(err, "postApplicationReleaseHandler> Cannot load workflow %s", promotion.WorkflowName)
+	}
+
+	wr, errW := workflow.LoadRun(db, key, promotion.WorkflowName, promotion.WorkflowRunNumber)
+	if errW != nil {
+		return sdk.WrapError(errW, "postApplicationReleaseHandler> Cannot load workflow run %s #%d", promotion.WorkflowName, promotion.WorkflowRunNumber)
+	}
+
+	arts, errArts := releaseArtifacts(wr, app.ID, promotion.Artifacts)
+	if errArts != nil {
+		return errArts
+	}
+
+	rel := &sdk.Release{
+		ApplicationID:     app.ID,
+		Version:           promotion.Version,
+		WorkflowName:      promotion.WorkflowName,
+		WorkflowRunNumber: promotion.WorkflowRunNumber,
+		Author:            c.User.Username,
+	}
+
+	deleteFiles := func() {
+		for i := range rel.Artifacts {
+			if err := artifact.DeleteReleaseFile(rel, &rel.Artifacts[i]); err != nil {
+				log.Warning("postApplicationReleaseHandler> Cannot delete artifact %s of release %s: %s", rel.Artifacts[i].Name, rel.Version, err)
+			}
+		}
+	}
+
+	for i := range arts {
+		a, err := artifact.PromoteWorkflowFile(rel, &arts[i])
+		if err != nil {
+			deleteFiles()
+			return sdk.WrapError(err, "postApplicationReleaseHandler> Cannot promote artifact %s", arts[i].Name)
+		}
+		rel.Artifacts = append(rel.Artifacts, *a)
+	}
+
+	tx, errT := db.Begin()
+	if errT != nil {
+		deleteFiles()
+		return sdk.WrapError(errT, "postApplicationReleaseHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	if err := application.InsertRelease(tx, rel); err != nil {
+		// Files of an existing release must not be deleted
+		if errors.Cause(err) != sdk.ErrReleaseAlreadyExists {
+			deleteFiles()
+		}
+		return sdk.WrapError(err, "postApplicationReleaseHandler> Cannot insert release %s", rel.Version)
+	}
+
+	if err := tx.Commit(); err != nil {
+		deleteFiles()
+		return sdk.WrapError(err, "postApplicationReleaseHandler> Cannot commit transaction")
+	}
+
+	return WriteJSON(w, r, rel, http.StatusCreated)
+}
+
+// releaseArtifacts returns the artifacts of the last runs of the nodes of a workflow run linked to an application.
+// If names is not empty, only the artifacts with these names are returned
+func releaseArtifacts(wr *sdk.WorkflowRun, appID int64, names []string) ([]sdk.WorkflowNodeRunArtifact, error) {
+	wanted := map[string]bool{}
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	arts := []sdk.WorkflowNodeRunArtifact{}
+	seen := map[string]bool{}
+	for nodeID, runs := range wr.WorkflowNodeRuns {
+		if len(runs) == 0 {
+			continue
+		}
+		n := wr.Workflow.GetNode(nodeID)
+		if n == nil || n.Context == nil || n.Context.ApplicationID != appID {
+			continue
+		}
+
+		sort.Slice(runs, func(i, j int) bool {
+			return runs[i].SubNumber > runs[j].SubNumber
+		})
+
+		for _, a := range runs[0].Artifacts {
+			if len(wanted) > 0 && !wanted[a.Name] {
+				continue
+			}
+			if seen[a.Name] {
+				return nil, sdk.WrapError(sdk.ErrWrongRequest, "releaseArtifacts> Several artifacts are named %s", a.Name)
+			}
+			seen[a.Name] = true
+			arts = append(arts, a)
+		}
+	}
+
+	for _, name := range names {
+		if !seen[name] {
+			return nil, sdk.WrapError(sdk.ErrNotFound, "releaseArtifacts> Artifact %s not found in workflow run %d", name, wr.Number)
+		}
+	}
+	if len(arts) == 0 {
+		return nil, sdk.WrapError(sdk.ErrReleaseNoArtifact, "releaseArtifacts> No artifact found in workflow run %d", wr.Number)
+	}
+	return arts, nil
+}
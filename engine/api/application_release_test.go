@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_releaseArtifacts(t *testing.T) {
+	wr := &sdk.WorkflowRun{
+		Number: 1,
+		Workflow: sdk.Workflow{
+			Root: &sdk.WorkflowNode{
+				ID:      1,
+				Context: &sdk.WorkflowNodeContext{ApplicationID: 10},
+				Triggers: []sdk.WorkflowNodeTrigger{
+					{WorkflowDestNode: sdk.WorkflowNode{ID: 2, Context: &sdk.WorkflowNodeContext{ApplicationID: 20}}},
+				},
+			},
+		},
+		WorkflowNodeRuns: map[int64][]sdk.WorkflowNodeRun{
+			1: {
+				{SubNumber: 0, Artifacts: []sdk.WorkflowNodeRunArtifact{{Name: "old.tar.gz"}}},
+				{SubNumber: 1, Artifacts: []sdk.WorkflowNodeRunArtifact{{Name: "app.tar.gz"}, {Name: "doc.zip"}}},
+			},
+			2: {
+				{SubNumber: 0, Artifacts: []sdk.WorkflowNodeRunArtifact{{Name: "other.tar.gz"}}},
+			},
+		},
+	}
+
+	arts, err := releaseArtifacts(wr, 10, nil)
+	assert.NoError(t, err)
+	assert.Len(t, arts, 2)
+	assert.Equal(t, "app.tar.gz", arts[0].Name)
+
+	arts, err = releaseArtifacts(wr, 10, []string{"doc.zip"})
+	assert.NoError(t, err)
+	assert.Len(t, arts, 1)
+	assert.Equal(t, "doc.zip", arts[0].Name)
+
+	_, err = releaseArtifacts(wr, 10, []string{"other.tar.gz"})
+	assert.Equal(t, sdk.ErrNotFound, errors.Cause(err))
+
+	_, err = releaseArtifacts(wr, 30, nil)
+	assert.Equal(t, sdk.ErrReleaseNoArtifact, errors.Cause(err))
+}
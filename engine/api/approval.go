@@ -0,0 +1,114 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/approval"
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/sdk"
+)
+
+func getApprovalsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+
+	p, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "getApprovalsHandler> Cannot load project")
+	}
+
+	approvals, errA := approval.LoadAll(db, p.ID, r.FormValue("status"))
+	if errA != nil {
+		return sdk.WrapError(errA, "getApprovalsHandler> Cannot load approvals")
+	}
+
+	return WriteJSON(w, r, approvals, http.StatusOK)
+}
+
+func getApprovalHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+
+	id, errID := strconv.ParseInt(vars["id"], 10, 64)
+	if errID != nil {
+		return sdk.ErrWrongRequest
+	}
+
+	p, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "getApprovalHandler> Cannot load project")
+	}
+
+	a, errA := approval.LoadByID(db, p.ID, id)
+	if errA != nil {
+		return sdk.WrapError(errA, "getApprovalHandler> Cannot load approval %d", id)
+	}
+
+	return WriteJSON(w, r, a, http.StatusOK)
+}
+
+func postApprovalVoteHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+
+	id, errID := strconv.ParseInt(vars["id"], 10, 64)
+	if errID != nil {
+		return sdk.ErrWrongRequest
+	}
+
+	var vote sdk.ApprovalVote
+	if err := UnmarshalBody(r, &vote); err != nil {
+		return err
+	}
+
+	a, err := voteApproval(db, c.User, key, id, vote)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(w, r, a, http.StatusOK)
+}
+
+func voteApproval(db *gorp.DbMap, u *sdk.User, key string, id int64, vote sdk.ApprovalVote) (*sdk.Approval, error) {
+	p, errP := project.Load(db, key, u)
+	if errP != nil {
+		return nil, sdk.WrapError(errP, "voteApproval> Cannot load project")
+	}
+
+	tx, errB := db.Begin()
+	if errB != nil {
+		return nil, sdk.WrapError(errB, "voteApproval> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	// Lock the approval, so that concurrent votes are applied one after the other
+	a, errA := approval.LoadAndLockByID(tx, p.ID, id)
+	if errA != nil {
+		return nil, sdk.WrapError(errA, "voteApproval> Cannot load approval %d", id)
+	}
+
+	// A timed out approval cannot be voted anymore
+	if _, err := approval.Check(tx, a); err != nil {
+		return nil, sdk.WrapError(err, "voteApproval> Cannot check approval %d", id)
+	}
+
+	if err := a.Vote(u, vote.Approved, vote.Comment, time.Now()); err != nil {
+		return nil, sdk.WrapError(err, "voteApproval> %s cannot vote on approval %d", u.Username, id)
+	}
+
+	if err := approval.Update(tx, a); err != nil {
+		return nil, sdk.WrapError(err, "voteApproval> Cannot update approval %d", id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, sdk.WrapError(err, "voteApproval> Cannot commit transaction")
+	}
+
+	return a, nil
+}
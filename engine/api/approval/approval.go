@@ -0,0 +1,177 @@
+package approval
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/mail"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Insert inserts a waiting approval. The approver groups are notified by the Notifier once the transaction is committed
+func Insert(db gorp.SqlExecutor, a *sdk.Approval) error {
+	a.Notified = false
+	dba := dbApproval(*a)
+	if err := db.Insert(&dba); err != nil {
+		return sdk.WrapError(err, "approval.Insert> Unable to insert approval")
+	}
+	*a = sdk.Approval(dba)
+	return nil
+}
+
+// Update updates the status and the votes of an approval
+func Update(db gorp.SqlExecutor, a *sdk.Approval) error {
+	dba := dbApproval(*a)
+	if _, err := db.Update(&dba); err != nil {
+		return sdk.WrapError(err, "approval.Update> Unable to update approval %d", a.ID)
+	}
+	return nil
+}
+
+// LoadByID loads an approval of a project
+func LoadByID(db gorp.SqlExecutor, projectID, id int64) (*sdk.Approval, error) {
+	return load(db, "select * from approval where project_id = $1 and id = $2", projectID, id)
+}
+
+// LoadAndLockByID loads an approval of a project and locks it until the end of the transaction
+func LoadAndLockByID(db gorp.SqlExecutor, projectID, id int64) (*sdk.Approval, error) {
+	return load(db, "select * from approval where project_id = $1 and id = $2 for update", projectID, id)
+}
+
+// LoadByPipelineBuildID loads the approval of a pipeline build, nil if it has none
+func LoadByPipelineBuildID(db gorp.SqlExecutor, id int64) (*sdk.Approval, error) {
+	a, err := load(db, "select * from approval where pipeline_build_id = $1", id)
+	if err == sdk.ErrApprovalNotFound {
+		return nil, nil
+	}
+	return a, err
+}
+
+// LoadByWorkflowNodeRunID loads the approval of a workflow node run, nil if it has none
+func LoadByWorkflowNodeRunID(db gorp.SqlExecutor, id int64) (*sdk.Approval, error) {
+	a, err := load(db, "select * from approval where workflow_node_run_id = $1", id)
+	if err == sdk.ErrApprovalNotFound {
+		return nil, nil
+	}
+	return a, err
+}
+
+// LoadAll loads the approvals of a project, filtered on the status if not empty
+func LoadAll(db gorp.SqlExecutor, projectID int64, status string) ([]sdk.Approval, error) {
+	var res []dbApproval
+	query := "select * from approval where project_id = $1 and ($2 = '' or status = $2) order by created desc"
+	if _, err := db.Select(&res, query, projectID, status); err != nil {
+		return nil, sdk.WrapError(err, "approval.LoadAll> Unable to load approvals")
+	}
+	approvals := make([]sdk.Approval, len(res))
+	for i := range res {
+		approvals[i] = sdk.Approval(res[i])
+	}
+	return approvals, nil
+}
+
+// Check fails the approval if it is past its timeout, and returns its status
+func Check(db gorp.SqlExecutor, a *sdk.Approval) (sdk.Status, error) {
+	if a.Expire(time.Now()) {
+		log.Info("approval.Check> approval %d of %s has timed out", a.ID, a.Description)
+		if err := Update(db, a); err != nil {
+			return sdk.StatusUnknown, err
+		}
+	}
+	return sdk.StatusFromString(a.Status), nil
+}
+
+func load(db gorp.SqlExecutor, query string, args ...interface{}) (*sdk.Approval, error) {
+	var dba dbApproval
+	if err := db.SelectOne(&dba, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrApprovalNotFound
+		}
+		return nil, sdk.WrapError(err, "approval.load> Unable to load approval")
+	}
+	a := sdk.Approval(dba)
+	return &a, nil
+}
+
+func loadApproverEmails(db gorp.SqlExecutor, groups []string) ([]string, error) {
+	query := `SELECT DISTINCT "user".data::json->>'email' FROM "user"
+		JOIN group_user ON group_user.user_id = "user".id
+		JOIN "group" ON "group".id = group_user.group_id
+		WHERE "group".name = ANY(string_to_array($1, ','))`
+	rows, err := db.Query(query, strings.Join(groups, ","))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var emails []string
+	for rows.Next() {
+		var email sql.NullString
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		if email.Valid && email.String != "" {
+			emails = append(emails, email.String)
+		}
+	}
+	return emails, nil
+}
+
+// Notifier notifies the approver groups of the new approvals. Approvals are inserted in the transaction running
+// the pipeline build or the workflow node run, so approvers are only notified once this transaction is committed
+func Notifier(c context.Context, DBFunc func() *gorp.DbMap) {
+	tick := time.NewTicker(5 * time.Second)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-c.Done():
+			log.Error("Exiting approval.Notifier: %v", c.Err())
+			return
+		case <-tick.C:
+			if err := notifyApprovers(DBFunc()); err != nil {
+				log.Warning("approval.Notifier> %s", err)
+			}
+		}
+	}
+}
+
+func notifyApprovers(db *gorp.DbMap) error {
+	var ids []int64
+	if _, err := db.Select(&ids, "select id from approval where notified = false"); err != nil {
+		return sdk.WrapError(err, "approval.notifyApprovers> Unable to load approvals to notify")
+	}
+
+	for _, id := range ids {
+		// Each approval is notified once, even with several API instances
+		res, err := db.Exec("update approval set notified = true where id = $1 and notified = false", id)
+		if err != nil {
+			return sdk.WrapError(err, "approval.notifyApprovers> Unable to update approval %d", id)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			continue
+		}
+
+		var dba dbApproval
+		if err := db.SelectOne(&dba, "select * from approval where id = $1", id); err != nil {
+			return sdk.WrapError(err, "approval.notifyApprovers> Unable to load approval %d", id)
+		}
+		var projectKey string
+		if err := db.QueryRow("select projectkey from project where id = $1", dba.ProjectID).Scan(&projectKey); err != nil {
+			return sdk.WrapError(err, "approval.notifyApprovers> Unable to load project of approval %d", id)
+		}
+		emails, err := loadApproverEmails(db, dba.Gate.Groups)
+		if err != nil {
+			return sdk.WrapError(err, "approval.notifyApprovers> Unable to load approvers of approval %d", id)
+		}
+		if err := mail.SendMailApprovalRequest(emails, dba.Description, projectKey, id); err != nil {
+			log.Warning("approval.notifyApprovers> Unable to notify approvers of approval %d: %s", id, err)
+		}
+	}
+	return nil
+}
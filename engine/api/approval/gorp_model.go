@@ -0,0 +1,61 @@
+package approval
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbApproval sdk.Approval
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbApproval{}, "approval", true, "id"))
+}
+
+// PostInsert is a db hook
+func (a *dbApproval) PostInsert(db gorp.SqlExecutor) error {
+	return a.PostUpdate(db)
+}
+
+// PostUpdate is a db hook
+func (a *dbApproval) PostUpdate(db gorp.SqlExecutor) error {
+	gate, err := json.Marshal(a.Gate)
+	if err != nil {
+		return err
+	}
+	votes, err := json.Marshal(a.Votes)
+	if err != nil {
+		return err
+	}
+
+	query := "update approval set gate = $2, votes = $3 where id = $1"
+	if _, err := db.Exec(query, a.ID, gate, votes); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PostGet is a db hook
+func (a *dbApproval) PostGet(db gorp.SqlExecutor) error {
+	var gate, votes sql.NullString
+	query := "select gate, votes from approval where id = $1"
+	if err := db.QueryRow(query, a.ID).Scan(&gate, &votes); err != nil {
+		return err
+	}
+
+	if gate.Valid {
+		if err := json.Unmarshal([]byte(gate.String), &a.Gate); err != nil {
+			return err
+		}
+	}
+	if votes.Valid {
+		if err := json.Unmarshal([]byte(votes.String), &a.Votes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
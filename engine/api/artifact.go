@@ -38,7 +38,7 @@ func uploadArtifactHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 	m := r.MultipartForm
 	envName := m.Value["env"][0]
 
-	var sizeStr, permStr, md5sum string
+	var sizeStr, permStr, md5sum, sha256sum string
 	if len(m.Value["size"]) > 0 {
 		sizeStr = m.Value["size"][0]
 	}
@@ -48,6 +48,9 @@ func uploadArtifactHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 	if len(m.Value["md5sum"]) > 0 {
 		md5sum = m.Value["md5sum"][0]
 	}
+	if len(m.Value["sha256sum"]) > 0 {
+		sha256sum = m.Value["sha256sum"][0]
+	}
 
 	if fileName == "" {
 		return sdk.WrapError(sdk.ErrWrongRequest, "uploadArtifactHandler> %s header is not set", sdk.ArtifactFileName)
@@ -111,6 +114,7 @@ func uploadArtifactHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 		Size:         size,
 		Perm:         uint32(perm),
 		MD5sum:       md5sum,
+		SHA256Sum:    sha256sum,
 	}
 
 	files := m.File[fileName]
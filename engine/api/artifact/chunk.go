@@ -0,0 +1,167 @@
+package artifact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	// ChunkSize is the size of the chunks of an artifact upload
+	ChunkSize int64 = 32 * 1024 * 1024
+	// uploadTTL is the time (in seconds) during which an upload can be resumed
+	uploadTTL = 24 * 60 * 60
+)
+
+// chunk is a part of an artifact being uploaded, stored next to the artifact
+type chunk struct {
+	art   *sdk.WorkflowNodeRunArtifact
+	ref   string
+	index int
+}
+
+func (c *chunk) GetName() string {
+	return fmt.Sprintf("%s.%s.part%d", c.art.Name, c.ref, c.index)
+}
+
+func (c *chunk) GetPath() string {
+	return c.art.GetPath()
+}
+
+func keyUpload(ref string) string {
+	return cache.Key("artifact", "upload", ref)
+}
+
+// keyChunk is set once a chunk is received. Each chunk has its own key, so that concurrent uploads of chunks do
+// not overwrite each other
+func keyChunk(ref string, index int) string {
+	return cache.Key("artifact", "upload", ref, "chunk", strconv.Itoa(index))
+}
+
+// loadReceivedChunks sets the chunks of an upload already received
+func loadReceivedChunks(up *sdk.ArtifactChunkedUpload) {
+	up.Received = []int{}
+	for i := 0; i < up.NbChunks(); i++ {
+		var received bool
+		if cache.Get(keyChunk(up.Ref, i), &received) && received {
+			up.Received = append(up.Received, i)
+		}
+	}
+}
+
+// uploadRef computes the reference of an upload. The same file uploaded for the same job gets the same reference
+// so that the upload can be resumed
+func uploadRef(jobID int64, up *sdk.ArtifactChunkedUpload) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d/%s/%s/%s/%d", jobID, up.Tag, up.Name, up.SHA256Sum, up.Size)
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// StartWorkflowUpload starts, or resumes, a chunked upload
+func StartWorkflowUpload(jobID int64, up *sdk.ArtifactChunkedUpload) {
+	up.Ref = uploadRef(jobID, up)
+	up.JobID = jobID
+
+	var existing sdk.ArtifactChunkedUpload
+	if cache.Get(keyUpload(up.Ref), &existing) {
+		up.ChunkSize = existing.ChunkSize
+		loadReceivedChunks(up)
+		return
+	}
+
+	up.ChunkSize = ChunkSize
+	up.Received = []int{}
+	cache.SetWithTTL(keyUpload(up.Ref), up, uploadTTL)
+}
+
+// LoadWorkflowUpload loads a chunked upload started by a job
+func LoadWorkflowUpload(jobID int64, ref string) (*sdk.ArtifactChunkedUpload, error) {
+	var up sdk.ArtifactChunkedUpload
+	if !cache.Get(keyUpload(ref), &up) {
+		return nil, sdk.ErrArtifactUploadNotFound
+	}
+	if up.JobID != jobID {
+		return nil, sdk.WrapError(sdk.ErrArtifactUploadNotFound, "LoadWorkflowUpload> Upload %s has not been started by job %d", ref, jobID)
+	}
+	loadReceivedChunks(&up)
+	return &up, nil
+}
+
+// SaveWorkflowFileChunk stores a chunk of an artifact. The sha256 sum of the chunk is checked
+func SaveWorkflowFileChunk(art *sdk.WorkflowNodeRunArtifact, up *sdk.ArtifactChunkedUpload, index int, sha256sum string, content io.ReadCloser) error {
+	if index < 0 || index >= up.NbChunks() {
+		return sdk.WrapError(sdk.ErrWrongRequest, "SaveWorkflowFileChunk> Invalid chunk %d", index)
+	}
+
+	c := &chunk{art: art, ref: up.Ref, index: index}
+	h := sha256.New()
+	if _, err := objectstore.StoreArtifact(c, hashReadCloser(content, h)); err != nil {
+		return sdk.WrapError(err, "SaveWorkflowFileChunk> Cannot store chunk %d", index)
+	}
+	if err := sdk.CheckSHA256(sha256sum, h.Sum(nil)); err != nil {
+		_ = objectstore.DeleteArtifact(c)
+		return sdk.WrapError(err, "SaveWorkflowFileChunk> Invalid chunk %d", index)
+	}
+
+	cache.SetWithTTL(keyChunk(up.Ref, index), true, uploadTTL)
+	if !up.IsReceived(index) {
+		up.Received = append(up.Received, index)
+		sort.Ints(up.Received)
+	}
+	return nil
+}
+
+// AssembleWorkflowFile concatenates the chunks of an upload and stores the artifact. Chunks are deleted,
+// even if the sha256 sum of the artifact does not match
+func AssembleWorkflowFile(art *sdk.WorkflowNodeRunArtifact, up *sdk.ArtifactChunkedUpload) error {
+	nbChunks := up.NbChunks()
+	for i := 0; i < nbChunks; i++ {
+		if !up.IsReceived(i) {
+			return sdk.WrapError(sdk.ErrArtifactUploadIncomplete, "AssembleWorkflowFile> Chunk %d is missing", i)
+		}
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		for i := 0; i < nbChunks; i++ {
+			f, err := objectstore.FetchArtifact(&chunk{art: art, ref: up.Ref, index: i})
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			_, err = io.Copy(pw, f)
+			f.Close()
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.Close()
+	}()
+
+	err := SaveWorkflowFile(art, pr)
+	// Drain the pipe if the store has stopped reading
+	io.Copy(ioutil.Discard, pr)
+
+	for i := 0; i < nbChunks; i++ {
+		if errD := objectstore.DeleteArtifact(&chunk{art: art, ref: up.Ref, index: i}); errD != nil {
+			log.Warning("AssembleWorkflowFile> Unable to delete chunk %d: %s", i, errD)
+		}
+		cache.Delete(keyChunk(up.Ref, i))
+	}
+	cache.Delete(keyUpload(up.Ref))
+
+	if err != nil {
+		return sdk.WrapError(err, "AssembleWorkflowFile> Cannot save artifact %s", art.Name)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package artifact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+)
+
+// releaseFile is an artifact of a release. Release artifacts are stored apart from the workflow artifacts
+// so that they are not removed with the workflow runs
+type releaseFile struct {
+	rel *sdk.Release
+	art *sdk.ReleaseArtifact
+}
+
+func (f *releaseFile) GetName() string {
+	return f.art.Name
+}
+
+func (f *releaseFile) GetPath() string {
+	container := fmt.Sprintf("release-%d-%s", f.rel.ApplicationID, f.rel.Version)
+	container = url.QueryEscape(container)
+	return strings.Replace(container, "/", "-", -1)
+}
+
+// PromoteWorkflowFile copies a workflow artifact to a release. The sha256 sum of the copy is checked
+func PromoteWorkflowFile(rel *sdk.Release, src *sdk.WorkflowNodeRunArtifact) (*sdk.ReleaseArtifact, error) {
+	content, err := objectstore.FetchArtifact(src)
+	if err != nil {
+		return nil, sdk.WrapError(err, "PromoteWorkflowFile> Cannot fetch artifact %s", src.Name)
+	}
+
+	art := &sdk.ReleaseArtifact{
+		Name:      src.Name,
+		Size:      src.Size,
+		Perm:      src.Perm,
+		MD5sum:    src.MD5sum,
+		SHA256Sum: src.SHA256Sum,
+	}
+	f := &releaseFile{rel: rel, art: art}
+
+	h := sha256.New()
+	objectPath, err := objectstore.StoreArtifact(f, hashReadCloser(content, h))
+	content.Close()
+	if err != nil {
+		return nil, sdk.WrapError(err, "PromoteWorkflowFile> Cannot store artifact %s", src.Name)
+	}
+	if err := sdk.CheckSHA256(art.SHA256Sum, h.Sum(nil)); err != nil {
+		_ = objectstore.DeleteArtifact(f)
+		return nil, sdk.WrapError(err, "PromoteWorkflowFile> Invalid artifact %s", src.Name)
+	}
+	art.SHA256Sum = hex.EncodeToString(h.Sum(nil))
+	art.ObjectPath = objectPath
+	return art, nil
+}
+
+// DeleteReleaseFile removes an artifact of a release from the object store
+func DeleteReleaseFile(rel *sdk.Release, art *sdk.ReleaseArtifact) error {
+	return objectstore.DeleteArtifact(&releaseFile{rel: rel, art: art})
+}
+
+// StreamReleaseFile streams an artifact of a release
+func StreamReleaseFile(w io.Writer, rel *sdk.Release, art *sdk.ReleaseArtifact) error {
+	return StreamFile(w, &releaseFile{rel: rel, art: art})
+}
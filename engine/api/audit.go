@@ -2,10 +2,16 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -14,6 +20,9 @@ const (
 	delay      = 1
 )
 
+// auditEventsRetention is the delay after which the audit events are deleted, 0 to keep them
+var auditEventsRetention time.Duration
+
 func auditCleanerRoutine(c context.Context, DBFunc func() *gorp.DbMap) {
 	tick := time.NewTicker(delay * time.Minute).C
 
@@ -31,6 +40,11 @@ func auditCleanerRoutine(c context.Context, DBFunc func() *gorp.DbMap) {
 				if err != nil {
 					log.Warning("AuditCleanerRoutine> Action clean failed: %s", err)
 				}
+				if auditEventsRetention > 0 {
+					if _, err := audit.DeleteEventsBefore(db, time.Now().Add(-auditEventsRetention)); err != nil {
+						log.Warning("AuditCleanerRoutine> Audit events clean failed: %s", err)
+					}
+				}
 			}
 		}
 	}
@@ -84,3 +98,74 @@ func actionAuditCleaner(db *gorp.DbMap) error {
 
 	return nil
 }
+
+// auditFilter reads the filter of the audit events from the query of a request
+func auditFilter(r *http.Request) (audit.Filter, error) {
+	f := audit.Filter{
+		Username:   r.FormValue("username"),
+		TargetType: r.FormValue("target_type"),
+		Target:     r.FormValue("target"),
+		Route:      r.FormValue("route"),
+		Method:     r.FormValue("method"),
+	}
+
+	for name, t := range map[string]*time.Time{"since": &f.Since, "until": &f.Until} {
+		if v := r.FormValue(name); v != "" {
+			var err error
+			if *t, err = time.Parse(time.RFC3339, v); err != nil {
+				return f, sdk.WrapError(sdk.ErrWrongRequest, "auditFilter> %s is not a RFC3339 date: %s", name, v)
+			}
+		}
+	}
+
+	for name, i := range map[string]*int{"limit": &f.Limit, "offset": &f.Offset} {
+		if v := r.FormValue(name); v != "" {
+			var err error
+			if *i, err = strconv.Atoi(v); err != nil || *i < 0 {
+				return f, sdk.WrapError(sdk.ErrWrongRequest, "auditFilter> %s is not a positive integer: %s", name, v)
+			}
+		}
+	}
+	return f, nil
+}
+
+func getAdminAuditHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	f, err := auditFilter(r)
+	if err != nil {
+		return err
+	}
+	if f.Limit == 0 {
+		f.Limit = 50
+	}
+
+	events, err := audit.LoadEvents(db, f)
+	if err != nil {
+		return sdk.WrapError(err, "getAdminAuditHandler> Cannot load audit events")
+	}
+	return WriteJSON(w, r, events, http.StatusOK)
+}
+
+// getAdminAuditExportHandler exports the audit events as JSON lines, one event per line
+func getAdminAuditExportHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	f, err := auditFilter(r)
+	if err != nil {
+		return err
+	}
+
+	events, err := audit.LoadEvents(db, f)
+	if err != nil {
+		return sdk.WrapError(err, "getAdminAuditExportHandler> Cannot load audit events")
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", "attachment;filename=\"audit.jsonl\"")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+	for i := range events {
+		if err := enc.Encode(events[i]); err != nil {
+			log.Warning("getAdminAuditExportHandler> Cannot write audit event %d: %s", events[i].ID, err)
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/sdk"
+)
+
+var forwardEvents bool
+
+// Init sets if the audit events are also published as CDS events, and the proxies trusted to give the address of
+// the clients. The proxies are IP addresses or CIDR ranges.
+func Init(forward bool, proxies []string) {
+	forwardEvents = forward
+	trustedProxies = parseProxies(proxies)
+}
+
+// Filter selects the audit events to load. Target and Route match a part of the target and of the route of the
+// events, and a zero Since or Until is ignored.
+type Filter struct {
+	Username   string
+	TargetType string
+	Target     string
+	Route      string
+	Method     string
+	Since      time.Time
+	Until      time.Time
+	Limit      int
+	Offset     int
+}
+
+// Insert records an audit event, and publishes it if the audit events are forwarded
+func Insert(db gorp.SqlExecutor, e *sdk.AuditEvent) error {
+	dbE := dbEvent(*e)
+	if err := db.Insert(&dbE); err != nil {
+		return sdk.WrapError(err, "Insert> Cannot insert audit event")
+	}
+	*e = sdk.AuditEvent(dbE)
+
+	if forwardEvents {
+		event.Publish(*e)
+	}
+	return nil
+}
+
+// LoadEvents loads the audit events matching the filter, the last ones first
+func LoadEvents(db gorp.SqlExecutor, f Filter) ([]sdk.AuditEvent, error) {
+	clauses := []string{}
+	args := []interface{}{}
+	where := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		clauses = append(clauses, fmt.Sprintf(clause, len(args)))
+	}
+
+	if f.Username != "" {
+		where("username = $%d", f.Username)
+	}
+	if f.TargetType != "" {
+		where("target_type = $%d", f.TargetType)
+	}
+	if f.Target != "" {
+		where("target ILIKE $%d", "%"+f.Target+"%")
+	}
+	if f.Route != "" {
+		where("route ILIKE $%d", "%"+f.Route+"%")
+	}
+	if f.Method != "" {
+		where("method = $%d", strings.ToUpper(f.Method))
+	}
+	if !f.Since.IsZero() {
+		where("created >= $%d", f.Since)
+	}
+	if !f.Until.IsZero() {
+		where("created < $%d", f.Until)
+	}
+
+	query := `SELECT * FROM audit_event`
+	if len(clauses) > 0 {
+		query += " WHERE " + strings.Join(clauses, " AND ")
+	}
+	query += " ORDER BY created DESC, id DESC"
+	if f.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", f.Limit)
+	}
+	if f.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET %d", f.Offset)
+	}
+
+	var dbEvents []dbEvent
+	if _, err := db.Select(&dbEvents, query, args...); err != nil {
+		return nil, sdk.WrapError(err, "LoadEvents> Cannot load audit events")
+	}
+
+	events := make([]sdk.AuditEvent, len(dbEvents))
+	for i := range dbEvents {
+		events[i] = sdk.AuditEvent(dbEvents[i])
+	}
+	return events, nil
+}
+
+// DeleteEventsBefore deletes the audit events older than the given date
+func DeleteEventsBefore(db gorp.SqlExecutor, t time.Time) (int64, error) {
+	res, err := db.Exec(`DELETE FROM audit_event WHERE created < $1`, t)
+	if err != nil {
+		return 0, sdk.WrapError(err, "DeleteEventsBefore> Cannot delete audit events")
+	}
+	return res.RowsAffected()
+}
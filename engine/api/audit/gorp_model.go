@@ -0,0 +1,12 @@
+package audit
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbEvent sdk.AuditEvent
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbEvent{}, "audit_event", true, "id"))
+}
@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Permissions summarizes the groups of a project, an application, a pipeline or an environment with their role,
+// e.g. "devs:7,ops:4". It is used as the before and after summary of the permission changes, so an error is
+// logged and does not fail the call.
+func Permissions(db gorp.SqlExecutor, objectType string, objectID int64) string {
+	switch objectType {
+	case "project", "application", "pipeline", "environment":
+	default:
+		log.Warning("audit.Permissions> Unknown object type %s", objectType)
+		return ""
+	}
+
+	query := fmt.Sprintf(`SELECT "group".name, %[1]s_group.role FROM "group"
+		JOIN %[1]s_group ON %[1]s_group.group_id = "group".id
+		WHERE %[1]s_group.%[1]s_id = $1 ORDER BY "group".name ASC`, objectType)
+	rows, err := db.Query(query, objectID)
+	if err != nil {
+		log.Warning("audit.Permissions> Cannot load groups of %s %d: %s", objectType, objectID, err)
+		return ""
+	}
+	defer rows.Close()
+
+	perms := []string{}
+	for rows.Next() {
+		var name string
+		var role int
+		if err := rows.Scan(&name, &role); err != nil {
+			log.Warning("audit.Permissions> Cannot load groups of %s %d: %s", objectType, objectID, err)
+			return ""
+		}
+		perms = append(perms, fmt.Sprintf("%s:%d", name, role))
+	}
+	return strings.Join(perms, ",")
+}
@@ -0,0 +1,105 @@
+package audit
+
+import (
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+var routeVariable = regexp.MustCompile(`\{([^}]+)\}`)
+
+// TargetType returns the type of the target of a route, which is the first element of the route
+func TargetType(route string) string {
+	route = strings.TrimPrefix(route, "/")
+	if i := strings.Index(route, "/"); i >= 0 {
+		route = route[:i]
+	}
+	if strings.HasPrefix(route, "{") {
+		return ""
+	}
+	return route
+}
+
+// Target returns the values of the variables of a route, in the order of the route, joined by slashes
+func Target(route string, vars map[string]string) string {
+	values := []string{}
+	for _, m := range routeVariable.FindAllStringSubmatch(route, -1) {
+		name := m[1]
+		if i := strings.Index(name, ":"); i >= 0 {
+			name = name[:i]
+		}
+		if v, ok := vars[name]; ok {
+			values = append(values, v)
+		}
+	}
+	return strings.Join(values, "/")
+}
+
+// trustedProxies are the proxies whose X-Forwarded-For header is trusted
+var trustedProxies []*net.IPNet
+
+// parseProxies parses a list of IP addresses and CIDR ranges, invalid entries are ignored
+func parseProxies(proxies []string) []*net.IPNet {
+	nets := []*net.IPNet{}
+	for _, p := range proxies {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !strings.Contains(p, "/") {
+			if ip := net.ParseIP(p); ip != nil && ip.To4() != nil {
+				p += "/32"
+			} else {
+				p += "/128"
+			}
+		}
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			log.Warning("audit.parseProxies> Invalid trusted proxy %s: %s", p, err)
+			continue
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
+
+func isTrustedProxy(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// SourceIP returns the address of the client of a request. The X-Forwarded-For header is only read if the request
+// comes from a trusted proxy: the client is the last address of the header which is not a trusted proxy.
+func SourceIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !isTrustedProxy(host) {
+		return host
+	}
+
+	fwd := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(fwd) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(fwd[i])
+		if addr == "" {
+			continue
+		}
+		host = addr
+		if !isTrustedProxy(addr) {
+			break
+		}
+	}
+	return host
+}
@@ -0,0 +1,49 @@
+package audit
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetType(t *testing.T) {
+	assert.Equal(t, "project", TargetType("/project/{permProjectKey}/group/{group}"))
+	assert.Equal(t, "admin", TargetType("/admin/maintenance"))
+	assert.Equal(t, "worker", TargetType("/worker"))
+	assert.Equal(t, "", TargetType("/{key}"))
+}
+
+func TestTarget(t *testing.T) {
+	route := "/project/{permProjectKey}/application/{permApplicationName}/group/{group}"
+	vars := map[string]string{
+		"group":               "devs",
+		"permProjectKey":      "KEY",
+		"permApplicationName": "app",
+	}
+	assert.Equal(t, "KEY/app/devs", Target(route, vars))
+	assert.Equal(t, "", Target("/admin/maintenance", map[string]string{}))
+	assert.Equal(t, "42", Target("/worker/model/{permModelID:[0-9]+}", map[string]string{"permModelID": "42"}))
+}
+
+func TestSourceIP(t *testing.T) {
+	trustedProxies = parseProxies([]string{"10.0.0.1", "10.1.0.0/16", "invalid"})
+	defer func() { trustedProxies = nil }()
+	assert.Len(t, trustedProxies, 2)
+
+	r, _ := http.NewRequest("POST", "/admin/maintenance", nil)
+	r.RemoteAddr = "10.0.0.1:45678"
+	assert.Equal(t, "10.0.0.1", SourceIP(r))
+
+	r.Header.Set("X-Forwarded-For", "192.168.1.12, 10.1.0.2")
+	assert.Equal(t, "192.168.1.12", SourceIP(r))
+
+	// The client cannot forge the addresses before the proxies
+	r.Header.Set("X-Forwarded-For", "1.2.3.4, 192.168.1.12, 10.1.0.2")
+	assert.Equal(t, "192.168.1.12", SourceIP(r))
+
+	// Only the trusted proxies can give the address of the client
+	r.RemoteAddr = "192.168.1.12:45678"
+	r.Header.Set("X-Forwarded-For", "1.2.3.4")
+	assert.Equal(t, "192.168.1.12", SourceIP(r))
+}
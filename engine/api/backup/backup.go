@@ -0,0 +1,272 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/actionplugin"
+	"github.com/ovh/cds/engine/api/artifact"
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+)
+
+const (
+	manifestFile = "manifest.json"
+	tablesDir    = "tables"
+	objectsDir   = "objects"
+)
+
+// object is a file of the object store saved in the backups
+type object struct {
+	path string
+	name string
+}
+
+func (o *object) GetPath() string {
+	return o.path
+}
+
+func (o *object) GetName() string {
+	return o.name
+}
+
+// Write writes a backup archive of the instance: the tables of the database, read in a single transaction so that
+// the backup is consistent, and the binaries of the plugins and of the templates. The secrets are saved encrypted.
+// The artifacts are saved only if withArtifacts is true.
+func Write(db *gorp.DbMap, w io.Writer, withArtifacts bool) error {
+	tmpDir, err := ioutil.TempDir("", "cds-backup")
+	if err != nil {
+		return sdk.WrapError(err, "Write> Cannot create temporary directory")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	m, objects, err := dump(db, tmpDir, withArtifacts)
+	if err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	btes, err := json.Marshal(m)
+	if err != nil {
+		return sdk.WrapError(err, "Write> Cannot marshal manifest")
+	}
+	if err := writeEntry(tw, manifestFile, int64(len(btes)), m.Created); err != nil {
+		return err
+	}
+	if _, err := tw.Write(btes); err != nil {
+		return sdk.WrapError(err, "Write> Cannot write manifest")
+	}
+
+	for _, t := range m.Tables {
+		if err := writeFile(tw, path.Join(tablesDir, t.Name+".jsonl"), filepath.Join(tmpDir, t.Name), m.Created); err != nil {
+			return err
+		}
+	}
+
+	for i := range objects {
+		if err := writeObject(tw, tmpDir, &objects[i], m.Created); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return sdk.WrapError(err, "Write> Cannot close archive")
+	}
+	if err := gw.Close(); err != nil {
+		return sdk.WrapError(err, "Write> Cannot close archive")
+	}
+	return nil
+}
+
+// dump writes the rows of the saved tables as JSON lines in a directory, and lists the objects to save
+func dump(db *gorp.DbMap, dir string, withArtifacts bool) (*sdk.BackupManifest, []object, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "dump> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	// All the tables are read from the same snapshot of the database
+	if _, err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY"); err != nil {
+		return nil, nil, sdk.WrapError(err, "dump> Cannot set transaction isolation level")
+	}
+
+	migration, err := lastMigration(tx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m := &sdk.BackupManifest{
+		Version:   sdk.VERSION,
+		Migration: migration,
+		Created:   time.Now(),
+		Artifacts: withArtifacts,
+	}
+
+	for _, t := range tables {
+		if t.artifacts && !withArtifacts {
+			continue
+		}
+		n, err := dumpTable(tx, t.name, filepath.Join(dir, t.name))
+		if err != nil {
+			return nil, nil, err
+		}
+		m.Tables = append(m.Tables, sdk.BackupTable{Name: t.name, Rows: n})
+	}
+
+	objects, err := listObjects(tx, withArtifacts)
+	if err != nil {
+		return nil, nil, err
+	}
+	m.Objects = len(objects)
+
+	return m, objects, tx.Commit()
+}
+
+// lastMigration returns the last migration applied on the database
+func lastMigration(db gorp.SqlExecutor) (string, error) {
+	migration, err := db.SelectStr("SELECT id FROM gorp_migrations ORDER BY id DESC LIMIT 1")
+	if err != nil {
+		return "", sdk.WrapError(err, "lastMigration> Cannot load the migrations of the database")
+	}
+	return migration, nil
+}
+
+// dumpTable writes the rows of a table in a file, one JSON object per line
+func dumpTable(db gorp.SqlExecutor, name, filename string) (int, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return 0, sdk.WrapError(err, "dumpTable> Cannot create file for table %s", name)
+	}
+	defer f.Close()
+
+	rows, err := db.Query(fmt.Sprintf(`SELECT row_to_json(t) FROM "%s" t`, name))
+	if err != nil {
+		return 0, sdk.WrapError(err, "dumpTable> Cannot read table %s", name)
+	}
+	defer rows.Close()
+
+	var n int
+	for rows.Next() {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return n, sdk.WrapError(err, "dumpTable> Cannot read table %s", name)
+		}
+		if _, err := f.Write(append(row, '\n')); err != nil {
+			return n, sdk.WrapError(err, "dumpTable> Cannot write table %s", name)
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return n, sdk.WrapError(err, "dumpTable> Cannot read table %s", name)
+	}
+	return n, nil
+}
+
+// listObjects lists the binaries of the plugins and of the templates, and the artifacts if withArtifacts is true
+func listObjects(db gorp.SqlExecutor, withArtifacts bool) ([]object, error) {
+	objects := []object{}
+
+	plugins, err := actionplugin.LoadAll(db)
+	if err != nil {
+		return nil, sdk.WrapError(err, "listObjects> Cannot load plugins")
+	}
+	for i := range plugins {
+		objects = append(objects, object{path: plugins[i].GetPath(), name: plugins[i].GetName()})
+	}
+
+	var templates []string
+	if _, err := db.Select(&templates, `SELECT name FROM template WHERE object_path <> ''`); err != nil && err != sql.ErrNoRows {
+		return nil, sdk.WrapError(err, "listObjects> Cannot load templates")
+	}
+	for _, name := range templates {
+		tmpl := sdk.TemplateExtension{Name: name}
+		objects = append(objects, object{path: tmpl.GetPath(), name: tmpl.GetName()})
+	}
+
+	if !withArtifacts {
+		return objects, nil
+	}
+
+	var ids []int64
+	if _, err := db.Select(&ids, `SELECT id FROM artifact ORDER BY id`); err != nil && err != sql.ErrNoRows {
+		return nil, sdk.WrapError(err, "listObjects> Cannot load artifacts")
+	}
+	for _, id := range ids {
+		a, err := artifact.LoadArtifact(db, id)
+		if err != nil {
+			return nil, sdk.WrapError(err, "listObjects> Cannot load artifact %d", id)
+		}
+		objects = append(objects, object{path: a.GetPath(), name: a.GetName()})
+	}
+	return objects, nil
+}
+
+// writeObject fetches an object from the object store, and writes it in the archive
+func writeObject(tw *tar.Writer, tmpDir string, o *object, modTime time.Time) error {
+	r, err := objectstore.FetchArtifact(o)
+	if err != nil {
+		return sdk.WrapError(err, "writeObject> Cannot fetch %s/%s", o.path, o.name)
+	}
+	defer r.Close()
+
+	// The size of the object is needed before writing it in the archive
+	f, err := ioutil.TempFile(tmpDir, "object")
+	if err != nil {
+		return sdk.WrapError(err, "writeObject> Cannot create temporary file")
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return sdk.WrapError(err, "writeObject> Cannot fetch %s/%s", o.path, o.name)
+	}
+	return writeFile(tw, path.Join(objectsDir, o.path, o.name), f.Name(), modTime)
+}
+
+// writeFile writes a file in the archive
+func writeFile(tw *tar.Writer, name, filename string, modTime time.Time) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return sdk.WrapError(err, "writeFile> Cannot open %s", filename)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return sdk.WrapError(err, "writeFile> Cannot stat %s", filename)
+	}
+	if err := writeEntry(tw, name, fi.Size(), modTime); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return sdk.WrapError(err, "writeFile> Cannot write %s", name)
+	}
+	return nil
+}
+
+func writeEntry(tw *tar.Writer, name string, size int64, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    size,
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return sdk.WrapError(err, "writeEntry> Cannot write %s", name)
+	}
+	return nil
+}
@@ -0,0 +1,405 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// lateValue is the value of a late column of a restored row
+type lateValue struct {
+	table  string
+	column string
+	id     json.RawMessage
+	value  json.RawMessage
+}
+
+// Restore restores a backup archive. The instance must not have any project, and its database must have the same
+// migrations as the database the backup has been made on. The restored tables replace the content of the instance.
+// If previousCipherKey is set, the secrets are re-encrypted with the cipher key of the instance.
+func Restore(db *gorp.DbMap, r io.Reader, previousCipherKey string) (*sdk.BackupManifest, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, sdk.WrapError(sdk.ErrInvalidBackup, "Restore> Cannot read archive: %s", err)
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != manifestFile {
+		return nil, sdk.WrapError(sdk.ErrInvalidBackup, "Restore> The archive does not start with a manifest")
+	}
+	var m sdk.BackupManifest
+	if err := json.NewDecoder(tr).Decode(&m); err != nil {
+		return nil, sdk.WrapError(sdk.ErrInvalidBackup, "Restore> Cannot read manifest: %s", err)
+	}
+
+	migration, err := lastMigration(db)
+	if err != nil {
+		return nil, err
+	}
+	if migration != m.Migration {
+		return nil, sdk.WrapError(sdk.ErrBackupVersionMismatch, "Restore> The backup has been made at migration %s, the database is at migration %s", m.Migration, migration)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, sdk.WrapError(err, "Restore> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	if err := prepare(tx); err != nil {
+		return nil, err
+	}
+
+	// The tables come first in the archive, in the order they are restored, then the objects
+	lates := []lateValue{}
+	last := -1
+	for {
+		hdr, err = tr.Next()
+		if err == io.EOF || (err == nil && !strings.HasPrefix(hdr.Name, tablesDir+"/")) {
+			break
+		}
+		if err != nil {
+			return nil, sdk.WrapError(sdk.ErrInvalidBackup, "Restore> Cannot read archive: %s", err)
+		}
+
+		name := strings.TrimSuffix(strings.TrimPrefix(hdr.Name, tablesDir+"/"), ".jsonl")
+		i := lookupTable(name)
+		if i <= last {
+			return nil, sdk.WrapError(sdk.ErrInvalidBackup, "Restore> Unexpected table %s", name)
+		}
+		last = i
+
+		l, err := restoreTable(tx, tables[i], tr)
+		if err != nil {
+			return nil, err
+		}
+		lates = append(lates, l...)
+	}
+
+	if err := restoreLates(tx, lates); err != nil {
+		return nil, err
+	}
+	if previousCipherKey != "" {
+		if err := reencrypt(tx, previousCipherKey); err != nil {
+			return nil, err
+		}
+	}
+	if err := resetSequences(tx); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, sdk.WrapError(err, "Restore> Cannot commit transaction")
+	}
+
+	// The objects are stored once the database is restored
+	for hdr != nil && err == nil {
+		if !strings.HasPrefix(hdr.Name, objectsDir+"/") {
+			return nil, sdk.WrapError(sdk.ErrInvalidBackup, "Restore> Unexpected file %s", hdr.Name)
+		}
+		dir, name := path.Split(strings.TrimPrefix(hdr.Name, objectsDir+"/"))
+		o := &object{path: strings.TrimSuffix(dir, "/"), name: name}
+		if _, err := objectstore.StoreArtifact(o, ioutil.NopCloser(tr)); err != nil {
+			return nil, sdk.WrapError(err, "Restore> Cannot store %s/%s", o.path, o.name)
+		}
+		hdr, err = tr.Next()
+	}
+	if err != nil && err != io.EOF {
+		return nil, sdk.WrapError(sdk.ErrInvalidBackup, "Restore> Cannot read archive: %s", err)
+	}
+
+	return &m, nil
+}
+
+// prepare checks that the instance does not have any project, and empties the restored tables
+func prepare(db gorp.SqlExecutor) error {
+	n, err := db.SelectInt("SELECT COUNT(1) FROM project")
+	if err != nil {
+		return sdk.WrapError(err, "prepare> Cannot count projects")
+	}
+	if n > 0 {
+		return sdk.ErrRestoreNotFresh
+	}
+
+	names := make([]string, len(tables))
+	for i := range tables {
+		names[i] = fmt.Sprintf(`"%s"`, tables[i].name)
+	}
+	if _, err := db.Exec(fmt.Sprintf("TRUNCATE %s CASCADE", strings.Join(names, ", "))); err != nil {
+		return sdk.WrapError(err, "prepare> Cannot empty tables")
+	}
+	return nil
+}
+
+// restoreTable inserts the rows of a table, read as JSON lines. The late columns are inserted as NULL, and their
+// values are returned.
+func restoreTable(db gorp.SqlExecutor, t table, r io.Reader) ([]lateValue, error) {
+	lates := []lateValue{}
+	query := fmt.Sprintf(`INSERT INTO "%[1]s" SELECT * FROM json_populate_record(NULL::"%[1]s", $1)`, t.name)
+
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, sdk.WrapError(sdk.ErrInvalidBackup, "restoreTable> Cannot read table %s: %s", t.name, err)
+		}
+		if len(strings.TrimSpace(string(line))) > 0 {
+			row := line
+			if len(t.late) > 0 {
+				var l []lateValue
+				if row, l, err = extractLates(t, line); err != nil {
+					return nil, err
+				}
+				lates = append(lates, l...)
+			}
+			if _, err := db.Exec(query, string(row)); err != nil {
+				return nil, sdk.WrapError(err, "restoreTable> Cannot insert in table %s", t.name)
+			}
+		}
+		if err == io.EOF {
+			return lates, nil
+		}
+	}
+}
+
+// extractLates removes the late columns of a row, and returns their values
+func extractLates(t table, line []byte) ([]byte, []lateValue, error) {
+	var row map[string]json.RawMessage
+	if err := json.Unmarshal(line, &row); err != nil {
+		return nil, nil, sdk.WrapError(sdk.ErrInvalidBackup, "extractLates> Invalid row in table %s: %s", t.name, err)
+	}
+
+	lates := []lateValue{}
+	for _, c := range t.late {
+		if v, ok := row[c]; ok && string(v) != "null" {
+			lates = append(lates, lateValue{table: t.name, column: c, id: row["id"], value: v})
+		}
+		delete(row, c)
+	}
+
+	btes, err := json.Marshal(row)
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "extractLates> Cannot marshal row of table %s", t.name)
+	}
+	return btes, lates, nil
+}
+
+// restoreLates sets the late columns of the restored rows
+func restoreLates(db gorp.SqlExecutor, lates []lateValue) error {
+	for _, l := range lates {
+		var id, value int64
+		if err := json.Unmarshal(l.id, &id); err != nil {
+			return sdk.WrapError(sdk.ErrInvalidBackup, "restoreLates> Invalid id in table %s: %s", l.table, err)
+		}
+		if err := json.Unmarshal(l.value, &value); err != nil {
+			return sdk.WrapError(sdk.ErrInvalidBackup, "restoreLates> Invalid %s in table %s: %s", l.column, l.table, err)
+		}
+		query := fmt.Sprintf(`UPDATE "%s" SET %s = $1 WHERE id = $2`, l.table, l.column)
+		if _, err := db.Exec(query, value, id); err != nil {
+			return sdk.WrapError(err, "restoreLates> Cannot update %s of table %s", l.column, l.table)
+		}
+	}
+	return nil
+}
+
+// secretValue is an encrypted value of a restored row
+type secretValue struct {
+	id    int64
+	value interface{}
+}
+
+// reencrypt deciphers the secrets with the cipher key of the instance the backup has been made on, and ciphers them
+// with the cipher key of the instance
+func reencrypt(db gorp.SqlExecutor, previousCipherKey string) error {
+	for _, t := range tables {
+		for _, c := range t.secrets {
+			rows, err := db.Query(fmt.Sprintf(`SELECT id, %s FROM "%s" WHERE %s IS NOT NULL`, c, t.name, c))
+			if err != nil {
+				return sdk.WrapError(err, "reencrypt> Cannot load %s of table %s", c, t.name)
+			}
+			values := []secretValue{}
+			for rows.Next() {
+				var v secretValue
+				if err := rows.Scan(&v.id, &v.value); err != nil {
+					rows.Close()
+					return sdk.WrapError(err, "reencrypt> Cannot load %s of table %s", c, t.name)
+				}
+				values = append(values, v)
+			}
+			rows.Close()
+
+			query := fmt.Sprintf(`UPDATE "%s" SET %s = $1 WHERE id = $2`, t.name, c)
+			for _, v := range values {
+				// The text columns are read as strings and the binary columns as bytes, they are written back the same way
+				var value interface{}
+				switch data := v.value.(type) {
+				case []byte:
+					value, err = secret.Reencrypt(previousCipherKey, data)
+				case string:
+					var btes []byte
+					btes, err = secret.Reencrypt(previousCipherKey, []byte(data))
+					value = string(btes)
+				default:
+					continue
+				}
+				if err != nil {
+					return sdk.WrapError(err, "reencrypt> Cannot re-encrypt %s %d of table %s", c, v.id, t.name)
+				}
+				if _, err := db.Exec(query, value, v.id); err != nil {
+					return sdk.WrapError(err, "reencrypt> Cannot update %s of table %s", c, t.name)
+				}
+			}
+			log.Debug("reencrypt> %d values of %s.%s re-encrypted", len(values), t.name, c)
+		}
+	}
+	return reencryptNotifications(db, previousCipherKey)
+}
+
+// reencryptNotifications re-encrypts the slack webhook urls stored in the JSON settings of the notifications. The
+// settings of application_pipeline_notif are keyed by notification type, the ones of workflow_notification are the
+// settings of the notification type.
+func reencryptNotifications(db gorp.SqlExecutor, previousCipherKey string) error {
+	type appNotif struct {
+		appPipelineID int64
+		envID         int64
+		settings      string
+	}
+	type wfNotif struct {
+		id       int64
+		settings string
+	}
+	rows, err := db.Query(`SELECT application_pipeline_id, environment_id, settings FROM application_pipeline_notif WHERE settings IS NOT NULL`)
+	if err != nil {
+		return sdk.WrapError(err, "reencryptNotifications> Cannot load application_pipeline_notif settings")
+	}
+	appNotifs := []appNotif{}
+	for rows.Next() {
+		var n appNotif
+		if err := rows.Scan(&n.appPipelineID, &n.envID, &n.settings); err != nil {
+			rows.Close()
+			return sdk.WrapError(err, "reencryptNotifications> Cannot load application_pipeline_notif settings")
+		}
+		appNotifs = append(appNotifs, n)
+	}
+	rows.Close()
+
+	for _, n := range appNotifs {
+		settings, err := reencryptTypedSettings(previousCipherKey, []byte(n.settings))
+		if err != nil {
+			return sdk.WrapError(err, "reencryptNotifications> Cannot re-encrypt settings of application_pipeline %d", n.appPipelineID)
+		}
+		query := `UPDATE application_pipeline_notif SET settings = $1 WHERE application_pipeline_id = $2 AND environment_id = $3`
+		if _, err := db.Exec(query, string(settings), n.appPipelineID, n.envID); err != nil {
+			return sdk.WrapError(err, "reencryptNotifications> Cannot update settings of application_pipeline %d", n.appPipelineID)
+		}
+	}
+	log.Debug("reencryptNotifications> %d settings of application_pipeline_notif re-encrypted", len(appNotifs))
+
+	rows, err = db.Query(`SELECT id, settings FROM workflow_notification WHERE type = $1 AND settings IS NOT NULL`, string(sdk.SlackUserNotification))
+	if err != nil {
+		return sdk.WrapError(err, "reencryptNotifications> Cannot load workflow_notification settings")
+	}
+	wfNotifs := []wfNotif{}
+	for rows.Next() {
+		var n wfNotif
+		if err := rows.Scan(&n.id, &n.settings); err != nil {
+			rows.Close()
+			return sdk.WrapError(err, "reencryptNotifications> Cannot load workflow_notification settings")
+		}
+		wfNotifs = append(wfNotifs, n)
+	}
+	rows.Close()
+
+	for _, n := range wfNotifs {
+		settings, err := reencryptSlackSettings(previousCipherKey, []byte(n.settings))
+		if err != nil {
+			return sdk.WrapError(err, "reencryptNotifications> Cannot re-encrypt settings of workflow_notification %d", n.id)
+		}
+		if _, err := db.Exec(`UPDATE workflow_notification SET settings = $1 WHERE id = $2`, string(settings), n.id); err != nil {
+			return sdk.WrapError(err, "reencryptNotifications> Cannot update settings of workflow_notification %d", n.id)
+		}
+	}
+	log.Debug("reencryptNotifications> %d settings of workflow_notification re-encrypted", len(wfNotifs))
+	return nil
+}
+
+// reencryptTypedSettings re-encrypts the webhook url of the slack settings of notification settings keyed by type
+func reencryptTypedSettings(previousCipherKey string, settings []byte) ([]byte, error) {
+	var typed map[string]json.RawMessage
+	if err := json.Unmarshal(settings, &typed); err != nil {
+		return nil, sdk.WrapError(sdk.ErrInvalidBackup, "reencryptTypedSettings> Invalid settings: %s", err)
+	}
+	slack, ok := typed[string(sdk.SlackUserNotification)]
+	if !ok || string(slack) == "null" {
+		return settings, nil
+	}
+	reencrypted, err := reencryptSlackSettings(previousCipherKey, slack)
+	if err != nil {
+		return nil, err
+	}
+	typed[string(sdk.SlackUserNotification)] = reencrypted
+	return json.Marshal(typed)
+}
+
+// reencryptSlackSettings re-encrypts the webhook url of slack settings. The webhook url is stored as base64 of its
+// cipher, the urls stored before they were encrypted are not valid base64 and are kept as they are.
+func reencryptSlackSettings(previousCipherKey string, settings []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(settings, &fields); err != nil {
+		return nil, sdk.WrapError(sdk.ErrInvalidBackup, "reencryptSlackSettings> Invalid settings: %s", err)
+	}
+	var webhookURL string
+	if v, ok := fields["webhook_url"]; ok {
+		if err := json.Unmarshal(v, &webhookURL); err != nil {
+			return nil, sdk.WrapError(sdk.ErrInvalidBackup, "reencryptSlackSettings> Invalid webhook url: %s", err)
+		}
+	}
+	cipher, err := base64.StdEncoding.DecodeString(webhookURL)
+	if webhookURL == "" || err != nil {
+		return settings, nil
+	}
+
+	cipher, err = secret.Reencrypt(previousCipherKey, cipher)
+	if err != nil {
+		return nil, err
+	}
+	btes, err := json.Marshal(base64.StdEncoding.EncodeToString(cipher))
+	if err != nil {
+		return nil, err
+	}
+	fields["webhook_url"] = btes
+	return json.Marshal(fields)
+}
+
+// resetSequences sets the sequences of the restored tables after their greatest value
+func resetSequences(db gorp.SqlExecutor) error {
+	for _, t := range tables {
+		var columns []string
+		query := `SELECT column_name FROM information_schema.columns WHERE table_name = $1 AND column_default LIKE 'nextval%'`
+		if _, err := db.Select(&columns, query, t.name); err != nil {
+			return sdk.WrapError(err, "resetSequences> Cannot load sequences of table %s", t.name)
+		}
+		for _, c := range columns {
+			query := fmt.Sprintf(`SELECT setval(pg_get_serial_sequence('"%[1]s"', '%[2]s'), COALESCE(MAX(%[2]s), 0) + 1, false) FROM "%[1]s"`, t.name, c)
+			if _, err := db.Exec(query); err != nil {
+				return sdk.WrapError(err, "resetSequences> Cannot reset sequence of %s.%s", t.name, c)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package backup
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestReencryptNotificationSettings(t *testing.T) {
+	previousKey := "78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf"
+	secret.Init(previousKey)
+	cipher, err := secret.Encrypt([]byte("https://hooks.slack.com/services/T/B/X"))
+	assert.NoError(t, err)
+	webhookURL := base64.StdEncoding.EncodeToString(cipher)
+	secret.Init("9fIkZ04mPq2xLcVb7RtYu1NsWe3GhJaD")
+
+	// decrypt returns the webhook url of slack settings deciphered with the cipher key of the instance
+	decrypt := func(settings []byte) string {
+		var sn sdk.SlackUserNotificationSettings
+		assert.NoError(t, json.Unmarshal(settings, &sn))
+		cipher, err := base64.StdEncoding.DecodeString(sn.WebhookURL)
+		if err != nil {
+			return sn.WebhookURL
+		}
+		clear, err := secret.Decrypt(cipher)
+		assert.NoError(t, err)
+		return string(clear)
+	}
+
+	// Settings of a workflow notification
+	settings, err := reencryptSlackSettings(previousKey, []byte(`{"on_success": "never", "webhook_url": "`+webhookURL+`"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://hooks.slack.com/services/T/B/X", decrypt(settings))
+	assert.Contains(t, string(settings), `"on_success":"never"`)
+
+	// The urls stored before they were encrypted are kept as they are
+	settings, err = reencryptSlackSettings(previousKey, []byte(`{"webhook_url": "https://hooks.slack.com/services/T/B/Y"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://hooks.slack.com/services/T/B/Y", decrypt(settings))
+
+	// Settings of an application pipeline notification, keyed by type
+	settings, err = reencryptTypedSettings(previousKey, []byte(`{"email": {"on_success": "always"}, "slack": {"webhook_url": "`+webhookURL+`"}}`))
+	assert.NoError(t, err)
+	var typed map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(settings, &typed))
+	assert.Equal(t, "https://hooks.slack.com/services/T/B/X", decrypt(typed["slack"]))
+	assert.JSONEq(t, `{"on_success": "always"}`, string(typed["email"]))
+
+	// Settings without slack notification are kept as they are
+	settings, err = reencryptTypedSettings(previousKey, []byte(`{"email": {"on_success": "always"}}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"email": {"on_success": "always"}}`, string(settings))
+
+	_, err = reencryptTypedSettings(previousKey, []byte(`not json`))
+	assert.Error(t, err)
+}
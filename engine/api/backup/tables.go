@@ -0,0 +1,89 @@
+package backup
+
+// table is a table of the database saved in the backups
+type table struct {
+	name string
+	// late are the columns referencing rows of tables restored after this one. They are restored once all the
+	// tables are restored.
+	late []string
+	// secrets are the columns encrypted with the cipher key of the instance
+	secrets []string
+	// artifacts is true for the tables saved only with the artifacts
+	artifacts bool
+}
+
+// tables are the tables saved in the backups, in the order they are restored. The history of the builds and of the
+// workflow runs, the sessions and the audits are not saved.
+var tables = []table{
+	{name: "group"},
+	{name: "user"},
+	{name: "group_user"},
+	{name: "token"},
+	{name: "chatops_account"},
+	{name: "repositories_manager"},
+	{name: "project"},
+	{name: "project_group"},
+	{name: "project_variable", secrets: []string{"cipher_value"}},
+	{name: "project_key", secrets: []string{"private"}},
+	{name: "repositories_manager_project"},
+	{name: "action"},
+	{name: "object_history"},
+	{name: "action_parameter"},
+	{name: "action_requirement"},
+	{name: "action_edge"},
+	{name: "action_edge_parameter"},
+	{name: "action_version_deprecated"},
+	{name: "action_source"},
+	{name: "action_source_action"},
+	{name: "plugin"},
+	{name: "template"},
+	{name: "template_params"},
+	{name: "template_action"},
+	{name: "worker_model"},
+	{name: "worker_capability"},
+	{name: "application"},
+	{name: "application_group"},
+	{name: "application_variable", secrets: []string{"cipher_value"}},
+	{name: "application_key", secrets: []string{"private"}},
+	{name: "environment"},
+	{name: "environment_group"},
+	{name: "environment_variable", secrets: []string{"cipher_value"}},
+	{name: "environment_key", secrets: []string{"private"}},
+	{name: "environment_exclusive_application"},
+	{name: "pipeline"},
+	{name: "pipeline_group"},
+	{name: "pipeline_parameter"},
+	{name: "pipeline_stage"},
+	{name: "pipeline_stage_prerequisite"},
+	{name: "pipeline_action"},
+	{name: "application_pipeline"},
+	{name: "application_pipeline_notif"},
+	{name: "pipeline_trigger"},
+	{name: "pipeline_trigger_parameter"},
+	{name: "pipeline_trigger_prerequisite"},
+	{name: "pipeline_scheduler"},
+	{name: "poller"},
+	{name: "hook"},
+	{name: "workflow", late: []string{"root_node_id"}},
+	{name: "workflow_node"},
+	{name: "workflow_node_context"},
+	{name: "workflow_node_join"},
+	{name: "workflow_node_trigger"},
+	{name: "workflow_node_join_source"},
+	{name: "workflow_node_join_trigger"},
+	{name: "workflow_hook_model"},
+	{name: "workflow_node_hook"},
+	{name: "workflow_notification"},
+	{name: "workflow_notification_source"},
+	{name: "artifact", artifacts: true},
+}
+
+// lookupTable returns the index of a saved table, -1 if the table is not saved in the backups
+func lookupTable(name string) int {
+	for i := range tables {
+		if tables[i].name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,86 @@
+package backup
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	createForeignKey = regexp.MustCompile(`create_foreign_key(?:_idx_cascade)?\('[^']*',\s*'([^']*)',\s*'([^']*)',\s*'([^']*)'`)
+	alterForeignKey  = regexp.MustCompile(`(?i)ALTER TABLE "?(\w+)"? ADD CONSTRAINT \w+ FOREIGN KEY\s*\((\w+)\) REFERENCES "?(\w+)"?`)
+)
+
+// deferred are the foreign keys checked at the end of the transaction
+var deferred = map[string]bool{
+	"workflow_node.workflow_trigger_src_id":      true,
+	"workflow_node.workflow_trigger_join_src_id": true,
+}
+
+// historyReferences are the columns referencing a version of the object_history table, without a foreign key
+var historyReferences = map[string]string{
+	"action_edge":               "child_version",
+	"action_version_deprecated": "version",
+}
+
+// foreignKeys returns the child table, the child column and the parent table of the foreign keys created in a migration
+func foreignKeys(sql string) [][3]string {
+	fks := [][3]string{}
+	for _, m := range createForeignKey.FindAllStringSubmatch(sql, -1) {
+		fks = append(fks, [3]string{m[1], m[3], m[2]})
+	}
+	for _, m := range alterForeignKey.FindAllStringSubmatch(sql, -1) {
+		fks = append(fks, [3]string{m[1], m[2], m[3]})
+	}
+	return fks
+}
+
+func TestTablesOrder(t *testing.T) {
+	files, err := filepath.Glob("../../sql/*.sql")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	for _, f := range files {
+		btes, err := ioutil.ReadFile(f)
+		assert.NoError(t, err)
+
+		for _, fk := range foreignKeys(string(btes)) {
+			child, column, parent := fk[0], fk[1], fk[2]
+			c, p := lookupTable(child), lookupTable(parent)
+			if c == -1 || p == -1 || c == p || deferred[child+"."+column] {
+				continue
+			}
+			if p > c {
+				var late bool
+				for _, l := range tables[c].late {
+					late = late || l == column
+				}
+				assert.True(t, late, "%s: %s.%s references %s, which is restored after it", filepath.Base(f), child, column, parent)
+			}
+		}
+	}
+}
+
+func TestTablesHistory(t *testing.T) {
+	h := lookupTable("object_history")
+	for table, column := range historyReferences {
+		c := lookupTable(table)
+		if c == -1 {
+			continue
+		}
+		assert.NotEqual(t, -1, h, "%s.%s references a version of object_history, which is not saved", table, column)
+		assert.True(t, h < c, "%s.%s references a version of object_history, which is restored after it", table, column)
+	}
+}
+
+func TestTablesSaved(t *testing.T) {
+	names := map[string]bool{}
+	for _, t := range tables {
+		names[t.name] = true
+	}
+	assert.Len(t, names, len(tables), "a table is saved twice")
+	assert.Equal(t, -1, lookupTable("workflow_run"))
+}
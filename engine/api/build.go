@@ -17,6 +17,7 @@ import (
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/queue"
 	"github.com/ovh/cds/engine/api/stats"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
@@ -345,6 +346,14 @@ func takePipelineBuildJobHandler(w http.ResponseWriter, r *http.Request, db *gor
 		workerModel = wm.Name
 	}
 
+	projects, errP := pipeline.LoadPipelineBuildJobProjectIDs(tx, []int64{id})
+	if errP != nil {
+		return sdk.WrapError(errP, "takePipelineBuildJobHandler> Cannot load project of job %d", id)
+	}
+	if err := checkConcurrencyQuotas(tx, projects[id], id); err != nil {
+		return sdk.WrapError(err, "takePipelineBuildJobHandler> Cannot take job %d", id)
+	}
+
 	infos := []sdk.SpawnInfo{{
 		RemoteTime: takeForm.Time,
 		Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobTaken.ID, Args: []interface{}{c.Worker.Name}},
@@ -394,9 +403,27 @@ func bookPipelineBuildJobHandler(w http.ResponseWriter, r *http.Request, db *gor
 		return sdk.WrapError(errc, "bookPipelineBuildJobHandler> invalid id")
 	}
 
+	tx, errBegin := db.Begin()
+	if errBegin != nil {
+		return sdk.WrapError(errBegin, "bookPipelineBuildJobHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	projects, errP := pipeline.LoadPipelineBuildJobProjectIDs(tx, []int64{id})
+	if errP != nil {
+		return sdk.WrapError(errP, "bookPipelineBuildJobHandler> Cannot load project of job %d", id)
+	}
+	if err := checkConcurrencyQuotas(tx, projects[id], id); err != nil {
+		return sdk.WrapError(err, "bookPipelineBuildJobHandler> Cannot book job %d", id)
+	}
+
 	if _, err := pipeline.BookPipelineBuildJob(id, c.Hatchery); err != nil {
 		return sdk.WrapError(err, "bookPipelineBuildJobHandler> job already booked")
 	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "bookPipelineBuildJobHandler> Cannot commit transaction")
+	}
 	return WriteJSON(w, r, nil, http.StatusOK)
 }
 
@@ -495,27 +522,65 @@ func loadActionBuildSecrets(db *gorp.DbMap, pbJobID int64) ([]sdk.Variable, erro
 }
 
 func getQueueHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
-	var queue []sdk.PipelineBuildJob
+	var jobs []sdk.PipelineBuildJob
 	var errQ error
 	switch c.Agent {
 	case sdk.HatcheryAgent:
-		queue, errQ = pipeline.LoadGroupWaitingQueue(db, c.Hatchery.GroupID)
+		jobs, errQ = pipeline.LoadGroupWaitingQueue(db, c.Hatchery.GroupID)
 	case sdk.WorkerAgent:
-		queue, errQ = pipeline.LoadGroupWaitingQueue(db, c.Worker.GroupID)
+		jobs, errQ = pipeline.LoadGroupWaitingQueue(db, c.Worker.GroupID)
 	default:
-		queue, errQ = pipeline.LoadUserWaitingQueue(db, c.User)
+		jobs, errQ = pipeline.LoadUserWaitingQueue(db, c.User)
 	}
 
-	lang := r.Header.Get("Accept-Language")
-	for p := range queue {
-		queue[p].Translate(lang)
+	if errQ != nil {
+		return sdk.WrapError(errQ, "getQueueHandler> Cannot load queue from db: %s", errQ)
 	}
 
+	// Hatcheries and workers only get jobs within concurrency quotas
+	applyQuotas := c.Agent == sdk.HatcheryAgent || c.Agent == sdk.WorkerAgent
+	jobs, errQ = sortPipelineBuildJobs(db, jobs, applyQuotas)
 	if errQ != nil {
-		return sdk.WrapError(errQ, "getQueueHandler> Cannot load queue from db: %s", errQ)
+		return sdk.WrapError(errQ, "getQueueHandler> Cannot sort queue")
 	}
 
-	return WriteJSON(w, r, queue, http.StatusOK)
+	lang := r.Header.Get("Accept-Language")
+	for p := range jobs {
+		jobs[p].Translate(lang)
+	}
+
+	return WriteJSON(w, r, jobs, http.StatusOK)
+}
+
+// sortPipelineBuildJobs orders pipeline jobs in queue, see orderQueue
+func sortPipelineBuildJobs(db gorp.SqlExecutor, jobs []sdk.PipelineBuildJob, applyQuotas bool) ([]sdk.PipelineBuildJob, error) {
+	ids := make([]int64, len(jobs))
+	byID := make(map[int64]sdk.PipelineBuildJob, len(jobs))
+	for i, j := range jobs {
+		ids[i] = j.ID
+		byID[j.ID] = j
+	}
+
+	projects, err := pipeline.LoadPipelineBuildJobProjectIDs(db, ids)
+	if err != nil {
+		return nil, sdk.WrapError(err, "sortPipelineBuildJobs> Unable to load projects")
+	}
+
+	queued := make([]queue.Job, len(jobs))
+	for i, j := range jobs {
+		queued[i] = queue.Job{ID: j.ID, ProjectID: projects[j.ID], Priority: j.Priority, Queued: j.Queued}
+	}
+
+	ordered, err := orderQueue(db, queued, applyQuotas)
+	if err != nil {
+		return nil, sdk.WrapError(err, "sortPipelineBuildJobs> Unable to order queue")
+	}
+
+	res := make([]sdk.PipelineBuildJob, len(ordered))
+	for i, j := range ordered {
+		res[i] = byID[j.ID]
+	}
+	return res, nil
 }
 
 func addBuildVariableHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
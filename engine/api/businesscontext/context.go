@@ -10,4 +10,7 @@ type Ctx struct {
 	User     *sdk.User
 	Worker   *sdk.Worker
 	Hatchery *sdk.Hatchery
+	// AuditBefore and AuditAfter summarize the target of an audited call, before and after the call
+	AuditBefore string
+	AuditAfter  string
 }
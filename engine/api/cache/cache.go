@@ -30,6 +30,7 @@ type Store interface {
 	SetWithTTL(key string, value interface{}, ttl int)
 	Delete(key string)
 	DeleteAll(key string)
+	Incr(key string, ttl int) int64
 	Enqueue(queueName string, value interface{})
 	Dequeue(queueName string, value interface{})
 	DequeueWithContext(c context.Context, queueName string, value interface{})
@@ -106,6 +107,15 @@ func DeleteAll(key string) {
 	s.DeleteAll(key)
 }
 
+//Incr increments a counter in the cache, and returns its new value. The counter expires ttl seconds (0 for eternity)
+//after its creation.
+func Incr(key string, ttl int) int64 {
+	if s == nil {
+		return 0
+	}
+	return s.Incr(key, ttl)
+}
+
 //Enqueue pushes to queue
 func Enqueue(queueName string, value interface{}) {
 	if s == nil {
@@ -80,6 +80,30 @@ func (s *LocalStore) DeleteAll(key string) {
 	}
 }
 
+//Incr increments a counter in local store, which expires ttl seconds after its creation (0 for eternity)
+func (s *LocalStore) Incr(key string, ttl int) int64 {
+	s.Mutex.Lock()
+	var n int64
+	if b := s.Data[key]; len(b) > 0 {
+		if err := json.Unmarshal(b, &n); err != nil {
+			log.Warning("Cache> Cannot unmarshal %s :%s", key, err)
+		}
+	}
+	n++
+	s.Data[key], _ = json.Marshal(n)
+	s.Mutex.Unlock()
+
+	if n == 1 && ttl > 0 {
+		go func(s *LocalStore, key string) {
+			time.Sleep(time.Duration(ttl) * time.Second)
+			s.Mutex.Lock()
+			delete(s.Data, key)
+			s.Mutex.Unlock()
+		}(s, key)
+	}
+	return n
+}
+
 //Enqueue pushes to queue
 func (s *LocalStore) Enqueue(queueName string, value interface{}) {
 	s.Mutex.Lock()
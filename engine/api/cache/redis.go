@@ -126,6 +126,25 @@ func (s *RedisStore) DeleteAll(pattern string) {
 	}
 }
 
+//Incr increments a counter in redis, which expires ttl seconds after its creation (0 for eternity)
+func (s *RedisStore) Incr(key string, ttl int) int64 {
+	if s.Client == nil {
+		log.Error("redis> cannot get redis client")
+		return 0
+	}
+	n, err := s.Client.Incr(key).Result()
+	if err != nil {
+		log.Warning("redis> Error incrementing %s : %s", key, err)
+		return 0
+	}
+	if n == 1 && ttl > 0 {
+		if err := s.Client.Expire(key, time.Duration(ttl)*time.Second).Err(); err != nil {
+			log.Warning("redis> Error setting expiration of %s : %s", key, err)
+		}
+	}
+	return n
+}
+
 //Enqueue pushes to queue
 func (s *RedisStore) Enqueue(queueName string, value interface{}) {
 	if s.Client == nil {
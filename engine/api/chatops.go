@@ -0,0 +1,389 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/engine/api/chatops"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// chatOpsMaxLogLines is the number of lines of logs shown for each failed step
+const chatOpsMaxLogLines = 20
+
+// postChatOpsSlackHandler receives the slash commands of a Slack application. The request is verified with the signing
+// secret of the application, and the command is run as the CDS user linked to the Slack account.
+func postChatOpsSlackHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return sdk.WrapError(sdk.ErrWrongRequest, "postChatOpsSlackHandler> Cannot read body: %s", err)
+	}
+	if err := chatops.VerifySlackRequest(r.Header, body, time.Now()); err != nil {
+		return sdk.WrapError(sdk.ErrUnauthorized, "postChatOpsSlackHandler> %s", err)
+	}
+
+	cmd, err := chatops.ParseSlackCommand(body)
+	if err != nil {
+		return sdk.WrapError(sdk.ErrWrongRequest, "postChatOpsSlackHandler> Cannot parse command: %s", err)
+	}
+
+	command := chatops.ParseCommand(cmd.Text)
+	if command.Name == chatops.CommandLink {
+		text, err := chatOpsLink(db, cmd, command)
+		if err != nil {
+			msg, _ := sdk.ProcessError(err, r.Header.Get("Accept-Language"))
+			log.Warning("postChatOpsSlackHandler> link %s/%s: %s", cmd.TeamID, cmd.UserID, err)
+			text = msg
+		}
+		return WriteJSON(w, r, chatops.SlackResponse{ResponseType: chatops.SlackEphemeral, Text: text}, http.StatusOK)
+	}
+
+	u, err := chatops.LoadUser(db, sdk.ChatOpsSlack, cmd.TeamID, cmd.UserID)
+	if err != nil {
+		if err == sdk.ErrChatOpsAccountNotFound {
+			text := "Your account is not linked to a CDS user. Generate a link code in your CDS profile and send it with the `link <code>` command."
+			return WriteJSON(w, r, chatops.SlackResponse{ResponseType: chatops.SlackEphemeral, Text: text}, http.StatusOK)
+		}
+		return sdk.WrapError(err, "postChatOpsSlackHandler> Cannot load user")
+	}
+	if err := loadUserPermissions(db, u); err != nil {
+		return sdk.WrapError(err, "postChatOpsSlackHandler> Cannot load permissions of user %s", u.Username)
+	}
+	c.User = u
+
+	log.Info("postChatOpsSlackHandler> %s (%s) runs %s %s", u.Username, cmd.UserName, cmd.Command, cmd.Text)
+	text, inChannel, err := execChatOpsCommand(db, c, command)
+	if err != nil {
+		msg, _ := sdk.ProcessError(err, r.Header.Get("Accept-Language"))
+		log.Warning("postChatOpsSlackHandler> %s: %s", cmd.Text, err)
+		return WriteJSON(w, r, chatops.SlackResponse{ResponseType: chatops.SlackEphemeral, Text: msg}, http.StatusOK)
+	}
+
+	resp := chatops.SlackResponse{ResponseType: chatops.SlackEphemeral, Text: text}
+	if inChannel {
+		resp.ResponseType = chatops.SlackInChannel
+	}
+	return WriteJSON(w, r, resp, http.StatusOK)
+}
+
+// chatOpsLink links the chat account which sent the command to the user who generated the link code. The account
+// is the one of the signed request, so a user cannot link the account of someone else.
+func chatOpsLink(db gorp.SqlExecutor, cmd *chatops.SlackCommand, command chatops.Command) (string, error) {
+	if len(command.Args) != 1 {
+		return "", sdk.WrapError(sdk.ErrWrongRequest, "chatOpsLink> Usage: link <code>")
+	}
+	if cmd.TeamID == "" || cmd.UserID == "" {
+		return "", sdk.WrapError(sdk.ErrWrongRequest, "chatOpsLink> Missing team or user id")
+	}
+	userID, err := chatops.ConsumeLinkCode(command.Args[0])
+	if err != nil {
+		return "", err
+	}
+	u, err := user.LoadUserWithoutAuthByID(db, userID)
+	if err != nil {
+		return "", sdk.WrapError(err, "chatOpsLink> Cannot load user %d", userID)
+	}
+
+	a := sdk.ChatOpsAccount{
+		UserID:     u.ID,
+		Provider:   sdk.ChatOpsSlack,
+		TeamID:     cmd.TeamID,
+		ChatUserID: cmd.UserID,
+	}
+	if err := chatops.InsertAccount(db, &a); err != nil {
+		return "", sdk.WrapError(err, "chatOpsLink> Cannot insert chat account")
+	}
+	log.Info("chatOpsLink> %s (%s/%s) linked to %s", cmd.UserName, cmd.TeamID, cmd.UserID, u.Username)
+	return fmt.Sprintf("Your account is now linked to the CDS user %s.", u.Username), nil
+}
+
+// execChatOpsCommand runs a chat command, it returns the answer and true if it has to be shown to the whole channel.
+// Each command checks the same permission as the route doing the same operation.
+func execChatOpsCommand(db *gorp.DbMap, c *businesscontext.Ctx, cmd chatops.Command) (string, bool, error) {
+	switch cmd.Name {
+	case chatops.CommandRun:
+		return chatOpsRun(db, c, cmd)
+	case chatops.CommandStatus:
+		return chatOpsStatus(db, c, cmd)
+	case chatops.CommandLogs:
+		return chatOpsLogs(db, c, cmd)
+	case chatops.CommandStop:
+		return chatOpsStop(db, c, cmd)
+	case chatops.CommandApprove, chatops.CommandReject:
+		return chatOpsVote(db, c, cmd)
+	}
+	return chatops.Usage, false, nil
+}
+
+func checkChatOpsPermission(c *businesscontext.Ctx, key string, method string, isExecution bool) error {
+	if c.User.Admin {
+		return nil
+	}
+	if !checkPermission(map[string]string{"permProjectKey": key}, c, getPermissionByMethod(method, isExecution)) {
+		return sdk.ErrForbidden
+	}
+	return nil
+}
+
+func chatOpsRun(db *gorp.DbMap, c *businesscontext.Ctx, cmd chatops.Command) (string, bool, error) {
+	key, name, err := cmd.Workflow()
+	if err != nil {
+		return err.Error(), false, nil
+	}
+	if err := checkChatOpsPermission(c, key, http.MethodPost, false); err != nil {
+		return "", false, err
+	}
+
+	wf, err := workflow.Load(db, key, name, c.User)
+	if err != nil {
+		return "", false, sdk.WrapError(err, "chatOpsRun> Unable to load workflow")
+	}
+
+	//Override the default pipeline parameters of the root node
+	var params []sdk.Parameter
+	if wf.Root != nil && wf.Root.Context != nil {
+		params = append(params, wf.Root.Context.DefaultPipelineParameters...)
+	}
+	for _, a := range cmd.Args[1:] {
+		p, err := sdk.NewStringParameter(a)
+		if err != nil {
+			return fmt.Sprintf("invalid parameter %q, expected name=value", a), false, nil
+		}
+		var found bool
+		for i := range params {
+			if params[i].Name == p.Name {
+				params[i].Value = p.Value
+				found = true
+			}
+		}
+		if !found {
+			params = append(params, p)
+		}
+	}
+
+	opts := &postWorkflowRunHandlerOption{
+		Manual: &sdk.WorkflowNodeRunManual{
+			User:               *c.User,
+			PipelineParameters: params,
+		},
+	}
+	wr, err := runWorkflow(db, c.User, key, name, opts)
+	if err != nil {
+		return "", false, err
+	}
+	return fmt.Sprintf("%s started %s/%s #%d: %s", c.User.Username, key, name, wr.Number, chatOpsRunURL(key, name, wr.Number)), true, nil
+}
+
+func chatOpsStatus(db *gorp.DbMap, c *businesscontext.Ctx, cmd chatops.Command) (string, bool, error) {
+	key, name, err := cmd.Workflow()
+	if err != nil {
+		return err.Error(), false, nil
+	}
+	if err := checkChatOpsPermission(c, key, http.MethodGet, false); err != nil {
+		return "", false, err
+	}
+
+	var wr *sdk.WorkflowRun
+	if len(cmd.Args) > 1 {
+		number, err := strconv.ParseInt(cmd.Args[1], 10, 64)
+		if err != nil {
+			return fmt.Sprintf("invalid run number %q", cmd.Args[1]), false, nil
+		}
+		wr, err = workflow.LoadRun(db, key, name, number)
+		if err != nil {
+			return "", false, sdk.WrapError(err, "chatOpsStatus> Unable to load workflow run")
+		}
+	} else {
+		wr, err = workflow.LoadLastRun(db, key, name)
+		if err != nil {
+			return "", false, sdk.WrapError(err, "chatOpsStatus> Unable to load last workflow run")
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s/%s #%d: %s\n", key, name, wr.Number, chatOpsRunURL(key, name, wr.Number))
+	for _, n := range chatOpsLastNodeRuns(wr) {
+		fmt.Fprintf(&buf, "• %s #%d.%d: %s\n", chatOpsNodeName(wr, n.WorkflowNodeID), n.Number, n.SubNumber, n.Status)
+	}
+	return buf.String(), false, nil
+}
+
+func chatOpsLogs(db *gorp.DbMap, c *businesscontext.Ctx, cmd chatops.Command) (string, bool, error) {
+	key, name, err := cmd.Workflow()
+	if err != nil {
+		return err.Error(), false, nil
+	}
+	if len(cmd.Args) < 2 {
+		return "missing run number", false, nil
+	}
+	number, err := strconv.ParseInt(cmd.Args[1], 10, 64)
+	if err != nil {
+		return fmt.Sprintf("invalid run number %q", cmd.Args[1]), false, nil
+	}
+	if err := checkChatOpsPermission(c, key, http.MethodGet, false); err != nil {
+		return "", false, err
+	}
+
+	wr, err := workflow.LoadRun(db, key, name, number)
+	if err != nil {
+		return "", false, sdk.WrapError(err, "chatOpsLogs> Unable to load workflow run")
+	}
+
+	var buf bytes.Buffer
+	for _, n := range chatOpsLastNodeRuns(wr) {
+		for _, s := range n.Stages {
+			for _, rj := range s.RunJobs {
+				for _, ss := range rj.Job.StepStatus {
+					if ss.Status != sdk.StatusFail.String() {
+						continue
+					}
+					logs, err := workflow.LoadStepLogs(db, rj.ID, int64(ss.StepOrder))
+					if err != nil {
+						log.Warning("chatOpsLogs> Cannot load log for runJob %d on step %d: %s", rj.ID, ss.StepOrder, err)
+						continue
+					}
+					fmt.Fprintf(&buf, "%s > %s > step %d:\n```\n%s\n```\n", chatOpsNodeName(wr, n.WorkflowNodeID), rj.Job.Action.Name, ss.StepOrder, lastLines(logs.Val, chatOpsMaxLogLines))
+				}
+			}
+		}
+	}
+	if buf.Len() == 0 {
+		return fmt.Sprintf("No failed step on %s/%s #%d", key, name, number), false, nil
+	}
+	return buf.String(), false, nil
+}
+
+func chatOpsStop(db *gorp.DbMap, c *businesscontext.Ctx, cmd chatops.Command) (string, bool, error) {
+	key, name, err := cmd.Workflow()
+	if err != nil {
+		return err.Error(), false, nil
+	}
+	if len(cmd.Args) < 2 {
+		return "missing run number", false, nil
+	}
+	number, err := strconv.ParseInt(cmd.Args[1], 10, 64)
+	if err != nil {
+		return fmt.Sprintf("invalid run number %q", cmd.Args[1]), false, nil
+	}
+	if err := checkChatOpsPermission(c, key, http.MethodPost, true); err != nil {
+		return "", false, err
+	}
+
+	if _, err := stopWorkflowRun(db, c.User, key, name, number); err != nil {
+		return "", false, err
+	}
+	return fmt.Sprintf("%s stopped %s/%s #%d", c.User.Username, key, name, number), true, nil
+}
+
+func chatOpsVote(db *gorp.DbMap, c *businesscontext.Ctx, cmd chatops.Command) (string, bool, error) {
+	if len(cmd.Args) < 2 {
+		return fmt.Sprintf("usage: %s <project> <approval> [comment]", cmd.Name), false, nil
+	}
+	key := cmd.Args[0]
+	id, err := strconv.ParseInt(cmd.Args[1], 10, 64)
+	if err != nil {
+		return fmt.Sprintf("invalid approval %q", cmd.Args[1]), false, nil
+	}
+	if err := checkChatOpsPermission(c, key, http.MethodPost, true); err != nil {
+		return "", false, err
+	}
+
+	vote := sdk.ApprovalVote{Approved: cmd.Name == chatops.CommandApprove, Comment: cmd.Comment(2)}
+	a, err := voteApproval(db, c.User, key, id, vote)
+	if err != nil {
+		return "", false, err
+	}
+	return fmt.Sprintf("%s voted on approval %d (%s): %s", c.User.Username, a.ID, a.Description, a.Status), true, nil
+}
+
+// chatOpsLastNodeRuns returns the last run of each node, sorted by node run id
+func chatOpsLastNodeRuns(wr *sdk.WorkflowRun) []sdk.WorkflowNodeRun {
+	var runs []sdk.WorkflowNodeRun
+	for _, nrs := range wr.WorkflowNodeRuns {
+		if len(nrs) > 0 {
+			runs = append(runs, nrs[0])
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].ID < runs[j].ID })
+	return runs
+}
+
+func chatOpsNodeName(wr *sdk.WorkflowRun, id int64) string {
+	n := wr.Workflow.GetNode(id)
+	if n == nil {
+		return strconv.FormatInt(id, 10)
+	}
+	if n.Name != "" {
+		return n.Name
+	}
+	return n.Pipeline.Name
+}
+
+func chatOpsRunURL(key, name string, number int64) string {
+	return fmt.Sprintf("%s/project/%s/workflow/%s/run/%d", baseURL, key, name, number)
+}
+
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func getUserChatOpsAccountsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	u, err := user.LoadUserWithoutAuth(db, mux.Vars(r)["username"])
+	if err != nil {
+		return sdk.WrapError(sdk.ErrNotFound, "getUserChatOpsAccountsHandler> Cannot load user: %s", err)
+	}
+
+	accounts, err := chatops.LoadAccounts(db, u.ID)
+	if err != nil {
+		return sdk.WrapError(err, "getUserChatOpsAccountsHandler> Cannot load chat accounts")
+	}
+	return WriteJSON(w, r, accounts, http.StatusOK)
+}
+
+// postUserChatOpsAccountHandler generates a one-time code, which links the chat account sending it with the
+// link chat command to the user
+func postUserChatOpsAccountHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	u, err := user.LoadUserWithoutAuth(db, mux.Vars(r)["username"])
+	if err != nil {
+		return sdk.WrapError(sdk.ErrNotFound, "postUserChatOpsAccountHandler> Cannot load user: %s", err)
+	}
+
+	code, err := chatops.NewLinkCode(u.ID)
+	if err != nil {
+		return sdk.WrapError(err, "postUserChatOpsAccountHandler> Cannot generate link code")
+	}
+	return WriteJSON(w, r, code, http.StatusCreated)
+}
+
+func deleteUserChatOpsAccountHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	u, err := user.LoadUserWithoutAuth(db, mux.Vars(r)["username"])
+	if err != nil {
+		return sdk.WrapError(sdk.ErrNotFound, "deleteUserChatOpsAccountHandler> Cannot load user: %s", err)
+	}
+	id, err := requestVarInt(r, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := chatops.DeleteAccount(db, u.ID, id); err != nil {
+		return sdk.WrapError(err, "deleteUserChatOpsAccountHandler> Cannot delete chat account %d", id)
+	}
+	return WriteJSON(w, r, nil, http.StatusOK)
+}
@@ -0,0 +1,76 @@
+package chatops
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadUser returns the CDS user linked to a chat account
+func LoadUser(db gorp.SqlExecutor, provider, teamID, chatUserID string) (*sdk.User, error) {
+	query := `SELECT user_id FROM chatops_account WHERE provider = $1 AND team_id = $2 AND chat_user_id = $3`
+	var userID int64
+	if err := db.QueryRow(query, provider, teamID, chatUserID).Scan(&userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrChatOpsAccountNotFound
+		}
+		return nil, sdk.WrapError(err, "LoadUser> Cannot load chat account %s/%s/%s", provider, teamID, chatUserID)
+	}
+
+	u, err := user.LoadUserWithoutAuthByID(db, userID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadUser> Cannot load user %d", userID)
+	}
+	return u, nil
+}
+
+// LoadAccounts returns the chat accounts of a user
+func LoadAccounts(db gorp.SqlExecutor, userID int64) ([]sdk.ChatOpsAccount, error) {
+	var dbAccounts []dbAccount
+	query := `SELECT * FROM chatops_account WHERE user_id = $1 ORDER BY id`
+	if _, err := db.Select(&dbAccounts, query, userID); err != nil {
+		return nil, sdk.WrapError(err, "LoadAccounts> Cannot load chat accounts of user %d", userID)
+	}
+
+	accounts := make([]sdk.ChatOpsAccount, len(dbAccounts))
+	for i := range dbAccounts {
+		accounts[i] = sdk.ChatOpsAccount(dbAccounts[i])
+	}
+	return accounts, nil
+}
+
+// InsertAccount links a chat account to a user. A chat account can only be linked to one user.
+func InsertAccount(db gorp.SqlExecutor, a *sdk.ChatOpsAccount) error {
+	query := `SELECT COUNT(id) FROM chatops_account WHERE provider = $1 AND team_id = $2 AND chat_user_id = $3`
+	n, err := db.SelectInt(query, a.Provider, a.TeamID, a.ChatUserID)
+	if err != nil {
+		return sdk.WrapError(err, "InsertAccount> Cannot check chat account")
+	}
+	if n > 0 {
+		return sdk.ErrChatOpsAccountExists
+	}
+
+	a.Created = time.Now()
+	dbA := dbAccount(*a)
+	if err := db.Insert(&dbA); err != nil {
+		return sdk.WrapError(err, "InsertAccount> Cannot insert chat account")
+	}
+	a.ID = dbA.ID
+	return nil
+}
+
+// DeleteAccount unlinks a chat account from a user
+func DeleteAccount(db gorp.SqlExecutor, userID, id int64) error {
+	res, err := db.Exec(`DELETE FROM chatops_account WHERE user_id = $1 AND id = $2`, userID, id)
+	if err != nil {
+		return sdk.WrapError(err, "DeleteAccount> Cannot delete chat account %d", id)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sdk.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package chatops
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Commands
+const (
+	CommandRun     = "run"
+	CommandStatus  = "status"
+	CommandLogs    = "logs"
+	CommandStop    = "stop"
+	CommandApprove = "approve"
+	CommandReject  = "reject"
+	CommandLink    = "link"
+	CommandHelp    = "help"
+)
+
+// Usage describes the commands
+const Usage = "Available commands:\n" +
+	"• `run <project>/<workflow> [name=value...]`: runs a workflow with pipeline parameters\n" +
+	"• `status <project>/<workflow> [number]`: shows the status of a run, the last one by default\n" +
+	"• `logs <project>/<workflow> <number>`: shows the logs of the failed steps of a run\n" +
+	"• `stop <project>/<workflow> <number>`: stops a run\n" +
+	"• `approve <project> <approval> [comment]`: approves a pending approval\n" +
+	"• `reject <project> <approval> [comment]`: rejects a pending approval\n" +
+	"• `link <code>`: links your chat account to the CDS user who generated the code"
+
+// Command is a chat command: a name and its arguments
+type Command struct {
+	Name string
+	Args []string
+}
+
+// ParseCommand splits the text of a chat message into a command
+func ParseCommand(text string) Command {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return Command{Name: CommandHelp}
+	}
+	return Command{Name: strings.ToLower(fields[0]), Args: fields[1:]}
+}
+
+// Workflow returns the project key and the name of the workflow given as first argument with <project>/<workflow> format
+func (c Command) Workflow() (string, string, error) {
+	if len(c.Args) == 0 {
+		return "", "", fmt.Errorf("missing <project>/<workflow>")
+	}
+	t := strings.SplitN(c.Args[0], "/", 2)
+	if len(t) != 2 || t[0] == "" || t[1] == "" {
+		return "", "", fmt.Errorf("invalid workflow %q, expected <project>/<workflow>", c.Args[0])
+	}
+	return t[0], t[1], nil
+}
+
+// Comment returns the arguments from the index as a sentence
+func (c Command) Comment(from int) string {
+	if len(c.Args) <= from {
+		return ""
+	}
+	return strings.Join(c.Args[from:], " ")
+}
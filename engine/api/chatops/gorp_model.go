@@ -0,0 +1,12 @@
+package chatops
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbAccount sdk.ChatOpsAccount
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbAccount{}, "chatops_account", true, "id"))
+}
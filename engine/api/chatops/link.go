@@ -0,0 +1,40 @@
+package chatops
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+// linkCodeTTL is the lifetime of a link code, in seconds
+const linkCodeTTL = 600
+
+// NewLinkCode generates a one-time code for a user. The chat account which sends the link command with this code
+// is linked to the user, so that only the owner of the chat account can link it.
+func NewLinkCode(userID int64) (*sdk.ChatOpsLinkCode, error) {
+	bs := make([]byte, 12)
+	if _, err := rand.Read(bs); err != nil {
+		return nil, sdk.WrapError(err, "NewLinkCode> Cannot generate link code")
+	}
+	code := hex.EncodeToString(bs)
+	cache.SetWithTTL(cache.Key("chatops", "link", code), userID, linkCodeTTL)
+	return &sdk.ChatOpsLinkCode{Code: code, Expire: time.Now().Add(linkCodeTTL * time.Second)}, nil
+}
+
+// ConsumeLinkCode returns the id of the user who generated a link code, the code cannot be used again
+func ConsumeLinkCode(code string) (int64, error) {
+	key := cache.Key("chatops", "link", code)
+	var userID int64
+	if code == "" || !cache.Get(key, &userID) {
+		return 0, sdk.ErrChatOpsInvalidLinkCode
+	}
+	// Only the first command sent with the code links an account
+	if cache.Incr(cache.Key("chatops", "link", code, "used"), linkCodeTTL) != 1 {
+		return 0, sdk.ErrChatOpsInvalidLinkCode
+	}
+	cache.Delete(key)
+	return userID, nil
+}
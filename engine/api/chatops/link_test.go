@@ -0,0 +1,29 @@
+package chatops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestLinkCode(t *testing.T) {
+	cache.Initialize("local", "", "", 30)
+
+	code, err := NewLinkCode(42)
+	assert.NoError(t, err)
+	assert.Len(t, code.Code, 24)
+
+	_, err = ConsumeLinkCode("unknown")
+	assert.Equal(t, sdk.ErrChatOpsInvalidLinkCode, err)
+
+	userID, err := ConsumeLinkCode(code.Code)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), userID)
+
+	// A code links only one account
+	_, err = ConsumeLinkCode(code.Code)
+	assert.Equal(t, sdk.ErrChatOpsInvalidLinkCode, err)
+}
@@ -0,0 +1,86 @@
+package chatops
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// slackMaxRequestAge is the maximum age of a signed request, to prevent replays
+const slackMaxRequestAge = 5 * time.Minute
+
+var slackSigningSecret string
+
+// Init initializes chatops package with the signing secret of the Slack application
+func Init(signingSecret string) {
+	slackSigningSecret = signingSecret
+}
+
+// SlackCommand is a slash command sent by Slack
+type SlackCommand struct {
+	TeamID      string
+	UserID      string
+	UserName    string
+	ChannelName string
+	Command     string
+	Text        string
+}
+
+// SlackResponse is the answer to a slash command. ResponseType is "in_channel" to show it to the whole channel,
+// or "ephemeral" to show it only to the user.
+type SlackResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// Slack response types
+const (
+	SlackInChannel = "in_channel"
+	SlackEphemeral = "ephemeral"
+)
+
+// VerifySlackRequest checks the signature of a request sent by Slack with the signing secret
+func VerifySlackRequest(h http.Header, body []byte, now time.Time) error {
+	if slackSigningSecret == "" {
+		return fmt.Errorf("slack signing secret is not set")
+	}
+
+	ts := h.Get("X-Slack-Request-Timestamp")
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", ts)
+	}
+	if age := now.Sub(time.Unix(sec, 0)); age > slackMaxRequestAge || age < -slackMaxRequestAge {
+		return fmt.Errorf("request is too old")
+	}
+
+	mac := hmac.New(sha256.New, []byte(slackSigningSecret))
+	mac.Write([]byte("v0:" + ts + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(h.Get("X-Slack-Signature"))) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// ParseSlackCommand reads the form sent by Slack
+func ParseSlackCommand(body []byte) (*SlackCommand, error) {
+	v, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	return &SlackCommand{
+		TeamID:      v.Get("team_id"),
+		UserID:      v.Get("user_id"),
+		UserName:    v.Get("user_name"),
+		ChannelName: v.Get("channel_name"),
+		Command:     v.Get("command"),
+		Text:        v.Get("text"),
+	}, nil
+}
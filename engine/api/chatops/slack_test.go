@@ -0,0 +1,58 @@
+package chatops
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func signSlackRequest(secret string, ts time.Time, body []byte) http.Header {
+	s := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + s + ":"))
+	mac.Write(body)
+
+	h := http.Header{}
+	h.Set("X-Slack-Request-Timestamp", s)
+	h.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return h
+}
+
+func TestVerifySlackRequest(t *testing.T) {
+	Init("8f742231b10e8888abcd99yyyzzz85a5")
+	body := []byte("team_id=T0001&user_id=U2147483697&user_name=steve&command=%2Fcds&text=status+KEY%2Fbuild")
+	now := time.Now()
+
+	assert.NoError(t, VerifySlackRequest(signSlackRequest("8f742231b10e8888abcd99yyyzzz85a5", now, body), body, now))
+	assert.Error(t, VerifySlackRequest(signSlackRequest("other", now, body), body, now))
+	assert.Error(t, VerifySlackRequest(signSlackRequest("8f742231b10e8888abcd99yyyzzz85a5", now.Add(-10*time.Minute), body), body, now))
+
+	cmd, err := ParseSlackCommand(body)
+	assert.NoError(t, err)
+	assert.Equal(t, "T0001", cmd.TeamID)
+	assert.Equal(t, "U2147483697", cmd.UserID)
+	assert.Equal(t, "status KEY/build", cmd.Text)
+
+	Init("")
+	assert.Error(t, VerifySlackRequest(signSlackRequest("", now, body), body, now))
+}
+
+func TestParseCommand(t *testing.T) {
+	c := ParseCommand("  Run KEY/build  version=1.0 env=prod ")
+	assert.Equal(t, CommandRun, c.Name)
+	key, name, err := c.Workflow()
+	assert.NoError(t, err)
+	assert.Equal(t, "KEY", key)
+	assert.Equal(t, "build", name)
+	assert.Equal(t, "version=1.0 env=prod", c.Comment(1))
+
+	_, _, err = ParseCommand("status build").Workflow()
+	assert.Error(t, err)
+	assert.Equal(t, CommandHelp, ParseCommand("").Name)
+}
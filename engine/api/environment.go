@@ -46,6 +46,24 @@ func getEnvironmentHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 	return WriteJSON(w, r, environment, http.StatusOK)
 }
 
+func getEnvironmentLocksHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	projectKey := vars["key"]
+	environmentName := vars["permEnvironmentName"]
+
+	env, errEnv := environment.LoadEnvironmentByName(db, projectKey, environmentName)
+	if errEnv != nil {
+		return sdk.WrapError(errEnv, "getEnvironmentLocksHandler> Cannot load environment %s", environmentName)
+	}
+
+	locks, errLocks := environment.LoadLocks(db, env.ID)
+	if errLocks != nil {
+		return sdk.WrapError(errLocks, "getEnvironmentLocksHandler> Cannot load locks of environment %s", environmentName)
+	}
+
+	return WriteJSON(w, r, locks, http.StatusOK)
+}
+
 // Deprecated
 func updateEnvironmentsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
 	// Get project name in URL
@@ -354,6 +372,9 @@ func updateEnvironmentHandler(w http.ResponseWriter, r *http.Request, db *gorp.D
 	}
 
 	env.Name = envPost.Name
+	env.Exclusive = envPost.Exclusive
+	env.ExclusiveApplications = envPost.ExclusiveApplications
+	env.LockPolicy = envPost.LockPolicy
 
 	tx, errBegin := db.Begin()
 	if errBegin != nil {
@@ -367,6 +388,14 @@ func updateEnvironmentHandler(w http.ResponseWriter, r *http.Request, db *gorp.D
 		return err
 	}
 
+	if err := environment.UpdateLockSettings(tx, env); err != nil {
+		return sdk.WrapError(err, "updateEnvironmentHandler> Cannot update lock settings of environment %s", environmentName)
+	}
+
+	if err := environment.UpdateLastModified(tx, c.User, env); err != nil {
+		return sdk.WrapError(err, "updateEnvironmentHandler> Cannot update environment %s last modified date", environmentName)
+	}
+
 	if len(envPost.Variable) > 0 {
 		preload, err := environment.GetAllVariable(tx, projectKey, env.Name, environment.WithClearPassword())
 		if err != nil {
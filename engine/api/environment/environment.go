@@ -10,6 +10,7 @@ import (
 
 	"github.com/ovh/cds/engine/api/artifact"
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/history"
 	"github.com/ovh/cds/engine/api/keys"
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/sdk"
@@ -167,6 +168,9 @@ func loadDependencies(db gorp.SqlExecutor, env *sdk.Environment) error {
 		return err
 	}
 	env.Variable = variables
+	if err := LoadLockSettings(db, env); err != nil {
+		return err
+	}
 	return loadGroupByEnvironment(db, env)
 }
 
@@ -325,8 +329,26 @@ func UpdateLastModified(db gorp.SqlExecutor, u *sdk.User, env *sdk.Environment)
 	}
 
 	query := `UPDATE environment SET last_modified = current_timestamp WHERE id=$1`
-	_, err := db.Exec(query, env.ID)
-	return err
+	if _, err := db.Exec(query, env.ID); err != nil {
+		return err
+	}
+	return recordHistory(db, env.ID, u)
+}
+
+// recordHistory stores the current settings of the environment as a new version in its history.
+// Variables are not part of the snapshot, they have their own audit.
+func recordHistory(db gorp.SqlExecutor, envID int64, u *sdk.User) error {
+	snapshot, err := LoadEnvironmentByID(db, envID)
+	if err != nil {
+		return sdk.WrapError(err, "environment.recordHistory> Unable to load environment %d", envID)
+	}
+	projectID, err := db.SelectInt("select project_id from environment where id = $1", envID)
+	if err != nil {
+		return sdk.WrapError(err, "environment.recordHistory> Unable to load project of environment %d", envID)
+	}
+	snapshot.Variable = nil
+	snapshot.EnvironmentGroups = nil
+	return history.Record(db, sdk.HistoryEnvironment, projectID, snapshot.ID, snapshot.Name, snapshot, u)
 }
 
 func loadGroupByEnvironment(db gorp.SqlExecutor, environment *sdk.Environment) error {
@@ -0,0 +1,212 @@
+package environment
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbEnvironmentLock sdk.EnvironmentLock
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbEnvironmentLock{}, "environment_lock", false, "environment_id", "application_id"))
+}
+
+// HolderStopper stops the run holding a lock, in the transaction taking the lock from it
+type HolderStopper func(db gorp.SqlExecutor, l *sdk.EnvironmentLock) error
+
+// Stoppers of workflow node runs and pipeline builds, registered by the packages running them
+var nodeRunStopper, pipelineBuildStopper HolderStopper
+
+// RegisterNodeRunStopper registers the func stopping workflow node runs holding a lock
+func RegisterNodeRunStopper(f HolderStopper) {
+	nodeRunStopper = f
+}
+
+// RegisterPipelineBuildStopper registers the func stopping pipeline builds holding a lock
+func RegisterPipelineBuildStopper(f HolderStopper) {
+	pipelineBuildStopper = f
+}
+
+// stopHolder stops the run holding the lock l
+func stopHolder(db gorp.SqlExecutor, l *sdk.EnvironmentLock) error {
+	stop := nodeRunStopper
+	if l.PipelineBuildID != 0 {
+		stop = pipelineBuildStopper
+	}
+	if stop == nil {
+		return fmt.Errorf("no stopper registered for lock holder %s", l.Holder)
+	}
+	return stop(db, l)
+}
+
+// LoadLockSettings loads the exclusive flags and the lock policy of the environment
+func LoadLockSettings(db gorp.SqlExecutor, env *sdk.Environment) error {
+	query := `SELECT exclusive, lock_policy FROM environment WHERE id = $1`
+	if err := db.QueryRow(query, env.ID).Scan(&env.Exclusive, &env.LockPolicy); err != nil {
+		if err == sql.ErrNoRows {
+			return sdk.ErrNoEnvironment
+		}
+		return sdk.WrapError(err, "LoadLockSettings> Cannot load lock settings of environment %d", env.ID)
+	}
+
+	query = `SELECT application.name FROM application
+		JOIN environment_exclusive_application ON environment_exclusive_application.application_id = application.id
+		WHERE environment_exclusive_application.environment_id = $1
+		ORDER BY application.name`
+	rows, err := db.Query(query, env.ID)
+	if err != nil {
+		return sdk.WrapError(err, "LoadLockSettings> Cannot load exclusive applications of environment %d", env.ID)
+	}
+	defer rows.Close()
+
+	env.ExclusiveApplications = nil
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return sdk.WrapError(err, "LoadLockSettings> Cannot scan application name")
+		}
+		env.ExclusiveApplications = append(env.ExclusiveApplications, name)
+	}
+	return nil
+}
+
+// UpdateLockSettings saves the exclusive flags and the lock policy of the environment
+func UpdateLockSettings(db gorp.SqlExecutor, env *sdk.Environment) error {
+	if err := sdk.IsValidEnvironmentLockPolicy(env.LockPolicy); err != nil {
+		return err
+	}
+	if env.LockPolicy == "" {
+		env.LockPolicy = sdk.EnvironmentLockQueue
+	}
+
+	query := `UPDATE environment SET exclusive = $1, lock_policy = $2 WHERE id = $3`
+	if _, err := db.Exec(query, env.Exclusive, env.LockPolicy, env.ID); err != nil {
+		return sdk.WrapError(err, "UpdateLockSettings> Cannot update environment %d", env.ID)
+	}
+
+	if _, err := db.Exec(`DELETE FROM environment_exclusive_application WHERE environment_id = $1`, env.ID); err != nil {
+		return sdk.WrapError(err, "UpdateLockSettings> Cannot delete exclusive applications of environment %d", env.ID)
+	}
+
+	query = `INSERT INTO environment_exclusive_application (environment_id, application_id)
+		SELECT environment.id, application.id FROM environment
+		JOIN application ON application.project_id = environment.project_id
+		WHERE environment.id = $1 AND application.name = $2`
+	for _, name := range env.ExclusiveApplications {
+		res, err := db.Exec(query, env.ID, name)
+		if err != nil {
+			return sdk.WrapError(err, "UpdateLockSettings> Cannot insert exclusive application %s", name)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return sdk.WrapError(sdk.ErrApplicationNotFound, "UpdateLockSettings> Unknown application %s", name)
+		}
+	}
+	return nil
+}
+
+// LoadLocks loads the locks currently held on the environment
+func LoadLocks(db gorp.SqlExecutor, envID int64) ([]sdk.EnvironmentLock, error) {
+	var res []dbEnvironmentLock
+	if _, err := db.Select(&res, `SELECT * FROM environment_lock WHERE environment_id = $1 ORDER BY since`, envID); err != nil {
+		return nil, sdk.WrapError(err, "LoadLocks> Cannot load locks of environment %d", envID)
+	}
+	locks := make([]sdk.EnvironmentLock, len(res))
+	for i := range res {
+		locks[i] = sdk.EnvironmentLock(res[i])
+	}
+	return locks, nil
+}
+
+// AcquireLock takes the lock l if it is free, or if it is held by a run which is over. With force, the lock
+// is taken from the current holder, whose waiting and building jobs are stopped in the same transaction.
+// It returns the current lock and false if the lock is held by another run.
+func AcquireLock(db gorp.SqlExecutor, l *sdk.EnvironmentLock, force bool) (*sdk.EnvironmentLock, bool, error) {
+	// Serialize lock requests on the environment
+	if _, err := db.Exec(`SELECT id FROM environment WHERE id = $1 FOR UPDATE`, l.EnvironmentID); err != nil {
+		return nil, false, sdk.WrapError(err, "AcquireLock> Cannot lock environment %d", l.EnvironmentID)
+	}
+
+	current, err := loadLock(db, l.EnvironmentID, l.ApplicationID)
+	if err != nil {
+		return nil, false, sdk.WrapError(err, "AcquireLock> Cannot load lock")
+	}
+
+	if current != nil {
+		if current.SameHolder(*l) {
+			return current, true, nil
+		}
+		running, err := isHolderRunning(db, current)
+		if err != nil {
+			return nil, false, sdk.WrapError(err, "AcquireLock> Cannot check lock holder")
+		}
+		if running && !force {
+			return current, false, nil
+		}
+		if running {
+			if err := stopHolder(db, current); err != nil {
+				return nil, false, sdk.WrapError(err, "AcquireLock> Cannot stop lock holder %s", current.Holder)
+			}
+		}
+		if _, err := db.Exec(`DELETE FROM environment_lock WHERE environment_id = $1 AND application_id = $2`, l.EnvironmentID, l.ApplicationID); err != nil {
+			return nil, false, sdk.WrapError(err, "AcquireLock> Cannot delete lock")
+		}
+	}
+
+	l.Since = time.Now()
+	dbl := dbEnvironmentLock(*l)
+	if err := db.Insert(&dbl); err != nil {
+		return nil, false, sdk.WrapError(err, "AcquireLock> Cannot insert lock")
+	}
+	return l, true, nil
+}
+
+// HoldsLock returns false if the lock l has been taken by another run
+func HoldsLock(db gorp.SqlExecutor, l *sdk.EnvironmentLock) (bool, error) {
+	current, err := loadLock(db, l.EnvironmentID, l.ApplicationID)
+	if err != nil {
+		return false, sdk.WrapError(err, "HoldsLock> Cannot load lock")
+	}
+	return current == nil || current.SameHolder(*l), nil
+}
+
+// ReleaseLock releases the lock l if it is still held by the run
+func ReleaseLock(db gorp.SqlExecutor, l *sdk.EnvironmentLock) error {
+	query := `DELETE FROM environment_lock
+		WHERE environment_id = $1 AND application_id = $2 AND pipeline_build_id = $3 AND workflow_node_run_id = $4`
+	if _, err := db.Exec(query, l.EnvironmentID, l.ApplicationID, l.PipelineBuildID, l.WorkflowNodeRunID); err != nil {
+		return sdk.WrapError(err, "ReleaseLock> Cannot delete lock")
+	}
+	return nil
+}
+
+func loadLock(db gorp.SqlExecutor, envID, appID int64) (*sdk.EnvironmentLock, error) {
+	var l dbEnvironmentLock
+	if err := db.SelectOne(&l, `SELECT * FROM environment_lock WHERE environment_id = $1 AND application_id = $2`, envID, appID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	res := sdk.EnvironmentLock(l)
+	return &res, nil
+}
+
+func isHolderRunning(db gorp.SqlExecutor, l *sdk.EnvironmentLock) (bool, error) {
+	query := `SELECT count(1) FROM workflow_node_run WHERE id = $1 AND status IN ($2, $3)`
+	id := l.WorkflowNodeRunID
+	if l.PipelineBuildID != 0 {
+		query = `SELECT count(1) FROM pipeline_build WHERE id = $1 AND status IN ($2, $3)`
+		id = l.PipelineBuildID
+	}
+	var n int
+	if err := db.QueryRow(query, id, sdk.StatusWaiting.String(), sdk.StatusBuilding.String()).Scan(&n); err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
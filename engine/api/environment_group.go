@@ -6,6 +6,7 @@ import (
 	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/businesscontext"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/group"
@@ -36,6 +37,7 @@ func updateGroupRoleOnEnvironmentHandler(w http.ResponseWriter, r *http.Request,
 	if errE != nil {
 		return sdk.WrapError(errE, "updateGroupRoleOnEnvironmentHandler> Cannot load environment %s", envName)
 	}
+	c.AuditBefore = audit.Permissions(db, "environment", env.ID)
 
 	if groupEnvironment.Permission != permission.PermissionReadWriteExecute {
 		permissions, errR := group.LoadAllEnvironmentGroupByRole(db, env.ID, permission.PermissionReadWriteExecute)
@@ -75,6 +77,7 @@ func updateGroupRoleOnEnvironmentHandler(w http.ResponseWriter, r *http.Request,
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "updateGroupRoleOnEnvironmentHandler> Cannot commit transaction")
 	}
+	c.AuditAfter = audit.Permissions(db, "environment", env.ID)
 
 	envUpdated, errE := environment.LoadEnvironmentByName(db, key, envName)
 	if errE != nil {
@@ -99,6 +102,7 @@ func addGroupsInEnvironmentHandler(w http.ResponseWriter, r *http.Request, db *g
 	if err != nil {
 		return sdk.WrapError(err, "addGroupsInEnvironmentHandler> Cannot load environment %s", envName)
 	}
+	c.AuditBefore = audit.Permissions(db, "environment", env.ID)
 
 	tx, errB := db.Begin()
 	if errB != nil {
@@ -147,6 +151,7 @@ func addGroupsInEnvironmentHandler(w http.ResponseWriter, r *http.Request, db *g
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "addGroupsInEnvironmentHandler: Cannot commit transaction")
 	}
+	c.AuditAfter = audit.Permissions(db, "environment", env.ID)
 
 	envUpdated, errL := environment.LoadEnvironmentByName(db, key, envName)
 	if errL != nil {
@@ -172,6 +177,7 @@ func addGroupInEnvironmentHandler(w http.ResponseWriter, r *http.Request, db *go
 		log.Warning("addGroupInEnvironmentHandler: Cannot load %s: %s\n", envName, err)
 		return err
 	}
+	c.AuditBefore = audit.Permissions(db, "environment", env.ID)
 
 	g, err := group.LoadGroup(db, groupPermission.Group.Name)
 	if err != nil {
@@ -194,6 +200,7 @@ func addGroupInEnvironmentHandler(w http.ResponseWriter, r *http.Request, db *go
 		log.Warning("addGroupInEnvironmentHandler: Cannot add group %s in environment %s:  %s\n", g.Name, env.Name, err)
 		return err
 	}
+	c.AuditAfter = audit.Permissions(db, "environment", env.ID)
 
 	return nil
 }
@@ -214,6 +221,7 @@ func deleteGroupFromEnvironmentHandler(w http.ResponseWriter, r *http.Request, d
 	if errE != nil {
 		return sdk.WrapError(errE, "deleteGroupFromEnvironmentHandler: Cannot load environment")
 	}
+	c.AuditBefore = audit.Permissions(db, "environment", env.ID)
 
 	tx, errT := db.Begin()
 	if errT != nil {
@@ -236,6 +244,7 @@ func deleteGroupFromEnvironmentHandler(w http.ResponseWriter, r *http.Request, d
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(errT, "deleteGroupFromEnvironmentHandler: Cannot commit transaction")
 	}
+	c.AuditAfter = audit.Permissions(db, "environment", env.ID)
 
 	return nil
 }
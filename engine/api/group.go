@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
@@ -122,6 +124,13 @@ func updateGroupHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap,
 		return sdk.WrapError(errl, "updateGroupHandler: Cannot load %s", oldName)
 	}
 
+	// Only CDS administrators can change the concurrency quota of a group
+	if !c.User.Admin {
+		updatedGroup.MaxConcurrentJobs = g.MaxConcurrentJobs
+	} else if updatedGroup.MaxConcurrentJobs < 0 {
+		return sdk.WrapError(sdk.ErrWrongRequest, "updateGroupHandler: Invalid max concurrent jobs %d", updatedGroup.MaxConcurrentJobs)
+	}
+
 	updatedGroup.ID = g.ID
 	tx, errb := db.Begin()
 	if errb != nil {
@@ -204,6 +213,11 @@ func addGroupHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *
 		return sdk.WrapError(err, "addGroupHandler> cannot unmarshal")
 	}
 
+	// Only CDS administrators can set the concurrency quota of a group
+	if !c.User.Admin || g.MaxConcurrentJobs < 0 {
+		g.MaxConcurrentJobs = 0
+	}
+
 	tx, errb := db.Begin()
 	if errb != nil {
 		return sdk.WrapError(errb, "addGroupHandler> cannot begin tx")
@@ -256,6 +270,7 @@ func removeUserFromGroupHandler(w http.ResponseWriter, r *http.Request, db *gorp
 		return sdk.WrapError(sdk.ErrWrongRequest, "User %s is not in group %s", userName, name)
 	}
 
+	c.AuditBefore = fmt.Sprintf("user=%s", userName)
 	if err := group.DeleteUserFromGroup(db, g.ID, userID); err != nil {
 		return sdk.WrapError(err, "removeUserFromGroupHandler: Cannot delete user %s from group %s", userName, g.Name)
 	}
@@ -304,6 +319,7 @@ func addUserInGroup(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *b
 	if err := tx.Commit(); err != nil {
 		return err
 	}
+	c.AuditAfter = fmt.Sprintf("users=%s", strings.Join(users, ","))
 
 	return nil
 }
@@ -327,6 +343,7 @@ func setUserGroupAdminHandler(w http.ResponseWriter, r *http.Request, db *gorp.D
 	if err := group.SetUserGroupAdmin(db, g.ID, userID); err != nil {
 		return sdk.WrapError(err, "setUserGroupAdminHandler: cannot set user group admin")
 	}
+	c.AuditAfter = fmt.Sprintf("user=%s admin=true", userName)
 
 	return nil
 }
@@ -350,6 +367,7 @@ func removeUserGroupAdminHandler(w http.ResponseWriter, r *http.Request, db *gor
 	if err := group.RemoveUserGroupAdmin(db, g.ID, userID); err != nil {
 		return sdk.WrapError(err, "removeUserGroupAdminHandler: cannot remove user group admin privilege")
 	}
+	c.AuditAfter = fmt.Sprintf("user=%s admin=false", userName)
 
 	return nil
 }
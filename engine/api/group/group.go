@@ -73,9 +73,10 @@ func AddGroup(db gorp.SqlExecutor, group *sdk.Group) (int64, bool, error) {
 
 // LoadGroup retrieves group informations from database
 func LoadGroup(db gorp.SqlExecutor, name string) (*sdk.Group, error) {
-	query := `SELECT "group".id FROM "group" WHERE "group".name = $1`
+	query := `SELECT "group".id, "group".max_concurrent_jobs FROM "group" WHERE "group".name = $1`
 	var groupID int64
-	err := db.QueryRow(query, name).Scan(&groupID)
+	var maxConcurrentJobs int
+	err := db.QueryRow(query, name).Scan(&groupID, &maxConcurrentJobs)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, sdk.ErrGroupNotFound
@@ -83,8 +84,9 @@ func LoadGroup(db gorp.SqlExecutor, name string) (*sdk.Group, error) {
 		return nil, err
 	}
 	return &sdk.Group{
-		ID:   groupID,
-		Name: name,
+		ID:                groupID,
+		Name:              name,
+		MaxConcurrentJobs: maxConcurrentJobs,
 	}, nil
 }
 
@@ -126,7 +128,7 @@ func LoadUserGroup(db gorp.SqlExecutor, group *sdk.Group) error {
 func LoadGroups(db gorp.SqlExecutor) ([]sdk.Group, error) {
 	groups := []sdk.Group{}
 
-	query := `SELECT * FROM "group" ORDER BY name`
+	query := `SELECT id, name, max_concurrent_jobs FROM "group" ORDER BY name`
 	rows, err := db.Query(query)
 	if err != nil {
 		return nil, err
@@ -136,10 +138,11 @@ func LoadGroups(db gorp.SqlExecutor) ([]sdk.Group, error) {
 	for rows.Next() {
 		var id int64
 		var name string
-		if err := rows.Scan(&id, &name); err != nil {
+		var maxConcurrentJobs int
+		if err := rows.Scan(&id, &name, &maxConcurrentJobs); err != nil {
 			return nil, err
 		}
-		g := sdk.Group{ID: id, Name: name}
+		g := sdk.Group{ID: id, Name: name, MaxConcurrentJobs: maxConcurrentJobs}
 		groups = append(groups, g)
 	}
 	return groups, nil
@@ -306,8 +309,8 @@ func DeleteGroupUserByGroup(db gorp.SqlExecutor, group *sdk.Group) error {
 
 // UpdateGroup updates group informations in database
 func UpdateGroup(db gorp.SqlExecutor, g *sdk.Group, oldName string) error {
-	query := `UPDATE "group" set name=$1 WHERE name=$2`
-	_, err := db.Exec(query, g.Name, oldName)
+	query := `UPDATE "group" set name=$1, max_concurrent_jobs=$3 WHERE name=$2`
+	_, err := db.Exec(query, g.Name, oldName, g.MaxConcurrentJobs)
 
 	if err != nil && strings.Contains(err.Error(), "idx_group_name") {
 		return sdk.ErrGroupExists
@@ -318,8 +321,8 @@ func UpdateGroup(db gorp.SqlExecutor, g *sdk.Group, oldName string) error {
 
 // InsertGroup insert given group into given database
 func InsertGroup(db gorp.SqlExecutor, g *sdk.Group) error {
-	query := `INSERT INTO "group" (name) VALUES($1) RETURNING id`
-	err := db.QueryRow(query, g.Name).Scan(&g.ID)
+	query := `INSERT INTO "group" (name, max_concurrent_jobs) VALUES($1, $2) RETURNING id`
+	err := db.QueryRow(query, g.Name, g.MaxConcurrentJobs).Scan(&g.ID)
 	return err
 }
 
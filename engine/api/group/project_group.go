@@ -3,6 +3,7 @@ package group
 import (
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -76,3 +77,50 @@ func CheckGroupInProject(db gorp.SqlExecutor, projectID, groupID int64) (bool, e
 	}
 	return false, nil
 }
+
+// LoadConcurrencyQuotas loads groups with a concurrency quota. It returns the groups with a quota and
+// a read/write/execute permission for each project, and the quota of each group.
+func LoadConcurrencyQuotas(db gorp.SqlExecutor) (map[int64][]int64, map[int64]int, error) {
+	query := `
+		SELECT project_group.project_id, "group".id, "group".max_concurrent_jobs
+		FROM "group"
+		JOIN project_group ON project_group.group_id = "group".id
+		WHERE "group".max_concurrent_jobs > 0 AND project_group.role >= $1
+	`
+	rows, err := db.Query(query, permission.PermissionReadWriteExecute)
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "LoadConcurrencyQuotas> Unable to load quotas")
+	}
+	defer rows.Close()
+
+	projectGroups := map[int64][]int64{}
+	quotas := map[int64]int{}
+	for rows.Next() {
+		var projectID, groupID int64
+		var max int
+		if err := rows.Scan(&projectID, &groupID, &max); err != nil {
+			return nil, nil, sdk.WrapError(err, "LoadConcurrencyQuotas> Unable to scan quota")
+		}
+		projectGroups[projectID] = append(projectGroups[projectID], groupID)
+		quotas[groupID] = max
+	}
+	return projectGroups, quotas, nil
+}
+
+// LockConcurrencyQuotas locks the groups with a concurrency quota and a read/write/execute permission on the project
+// until the end of the transaction, and returns the number of locked groups
+func LockConcurrencyQuotas(db gorp.SqlExecutor, projectID int64) (int, error) {
+	query := `
+		SELECT "group".id
+		FROM "group"
+		JOIN project_group ON project_group.group_id = "group".id
+		WHERE "group".max_concurrent_jobs > 0 AND project_group.role >= $1 AND project_group.project_id = $2
+		ORDER BY "group".id
+		FOR UPDATE OF "group"
+	`
+	var ids []int64
+	if _, err := db.Select(&ids, query, permission.PermissionReadWriteExecute, projectID); err != nil {
+		return 0, sdk.WrapError(err, "LockConcurrencyQuotas> Unable to lock groups of project %d", projectID)
+	}
+	return len(ids), nil
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/action"
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/history"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+// historyObject returns the type, the project key and the id of the pipeline, application, environment,
+// workflow or action targeted by the route
+func historyObject(db gorp.SqlExecutor, r *http.Request, c *businesscontext.Ctx) (string, string, int64, error) {
+	vars := mux.Vars(r)
+	key := vars["key"]
+
+	if name, ok := vars["permPipelineKey"]; ok {
+		p, err := pipeline.LoadPipeline(db, key, name, false)
+		if err != nil {
+			return "", "", 0, sdk.WrapError(err, "historyObject> Cannot load pipeline %s", name)
+		}
+		return sdk.HistoryPipeline, key, p.ID, nil
+	}
+	if name, ok := vars["permApplicationName"]; ok {
+		app, err := application.LoadByName(db, key, name, c.User)
+		if err != nil {
+			return "", "", 0, sdk.WrapError(err, "historyObject> Cannot load application %s", name)
+		}
+		return sdk.HistoryApplication, key, app.ID, nil
+	}
+	if name, ok := vars["permEnvironmentName"]; ok {
+		env, err := environment.LoadEnvironmentByName(db, key, name)
+		if err != nil {
+			return "", "", 0, sdk.WrapError(err, "historyObject> Cannot load environment %s", name)
+		}
+		return sdk.HistoryEnvironment, key, env.ID, nil
+	}
+
+	if name, ok := vars["permActionName"]; ok {
+		a, err := action.LoadPublicAction(db, name)
+		if err != nil {
+			return "", "", 0, sdk.WrapError(err, "historyObject> Cannot load action %s", name)
+		}
+		return sdk.HistoryAction, "", a.ID, nil
+	}
+
+	key = vars["permProjectKey"]
+	if name, ok := vars["actionName"]; ok {
+		a, err := loadProjectAction(db, key, name, c)
+		if err != nil {
+			return "", "", 0, err
+		}
+		return sdk.HistoryAction, key, a.ID, nil
+	}
+
+	name := vars["workflowName"]
+	wf, err := workflow.Load(db, key, name, c.User)
+	if err != nil {
+		return "", "", 0, sdk.WrapError(err, "historyObject> Cannot load workflow %s", name)
+	}
+	return sdk.HistoryWorkflow, key, wf.ID, nil
+}
+
+func getHistoryHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	objectType, _, id, err := historyObject(db, r, c)
+	if err != nil {
+		return err
+	}
+
+	versions, errH := history.LoadAll(db, objectType, id)
+	if errH != nil {
+		return sdk.WrapError(errH, "getHistoryHandler> Cannot load history")
+	}
+
+	if objectType == sdk.HistoryAction {
+		deprecated, err := action.LoadDeprecatedVersions(db, id)
+		if err != nil {
+			return sdk.WrapError(err, "getHistoryHandler> Cannot load deprecated versions")
+		}
+		for i := range versions {
+			versions[i].Deprecated = deprecated[versions[i].Version]
+		}
+	}
+
+	return WriteJSON(w, r, versions, http.StatusOK)
+}
+
+func getHistoryVersionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	version, errV := requestVarInt(r, "version")
+	if errV != nil {
+		return errV
+	}
+
+	objectType, _, id, err := historyObject(db, r, c)
+	if err != nil {
+		return err
+	}
+
+	h, errH := history.LoadVersion(db, objectType, id, version)
+	if errH != nil {
+		return sdk.WrapError(errH, "getHistoryVersionHandler> Cannot load version %d", version)
+	}
+
+	if objectType == sdk.HistoryAction {
+		deprecated, err := action.LoadDeprecatedVersions(db, id)
+		if err != nil {
+			return sdk.WrapError(err, "getHistoryVersionHandler> Cannot load deprecated versions")
+		}
+		h.Deprecated = deprecated[version]
+	}
+
+	return WriteJSON(w, r, h, http.StatusOK)
+}
+
+// getHistoryDiffHandler returns the changes from a version to the version given by the query parameter "to",
+// the last version by default
+func getHistoryDiffHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	version, errV := requestVarInt(r, "version")
+	if errV != nil {
+		return errV
+	}
+
+	objectType, _, id, err := historyObject(db, r, c)
+	if err != nil {
+		return err
+	}
+
+	var to int64
+	if toS := r.FormValue("to"); toS != "" {
+		var errTo error
+		to, errTo = strconv.ParseInt(toS, 10, 64)
+		if errTo != nil {
+			return sdk.ErrWrongRequest
+		}
+	} else {
+		last, errL := history.LoadLastVersion(db, objectType, id)
+		if errL != nil {
+			return sdk.WrapError(errL, "getHistoryDiffHandler> Cannot load last version")
+		}
+		to = last.Version
+	}
+
+	diff, errD := history.Diff(db, objectType, id, version, to)
+	if errD != nil {
+		return sdk.WrapError(errD, "getHistoryDiffHandler> Cannot compare versions %d and %d", version, to)
+	}
+
+	return WriteJSON(w, r, diff, http.StatusOK)
+}
+
+// postHistoryRestoreHandler restores a version of the object. The restoration is recorded as a new version.
+func postHistoryRestoreHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	version, errV := requestVarInt(r, "version")
+	if errV != nil {
+		return errV
+	}
+
+	objectType, key, id, err := historyObject(db, r, c)
+	if err != nil {
+		return err
+	}
+
+	proj, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "postHistoryRestoreHandler> Cannot load project %s", key)
+	}
+
+	h, errH := history.LoadVersion(db, objectType, id, version)
+	if errH != nil {
+		return sdk.WrapError(errH, "postHistoryRestoreHandler> Cannot load version %d", version)
+	}
+
+	tx, errB := db.Begin()
+	if errB != nil {
+		return sdk.WrapError(errB, "postHistoryRestoreHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	switch objectType {
+	case sdk.HistoryPipeline:
+		var p sdk.Pipeline
+		if err := json.Unmarshal(h.Snapshot, &p); err != nil {
+			return sdk.WrapError(err, "postHistoryRestoreHandler> Cannot read version %d", version)
+		}
+		p.ID = id
+		if err := pipeline.Restore(tx, proj, &p, c.User); err != nil {
+			return sdk.WrapError(err, "postHistoryRestoreHandler> Cannot restore pipeline %s", h.ObjectName)
+		}
+	case sdk.HistoryApplication:
+		var snapshot sdk.Application
+		if err := json.Unmarshal(h.Snapshot, &snapshot); err != nil {
+			return sdk.WrapError(err, "postHistoryRestoreHandler> Cannot read version %d", version)
+		}
+		app, err := application.LoadByID(tx, id, c.User)
+		if err != nil {
+			return sdk.WrapError(err, "postHistoryRestoreHandler> Cannot load application %d", id)
+		}
+		app.Description = snapshot.Description
+		app.RepositoryFullname = snapshot.RepositoryFullname
+		app.Metadata = snapshot.Metadata
+		if err := application.Update(tx, app, c.User); err != nil {
+			return sdk.WrapError(err, "postHistoryRestoreHandler> Cannot restore application %s", app.Name)
+		}
+	case sdk.HistoryEnvironment:
+		var snapshot sdk.Environment
+		if err := json.Unmarshal(h.Snapshot, &snapshot); err != nil {
+			return sdk.WrapError(err, "postHistoryRestoreHandler> Cannot read version %d", version)
+		}
+		env, err := environment.LoadEnvironmentByID(tx, id)
+		if err != nil {
+			return sdk.WrapError(err, "postHistoryRestoreHandler> Cannot load environment %d", id)
+		}
+		env.Exclusive = snapshot.Exclusive
+		env.ExclusiveApplications = snapshot.ExclusiveApplications
+		env.LockPolicy = snapshot.LockPolicy
+		if err := environment.UpdateLockSettings(tx, env); err != nil {
+			return sdk.WrapError(err, "postHistoryRestoreHandler> Cannot restore environment %s", env.Name)
+		}
+		if err := environment.UpdateLastModified(tx, c.User, env); err != nil {
+			return sdk.WrapError(err, "postHistoryRestoreHandler> Cannot update environment %s", env.Name)
+		}
+	case sdk.HistoryWorkflow:
+		var wf sdk.Workflow
+		if err := json.Unmarshal(h.Snapshot, &wf); err != nil {
+			return sdk.WrapError(err, "postHistoryRestoreHandler> Cannot read version %d", version)
+		}
+		oldW, err := workflow.LoadByID(tx, id, c.User)
+		if err != nil {
+			return sdk.WrapError(err, "postHistoryRestoreHandler> Cannot load workflow %d", id)
+		}
+		if wf.Root == nil {
+			return sdk.ErrWorkflowInvalidRoot
+		}
+		wf.ID = oldW.ID
+		wf.Name = oldW.Name
+		wf.RootID = oldW.RootID
+		wf.Root.ID = oldW.RootID
+		wf.ProjectID = proj.ID
+		wf.ProjectKey = proj.Key
+		if err := workflow.Update(tx, &wf, oldW, c.User); err != nil {
+			return sdk.WrapError(err, "postHistoryRestoreHandler> Cannot restore workflow %s", oldW.Name)
+		}
+	}
+
+	if err := project.UpdateLastModified(tx, c.User, proj); err != nil {
+		return sdk.WrapError(err, "postHistoryRestoreHandler> Cannot update project last modified date")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "postHistoryRestoreHandler> Cannot commit transaction")
+	}
+
+	last, errL := history.LoadLastVersion(db, objectType, id)
+	if errL != nil {
+		return sdk.WrapError(errL, "postHistoryRestoreHandler> Cannot load last version")
+	}
+	return WriteJSON(w, r, last, http.StatusOK)
+}
@@ -0,0 +1,12 @@
+package history
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbHistory sdk.History
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbHistory{}, "object_history", true, "id"))
+}
@@ -0,0 +1,124 @@
+package history
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// columns are the columns of a version without its snapshot. The versions of the public actions have no project.
+const columns = "id, coalesce(project_id, 0) as project_id, object_type, object_id, object_name, version, author, created"
+
+// Record stores a new version of the object, unless its snapshot does not differ from the last version. The
+// projectID of a public object is 0.
+func Record(db gorp.SqlExecutor, objectType string, projectID, objectID int64, objectName string, snapshot interface{}, u *sdk.User) error {
+	btes, err := json.Marshal(snapshot)
+	if err != nil {
+		return sdk.WrapError(err, "history.Record> Unable to marshal %s %s", objectType, objectName)
+	}
+
+	last, err := LoadLastVersion(db, objectType, objectID)
+	if err != nil && err != sdk.ErrHistoryNotFound {
+		return sdk.WrapError(err, "history.Record> Unable to load last version of %s %s", objectType, objectName)
+	}
+
+	h := dbHistory{
+		ProjectID:  projectID,
+		ObjectType: objectType,
+		ObjectID:   objectID,
+		ObjectName: objectName,
+		Version:    1,
+		Created:    time.Now(),
+		Snapshot:   btes,
+	}
+	if last != nil {
+		changes, err := sdk.DiffSnapshots(last.Snapshot, btes)
+		if err != nil {
+			return sdk.WrapError(err, "history.Record> Unable to compare %s %s with its last version", objectType, objectName)
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		h.Version = last.Version + 1
+	}
+	if u != nil {
+		h.Author = u.Username
+	}
+
+	query := `insert into object_history (project_id, object_type, object_id, object_name, version, author, created, snapshot)
+	values (nullif($1::bigint, 0), $2, $3, $4, $5, $6, $7, $8) returning id`
+	if err := db.QueryRow(query, h.ProjectID, h.ObjectType, h.ObjectID, h.ObjectName, h.Version, h.Author, h.Created, btes).Scan(&h.ID); err != nil {
+		return sdk.WrapError(err, "history.Record> Unable to insert version %d of %s %s", h.Version, objectType, objectName)
+	}
+	return nil
+}
+
+// LoadAll loads all the versions of an object, without their snapshots, last version first
+func LoadAll(db gorp.SqlExecutor, objectType string, objectID int64) ([]sdk.History, error) {
+	var res []dbHistory
+	query := "select " + columns + " from object_history where object_type = $1 and object_id = $2 order by version desc"
+	if _, err := db.Select(&res, query, objectType, objectID); err != nil {
+		return nil, sdk.WrapError(err, "history.LoadAll> Unable to load history of %s %d", objectType, objectID)
+	}
+	versions := make([]sdk.History, len(res))
+	for i := range res {
+		versions[i] = sdk.History(res[i])
+	}
+	return versions, nil
+}
+
+// LoadVersion loads a version of an object, with its snapshot
+func LoadVersion(db gorp.SqlExecutor, objectType string, objectID, version int64) (*sdk.History, error) {
+	return load(db, "select "+columns+" from object_history where object_type = $1 and object_id = $2 and version = $3", objectType, objectID, version)
+}
+
+// LoadLastVersion loads the last version of an object, with its snapshot
+func LoadLastVersion(db gorp.SqlExecutor, objectType string, objectID int64) (*sdk.History, error) {
+	return load(db, "select "+columns+" from object_history where object_type = $1 and object_id = $2 order by version desc limit 1", objectType, objectID)
+}
+
+// Diff returns the changes between two versions of an object
+func Diff(db gorp.SqlExecutor, objectType string, objectID, from, to int64) (*sdk.HistoryDiff, error) {
+	hFrom, err := LoadVersion(db, objectType, objectID, from)
+	if err != nil {
+		return nil, sdk.WrapError(err, "history.Diff> Unable to load version %d", from)
+	}
+	hTo, err := LoadVersion(db, objectType, objectID, to)
+	if err != nil {
+		return nil, sdk.WrapError(err, "history.Diff> Unable to load version %d", to)
+	}
+
+	changes, err := sdk.DiffSnapshots(hFrom.Snapshot, hTo.Snapshot)
+	if err != nil {
+		return nil, sdk.WrapError(err, "history.Diff> Unable to compare versions %d and %d", from, to)
+	}
+	return &sdk.HistoryDiff{
+		ObjectType: objectType,
+		ObjectName: hTo.ObjectName,
+		From:       from,
+		To:         to,
+		Changes:    changes,
+	}, nil
+}
+
+func load(db gorp.SqlExecutor, query string, args ...interface{}) (*sdk.History, error) {
+	var dbh dbHistory
+	if err := db.SelectOne(&dbh, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrHistoryNotFound
+		}
+		return nil, sdk.WrapError(err, "history.load> Unable to load version")
+	}
+	h := sdk.History(dbh)
+
+	var snapshot []byte
+	if err := db.QueryRow("select snapshot from object_history where id = $1", h.ID).Scan(&snapshot); err != nil {
+		return nil, sdk.WrapError(err, "history.load> Unable to load snapshot of version %d", h.Version)
+	}
+	h.Snapshot = snapshot
+	return &h, nil
+}
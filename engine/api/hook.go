@@ -41,6 +41,7 @@ func receiveHook(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *busi
 		ProjectKey: r.FormValue("project"),
 		Repository: r.FormValue("name"),
 		Branch:     r.FormValue("branch"),
+		From:       r.FormValue("from"),
 		Hash:       r.FormValue("hash"),
 		Author:     r.FormValue("author"),
 		Message:    r.FormValue("message"),
@@ -300,7 +301,7 @@ func processHook(db *gorp.DbMap, h hook.ReceivedHook) error {
 		}
 		projectData.Variable = projectsVar
 
-		pb, err := application.TriggerPipeline(tx, hooks[i], h.Branch, h.Hash, h.Author, p, projectData)
+		pb, err := application.TriggerPipeline(tx, hooks[i], h.Branch, h.From, h.Hash, h.Author, p, projectData)
 		if err != nil {
 			log.Warning("processHook> cannot trigger pipeline %d: %s\n", hooks[i].Pipeline.ID, err)
 			return err
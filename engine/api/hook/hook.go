@@ -11,6 +11,7 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/sdk"
@@ -31,6 +32,7 @@ type ReceivedHook struct {
 	ProjectKey string
 	Repository string
 	Branch     string
+	From       string
 	Hash       string
 	Author     string
 	Message    string
@@ -38,7 +40,7 @@ type ReceivedHook struct {
 }
 
 // HookLink format in stash/bitbucket
-const HookLink = "/hook?uid=%s&project=%s&name=%s&branch=${refChange.name}&hash=${refChange.toHash}&message=${refChange.type}&author=${user.name}"
+const HookLink = "/hook?uid=%s&project=%s&name=%s&branch=${refChange.name}&from=${refChange.fromHash}&hash=${refChange.toHash}&message=${refChange.type}&author=${user.name}"
 
 // InsertReceivedHook insert raw data received from public handler in database
 func InsertReceivedHook(db gorp.SqlExecutor, link string, data string) error {
@@ -54,9 +56,14 @@ func InsertReceivedHook(db gorp.SqlExecutor, link string, data string) error {
 
 // UpdateHook update the given hook
 func UpdateHook(db gorp.SqlExecutor, h sdk.Hook) error {
-	query := `UPDATE hook set pipeline_id=$1, kind=$2, host=$3, project=$4, repository=$5, application_id=$6, enabled=$7 WHERE id=$8`
+	query := `UPDATE hook set pipeline_id=$1, kind=$2, host=$3, project=$4, repository=$5, application_id=$6, enabled=$7, filter=$9 WHERE id=$8`
 
-	res, err := db.Exec(query, h.Pipeline.ID, h.Kind, h.Host, h.Project, h.Repository, h.ApplicationID, h.Enabled, h.ID)
+	filter, err := gorpmapping.JSONToNullString(h.Filter)
+	if err != nil {
+		return sdk.WrapError(err, "UpdateHook> Unable to marshal filter")
+	}
+
+	res, err := db.Exec(query, h.Pipeline.ID, h.Kind, h.Host, h.Project, h.Repository, h.ApplicationID, h.Enabled, h.ID, filter)
 	if err != nil {
 		return err
 	}
@@ -72,7 +79,7 @@ func UpdateHook(db gorp.SqlExecutor, h sdk.Hook) error {
 
 // InsertHook add link between git repository and pipeline in database
 func InsertHook(db gorp.SqlExecutor, h *sdk.Hook) error {
-	query := `INSERT INTO hook (pipeline_id, kind, host, project, repository, application_id, enabled, uid) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	query := `INSERT INTO hook (pipeline_id, kind, host, project, repository, application_id, enabled, uid, filter) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
 
 	// Generate UID
 	uid, err := generateHash()
@@ -81,7 +88,12 @@ func InsertHook(db gorp.SqlExecutor, h *sdk.Hook) error {
 	}
 	h.UID = uid
 
-	err = db.QueryRow(query, h.Pipeline.ID, h.Kind, h.Host, h.Project, h.Repository, h.ApplicationID, h.Enabled, h.UID).Scan(&h.ID)
+	filter, err := gorpmapping.JSONToNullString(h.Filter)
+	if err != nil {
+		return sdk.WrapError(err, "InsertHook> Unable to marshal filter")
+	}
+
+	err = db.QueryRow(query, h.Pipeline.ID, h.Kind, h.Host, h.Project, h.Repository, h.ApplicationID, h.Enabled, h.UID, filter).Scan(&h.ID)
 	if err != nil {
 		return err
 	}
@@ -92,20 +104,21 @@ func InsertHook(db gorp.SqlExecutor, h *sdk.Hook) error {
 // LoadHook loads a single hook
 func LoadHook(db gorp.SqlExecutor, id int64) (sdk.Hook, error) {
 	h := sdk.Hook{ID: id}
-	query := `SELECT application_id, pipeline_id, kind, host, project, repository, enabled FROM hook WHERE id = $1`
+	query := `SELECT application_id, pipeline_id, kind, host, project, repository, enabled, filter FROM hook WHERE id = $1`
 
-	err := db.QueryRow(query, id).Scan(&h.ApplicationID, &h.Pipeline.ID, &h.Kind, &h.Host, &h.Project, &h.Repository, &h.Enabled)
+	var filter sql.NullString
+	err := db.QueryRow(query, id).Scan(&h.ApplicationID, &h.Pipeline.ID, &h.Kind, &h.Host, &h.Project, &h.Repository, &h.Enabled, &filter)
 	if err != nil {
 		return h, err
 	}
 
-	return h, nil
+	return h, gorpmapping.JSONNullString(filter, &h.Filter)
 }
 
 //FindHook loads a hook from its attributes
 func FindHook(db gorp.SqlExecutor, applicationID, pipelineID int64, kind, host, project, repository string) (sdk.Hook, error) {
 	h := sdk.Hook{}
-	query := `SELECT 	id, application_id, pipeline_id, kind, host, project, repository, uid
+	query := `SELECT 	id, application_id, pipeline_id, kind, host, project, repository, uid, filter
 						FROM 		hook
 						WHERE  	application_id=$1
 						AND 		pipeline_id=$2
@@ -114,11 +127,12 @@ func FindHook(db gorp.SqlExecutor, applicationID, pipelineID int64, kind, host,
 						AND 		project=$5
 						AND 		repository=$6`
 
-	err := db.QueryRow(query, applicationID, pipelineID, kind, host, project, repository).Scan(&h.ID, &h.ApplicationID, &h.Pipeline.ID, &h.Kind, &h.Host, &h.Project, &h.Repository, &h.UID)
+	var filter sql.NullString
+	err := db.QueryRow(query, applicationID, pipelineID, kind, host, project, repository).Scan(&h.ID, &h.ApplicationID, &h.Pipeline.ID, &h.Kind, &h.Host, &h.Project, &h.Repository, &h.UID, &filter)
 	if err != nil {
 		return h, err
 	}
-	return h, nil
+	return h, gorpmapping.JSONNullString(filter, &h.Filter)
 }
 
 // DeleteHook removes hook from database
@@ -143,7 +157,7 @@ func DeleteHook(db gorp.SqlExecutor, id int64) error {
 // LoadApplicationHooks will load all hooks related to given application
 func LoadApplicationHooks(db gorp.SqlExecutor, applicationID int64) ([]sdk.Hook, error) {
 	hooks := []sdk.Hook{}
-	query := `SELECT hook.id, hook.kind, hook.host, hook.project, hook.repository, hook.enabled, hook.uid, pipeline.id, pipeline.name
+	query := `SELECT hook.id, hook.kind, hook.host, hook.project, hook.repository, hook.enabled, hook.uid, hook.filter, pipeline.id, pipeline.name
 		  FROM hook
 		  JOIN pipeline ON pipeline.id = hook.pipeline_id
 		  WHERE application_id= $1
@@ -157,11 +171,15 @@ func LoadApplicationHooks(db gorp.SqlExecutor, applicationID int64) ([]sdk.Hook,
 
 	for rows.Next() {
 		var h sdk.Hook
+		var filter sql.NullString
 		h.ApplicationID = applicationID
-		err = rows.Scan(&h.ID, &h.Kind, &h.Host, &h.Project, &h.Repository, &h.Enabled, &h.UID, &h.Pipeline.ID, &h.Pipeline.Name)
+		err = rows.Scan(&h.ID, &h.Kind, &h.Host, &h.Project, &h.Repository, &h.Enabled, &h.UID, &filter, &h.Pipeline.ID, &h.Pipeline.Name)
 		if err != nil {
 			return hooks, err
 		}
+		if err := gorpmapping.JSONNullString(filter, &h.Filter); err != nil {
+			return hooks, err
+		}
 		link := apiURL + HookLink
 		h.Link = fmt.Sprintf(link, h.UID, h.Project, h.Repository)
 		hooks = append(hooks, h)
@@ -172,7 +190,7 @@ func LoadApplicationHooks(db gorp.SqlExecutor, applicationID int64) ([]sdk.Hook,
 
 // LoadPipelineHooks will load all hooks related to given pipeline
 func LoadPipelineHooks(db gorp.SqlExecutor, pipelineID int64, applicationID int64) ([]sdk.Hook, error) {
-	query := `SELECT id, kind, host, project, repository, uid, enabled FROM hook WHERE pipeline_id = $1 AND application_id= $2`
+	query := `SELECT id, kind, host, project, repository, uid, enabled, filter FROM hook WHERE pipeline_id = $1 AND application_id= $2`
 
 	rows, err := db.Query(query, pipelineID, applicationID)
 	if err != nil {
@@ -183,9 +201,13 @@ func LoadPipelineHooks(db gorp.SqlExecutor, pipelineID int64, applicationID int6
 	var hooks []sdk.Hook
 	for rows.Next() {
 		var h sdk.Hook
+		var filter sql.NullString
 		h.Pipeline.ID = pipelineID
 		h.ApplicationID = applicationID
-		if err = rows.Scan(&h.ID, &h.Kind, &h.Host, &h.Project, &h.Repository, &h.UID, &h.Enabled); err != nil {
+		if err = rows.Scan(&h.ID, &h.Kind, &h.Host, &h.Project, &h.Repository, &h.UID, &h.Enabled, &filter); err != nil {
+			return nil, err
+		}
+		if err := gorpmapping.JSONNullString(filter, &h.Filter); err != nil {
 			return nil, err
 		}
 		link := apiURL + HookLink
@@ -198,7 +220,7 @@ func LoadPipelineHooks(db gorp.SqlExecutor, pipelineID int64, applicationID int6
 
 // LoadHooks related to given repository
 func LoadHooks(db gorp.SqlExecutor, project string, repository string) ([]sdk.Hook, error) {
-	query := `SELECT id, pipeline_id, application_id, kind, host, enabled, uid FROM hook WHERE project = $1 AND repository = $2`
+	query := `SELECT id, pipeline_id, application_id, kind, host, enabled, uid, filter FROM hook WHERE project = $1 AND repository = $2`
 
 	rows, err := db.Query(query, project, repository)
 	if err != nil {
@@ -209,12 +231,16 @@ func LoadHooks(db gorp.SqlExecutor, project string, repository string) ([]sdk.Ho
 	var hooks []sdk.Hook
 	for rows.Next() {
 		var h sdk.Hook
+		var filter sql.NullString
 		h.Project = project
 		h.Repository = repository
-		err = rows.Scan(&h.ID, &h.Pipeline.ID, &h.ApplicationID, &h.Kind, &h.Host, &h.Enabled, &h.UID)
+		err = rows.Scan(&h.ID, &h.Pipeline.ID, &h.ApplicationID, &h.Kind, &h.Host, &h.Enabled, &h.UID, &filter)
 		if err != nil {
 			return nil, err
 		}
+		if err := gorpmapping.JSONNullString(filter, &h.Filter); err != nil {
+			return nil, err
+		}
 		hooks = append(hooks, h)
 	}
 
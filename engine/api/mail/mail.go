@@ -31,6 +31,21 @@ Regards,
 CDS Team
 `
 
+const templateApprovalRequest = `Hello,
+
+%s is waiting for your approval.
+
+To approve it:
+cdsctl approval approve %s %d
+
+To reject it:
+cdsctl approval reject %s %d
+
+Regards,
+--
+CDS Team
+`
+
 // Init initializes configuration
 func Init(user, password, from, host, port string, tls, disable bool) {
 	smtpUser = user
@@ -120,6 +135,27 @@ func SendMailVerifyToken(userMail, username, token, callback string) error {
 	return SendEmail(subject, &mailContent, userMail)
 }
 
+// SendMailApprovalRequest Send mail to ask approvers to approve a run
+func SendMailApprovalRequest(userMails []string, description, projectKey string, approvalID int64) error {
+	var mailContent bytes.Buffer
+	fmt.Fprintf(&mailContent, templateApprovalRequest, description, projectKey, approvalID, projectKey, approvalID)
+	subject := fmt.Sprintf("[CDS] %s is waiting for your approval", description)
+	if !smtpEnable {
+		fmt.Println("##### NO SMTP DISPLAY MAIL IN CONSOLE ######")
+		fmt.Printf("Subject:%s\n", subject)
+		fmt.Printf("Text:%s\n", mailContent.Bytes())
+		fmt.Println("##### END MAIL ######")
+		return nil
+	}
+	for _, m := range userMails {
+		content := bytes.NewBuffer(mailContent.Bytes())
+		if err := SendEmail(subject, content, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func getCallbackURL(username, token, callback string) string {
 	if callback == "cdscli" {
 		return fmt.Sprintf("cds user verify %s %s", username, token)
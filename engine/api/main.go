@@ -15,9 +15,13 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/ovh/cds/engine/api/action"
+	"github.com/ovh/cds/engine/api/actionsource"
+	"github.com/ovh/cds/engine/api/approval"
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/auth"
 	"github.com/ovh/cds/engine/api/bootstrap"
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/chatops"
 	"github.com/ovh/cds/engine/api/database"
 	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/engine/api/grpc"
@@ -29,6 +33,7 @@ import (
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/poller"
 	"github.com/ovh/cds/engine/api/queue"
+	"github.com/ovh/cds/engine/api/ratelimit"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/api/scheduler"
 	"github.com/ovh/cds/engine/api/secret"
@@ -188,6 +193,17 @@ var mainCmd = &cobra.Command{
 
 		//Intialize notification package
 		notification.Init(viper.GetString(viperURLAPI), baseURL)
+		chatops.Init(viper.GetString(viperChatOpsSlackSigningSecret))
+		audit.Init(viper.GetBool(viperAuditEventsEnabled), viper.GetStringSlice(viperAuditTrustedProxies))
+		auditEventsRetention = time.Duration(viper.GetInt(viperAuditRetention)) * 24 * time.Hour
+		ratelimit.Init(ratelimit.Config{
+			Enabled:  viper.GetBool(viperRateLimitEnabled),
+			Period:   viper.GetInt(viperRateLimitPeriod),
+			User:     viper.GetInt(viperRateLimitUser),
+			Token:    viper.GetInt(viperRateLimitToken),
+			Worker:   viper.GetInt(viperRateLimitWorker),
+			Hatchery: viper.GetInt(viperRateLimitHatchery),
+		})
 
 		// Initialize the auth driver
 		var authMode string
@@ -241,6 +257,7 @@ var mainCmd = &cobra.Command{
 		go workflow.Scheduler(ctx, database.GetDBMap)
 		go pipeline.AWOLPipelineKiller(ctx, database.GetDBMap)
 		go hatchery.Heartbeat(ctx, database.GetDBMap)
+		go approval.Notifier(ctx, database.GetDBMap)
 		go auditCleanerRoutine(ctx, database.GetDBMap)
 
 		go repositoriesmanager.ReceiveEvents(ctx, database.GetDBMap)
@@ -257,6 +274,16 @@ var mainCmd = &cobra.Command{
 			log.Warning("⚠ Repositories polling is disabled")
 		}
 
+		if !viper.GetBool(viperActionSourcesDisabled) {
+			delay := viper.GetInt(viperActionSourcesDelay)
+			if delay <= 0 {
+				delay = 15
+			}
+			go actionsource.Synchronizer(ctx, database.GetDBMap, time.Duration(delay)*time.Minute)
+		} else {
+			log.Warning("⚠ Action sources synchronization is disabled")
+		}
+
 		if !viper.GetBool(viperSchedulersDisabled) {
 			go scheduler.Initialize(ctx, 10, database.GetDBMap)
 		} else {
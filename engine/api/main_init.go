@@ -78,6 +78,18 @@ const (
 	viperVCSRepoBitbucketStatusDisabled = "vcs.repositories.bitbucket.statuses_disabled"
 	viperVCSRepoBitbucketConsumerKey    = "vcs.repositories.bitbucket.consumerkey"
 	viperVCSRepoBitbucketPrivateKey     = "vcs.repositories.bitbucket.privatekey"
+	viperChatOpsSlackSigningSecret      = "chatops.slack.signingsecret"
+	viperActionSourcesDisabled          = "actions.sources.disabled"
+	viperActionSourcesDelay             = "actions.sources.delay"
+	viperAuditRetention                 = "audit.retention"
+	viperAuditEventsEnabled             = "audit.events.enabled"
+	viperAuditTrustedProxies            = "audit.trustedproxies"
+	viperRateLimitEnabled               = "ratelimit.enabled"
+	viperRateLimitPeriod                = "ratelimit.period"
+	viperRateLimitUser                  = "ratelimit.user"
+	viperRateLimitToken                 = "ratelimit.token"
+	viperRateLimitWorker                = "ratelimit.worker"
+	viperRateLimitHatchery              = "ratelimit.hatchery"
 	vaultConfKey                        = "/secret/cds/conf"
 )
 
@@ -426,4 +438,40 @@ disabled = false #This is mainly for dev purpose, you should not have to change
     [vcs.repositories.bitbucket]
     statuses_disabled = false
     privatekey = ""
+
+########################
+# CDS ChatOps Settings #
+########################
+[chatops]
+    [chatops.slack]
+    signingsecret = "" # Signing secret of the Slack application sending the slash commands
+
+###############################
+# CDS Action Sources Settings #
+###############################
+[actions]
+    [actions.sources]
+    disabled = false # Set to true if you don't want CDS to synchronize the actions from the git repositories of the projects
+    delay = 15 # Delay in minutes between two synchronizations of an action source
+
+######################
+# CDS Audit Settings #
+######################
+[audit]
+retention = 90 # Number of days the audit events are kept, 0 to keep them forever
+trustedproxies = [] # Addresses or CIDR ranges of the proxies whose X-Forwarded-For header gives the source IP, e.g. ["10.0.0.0/8"]
+    [audit.events]
+    enabled = false # Set to true to also publish the audit events as CDS events
+
+###########################
+# CDS Rate Limit Settings #
+###########################
+# Maximum number of requests per period of each caller, 0 for no limit. The counters are shared through the cache.
+[ratelimit]
+enabled = false
+period = 60 # Duration of a period in seconds
+user = 600 # Per user
+token = 600 # Per persistent session token, used by the CLI and the scripts
+worker = 1200 # Per worker, the workers posting logs are never limited
+hatchery = 1200 # Per hatchery
 `
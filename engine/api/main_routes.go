@@ -17,15 +17,26 @@ func (router *Router) init() {
 	router.Handle("/action/{permActionName}", GET(getActionHandler), POST(addActionHandler), PUT(updateActionHandler), DELETE(deleteActionHandler))
 	router.Handle("/action/{actionName}/using", NeedAdmin(true), GET(getPipelinesUsingActionHandler))
 	router.Handle("/action/{actionID}/audit", NeedAdmin(true), GET(getActionAuditHandler))
+	router.Handle("/action/{permActionName}/history", GET(getHistoryHandler))
+	router.Handle("/action/{permActionName}/history/{version}", GET(getHistoryVersionHandler))
+	router.Handle("/action/{permActionName}/history/{version}/diff", GET(getHistoryDiffHandler))
+	router.Handle("/action/{permActionName}/history/{version}/deprecate", POST(postActionVersionDeprecateHandler))
 
 	// Admin
 	router.Handle("/admin/warning", NeedAdmin(true), DELETE(adminTruncateWarningsHandler))
+	router.Handle("/admin/audit", NeedAdmin(true), GET(getAdminAuditHandler))
+	router.Handle("/admin/audit/export", NeedAdmin(true), GET(getAdminAuditExportHandler))
+	router.Handle("/admin/backup", NeedAdmin(true), GET(getAdminBackupHandler))
+	router.Handle("/admin/restore", NeedAdmin(true), POST(postAdminRestoreHandler))
 	router.Handle("/admin/maintenance", NeedAdmin(true), POST(postAdminMaintenanceHandler), GET(getAdminMaintenanceHandler), DELETE(deleteAdminMaintenanceHandler))
 
 	// Action plugin
-	router.Handle("/plugin", NeedAdmin(true), POST(addPluginHandler), PUT(updatePluginHandler))
-	router.Handle("/plugin/{name}", NeedAdmin(true), DELETE(deletePluginHandler))
+	router.Handle("/plugin", NeedAdmin(true), GET(getPluginsHandler), POST(addPluginHandler), PUT(updatePluginHandler))
 	router.Handle("/plugin/download/{name}", GET(downloadPluginHandler))
+	router.Handle("/plugin/{name}", NeedAdmin(true), GET(getPluginVersionsHandler), DELETE(deletePluginHandler))
+	router.Handle("/plugin/{name}/binary", GET(getPluginBinaryHandler))
+	router.Handle("/plugin/{name}/version/{version}/promote", NeedAdmin(true), POST(postPromotePluginVersionHandler))
+	router.Handle("/plugin/{name}/version/{version}/deprecate", NeedAdmin(true), POST(postDeprecatePluginVersionHandler))
 
 	// Download file
 	router.ServeAbsoluteFile("/download/cli/x86_64", path.Join(viper.GetString(viperDownloadDirectory), "cds"), "cds")
@@ -49,6 +60,9 @@ func (router *Router) init() {
 	// Hooks
 	router.Handle("/hook", Auth(false) /* Public handler called by third parties */, POST(receiveHook))
 
+	// ChatOps
+	router.Handle("/chatops/slack", Auth(false) /* Public handler called by Slack, verified with the signing secret */, POST(postChatOpsSlackHandler))
+
 	// Overall health
 	router.Handle("/mon/status", Auth(false), GET(statusHandler))
 	router.Handle("/mon/smtp/ping", Auth(true), GET(smtpPingHandler))
@@ -74,6 +88,18 @@ func (router *Router) init() {
 	router.Handle("/project/{permProjectKey}/notifications", GET(getProjectNotificationsHandler))
 	router.Handle("/project/{permProjectKey}/keys", GET(getKeysInProjectHandler), POST(addKeyInProjectHandler))
 	router.Handle("/project/{permProjectKey}/keys/{name}", DELETE(deleteKeyInProjectHandler))
+	router.Handle("/project/{permProjectKey}/approval", GET(getApprovalsHandler))
+	router.Handle("/project/{permProjectKey}/approval/{id}", GET(getApprovalHandler), POSTEXECUTE(postApprovalVoteHandler))
+	router.Handle("/project/{permProjectKey}/deployment", GET(getProjectDeploymentsHandler))
+	router.Handle("/project/{permProjectKey}/action_source", GET(getProjectActionSourcesHandler), POST(addProjectActionSourceHandler))
+	router.Handle("/project/{permProjectKey}/action_source/{sourceName}", GET(getProjectActionSourceHandler), PUT(updateProjectActionSourceHandler), DELETE(deleteProjectActionSourceHandler))
+	router.Handle("/project/{permProjectKey}/action_source/{sourceName}/sync", POST(postProjectActionSourceSyncHandler))
+	router.Handle("/project/{permProjectKey}/action", GET(getProjectActionsHandler), POST(addProjectActionHandler))
+	router.Handle("/project/{permProjectKey}/action/{actionName}", GET(getProjectActionHandler), PUT(updateProjectActionHandler), DELETE(deleteProjectActionHandler))
+	router.Handle("/project/{permProjectKey}/action/{actionName}/history", GET(getHistoryHandler))
+	router.Handle("/project/{permProjectKey}/action/{actionName}/history/{version}", GET(getHistoryVersionHandler))
+	router.Handle("/project/{permProjectKey}/action/{actionName}/history/{version}/diff", GET(getHistoryDiffHandler))
+	router.Handle("/project/{permProjectKey}/action/{actionName}/history/{version}/deprecate", POST(postActionVersionDeprecateHandler))
 
 	// Application
 	router.Handle("/project/{key}/application/{permApplicationName}", GET(getApplicationHandler), PUT(updateApplicationHandler), DELETE(deleteApplicationHandler))
@@ -84,13 +110,21 @@ func (router *Router) init() {
 	router.Handle("/project/{key}/application/{permApplicationName}/group/{group}", PUT(updateGroupRoleOnApplicationHandler), DELETE(deleteGroupFromApplicationHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/history/branch", GET(getPipelineBuildBranchHistoryHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/history/env/deploy", GET(getApplicationDeployHistoryHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/history", GET(getHistoryHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/history/{version}", GET(getHistoryVersionHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/history/{version}/diff", GET(getHistoryDiffHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/history/{version}/restore", POST(postHistoryRestoreHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/notifications", POST(addNotificationsHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline", GET(getPipelinesInApplicationHandler), PUT(updatePipelinesToApplicationHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/attach", POST(attachPipelinesToApplicationHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}", POST(attachPipelineToApplicationHandler, DEPRECATED), PUT(updatePipelineToApplicationHandler, DEPRECATED), DELETE(removePipelineFromApplicationHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/notification", GET(getUserNotificationApplicationPipelineHandler), PUT(updateUserNotificationApplicationPipelineHandler), DELETE(deleteUserNotificationApplicationPipelineHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/notification/slack/test", POST(postSlackNotificationTestHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/scheduler", GET(getSchedulerApplicationPipelineHandler), POST(addSchedulerApplicationPipelineHandler), PUT(updateSchedulerApplicationPipelineHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/scheduler/{id}", DELETE(deleteSchedulerApplicationPipelineHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/release", GET(getApplicationReleasesHandler), POST(postApplicationReleaseHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/release/{version}", GET(getApplicationReleaseHandler))
+	router.Handle("/project/{key}/application/{permApplicationName}/release/{version}/artifact/{name}", GET(getApplicationReleaseArtifactHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/tree", GET(getApplicationTreeHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/tree/status", GET(getApplicationTreeStatusHandler))
 	router.Handle("/project/{key}/application/{permApplicationName}/variable", GET(getVariablesInApplicationHandler), PUT(updateVariablesInApplicationHandler))
@@ -124,6 +158,10 @@ func (router *Router) init() {
 	router.Handle("/project/{key}/pipeline/{permPipelineKey}/parameter", GET(getParametersInPipelineHandler), PUT(updateParametersInPipelineHandler, DEPRECATED))
 	router.Handle("/project/{key}/pipeline/{permPipelineKey}/parameter/{name}", POST(addParameterInPipelineHandler), PUT(updateParameterInPipelineHandler), DELETE(deleteParameterFromPipelineHandler))
 	router.Handle("/project/{key}/pipeline/{permPipelineKey}", GET(getPipelineHandler), PUT(updatePipelineHandler), DELETE(deletePipeline))
+	router.Handle("/project/{key}/pipeline/{permPipelineKey}/history", GET(getHistoryHandler))
+	router.Handle("/project/{key}/pipeline/{permPipelineKey}/history/{version}", GET(getHistoryVersionHandler))
+	router.Handle("/project/{key}/pipeline/{permPipelineKey}/history/{version}/diff", GET(getHistoryDiffHandler))
+	router.Handle("/project/{key}/pipeline/{permPipelineKey}/history/{version}/restore", POST(postHistoryRestoreHandler))
 	router.Handle("/project/{key}/pipeline/{permPipelineKey}/stage", POST(addStageHandler))
 	router.Handle("/project/{key}/pipeline/{permPipelineKey}/stage/move", POST(moveStageHandler))
 	router.Handle("/project/{key}/pipeline/{permPipelineKey}/stage/{stageID}", GET(getStageHandler), PUT(updateStageHandler), DELETE(deleteStageHandler))
@@ -131,17 +169,26 @@ func (router *Router) init() {
 	router.Handle("/project/{key}/pipeline/{permPipelineKey}/stage/{stageID}/job/{jobID}", PUT(updateJobHandler), DELETE(deleteJobHandler))
 
 	// Workflows
+	router.Handle("/workflow/deployment", GET(getDeploymentsHandler))
 	router.Handle("/project/{permProjectKey}/workflows", POST(postWorkflowHandler), GET(getWorkflowsHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}", GET(getWorkflowHandler), PUT(putWorkflowHandler), DELETE(deleteWorkflowHandler))
+	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/history", GET(getHistoryHandler))
+	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/history/{version}", GET(getHistoryVersionHandler))
+	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/history/{version}/diff", GET(getHistoryDiffHandler))
+	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/history/{version}/restore", POST(postHistoryRestoreHandler))
 	// Workflows run
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs", GET(getWorkflowRunsHandler), POST(postWorkflowRunHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/latest", GET(getLatestWorkflowRunHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}", GET(getWorkflowRunHandler))
+	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/stop", POSTEXECUTE(postStopWorkflowRunHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/artifacts", GET(getWorkflowRunArtifactsHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}", GET(getWorkflowNodeRunHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}/job/{runJobId}/step/{stepOrder}", GET(getWorkflowNodeRunJobStepHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}/artifacts", GET(getWorkflowNodeRunArtifactsHandler))
+	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{id}/analysis", GET(getWorkflowNodeRunStaticAnalysisHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/artifact/{artifactId}", GET(getDownloadArtifactHandler))
+	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/artifacts/latest", GET(getWorkflowLatestArtifactsHandler))
+	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/deployment/{id}/rollback", POSTEXECUTE(postWorkflowDeploymentRollbackHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/node/{nodeID}/triggers/condition", GET(getWorkflowTriggerConditionHandler))
 	router.Handle("/project/{permProjectKey}/workflows/{workflowName}/join/{joinID}/triggers/condition", GET(getWorkflowTriggerJoinConditionHandler))
 
@@ -165,6 +212,11 @@ func (router *Router) init() {
 	router.Handle("/project/{key}/environment/{permEnvironmentName}/clone/{cloneName}", POST(cloneEnvironmentHandler))
 	router.Handle("/project/{key}/environment/{permEnvironmentName}/audit", GET(getEnvironmentsAuditHandler, DEPRECATED))
 	router.Handle("/project/{key}/environment/{permEnvironmentName}/audit/{auditID}", PUT(restoreEnvironmentAuditHandler, DEPRECATED))
+	router.Handle("/project/{key}/environment/{permEnvironmentName}/history", GET(getHistoryHandler))
+	router.Handle("/project/{key}/environment/{permEnvironmentName}/history/{version}", GET(getHistoryVersionHandler))
+	router.Handle("/project/{key}/environment/{permEnvironmentName}/history/{version}/diff", GET(getHistoryDiffHandler))
+	router.Handle("/project/{key}/environment/{permEnvironmentName}/history/{version}/restore", POST(postHistoryRestoreHandler))
+	router.Handle("/project/{key}/environment/{permEnvironmentName}/lock", GET(getEnvironmentLocksHandler))
 	router.Handle("/project/{key}/environment/{permEnvironmentName}/group", POST(addGroupInEnvironmentHandler))
 	router.Handle("/project/{key}/environment/{permEnvironmentName}/groups", POST(addGroupsInEnvironmentHandler))
 	router.Handle("/project/{key}/environment/{permEnvironmentName}/group/{group}", PUT(updateGroupRoleOnEnvironmentHandler), DELETE(deleteGroupFromEnvironmentHandler))
@@ -195,7 +247,7 @@ func (router *Router) init() {
 	router.Handle("/queue/{id}/spawn/infos", NeedWorker(), NeedHatchery(), POST(addSpawnInfosPipelineBuildJobHandler))
 	router.Handle("/queue/{id}/result", POST(addQueueResultHandler))
 	router.Handle("/queue/{id}/infos", GET(getPipelineBuildJobHandler))
-	router.Handle("/build/{id}/log", POST(addBuildLogHandler))
+	router.Handle("/build/{id}/log", NoWorkerRateLimit(), POST(addBuildLogHandler))
 	router.Handle("/build/{id}/step", POST(updateStepStatusHandler))
 
 	//Workflow queue
@@ -206,11 +258,14 @@ func (router *Router) init() {
 	router.Handle("/queue/workflows/{id}/infos", NeedWorker(), GET(getWorkflowJobHandler))
 	router.Handle("/queue/workflows/{id}/spawn/infos", NeedHatchery(), POST(postSpawnInfosWorkflowJobHandler))
 	router.Handle("/queue/workflows/{permID}/result", NeedWorker(), POSTEXECUTE(postWorkflowJobResultHandler))
-	router.Handle("/queue/workflows/{permID}/log", NeedWorker(), POSTEXECUTE(postWorkflowJobLogsHandler))
+	router.Handle("/queue/workflows/{permID}/log", NeedWorker(), NoWorkerRateLimit(), POSTEXECUTE(postWorkflowJobLogsHandler))
 	router.Handle("/queue/workflows/{permID}/test", NeedWorker(), POSTEXECUTE(postWorkflowJobTestsResultsHandler))
+	router.Handle("/queue/workflows/{permID}/analysis", NeedWorker(), POSTEXECUTE(postWorkflowJobStaticAnalysisHandler))
 	router.Handle("/queue/workflows/{permID}/variable", NeedWorker(), POSTEXECUTE(postWorkflowJobVariableHandler))
 	router.Handle("/queue/workflows/{permID}/step", NeedWorker(), POSTEXECUTE(postWorkflowJobStepStatusHandler))
 	router.Handle("/queue/workflows/{permID}/artifact/{tag}", NeedWorker(), POSTEXECUTE(postWorkflowJobArtifactHandler))
+	router.Handle("/queue/workflows/{permID}/artifact/{tag}/upload", NeedWorker(), POSTEXECUTE(postWorkflowJobArtifactUploadHandler))
+	router.Handle("/queue/workflows/{permID}/artifact/{tag}/upload/{ref}", NeedWorker(), PUTEXECUTE(putWorkflowJobArtifactChunkHandler), POSTEXECUTE(postWorkflowJobArtifactUploadCompleteHandler))
 
 	router.Handle("/variable/type", GET(getVariableTypeHandler))
 	router.Handle("/parameter/type", GET(getParameterTypeHandler))
@@ -259,6 +314,8 @@ func (router *Router) init() {
 	router.Handle("/user/import", NeedAdmin(true), POST(importUsersHandler))
 	router.Handle("/user/{username}", NeedUsernameOrAdmin(true), GET(GetUserHandler), PUT(UpdateUserHandler), DELETE(DeleteUserHandler))
 	router.Handle("/user/{username}/groups", NeedUsernameOrAdmin(true), GET(getUserGroupsHandler))
+	router.Handle("/user/{username}/chatops", NeedUsernameOrAdmin(true), GET(getUserChatOpsAccountsHandler), POST(postUserChatOpsAccountHandler))
+	router.Handle("/user/{username}/chatops/{id}", NeedUsernameOrAdmin(true), DELETE(deleteUserChatOpsAccountHandler))
 	router.Handle("/user/{username}/confirm/{token}", Auth(false), GET(ConfirmUser))
 	router.Handle("/user/{username}/reset", Auth(false), POST(ResetUser))
 	router.Handle("/auth/mode", Auth(false), GET(AuthModeHandler))
@@ -0,0 +1,272 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const slackMaxRetries = 3
+
+var (
+	slackRetryDelay = 2 * time.Second
+	slackClient     = &http.Client{
+		Timeout: 10 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return slackCheckWebhookURL(req.URL.String())
+		},
+	}
+	// slackCheckWebhookURL is replaced by the tests to post to a local server
+	slackCheckWebhookURL = checkWebhookURL
+)
+
+// SlackMessage is a message posted to a Slack compatible incoming webhook
+type SlackMessage struct {
+	Channel     string            `json:"channel,omitempty"`
+	Username    string            `json:"username,omitempty"`
+	IconURL     string            `json:"icon_url,omitempty"`
+	Text        string            `json:"text,omitempty"`
+	Attachments []SlackAttachment `json:"attachments,omitempty"`
+}
+
+// SlackAttachment is the rich content of a SlackMessage
+type SlackAttachment struct {
+	Fallback  string       `json:"fallback"`
+	Color     string       `json:"color,omitempty"`
+	Title     string       `json:"title,omitempty"`
+	TitleLink string       `json:"title_link,omitempty"`
+	Text      string       `json:"text,omitempty"`
+	Fields    []SlackField `json:"fields,omitempty"`
+	Timestamp int64        `json:"ts,omitempty"`
+}
+
+// SlackField is a field of a SlackAttachment
+type SlackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// slackColor returns the color of the attachment for a status
+func slackColor(status sdk.Status) string {
+	switch status {
+	case sdk.StatusSuccess:
+		return "good"
+	case sdk.StatusFail:
+		return "danger"
+	}
+	return "warning"
+}
+
+func getSlackMessage(pb *sdk.PipelineBuild, notif *sdk.SlackUserNotificationSettings, params map[string]string) SlackMessage {
+	title := fmt.Sprintf("%s/%s %s #%d %s", pb.Pipeline.ProjectKey, pb.Application.Name, pb.Pipeline.Name, pb.BuildNumber, pb.Status.String())
+
+	fields := []SlackField{
+		{Title: "Pipeline", Value: pb.Pipeline.Name, Short: true},
+		{Title: "Application", Value: pb.Application.Name, Short: true},
+	}
+	if pb.Environment.Name != "" && pb.Environment.ID != sdk.DefaultEnv.ID {
+		fields = append(fields, SlackField{Title: "Environment", Value: pb.Environment.Name, Short: true})
+	}
+	if pb.Trigger.VCSChangesBranch != "" {
+		fields = append(fields, SlackField{Title: "Branch", Value: pb.Trigger.VCSChangesBranch, Short: true})
+	}
+	if pb.Trigger.VCSChangesHash != "" {
+		fields = append(fields, SlackField{Title: "Commit", Value: shortHash(pb.Trigger.VCSChangesHash), Short: true})
+	}
+
+	return newSlackMessage(notif, title, pb.Status, fields, params)
+}
+
+// newSlackMessage builds a message with a single attachment. The author is added to the fields,
+// and the text is the body of the template
+func newSlackMessage(notif *sdk.SlackUserNotificationSettings, title string, status sdk.Status, fields []SlackField, params map[string]string) SlackMessage {
+	text := notif.Template.Body
+	for k, value := range params {
+		text = strings.Replace(text, "{{."+k+"}}", value, -1)
+	}
+
+	if author := params["cds.author"]; author != "" {
+		fields = append(fields, SlackField{Title: "Author", Value: author, Short: true})
+	}
+
+	return SlackMessage{
+		Channel:  notif.Channel,
+		Username: notif.Username,
+		IconURL:  notif.IconURL,
+		Attachments: []SlackAttachment{{
+			Fallback:  title,
+			Color:     slackColor(status),
+			Title:     title,
+			TitleLink: params["cds.buildURL"],
+			Text:      text,
+			Fields:    fields,
+			Timestamp: time.Now().Unix(),
+		}},
+	}
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// checkWebhookURL checks that the webhook is an http(s) URL whose host does not resolve to a loopback, link-local
+// or private address
+func checkWebhookURL(webhookURL string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook url: %s", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid webhook url scheme %s", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("invalid webhook url: missing host")
+	}
+
+	ips, err := net.LookupIP(u.Hostname())
+	if err != nil {
+		return fmt.Errorf("cannot resolve webhook host %s: %s", u.Hostname(), err)
+	}
+	for _, ip := range ips {
+		if !sdk.IsPublicIP(ip) {
+			return fmt.Errorf("webhook host %s resolves to the non public address %s", u.Hostname(), ip)
+		}
+	}
+	return nil
+}
+
+// EncryptSettings returns the settings to store: the webhook url of the slack settings is encrypted. If the webhook
+// url is the placeholder returned by MaskSettings, the webhook url of the stored settings is kept as it is
+func EncryptSettings(notif, stored sdk.UserNotificationSettings) (sdk.UserNotificationSettings, error) {
+	sn, ok := notif.(*sdk.SlackUserNotificationSettings)
+	if !ok || sn.WebhookURL == "" {
+		return notif, nil
+	}
+	encrypted := *sn
+	if sn.WebhookURL == sdk.PasswordPlaceholder {
+		previous, ok := stored.(*sdk.SlackUserNotificationSettings)
+		if !ok || previous.WebhookURL == "" {
+			return nil, sdk.WrapError(sdk.ErrWrongRequest, "notification.EncryptSettings> No stored webhook url")
+		}
+		encrypted.WebhookURL = previous.WebhookURL
+		return &encrypted, nil
+	}
+	cipher, err := secret.Encrypt([]byte(sn.WebhookURL))
+	if err != nil {
+		return nil, sdk.WrapError(err, "notification.EncryptSettings> Cannot encrypt webhook url")
+	}
+	encrypted.WebhookURL = base64.StdEncoding.EncodeToString(cipher)
+	return &encrypted, nil
+}
+
+// MaskSettings replaces the webhook url of slack settings by a placeholder, so that the API does not return it
+func MaskSettings(notif sdk.UserNotificationSettings) {
+	if sn, ok := notif.(*sdk.SlackUserNotificationSettings); ok && sn.WebhookURL != "" {
+		sn.WebhookURL = sdk.PasswordPlaceholder
+	}
+}
+
+// DecryptSettings decrypts the webhook url of stored slack settings, before sending a notification. The urls stored
+// before they were encrypted are not valid base64, they are kept as they are
+func DecryptSettings(notif sdk.UserNotificationSettings) error {
+	sn, ok := notif.(*sdk.SlackUserNotificationSettings)
+	if !ok || sn.WebhookURL == "" {
+		return nil
+	}
+	cipher, err := base64.StdEncoding.DecodeString(sn.WebhookURL)
+	if err != nil {
+		return nil
+	}
+	clear, err := secret.Decrypt(cipher)
+	if err != nil {
+		return sdk.WrapError(err, "notification.DecryptSettings> Cannot decrypt webhook url")
+	}
+	sn.WebhookURL = string(clear)
+	return nil
+}
+
+// SendSlackNotif posts the message to the webhook, retrying on network and server errors
+func SendSlackNotif(webhookURL string, m SlackMessage) error {
+	log.Info("notification.SendSlackNotif> Send notif to %s", m.Channel)
+	if err := slackCheckWebhookURL(webhookURL); err != nil {
+		return sdk.WrapError(sdk.ErrNotificationNotSent, "notification.SendSlackNotif> %s", err)
+	}
+	var err error
+	for i := 0; i < slackMaxRetries; i++ {
+		var retry bool
+		retry, err = postSlackMessage(webhookURL, m)
+		if err == nil || !retry {
+			break
+		}
+		log.Warning("notification.SendSlackNotif> attempt %d/%d failed: %s", i+1, slackMaxRetries, err)
+		time.Sleep(slackRetryDelay * time.Duration(i+1))
+	}
+	if err != nil {
+		return sdk.WrapError(sdk.ErrNotificationNotSent, "notification.SendSlackNotif> %s", err)
+	}
+	return nil
+}
+
+// SendSlackTestNotif posts a test message with the saved slack settings of an application pipeline
+func SendSlackTestNotif(db gorp.SqlExecutor, appID, pipID, envID int64, u *sdk.User) error {
+	notifs, err := loadUserNotificationSettings(db, appID, pipID, envID)
+	if err != nil {
+		return sdk.WrapError(err, "notification.SendSlackTestNotif> Cannot load notification settings")
+	}
+	if notifs == nil {
+		return sdk.WrapError(sdk.ErrNotFound, "notification.SendSlackTestNotif> No notification")
+	}
+	notif, ok := notifs.Notifications[sdk.SlackUserNotification].(*sdk.SlackUserNotificationSettings)
+	if !ok {
+		return sdk.WrapError(sdk.ErrNotFound, "notification.SendSlackTestNotif> No slack notification")
+	}
+	if notif.WebhookURL == "" {
+		return sdk.ErrWrongRequest
+	}
+	if err := DecryptSettings(notif); err != nil {
+		return err
+	}
+	m := SlackMessage{
+		Channel:  notif.Channel,
+		Username: notif.Username,
+		IconURL:  notif.IconURL,
+		Text:     fmt.Sprintf("CDS notification test sent by %s", u.Username),
+	}
+	return SendSlackNotif(notif.WebhookURL, m)
+}
+
+// postSlackMessage posts the message once, and returns true if it can be retried in case of error
+func postSlackMessage(webhookURL string, m SlackMessage) (bool, error) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return false, err
+	}
+	resp, err := slackClient.Post(webhookURL, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return true, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if resp.StatusCode >= 300 {
+		return false, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return false, nil
+}
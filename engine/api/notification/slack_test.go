@@ -0,0 +1,126 @@
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestSendSlackNotif(t *testing.T) {
+	slackRetryDelay = time.Millisecond
+	slackCheckWebhookURL = func(string) error { return nil }
+
+	var calls int
+	var received SlackMessage
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer ts.Close()
+
+	pb := &sdk.PipelineBuild{
+		BuildNumber: 12,
+		Status:      sdk.StatusFail,
+		Pipeline:    sdk.Pipeline{Name: "build", ProjectKey: "KEY"},
+		Application: sdk.Application{Name: "app"},
+		Environment: sdk.DefaultEnv,
+		Trigger:     sdk.PipelineBuildTrigger{VCSChangesBranch: "master", VCSChangesHash: "0123456789abcdef"},
+	}
+	notif := &sdk.SlackUserNotificationSettings{WebhookURL: ts.URL, Channel: "#cds", Template: sdk.UserNotificationTemplate{Body: "by {{.cds.author}}"}}
+	m := getSlackMessage(pb, notif, map[string]string{"cds.author": "john", "cds.buildURL": "http://ui/build/12"})
+
+	assert.NoError(t, SendSlackNotif(ts.URL, m))
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "#cds", received.Channel)
+	assert.Len(t, received.Attachments, 1)
+	assert.Equal(t, "danger", received.Attachments[0].Color)
+	assert.Equal(t, "KEY/app build #12 Fail", received.Attachments[0].Title)
+	assert.Equal(t, "by john", received.Attachments[0].Text)
+	assert.Equal(t, "http://ui/build/12", received.Attachments[0].TitleLink)
+	assert.Contains(t, received.Attachments[0].Fields, SlackField{Title: "Commit", Value: "0123456", Short: true})
+}
+
+func TestSendSlackNotifClientError(t *testing.T) {
+	slackRetryDelay = time.Millisecond
+	slackCheckWebhookURL = func(string) error { return nil }
+
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	assert.Error(t, SendSlackNotif(ts.URL, SlackMessage{Text: "test"}))
+	assert.Equal(t, 1, calls)
+}
+
+func TestCheckWebhookURL(t *testing.T) {
+	for _, u := range []string{
+		"file:///etc/passwd",
+		"gopher://hooks.example.com/",
+		"https://",
+		"http://127.0.0.1:8081/project",
+		"http://[::1]/",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.12/hooks",
+		"http://192.168.1.1/hooks",
+		"http://0.0.0.0/",
+	} {
+		assert.Error(t, checkWebhookURL(u), u)
+	}
+	assert.NoError(t, checkWebhookURL("https://8.8.8.8/services/XXX"))
+}
+
+func TestSendSlackNotifPrivateAddress(t *testing.T) {
+	slackCheckWebhookURL = checkWebhookURL
+	defer func() { slackCheckWebhookURL = func(string) error { return nil } }()
+
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer ts.Close()
+
+	assert.Error(t, SendSlackNotif(ts.URL, SlackMessage{Text: "test"}))
+	assert.Equal(t, 0, calls)
+}
+
+func TestEncryptSettings(t *testing.T) {
+	secret.Init("3dojuwevn94y7orh5e3t4ejtmbtstest")
+
+	notif := &sdk.SlackUserNotificationSettings{WebhookURL: "https://hooks.slack.com/services/XXX", Channel: "#cds"}
+	encrypted, err := EncryptSettings(notif, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, encrypted.JSON(), "hooks.slack.com")
+	assert.Equal(t, "https://hooks.slack.com/services/XXX", notif.WebhookURL)
+
+	// The masked settings posted back keep the stored webhook url
+	masked := &sdk.SlackUserNotificationSettings{WebhookURL: "https://hooks.slack.com/services/XXX", Channel: "#ops"}
+	MaskSettings(masked)
+	assert.Equal(t, sdk.PasswordPlaceholder, masked.WebhookURL)
+	kept, err := EncryptSettings(masked, encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, encrypted.(*sdk.SlackUserNotificationSettings).WebhookURL, kept.(*sdk.SlackUserNotificationSettings).WebhookURL)
+	assert.Equal(t, "#ops", kept.(*sdk.SlackUserNotificationSettings).Channel)
+	_, err = EncryptSettings(masked, nil)
+	assert.Error(t, err)
+
+	assert.NoError(t, DecryptSettings(encrypted))
+	assert.Equal(t, notif, encrypted)
+
+	clear := &sdk.SlackUserNotificationSettings{WebhookURL: "https://hooks.slack.com/services/YYY"}
+	assert.NoError(t, DecryptSettings(clear))
+	assert.Equal(t, "https://hooks.slack.com/services/YYY", clear.WebhookURL)
+}
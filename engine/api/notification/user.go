@@ -29,7 +29,7 @@ func Init(apiurl, uiurl string) {
 // GetUserEvents returns event from user notification
 func GetUserEvents(db gorp.SqlExecutor, pb *sdk.PipelineBuild, previous *sdk.PipelineBuild) []sdk.EventNotif {
 	//Load notif
-	userNotifs, errLoad := LoadUserNotificationSettings(db, pb.Application.ID, pb.Pipeline.ID, pb.Environment.ID)
+	userNotifs, errLoad := loadUserNotificationSettings(db, pb.Application.ID, pb.Pipeline.ID, pb.Environment.ID)
 	if errLoad != nil {
 		log.Error("notification.GetUserEvents> error while loading user notification settings: %s", errLoad)
 		return nil
@@ -94,7 +94,7 @@ func GetUserEvents(db gorp.SqlExecutor, pb *sdk.PipelineBuild, previous *sdk.Pip
 				}
 				//Finally deduplicate everyone
 				removeDuplicates(&jn.Recipients)
-				events = append(events, getEvent(pb, jn, params))
+				events = append(events, getEvent(jn, params))
 			case sdk.EmailUserNotification:
 				jn, ok := notif.(*sdk.JabberEmailUserNotificationSettings)
 				if !ok {
@@ -129,7 +129,22 @@ func GetUserEvents(db gorp.SqlExecutor, pb *sdk.PipelineBuild, previous *sdk.Pip
 				}
 				//Finally deduplicate everyone
 				removeDuplicates(&jn.Recipients)
-				go SendMailNotif(getEvent(pb, jn, params))
+				go SendMailNotif(getEvent(jn, params))
+			case sdk.SlackUserNotification:
+				sn, ok := notif.(*sdk.SlackUserNotificationSettings)
+				if !ok || sn.WebhookURL == "" {
+					log.Error("notification.GetUserEvents> cannot deal with %s", notif)
+					continue
+				}
+				if err := DecryptSettings(sn); err != nil {
+					log.Warning("notification[Slack].SendPipelineBuild> %s", err)
+					continue
+				}
+				go func(url string, m SlackMessage) {
+					if err := SendSlackNotif(url, m); err != nil {
+						log.Warning("notification[Slack].SendPipelineBuild> %s", err)
+					}
+				}(sn.WebhookURL, getSlackMessage(pb, sn, params))
 			}
 		}
 	}
@@ -151,6 +166,14 @@ func removeDuplicates(xs *[]string) {
 
 //ShouldSendUserNotification check if user notification has to be sent
 func ShouldSendUserNotification(notif sdk.UserNotificationSettings, current *sdk.PipelineBuild, previous *sdk.PipelineBuild) bool {
+	if previous == nil {
+		return shouldSend(notif, current.Status, nil)
+	}
+	return shouldSend(notif, current.Status, &previous.Status)
+}
+
+// shouldSend checks if a notification has to be sent for the current status, given the previous one if any
+func shouldSend(notif sdk.UserNotificationSettings, current sdk.Status, previous *sdk.Status) bool {
 	var check = func(s sdk.UserNotificationEventType) bool {
 		switch s {
 		case sdk.UserNotificationAlways:
@@ -161,11 +184,11 @@ func ShouldSendUserNotification(notif sdk.UserNotificationSettings, current *sdk
 			if previous == nil {
 				return true
 			}
-			return current.Status != previous.Status
+			return current != *previous
 		}
 		return false
 	}
-	switch current.Status {
+	switch current {
 	case sdk.StatusSuccess:
 		if check(notif.Success()) {
 			return true
@@ -181,7 +204,7 @@ func ShouldSendUserNotification(notif sdk.UserNotificationSettings, current *sdk
 	return false
 }
 
-func getEvent(pb *sdk.PipelineBuild, notif *sdk.JabberEmailUserNotificationSettings, params map[string]string) sdk.EventNotif {
+func getEvent(notif *sdk.JabberEmailUserNotificationSettings, params map[string]string) sdk.EventNotif {
 
 	subject := notif.Template.Subject
 	body := notif.Template.Body
@@ -260,6 +283,7 @@ func LoadAllUserNotificationSettingsByProject(db gorp.SqlExecutor, projectKey st
 		if err != nil {
 			return nil, err
 		}
+		maskNotifications(un.Notifications)
 
 		if u != nil {
 			if !permission.AccessToEnvironment(un.Environment.ID, u, permission.PermissionRead) {
@@ -302,14 +326,25 @@ func LoadAllUserNotificationSettings(db gorp.SqlExecutor, appID int64) ([]sdk.Us
 		if err != nil {
 			return nil, err
 		}
+		maskNotifications(un.Notifications)
 		n = append(n, un)
 	}
 
 	return n, nil
 }
 
-//LoadUserNotificationSettings load data from application_pipeline_notif
+//LoadUserNotificationSettings load data from application_pipeline_notif, the webhook urls are masked
 func LoadUserNotificationSettings(db gorp.SqlExecutor, appID, pipID, envID int64) (*sdk.UserNotification, error) {
+	n, err := loadUserNotificationSettings(db, appID, pipID, envID)
+	if err != nil || n == nil {
+		return n, err
+	}
+	maskNotifications(n.Notifications)
+	return n, nil
+}
+
+// loadUserNotificationSettings load data from application_pipeline_notif as they are stored, with the webhook urls encrypted
+func loadUserNotificationSettings(db gorp.SqlExecutor, appID, pipID, envID int64) (*sdk.UserNotification, error) {
 	var n = &sdk.UserNotification{}
 	var settings string
 	query := `
@@ -368,6 +403,11 @@ func InsertOrUpdateUserNotificationSettings(db gorp.SqlExecutor, appID, pipID, e
 		AND 	application_pipeline_notif.environment_id = $3
 	`
 
+	stored, err := loadUserNotificationSettings(db, appID, pipID, envID)
+	if err != nil {
+		return err
+	}
+
 	var nb int
 	if err := db.QueryRow(query, appID, pipID, envID).Scan(&nb); err != nil {
 		log.Error("notification.InsertOrUpdateUserNotificationSettings> Error counting application_pipeline_notif %d %d %d: %s", appID, pipID, envID, err)
@@ -398,7 +438,20 @@ func InsertOrUpdateUserNotificationSettings(db gorp.SqlExecutor, appID, pipID, e
 		notif.Environment.ID = envID
 	}
 
-	bytes, err := json.Marshal(notif.Notifications)
+	encrypted := make(map[sdk.UserNotificationSettingsType]sdk.UserNotificationSettings, len(notif.Notifications))
+	for t, n := range notif.Notifications {
+		var previous sdk.UserNotificationSettings
+		if stored != nil {
+			previous = stored.Notifications[t]
+		}
+		e, err := EncryptSettings(n, previous)
+		if err != nil {
+			return err
+		}
+		encrypted[t] = e
+	}
+
+	bytes, err := json.Marshal(encrypted)
 	if err != nil {
 		log.Error("notification.InsertOrUpdateUserNotificationSettings> Error marshalling notifications settings: %s", err)
 		return err
@@ -425,3 +478,10 @@ func InsertOrUpdateUserNotificationSettings(db gorp.SqlExecutor, appID, pipID, e
 
 	return nil
 }
+
+// maskNotifications masks the webhook urls of the loaded notifications
+func maskNotifications(notifs map[sdk.UserNotificationSettingsType]sdk.UserNotificationSettings) {
+	for _, n := range notifs {
+		MaskSettings(n)
+	}
+}
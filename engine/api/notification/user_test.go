@@ -0,0 +1,43 @@
+package notification
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestShouldSend(t *testing.T) {
+	success, fail := sdk.StatusSuccess, sdk.StatusFail
+	onChange := &sdk.JabberEmailUserNotificationSettings{OnSuccess: sdk.UserNotificationChange, OnFailure: sdk.UserNotificationChange}
+	always := &sdk.JabberEmailUserNotificationSettings{OnSuccess: sdk.UserNotificationAlways, OnFailure: sdk.UserNotificationAlways, OnStart: true}
+	never := &sdk.JabberEmailUserNotificationSettings{OnSuccess: sdk.UserNotificationNever, OnFailure: sdk.UserNotificationNever}
+
+	tests := []struct {
+		name     string
+		notif    sdk.UserNotificationSettings
+		current  sdk.Status
+		previous *sdk.Status
+		send     bool
+	}{
+		{"change: first success", onChange, sdk.StatusSuccess, nil, true},
+		{"change: first failure", onChange, sdk.StatusFail, nil, true},
+		{"change: success to success", onChange, sdk.StatusSuccess, &success, false},
+		{"change: failure to success", onChange, sdk.StatusSuccess, &fail, true},
+		{"change: success to failure", onChange, sdk.StatusFail, &success, true},
+		{"change: failure to failure", onChange, sdk.StatusFail, &fail, false},
+		{"change: start", onChange, sdk.StatusBuilding, &success, false},
+		{"always: success to success", always, sdk.StatusSuccess, &success, true},
+		{"always: failure to failure", always, sdk.StatusFail, &fail, true},
+		{"always: start", always, sdk.StatusBuilding, nil, true},
+		{"always: waiting", always, sdk.StatusWaiting, nil, false},
+		{"never: failure to success", never, sdk.StatusSuccess, &fail, false},
+		{"never: success to failure", never, sdk.StatusFail, &success, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.send, shouldSend(tt.notif, tt.current, tt.previous))
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package notification
+
+import (
+	"fmt"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// GetWorkflowEvents returns the events of a workflow notification rule for the run of a node, or for the whole
+// workflow run if the rule has no source node. Jabber events are returned, emails and slack messages are sent.
+func GetWorkflowEvents(db gorp.SqlExecutor, wr *sdk.WorkflowRun, nr *sdk.WorkflowNodeRun, wn sdk.WorkflowNotification, status sdk.Status, previous *sdk.Status) []sdk.EventNotif {
+	if wn.Settings == nil || !shouldSend(wn.Settings, status, previous) {
+		return nil
+	}
+
+	w := &wr.Workflow
+	node := w.GetNode(nr.WorkflowNodeID)
+	if len(wn.SourceNodeIDs) == 0 {
+		node = w.Root
+	}
+	if node == nil {
+		log.Warning("notification.GetWorkflowEvents> node %d not found in workflow %s", nr.WorkflowNodeID, w.Name)
+		return nil
+	}
+
+	//Compute notification
+	params := map[string]string{}
+	for _, p := range nr.BuildParameters {
+		params[p.Name] = p.Value
+	}
+	params["cds.status"] = status.String()
+	params["cds.buildURL"] = fmt.Sprintf("%s/project/%s/workflow/%s/run/%d", uiURL, w.ProjectKey, w.Name, wr.Number)
+	//find author (manual user or changes author)
+	var author string
+	if nr.Manual != nil && nr.Manual.User.Username != "" {
+		author = nr.Manual.User.Username
+	} else {
+		author = sdk.ParameterValue(nr.BuildParameters, "git.author")
+	}
+	if author != "" {
+		params["cds.author"] = author
+	}
+
+	title := fmt.Sprintf("%s/%s #%d %s", w.ProjectKey, w.Name, wr.Number, status.String())
+	if len(wn.SourceNodeIDs) > 0 {
+		title = fmt.Sprintf("%s/%s %s #%d.%d %s", w.ProjectKey, w.Name, node.Name, nr.Number, nr.SubNumber, status.String())
+	}
+
+	switch wn.Type {
+	case sdk.JabberUserNotification:
+		jn, ok := copyJabberEmailSettings(wn.Settings)
+		if !ok {
+			log.Error("notification.GetWorkflowEvents> cannot deal with %s", wn.Settings)
+			return nil
+		}
+		if jn.SendToGroups {
+			for _, u := range nodeUsers(db, node) {
+				jn.Recipients = append(jn.Recipients, u.Username)
+			}
+		}
+		if jn.SendToAuthor && author != "" {
+			jn.Recipients = append(jn.Recipients, author)
+		}
+		//Finally deduplicate everyone
+		removeDuplicates(&jn.Recipients)
+		return []sdk.EventNotif{getEvent(jn, params)}
+	case sdk.EmailUserNotification:
+		jn, ok := copyJabberEmailSettings(wn.Settings)
+		if !ok {
+			log.Error("notification.GetWorkflowEvents> cannot deal with %s", wn.Settings)
+			return nil
+		}
+		if jn.SendToGroups {
+			for _, u := range nodeUsers(db, node) {
+				jn.Recipients = append(jn.Recipients, u.Email)
+			}
+		}
+		if jn.SendToAuthor && author != "" {
+			u, err := user.LoadUserWithoutAuth(db, author)
+			if err != nil {
+				log.Warning("notification[Email].GetWorkflowEvents> Cannot load author %s: %s", author, err)
+			} else {
+				jn.Recipients = append(jn.Recipients, u.Email)
+			}
+		}
+		//Finally deduplicate everyone
+		removeDuplicates(&jn.Recipients)
+		go SendMailNotif(getEvent(jn, params))
+	case sdk.SlackUserNotification:
+		sn, ok := wn.Settings.(*sdk.SlackUserNotificationSettings)
+		if !ok || sn.WebhookURL == "" {
+			log.Error("notification.GetWorkflowEvents> cannot deal with %s", wn.Settings)
+			return nil
+		}
+		fields := []SlackField{{Title: "Pipeline", Value: node.Pipeline.Name, Short: true}}
+		if branch := params["git.branch"]; branch != "" {
+			fields = append(fields, SlackField{Title: "Branch", Value: branch, Short: true})
+		}
+		if hash := params["git.hash"]; hash != "" {
+			fields = append(fields, SlackField{Title: "Commit", Value: shortHash(hash), Short: true})
+		}
+		go func(url string, m SlackMessage) {
+			if err := SendSlackNotif(url, m); err != nil {
+				log.Warning("notification[Slack].GetWorkflowEvents> %s", err)
+			}
+		}(sn.WebhookURL, newSlackMessage(sn, title, status, fields, params))
+	}
+	return nil
+}
+
+// copyJabberEmailSettings returns a copy of jabber or email settings, the recipients are added to the copy
+// as the settings of the rule are shared by all the node runs of the workflow run
+func copyJabberEmailSettings(settings sdk.UserNotificationSettings) (*sdk.JabberEmailUserNotificationSettings, bool) {
+	jn, ok := settings.(*sdk.JabberEmailUserNotificationSettings)
+	if !ok {
+		return nil, false
+	}
+	c := *jn
+	c.Recipients = append([]string(nil), jn.Recipients...)
+	return &c, true
+}
+
+// nodeUsers returns the users allowed to read the application, the pipeline and the environment of a workflow node
+func nodeUsers(db gorp.SqlExecutor, node *sdk.WorkflowNode) []sdk.User {
+	if node.Context == nil || node.Context.ApplicationID == 0 {
+		log.Debug("notification.nodeUsers> no application on node %d", node.ID)
+		return nil
+	}
+	envID := node.Context.EnvironmentID
+	if envID == 0 {
+		envID = sdk.DefaultEnv.ID
+	}
+	u, err := permission.ApplicationPipelineEnvironmentUsers(db, node.Context.ApplicationID, node.PipelineID, envID, permission.PermissionRead)
+	if err != nil {
+		log.Error("notification.nodeUsers> error while loading permission: %s", err)
+		return nil
+	}
+	return u
+}
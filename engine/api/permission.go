@@ -41,6 +41,9 @@ func getPermissionByMethod(method string, isExecution bool) int {
 		}
 		return permission.PermissionReadWriteExecute
 	case "PUT":
+		if isExecution {
+			return permission.PermissionReadExecute
+		}
 		return permission.PermissionReadWriteExecute
 	case "DELETE":
 		return permission.PermissionReadWriteExecute
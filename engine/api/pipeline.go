@@ -281,6 +281,10 @@ func runPipelineHandlerFunc(w http.ResponseWriter, r *http.Request, db *gorp.DbM
 		return sdk.ErrPipelineNotAttached
 	}
 
+	if err := sdk.IsValidJobPriority(request.Priority); err != nil {
+		return sdk.WrapError(err, "runPipelineHandler> Invalid priority")
+	}
+
 	version := int64(0)
 	// Load parent pipeline build + add parent variable
 	var parentPipelineBuild *sdk.PipelineBuild
@@ -327,6 +331,22 @@ func runPipelineHandlerFunc(w http.ResponseWriter, r *http.Request, db *gorp.DbM
 	}
 	defer tx.Rollback()
 
+	// A pipeline manually triggered from its parent is gated by the approval of the trigger, as if it was triggered automatically
+	var gated *sdk.PipelineTrigger
+	if parentPipelineBuild != nil {
+		triggers, err := trigger.LoadTriggers(db, app.ID, pip.ID, envDest.ID)
+		if err != nil {
+			return sdk.WrapError(err, "runPipelineHandler> Cannot load triggers")
+		}
+		for i, t := range triggers {
+			if t.SrcApplication.ID == request.ParentApplicationID && t.SrcPipeline.ID == request.ParentPipelineID && t.SrcEnvironment.ID == envID &&
+				t.DestApplication.ID == app.ID && t.DestPipeline.ID == pip.ID && t.Approval != nil {
+				gated = &triggers[i]
+				break
+			}
+		}
+	}
+
 	// Schedule pipeline for build
 	log.Debug("runPipelineHandler> Scheduling %s/%s/%s[%s] with %d params, version 0",
 		projectKey, app.Name, pipelineName, envDest.Name, len(request.Params))
@@ -335,6 +355,7 @@ func runPipelineHandlerFunc(w http.ResponseWriter, r *http.Request, db *gorp.DbM
 		ManualTrigger:       true,
 		TriggeredBy:         c.User,
 		ParentPipelineBuild: parentPipelineBuild,
+		Priority:            request.Priority,
 	}
 	if parentPipelineBuild != nil {
 		trigger.VCSChangesAuthor = parentPipelineBuild.Trigger.VCSChangesAuthor
@@ -352,6 +373,12 @@ func runPipelineHandlerFunc(w http.ResponseWriter, r *http.Request, db *gorp.DbM
 		return sdk.WrapError(err, "runPipelineHandler> Cannot run pipeline")
 	}
 
+	if gated != nil {
+		if err := queue.InsertPipelineBuildApproval(tx, *gated, pb); err != nil {
+			return sdk.WrapError(err, "runPipelineHandler> Cannot insert approval of pipeline build %d", pb.ID)
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "runPipelineHandler> Cannot commit tx")
 	}
@@ -937,6 +964,10 @@ func updateJoinedAction(w http.ResponseWriter, r *http.Request, db *gorp.DbMap,
 		return sdk.WrapError(errlb, "updateJoinedAction> cannot load all binary requirements")
 	}
 
+	if err := action.ResolveChildren(tx, proj.ID, &a); err != nil {
+		return sdk.WrapError(err, "updateJoinedAction> cannot resolve steps")
+	}
+
 	log.Debug("updateJoinedAction> UpdateActionDB %d", a.ID)
 	if err := action.UpdateActionDB(tx, &a, c.User.ID); err != nil {
 		return sdk.WrapError(err, "updateJoinedAction> cannot update action")
@@ -0,0 +1,116 @@
+package pipeline
+
+import (
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/history"
+	"github.com/ovh/cds/sdk"
+)
+
+// recordHistory stores the current stages, jobs and parameters of the pipeline as a new version in its history.
+// Group permissions are not part of the snapshot.
+func recordHistory(db gorp.SqlExecutor, proj *sdk.Project, pipelineID int64, u *sdk.User) error {
+	snapshot, err := LoadPipelineByID(db, pipelineID, true)
+	if err != nil {
+		return sdk.WrapError(err, "recordHistory> Unable to load pipeline %d", pipelineID)
+	}
+	snapshot.ProjectID = proj.ID
+	snapshot.GroupPermission = nil
+	return history.Record(db, sdk.HistoryPipeline, proj.ID, snapshot.ID, snapshot.Name, snapshot, u)
+}
+
+// Restore updates the stages, jobs and parameters of a pipeline from a snapshot of its history
+func Restore(db gorp.SqlExecutor, proj *sdk.Project, snapshot *sdk.Pipeline, u *sdk.User) error {
+	current, err := LoadPipelineByID(db, snapshot.ID, true)
+	if err != nil {
+		return sdk.WrapError(err, "Restore> Unable to load pipeline %d", snapshot.ID)
+	}
+	snapshot.Name = current.Name
+	snapshot.ProjectID = proj.ID
+	snapshot.GroupPermission = nil
+
+	//Stages are matched by name, jobs are inserted or updated by ImportUpdate
+	for i := range snapshot.Stages {
+		s := &snapshot.Stages[i]
+		s.ID = 0
+		s.PipelineID = current.ID
+		for _, cs := range current.Stages {
+			if cs.Name == s.Name {
+				s.ID = cs.ID
+				break
+			}
+		}
+		for j := range s.Jobs {
+			s.Jobs[j].PipelineStageID = s.ID
+			s.Jobs[j].PipelineActionID = 0
+			s.Jobs[j].Action.ID = 0
+		}
+	}
+	if err := ImportUpdate(db, proj, snapshot, nil, u); err != nil {
+		return sdk.WrapError(err, "Restore> Unable to restore stages of pipeline %s", snapshot.Name)
+	}
+
+	//ImportUpdate keeps the jobs which are not in the snapshot
+	for _, cs := range current.Stages {
+		for _, s := range snapshot.Stages {
+			if s.Name != cs.Name {
+				continue
+			}
+			for _, cj := range cs.Jobs {
+				var found bool
+				for _, j := range s.Jobs {
+					if j.Action.Name == cj.Action.Name {
+						found = true
+						break
+					}
+				}
+				if !found {
+					if err := DeleteJob(db, cj, u.ID); err != nil {
+						return sdk.WrapError(err, "Restore> Unable to delete job %s of pipeline %s", cj.Action.Name, snapshot.Name)
+					}
+				}
+			}
+		}
+	}
+	for i := range snapshot.Stages {
+		if err := UpdateStage(db, &snapshot.Stages[i]); err != nil {
+			return sdk.WrapError(err, "Restore> Unable to restore stage %s of pipeline %s", snapshot.Stages[i].Name, snapshot.Name)
+		}
+	}
+
+	for _, p := range snapshot.Parameter {
+		var found bool
+		for _, cp := range current.Parameter {
+			if cp.Name == p.Name {
+				found = true
+				break
+			}
+		}
+		if found {
+			if err := UpdateParameterInPipeline(db, current.ID, p.Name, p); err != nil {
+				return sdk.WrapError(err, "Restore> Unable to restore parameter %s of pipeline %s", p.Name, snapshot.Name)
+			}
+			continue
+		}
+		param := p
+		if err := InsertParameterInPipeline(db, current.ID, &param); err != nil {
+			return sdk.WrapError(err, "Restore> Unable to restore parameter %s of pipeline %s", p.Name, snapshot.Name)
+		}
+	}
+	for _, cp := range current.Parameter {
+		var found bool
+		for _, p := range snapshot.Parameter {
+			if cp.Name == p.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			if err := DeleteParameterFromPipeline(db, current.ID, cp.Name); err != nil {
+				return sdk.WrapError(err, "Restore> Unable to delete parameter %s of pipeline %s", cp.Name, snapshot.Name)
+			}
+		}
+	}
+
+	return UpdatePipelineLastModified(db, proj, snapshot, u)
+}
@@ -48,7 +48,10 @@ func UpdatePipelineLastModified(db gorp.SqlExecutor, proj *sdk.Project, p *sdk.P
 		}
 	}
 
-	return err
+	if err != nil {
+		return err
+	}
+	return recordHistory(db, proj, p.ID, u)
 }
 
 // LoadPipeline loads a pipeline from database
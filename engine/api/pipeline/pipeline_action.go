@@ -73,6 +73,9 @@ func selectAllPipelineActionID(db gorp.SqlExecutor, pipelineStageID int64) ([]in
 func InsertJob(db gorp.SqlExecutor, job *sdk.Job, stageID int64, pip *sdk.Pipeline) error {
 	// Insert Joined Action
 	job.Action.Type = sdk.JoinedAction
+	if err := action.ResolveChildren(db, pip.ProjectID, &job.Action); err != nil {
+		return err
+	}
 	log.Debug("InsertJob> Insert Action %s on pipeline %s with %d children", job.Action.Name, pip.Name, len(job.Action.Actions))
 	if err := action.InsertAction(db, &job.Action, false); err != nil {
 		return err
@@ -111,7 +114,7 @@ func InsertJob(db gorp.SqlExecutor, job *sdk.Job, stageID int64, pip *sdk.Pipeli
 }
 
 // UpdateJob  updates the job by actionData.PipelineActionID and actionData.ID
-func UpdateJob(db gorp.SqlExecutor, job *sdk.Job, userID int64) error {
+func UpdateJob(db gorp.SqlExecutor, job *sdk.Job, projectID, userID int64) error {
 	clearJoinedAction, err := action.LoadActionByID(db, job.Action.ID)
 	if err != nil {
 		return err
@@ -121,6 +124,10 @@ func UpdateJob(db gorp.SqlExecutor, job *sdk.Job, userID int64) error {
 		return sdk.ErrForbidden
 	}
 
+	if err := action.ResolveChildren(db, projectID, &job.Action); err != nil {
+		return err
+	}
+
 	query := `UPDATE pipeline_action set action_id=$1, pipeline_stage_id=$2, enabled=$4  WHERE id=$3`
 	_, err = db.Exec(query, job.Action.ID, job.PipelineStageID, job.PipelineActionID, job.Enabled)
 	if err != nil {
@@ -160,8 +167,8 @@ func DeletePipelineAction(db gorp.SqlExecutor, pipelineActionID int64) error {
 	return nil
 }
 
-//CheckJob validate a job
-func CheckJob(db gorp.SqlExecutor, job *sdk.Job) error {
+//CheckJob validate a job, whose steps can use the actions of the project
+func CheckJob(db gorp.SqlExecutor, projectID int64, job *sdk.Job) error {
 	t := time.Now()
 	log.Debug("CheckJob> Begin")
 	defer log.Debug("CheckJob> End (%d ns)", time.Since(t).Nanoseconds())
@@ -170,18 +177,25 @@ func CheckJob(db gorp.SqlExecutor, job *sdk.Job) error {
 	for i := range job.Action.Actions {
 		step := &job.Action.Actions[i]
 		log.Debug("CheckJob> Checking step %s", step.Name)
-		a, err := action.LoadPublicAction(db, step.Name)
+		a, err := action.LoadActionForProject(db, projectID, step.Name)
 		if err != nil {
 			if err == sdk.ErrNoAction {
 				*errs = append(*errs, sdk.NewMessage(sdk.MsgJobNotValidActionNotFound, job.Action.Name, step.Name, i+1))
 				continue
 			}
-			return sdk.WrapError(err, "CheckJob> Unable to load public action %s", step.Name)
+			return sdk.WrapError(err, "CheckJob> Unable to load action %s", step.Name)
 		}
 
-		a.Parameters, err = action.LoadActionParameters(db, a.ID)
-		if err != nil {
-			return sdk.WrapError(err, "CheckJob> Unable to load public action %s parameters", step.Name)
+		if step.Version != 0 {
+			a, err = action.LoadVersion(db, a.ID, step.Version)
+			if err != nil {
+				return sdk.WrapError(err, "CheckJob> Unable to load version %d of action %s", step.Version, step.Name)
+			}
+		} else {
+			a.Parameters, err = action.LoadActionParameters(db, a.ID)
+			if err != nil {
+				return sdk.WrapError(err, "CheckJob> Unable to load action %s parameters", step.Name)
+			}
 		}
 
 		for x := range step.Parameters {
@@ -3,6 +3,7 @@ package pipeline
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-gorp/gorp"
@@ -365,3 +366,112 @@ func UpdatePipelineBuildJobStatus(db gorp.SqlExecutor, pbJob *sdk.PipelineBuildJ
 	event.PublishActionBuild(pb, pbJob)
 	return nil
 }
+
+// GetPipelineBuildJobPriority returns the priority of the jobs of a pipeline build, from the manual run or the project
+func GetPipelineBuildJobPriority(db gorp.SqlExecutor, pbID int64) (int, error) {
+	query := `
+		SELECT project.job_priority, pipeline_build.priority
+		FROM pipeline_build
+		JOIN application ON application.id = pipeline_build.application_id
+		JOIN project ON project.id = application.project_id
+		WHERE pipeline_build.id = $1
+	`
+	var projectPriority, manualPriority *int
+	if err := db.QueryRow(query, pbID).Scan(&projectPriority, &manualPriority); err != nil {
+		return 0, sdk.WrapError(err, "GetPipelineBuildJobPriority> Unable to load priorities of pipeline build %d", pbID)
+	}
+	return sdk.EffectiveJobPriority(projectPriority, nil, manualPriority), nil
+}
+
+// LoadPipelineBuildJobProjectIDs returns the project ID of each given pipeline_build_job
+func LoadPipelineBuildJobProjectIDs(db gorp.SqlExecutor, ids []int64) (map[int64]int64, error) {
+	res := map[int64]int64{}
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	strIDs := make([]string, len(ids))
+	for i, id := range ids {
+		strIDs[i] = strconv.FormatInt(id, 10)
+	}
+
+	query := `
+		SELECT pipeline_build_job.id, application.project_id
+		FROM pipeline_build_job
+		JOIN pipeline_build ON pipeline_build.id = pipeline_build_job.pipeline_build_id
+		JOIN application ON application.id = pipeline_build.application_id
+		WHERE pipeline_build_job.id = ANY(string_to_array($1, ',')::bigint[])
+	`
+	rows, err := db.Query(query, strings.Join(strIDs, ","))
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadPipelineBuildJobProjectIDs> Unable to load projects of jobs")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, projectID int64
+		if err := rows.Scan(&id, &projectID); err != nil {
+			return nil, sdk.WrapError(err, "LoadPipelineBuildJobProjectIDs> Unable to scan")
+		}
+		res[id] = projectID
+	}
+	return res, nil
+}
+
+// CountBuildingPipelineBuildJobsByProject returns the number of building pipeline_build_job of each project
+func CountBuildingPipelineBuildJobsByProject(db gorp.SqlExecutor) (map[int64]int, error) {
+	query := `
+		SELECT application.project_id, COUNT(pipeline_build_job.id)
+		FROM pipeline_build_job
+		JOIN pipeline_build ON pipeline_build.id = pipeline_build_job.pipeline_build_id
+		JOIN application ON application.id = pipeline_build.application_id
+		WHERE pipeline_build_job.status = $1
+		GROUP BY application.project_id
+	`
+	rows, err := db.Query(query, sdk.StatusBuilding.String())
+	if err != nil {
+		return nil, sdk.WrapError(err, "CountBuildingPipelineBuildJobsByProject> Unable to count jobs")
+	}
+	defer rows.Close()
+
+	res := map[int64]int{}
+	for rows.Next() {
+		var projectID int64
+		var count int
+		if err := rows.Scan(&projectID, &count); err != nil {
+			return nil, sdk.WrapError(err, "CountBuildingPipelineBuildJobsByProject> Unable to scan")
+		}
+		res[projectID] = count
+	}
+	return res, nil
+}
+
+// CountBookedPipelineBuildJobsByProject returns the number of waiting pipeline_build_job booked by a hatchery of each
+// project, the given job excepted
+func CountBookedPipelineBuildJobsByProject(db gorp.SqlExecutor, except int64) (map[int64]int, error) {
+	query := `
+		SELECT pipeline_build_job.id, application.project_id
+		FROM pipeline_build_job
+		JOIN pipeline_build ON pipeline_build.id = pipeline_build_job.pipeline_build_id
+		JOIN application ON application.id = pipeline_build.application_id
+		WHERE pipeline_build_job.status = $1 AND pipeline_build_job.id <> $2
+	`
+	rows, err := db.Query(query, sdk.StatusWaiting.String(), except)
+	if err != nil {
+		return nil, sdk.WrapError(err, "CountBookedPipelineBuildJobsByProject> Unable to load jobs")
+	}
+	defer rows.Close()
+
+	res := map[int64]int{}
+	for rows.Next() {
+		var id, projectID int64
+		if err := rows.Scan(&id, &projectID); err != nil {
+			return nil, sdk.WrapError(err, "CountBookedPipelineBuildJobsByProject> Unable to scan")
+		}
+		var h sdk.Hatchery
+		if cache.Get(keyBookJob(id), &h) {
+			res[projectID]++
+		}
+	}
+	return res, nil
+}
@@ -105,7 +105,7 @@ func ImportUpdate(db gorp.SqlExecutor, proj *sdk.Project, pip *sdk.Pipeline, msg
 			//Insert stage's Jobs
 			for x := range s.Jobs {
 				jobAction := &s.Jobs[x]
-				if errs := CheckJob(db, jobAction); errs != nil {
+				if errs := CheckJob(db, proj.ID, jobAction); errs != nil {
 					log.Debug("CheckJob > %s", errs)
 					return errs
 				}
@@ -128,7 +128,7 @@ func ImportUpdate(db gorp.SqlExecutor, proj *sdk.Project, pip *sdk.Pipeline, msg
 			for x := range s.Jobs {
 				jobAction := &s.Jobs[x]
 				//Check the job
-				if errs := CheckJob(db, jobAction); errs != nil {
+				if errs := CheckJob(db, proj.ID, jobAction); errs != nil {
 					log.Debug(">> CheckJob > %s", errs)
 					return errs
 				}
@@ -144,7 +144,7 @@ func ImportUpdate(db gorp.SqlExecutor, proj *sdk.Project, pip *sdk.Pipeline, msg
 						j.PipelineStageID = oj.PipelineStageID
 						j.Action.Type = sdk.JoinedAction
 						log.Debug(">> Updating job %s on stage %s on pipeline %s", j.Action.Name, s.Name, pip.Name)
-						if err := UpdateJob(db, j, u.ID); err != nil {
+						if err := UpdateJob(db, j, proj.ID, u.ID); err != nil {
 							return sdk.WrapError(err, "ImportUpdate> Unable to update job %s in %s", j.Action.Name, pip.Name)
 						}
 						if msgChan != nil {
@@ -285,7 +285,7 @@ func importNew(db gorp.SqlExecutor, proj *sdk.Project, pip *sdk.Pipeline, u *sdk
 			jobAction := &s.Jobs[i]
 			jobAction.Enabled = true
 			jobAction.Action.Enabled = true
-			if errs := CheckJob(db, jobAction); errs != nil {
+			if errs := CheckJob(db, proj.ID, jobAction); errs != nil {
 				log.Debug("CheckJob > %s", errs)
 				return errs
 			}
@@ -5,13 +5,16 @@ import (
 
 	"github.com/go-gorp/gorp"
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
 
+	"github.com/ovh/cds/engine/api/action"
 	"github.com/ovh/cds/engine/api/bootstrap"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/test"
 	"github.com/ovh/cds/engine/api/test/assets"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -502,8 +505,94 @@ func TestImportUpdate(t *testing.T) {
 		},
 	}
 
+	var test8 = testcase{
+		name:    "import an exported pipeline whose step pins a version of a project action",
+		wantErr: false,
+		args: args{
+			u:    u,
+			pkey: sdk.RandomString(7),
+			pip: &sdk.Pipeline{
+				Type: sdk.BuildPipeline,
+			},
+		},
+		setup: func(t *testing.T, args args) {
+			proj := assets.InsertTestProject(t, db, args.pkey, args.pkey, nil)
+			args.pip.Name = proj.Key + "_PIP"
+			args.pip.ProjectID = proj.ID
+			args.pip.ProjectKey = proj.Key
+			test.NoError(t, pipeline.InsertPipeline(db, proj, args.pip, nil))
+
+			a := sdk.Action{
+				Name:       proj.Key + "_ACTION",
+				Type:       sdk.DefaultAction,
+				ProjectID:  proj.ID,
+				Enabled:    true,
+				Parameters: []sdk.Parameter{{Name: "foo", Type: sdk.StringParameter, Value: "v1"}},
+			}
+			test.NoError(t, action.InsertAction(db, &a, false))
+			a.Parameters[0].Value = "v2"
+			test.NoError(t, action.UpdateActionDB(db, &a, u.ID))
+
+			args.pip.Stages = []sdk.Stage{
+				{
+					BuildOrder: 1,
+					Enabled:    true,
+					PipelineID: args.pip.ID,
+					Name:       "This is the first stage. It has 2 jobs",
+					Jobs: []sdk.Job{
+						{
+							Enabled: true,
+							Action: sdk.Action{
+								Name:    "Job n°1",
+								Actions: []sdk.Action{{Name: a.Name, Enabled: true, Version: 1}},
+							},
+						},
+						{
+							Enabled: true,
+							Action: sdk.Action{
+								Name:    "Job n°2",
+								Actions: []sdk.Action{{Name: a.Name, Enabled: true}},
+							},
+						},
+					},
+				},
+			}
+			test.NoError(t, pipeline.InsertStage(db, &args.pip.Stages[0]))
+			for i := range args.pip.Stages[0].Jobs {
+				test.NoError(t, pipeline.InsertJob(db, &args.pip.Stages[0].Jobs[i], args.pip.Stages[0].ID, args.pip))
+			}
+
+			//Export the pipeline and import it back
+			pip, err := pipeline.LoadPipeline(db, proj.Key, args.pip.Name, true)
+			test.NoError(t, err)
+			b, err := exportentities.Marshal(exportentities.NewPipeline(pip), exportentities.FormatYAML)
+			test.NoError(t, err)
+			payload := exportentities.Pipeline{}
+			test.NoError(t, yaml.Unmarshal(b, &payload))
+			imported, err := payload.Pipeline()
+			test.NoError(t, err)
+			imported.ProjectID = proj.ID
+			imported.ProjectKey = proj.Key
+			*args.pip = *imported
+		},
+		asserts: func(t *testing.T, pip sdk.Pipeline) {
+			t.Logf("Asserts on %+v", pip)
+			assert.Equal(t, 1, len(pip.Stages))
+			assert.Equal(t, 2, len(pip.Stages[0].Jobs))
+			for _, j := range pip.Stages[0].Jobs {
+				assert.Equal(t, 1, len(j.Action.Actions))
+				assert.Equal(t, int64(2), j.Action.Actions[0].LatestVersion)
+				if j.Action.Name == "Job n°1" {
+					assert.Equal(t, int64(1), j.Action.Actions[0].Version)
+				} else {
+					assert.Equal(t, int64(0), j.Action.Actions[0].Version)
+				}
+			}
+		},
+	}
+
 	//Run the tests
-	var tests = []testcase{test1, test2, test3, test4, test5, test6, test7}
+	var tests = []testcase{test1, test2, test3, test4, test5, test6, test7, test8}
 	for _, tt := range tests {
 		testImportUpdate(t, db, tt)
 	}
@@ -6,6 +6,7 @@ import (
 	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/businesscontext"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/permission"
@@ -38,6 +39,7 @@ func updateGroupRoleOnPipelineHandler(w http.ResponseWriter, r *http.Request, db
 	if errLoadP != nil {
 		return sdk.WrapError(errLoadP, "updateGroupRoleOnPipelineHandler: Cannot load %s", key)
 	}
+	c.AuditBefore = audit.Permissions(db, "pipeline", p.ID)
 
 	g, errLoadG := group.LoadGroup(db, groupPipeline.Group.Name)
 	if errLoadG != nil {
@@ -79,6 +81,7 @@ func updateGroupRoleOnPipelineHandler(w http.ResponseWriter, r *http.Request, db
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "updateGroupRoleOnPipelineHandler: Cannot start transaction")
 	}
+	c.AuditAfter = audit.Permissions(db, "pipeline", p.ID)
 
 	if err := pipeline.LoadGroupByPipeline(db, p); err != nil {
 		return sdk.WrapError(err, "updateGroupRoleOnPipelineHandler: Cannot load groups for pipeline %s", p.Name)
@@ -121,6 +124,7 @@ func updateGroupsOnPipelineHandler(w http.ResponseWriter, r *http.Request, db *g
 	if errLoad != nil {
 		return sdk.WrapError(errLoad, "updateGroupsOnPipelineHandler: Cannot load %s", key)
 	}
+	c.AuditBefore = audit.Permissions(db, "pipeline", p.ID)
 
 	tx, errb := db.Begin()
 	if errb != nil {
@@ -150,6 +154,7 @@ func updateGroupsOnPipelineHandler(w http.ResponseWriter, r *http.Request, db *g
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "updateGroupsOnPipelineHandler: Cannot commit transaction")
 	}
+	c.AuditAfter = audit.Permissions(db, "pipeline", p.ID)
 
 	return nil
 }
@@ -173,6 +178,7 @@ func addGroupInPipelineHandler(w http.ResponseWriter, r *http.Request, db *gorp.
 	if err != nil {
 		return sdk.WrapError(err, "addGroupInPipeline: Cannot load %s", key)
 	}
+	c.AuditBefore = audit.Permissions(db, "pipeline", p.ID)
 
 	g, err := group.LoadGroup(db, groupPermission.Group.Name)
 	if err != nil {
@@ -205,6 +211,7 @@ func addGroupInPipelineHandler(w http.ResponseWriter, r *http.Request, db *gorp.
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "addGroupInPipeline: Cannot commit transaction")
 	}
+	c.AuditAfter = audit.Permissions(db, "pipeline", p.ID)
 
 	if err := pipeline.LoadGroupByPipeline(db, p); err != nil {
 		return sdk.WrapError(err, "addGroupInPipeline: Cannot load group")
@@ -222,6 +229,7 @@ func deleteGroupFromPipelineHandler(w http.ResponseWriter, r *http.Request, db *
 	if err != nil {
 		return sdk.WrapError(err, "deleteGroupFromPipelineHandler: Cannot load %s", key)
 	}
+	c.AuditBefore = audit.Permissions(db, "pipeline", p.ID)
 
 	g, err := group.LoadGroup(db, groupName)
 	if err != nil {
@@ -250,6 +258,7 @@ func deleteGroupFromPipelineHandler(w http.ResponseWriter, r *http.Request, db *
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "deleteGroupFromPipelineHandler: Cannot commit transaction")
 	}
+	c.AuditAfter = audit.Permissions(db, "pipeline", p.ID)
 
 	if err := pipeline.LoadGroupByPipeline(db, p); err != nil {
 		return sdk.WrapError(err, "deleteGroupFromPipelineHandler: Cannot load groups")
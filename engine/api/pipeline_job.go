@@ -162,7 +162,7 @@ func updateJobHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 		return sdk.WrapError(errlb, "updateJobHandler> cannot load all binary requirements")
 	}
 
-	if err := pipeline.UpdateJob(tx, &job, c.User.ID); err != nil {
+	if err := pipeline.UpdateJob(tx, &job, pipelineData.ProjectID, c.User.ID); err != nil {
 		return sdk.WrapError(err, "updateJobHandler> Cannot update in database")
 	}
 
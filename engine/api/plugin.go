@@ -8,6 +8,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/go-gorp/gorp"
@@ -64,9 +65,38 @@ func fileUploadAndGetPlugin(w http.ResponseWriter, r *http.Request) (*sdk.Action
 		return nil, nil, nil, deferFunc, sdk.WrapError(err, "fileUploadAndGetPlugin> err on Open")
 	}
 
-	ap, params, err := actionplugin.Get(filename, tmpfn)
-	if err != nil {
-		return nil, nil, nil, deferFunc, sdk.WrapError(sdk.ErrPluginInvalid, "fileUploadAndGetPlugin> unable to get plugin info: %s", err)
+	goos, goarch := r.FormValue("os"), r.FormValue("arch")
+	if (goos == "") != (goarch == "") {
+		return nil, nil, nil, deferFunc, sdk.WrapError(sdk.ErrWrongRequest, "fileUploadAndGetPlugin> os and arch must be set together")
+	}
+
+	var ap *sdk.ActionPlugin
+	var params *plugin.Parameters
+	if goos == "" || (goos == runtime.GOOS && goarch == runtime.GOARCH) {
+		ap, params, err = actionplugin.Get(filename, tmpfn)
+		if err != nil {
+			return nil, nil, nil, deferFunc, sdk.WrapError(sdk.ErrPluginInvalid, "fileUploadAndGetPlugin> unable to get plugin info: %s", err)
+		}
+		if name := r.FormValue("name"); name != "" && name != ap.Name {
+			return nil, nil, nil, deferFunc, sdk.WrapError(sdk.ErrPluginInvalid, "fileUploadAndGetPlugin> plugin name is %s, not %s", ap.Name, name)
+		}
+	} else {
+		//The binary can't be run by the API, the plugin is described by the form
+		ap = &sdk.ActionPlugin{Name: r.FormValue("name"), Filename: filename}
+		if ap.Name == "" || r.FormValue("version") == "" {
+			return nil, nil, nil, deferFunc, sdk.WrapError(sdk.ErrWrongRequest, "fileUploadAndGetPlugin> name and version are mandatory for a binary built for %s/%s", goos, goarch)
+		}
+		if err := actionplugin.Stat(ap, tmpfn); err != nil {
+			return nil, nil, nil, deferFunc, sdk.WrapError(err, "fileUploadAndGetPlugin> unable to stat %s", tmpfn)
+		}
+	}
+
+	ap.OS, ap.Arch = goos, goarch
+	if v := r.FormValue("version"); v != "" {
+		ap.Version = v
+	}
+	if sum := r.FormValue("sha256"); sum != "" && sum != ap.SHA256sum {
+		return nil, nil, nil, deferFunc, sdk.WrapError(sdk.ErrPluginChecksum, "fileUploadAndGetPlugin> sha256 of %s is %s, not %s", filename, ap.SHA256sum, sum)
 	}
 
 	return ap, params, content, deferFunc, nil
@@ -83,13 +113,19 @@ func addPluginHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 	}
 	defer file.Close()
 
-	// Check that action does not already exists
-	conflict, err := action.Exists(db, ap.Name)
+	// Check that the binary does not already exists
+	exists, err := action.Exists(db, ap.Name)
 	if err != nil {
 		return sdk.WrapError(err, "updatePluginHandler>%T", err)
 	}
-	if conflict {
-		return sdk.ErrConflict
+	if exists {
+		if _, err := actionplugin.LoadBinary(db, ap.Name, ap.Version, ap.OS, ap.Arch); err == nil {
+			return sdk.ErrConflict
+		} else if err != sdk.ErrPluginNotFound {
+			return sdk.WrapError(err, "addPluginHandler> Unable to load plugin %s", ap.Name)
+		}
+	} else if params == nil {
+		return sdk.WrapError(sdk.ErrPluginInvalid, "addPluginHandler> The first binary of plugin %s must be built for %s/%s", ap.Name, runtime.GOOS, runtime.GOARCH)
 	}
 
 	//Upload it to objectstore
@@ -106,7 +142,15 @@ func addPluginHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 	defer tx.Rollback()
 
 	//Insert in database
-	a, err := actionplugin.Insert(tx, ap, params)
+	var a *sdk.Action
+	if exists {
+		err = actionplugin.AddBinary(tx, ap)
+		if err == nil {
+			a, err = action.LoadPublicAction(tx, ap.Name)
+		}
+	} else {
+		a, err = actionplugin.Insert(tx, ap, params)
+	}
 	if err != nil {
 		objectstore.DeletePlugin(*ap)
 		return sdk.WrapError(err, "addPluginHandler> Error while inserting action %s in database", ap.Name)
@@ -222,14 +266,21 @@ func deletePluginHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap,
 		return sdk.ErrWrongRequest
 	}
 
+	aps, err := actionplugin.LoadByName(db, name)
+	if err != nil {
+		return sdk.WrapError(err, "deletePluginHandler> Error while loading plugin %s", name)
+	}
+
 	//Delete in database
 	if err := actionplugin.Delete(db, name, c.User.ID); err != nil {
 		return sdk.WrapError(err, "deletePluginHandler> Error while deleting action %s in database", name)
 	}
 
 	//Delete from objectstore
-	if err := objectstore.DeletePlugin(sdk.ActionPlugin{Name: name}); err != nil {
-		return sdk.WrapError(err, "deletePluginHandler> Error while deleting action %s in objectstore", name)
+	for _, ap := range aps {
+		if err := objectstore.DeletePlugin(ap); err != nil {
+			return sdk.WrapError(err, "deletePluginHandler> Error while deleting action %s in objectstore", ap.GetName())
+		}
 	}
 	return nil
 }
@@ -242,7 +293,12 @@ func downloadPluginHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 		return sdk.ErrWrongRequest
 	}
 
-	f, err := objectstore.FetchPlugin(sdk.ActionPlugin{Name: name})
+	ap, err := actionplugin.Resolve(db, name, r.FormValue("version"), r.FormValue("os"), r.FormValue("arch"))
+	if err != nil {
+		return sdk.WrapError(err, "downloadPluginHandler> Unable to find plugin %s", name)
+	}
+
+	f, err := objectstore.FetchPlugin(*ap)
 	if err != nil {
 		return sdk.WrapError(err, "downloadPluginHandler> Error while fetching plugin", name)
 	}
@@ -256,3 +312,128 @@ func downloadPluginHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 
 	return nil
 }
+
+func getPluginsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	aps, err := actionplugin.LoadAll(db)
+	if err != nil {
+		return sdk.WrapError(err, "getPluginsHandler> Unable to load plugins")
+	}
+	return WriteJSON(w, r, aps, http.StatusOK)
+}
+
+func getPluginVersionsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	name := vars["name"]
+
+	aps, err := actionplugin.LoadByName(db, name)
+	if err != nil {
+		return sdk.WrapError(err, "getPluginVersionsHandler> Unable to load plugin %s", name)
+	}
+	if len(aps) == 0 {
+		return sdk.ErrPluginNotFound
+	}
+	return WriteJSON(w, r, aps, http.StatusOK)
+}
+
+func getPluginBinaryHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	name := vars["name"]
+
+	ap, err := actionplugin.Resolve(db, name, r.FormValue("version"), r.FormValue("os"), r.FormValue("arch"))
+	if err != nil {
+		return sdk.WrapError(err, "getPluginBinaryHandler> Unable to find plugin %s", name)
+	}
+	return WriteJSON(w, r, ap, http.StatusOK)
+}
+
+func postPromotePluginVersionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	name := vars["name"]
+	version := vars["version"]
+
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WrapError(err, "postPromotePluginVersionHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	if err := actionplugin.Promote(tx, name, version); err != nil {
+		return sdk.WrapError(err, "postPromotePluginVersionHandler> Unable to promote %s@%s", name, version)
+	}
+
+	//Update the action with the parameters of the promoted version
+	ap, err := actionplugin.LoadBinary(tx, name, version, runtime.GOOS, runtime.GOARCH)
+	if err == sdk.ErrPluginNotFound {
+		ap, err = actionplugin.LoadBinary(tx, name, version, "", "")
+	}
+	if err != nil && err != sdk.ErrPluginNotFound {
+		return sdk.WrapError(err, "postPromotePluginVersionHandler> Unable to load %s@%s", name, version)
+	}
+	if ap != nil {
+		if err := updatePluginAction(tx, ap, c.User.ID); err != nil {
+			return sdk.WrapError(err, "postPromotePluginVersionHandler> Unable to update action %s", name)
+		}
+	} else {
+		log.Warning("postPromotePluginVersionHandler> No binary of %s@%s for %s/%s, action is not updated", name, version, runtime.GOOS, runtime.GOARCH)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "postPromotePluginVersionHandler> Cannot commit transaction")
+	}
+
+	aps, err := actionplugin.LoadByName(db, name)
+	if err != nil {
+		return sdk.WrapError(err, "postPromotePluginVersionHandler> Unable to load plugin %s", name)
+	}
+	return WriteJSON(w, r, aps, http.StatusOK)
+}
+
+func postDeprecatePluginVersionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	name := vars["name"]
+	version := vars["version"]
+
+	if err := actionplugin.Deprecate(db, name, version); err != nil {
+		return sdk.WrapError(err, "postDeprecatePluginVersionHandler> Unable to deprecate %s@%s", name, version)
+	}
+
+	aps, err := actionplugin.LoadByName(db, name)
+	if err != nil {
+		return sdk.WrapError(err, "postDeprecatePluginVersionHandler> Unable to load plugin %s", name)
+	}
+	return WriteJSON(w, r, aps, http.StatusOK)
+}
+
+//updatePluginAction fetches the binary of the plugin from objectstore and updates the action with its parameters
+func updatePluginAction(db gorp.SqlExecutor, ap *sdk.ActionPlugin, userID int64) error {
+	f, err := objectstore.FetchPlugin(*ap)
+	if err != nil {
+		return sdk.WrapError(err, "updatePluginAction> Unable to fetch plugin %s", ap.GetName())
+	}
+	defer f.Close()
+
+	tmpDir, err := ioutil.TempDir("", "cds-plugin")
+	if err != nil {
+		return sdk.WrapError(err, "updatePluginAction> error with tempdir")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpFile := path.Join(tmpDir, ap.Name)
+	out, err := os.OpenFile(tmpFile, os.O_WRONLY|os.O_CREATE, 0700)
+	if err != nil {
+		return sdk.WrapError(err, "updatePluginAction> Error opening file %s", tmpFile)
+	}
+	_, errCopy := io.Copy(out, f)
+	out.Close()
+	if errCopy != nil {
+		return sdk.WrapError(errCopy, "updatePluginAction> Error writing file %s", tmpFile)
+	}
+
+	newAp, params, err := actionplugin.Get(ap.Name, tmpFile)
+	if err != nil {
+		return sdk.WrapError(sdk.ErrPluginInvalid, "updatePluginAction> unable to get plugin info: %s", err)
+	}
+
+	_, err = actionplugin.UpdateAction(db, newAp, params, userID)
+	return err
+}
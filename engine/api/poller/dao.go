@@ -52,7 +52,10 @@ func Update(db gorp.SqlExecutor, poller *sdk.RepositoryPoller) error {
 	if _, err := db.Exec(query, poller.Application.ID, poller.Pipeline.ID, poller.Enabled, poller.Name); err != nil {
 		return sdk.WrapError(err, "UpdatePoller> Error")
 	}
-	return nil
+	dbPoller := RepositoryPoller(*poller)
+	dbPoller.ApplicationID = poller.Application.ID
+	dbPoller.PipelineID = poller.Pipeline.ID
+	return dbPoller.PostUpdate(db)
 }
 
 // LoadAll retrieves all poller from database
@@ -3,7 +3,6 @@ package poller
 import (
 	"context"
 	"database/sql"
-	"regexp"
 	"time"
 
 	"github.com/go-gorp/gorp"
@@ -170,7 +169,7 @@ func executerProcess(tx gorp.SqlExecutor, p *sdk.RepositoryPoller, e *sdk.Reposi
 	var pbs []sdk.PipelineBuild
 	if len(e.PushEvents) > 0 {
 		var err error
-		pbs, err = triggerPipelines(tx, projectKey, rm, p, e)
+		pbs, err = triggerPipelines(tx, projectKey, rm, client, p, e)
 		if err != nil {
 			return nil, sdk.WrapError(err, "Polling> Unable to trigger pipeline %s for repository %s", p.Pipeline.Name, p.Application.RepositoryFullname)
 		}
@@ -183,7 +182,7 @@ func executerProcess(tx gorp.SqlExecutor, p *sdk.RepositoryPoller, e *sdk.Reposi
 	return pbs, nil
 }
 
-func triggerPipelines(tx gorp.SqlExecutor, projectKey string, rm *sdk.RepositoriesManager, poller *sdk.RepositoryPoller, e *sdk.RepositoryPollerExecution) ([]sdk.PipelineBuild, error) {
+func triggerPipelines(tx gorp.SqlExecutor, projectKey string, rm *sdk.RepositoriesManager, client sdk.RepositoriesManagerClient, poller *sdk.RepositoryPoller, e *sdk.RepositoryPollerExecution) ([]sdk.PipelineBuild, error) {
 	proj, err := project.LoadByPipelineID(tx, nil, poller.Pipeline.ID)
 	if err != nil {
 		return nil, sdk.WrapError(err, "Polling.triggerPipelines> Cannot load project for pipeline %s", poller.Pipeline.Name)
@@ -193,7 +192,7 @@ func triggerPipelines(tx gorp.SqlExecutor, projectKey string, rm *sdk.Repositori
 
 	var pbs []sdk.PipelineBuild
 	for _, event := range e.PushEvents {
-		pb, err := triggerPipeline(tx, rm, poller, event, proj)
+		pb, err := triggerPipeline(tx, rm, client, poller, event, proj)
 		if err != nil {
 			return nil, sdk.WrapError(err, "Polling.triggerPipelines> cannot trigger pipeline %d", poller.Pipeline.ID)
 		}
@@ -206,7 +205,7 @@ func triggerPipelines(tx gorp.SqlExecutor, projectKey string, rm *sdk.Repositori
 	}
 
 	for _, event := range e.CreateEvents {
-		pb, err := triggerPipeline(tx, rm, poller, sdk.VCSPushEvent(event), proj)
+		pb, err := triggerPipeline(tx, rm, client, poller, sdk.VCSPushEvent(event), proj)
 		if err != nil {
 			return nil, sdk.WrapError(err, "Polling.triggerPipelines> cannot trigger pipeline %d", poller.Pipeline.ID)
 		}
@@ -231,9 +230,23 @@ func triggerPipelines(tx gorp.SqlExecutor, projectKey string, rm *sdk.Repositori
 	return pbs, nil
 }
 
-func triggerPipeline(tx gorp.SqlExecutor, rm *sdk.RepositoriesManager, poller *sdk.RepositoryPoller, e sdk.VCSPushEvent, proj *sdk.Project) (*sdk.PipelineBuild, error) {
+func triggerPipeline(tx gorp.SqlExecutor, rm *sdk.RepositoriesManager, client sdk.RepositoriesManagerClient, poller *sdk.RepositoryPoller, e sdk.VCSPushEvent, proj *sdk.Project) (*sdk.PipelineBuild, error) {
+	// Check the branch or the tag against the poller filter
+	if !poller.Filter.MatchRef(e.Branch.ID) {
+		log.Debug("polling> Skipping build of %s/%s for %s: filtered", proj.Key, poller.Application.Name, e.Branch.ID)
+		return nil, nil
+	}
+	ref, tag := sdk.GitRef(e.Branch.ID)
+
 	// Create pipeline args
 	var params []sdk.Parameter
+	if tag {
+		params = append(params, sdk.Parameter{
+			Name:  "git.tag",
+			Type:  sdk.StringParameter,
+			Value: ref,
+		})
+	}
 
 	// Load pipeline Argument
 	parameters, errg := pipeline.GetAllParametersInPipeline(tx, poller.Pipeline.ID)
@@ -249,21 +262,29 @@ func triggerPipeline(tx gorp.SqlExecutor, rm *sdk.RepositoriesManager, poller *s
 
 	trigger := sdk.PipelineBuildTrigger{
 		ManualTrigger:    false,
-		VCSChangesBranch: e.Branch.ID,
+		VCSChangesBranch: ref,
 		VCSChangesHash:   e.Commit.Hash,
 		VCSChangesAuthor: e.Commit.Author.DisplayName,
 	}
 
-	// Get commit message to check if we have to skip the build
-	match, errm := regexp.Match(".*\\[ci skip\\].*|.*\\[cd skip\\].*", []byte(e.Commit.Message))
-	if errm != nil {
-		log.Warning("polling> Cannot check %s/%s for commit %s by %s : %s (%s)", proj.Key, poller.Application.Name, trigger.VCSChangesHash, trigger.VCSChangesAuthor, e.Commit.Message, errm)
-	}
-	if match {
+	// Check commit message to check if we have to skip the build
+	if poller.Filter.Skip(e.Commit.Message) {
 		log.Debug("polling> Skipping build of %s/%s for commit %s by %s", proj.Key, poller.Application.Name, trigger.VCSChangesHash, trigger.VCSChangesAuthor)
 		return nil, nil
 	}
 
+	// Check the files changed by the pushes against the poller filter, like the hooks the build is triggered
+	// if they cannot be listed
+	if len(poller.Filter.Paths) > 0 {
+		files, err := client.ChangedFiles(poller.Application.RepositoryFullname, e.Before, e.Commit.Hash)
+		if err != nil {
+			log.Warning("polling> Cannot get files changed by %s..%s on %s: %s", e.Before, e.Commit.Hash, poller.Application.RepositoryFullname, err)
+		} else if !poller.Filter.MatchPaths(files) {
+			log.Debug("polling> Skipping build of %s/%s for %s..%s: no change in %v", proj.Key, poller.Application.Name, e.Before, trigger.VCSChangesHash, poller.Filter.Paths)
+			return nil, nil
+		}
+	}
+
 	//Check if build exists
 	if b, err := pipeline.BuildExists(tx, poller.Application.ID, poller.Pipeline.ID, sdk.DefaultEnv.ID, &trigger); err != nil || b {
 		if err != nil {
@@ -1,6 +1,7 @@
 package poller
 
 import (
+	"database/sql"
 	"encoding/json"
 
 	"github.com/go-gorp/gorp"
@@ -35,6 +36,23 @@ func (p *RepositoryPoller) PreUpdate(s gorp.SqlExecutor) error {
 	return nil
 }
 
+// PostInsert is a DB Hook, it stores the filter
+func (p *RepositoryPoller) PostInsert(s gorp.SqlExecutor) error {
+	filter, err := gorpmapping.JSONToNullString(p.Filter)
+	if err != nil {
+		return sdk.WrapError(err, "PostInsert> unable to get json from filter")
+	}
+	if _, err := s.Exec("update poller set filter = $3 where application_id = $1 and pipeline_id = $2", p.ApplicationID, p.PipelineID, filter); err != nil {
+		return sdk.WrapError(err, "PostInsert> unable to update filter of poller %d/%d", p.ApplicationID, p.PipelineID)
+	}
+	return nil
+}
+
+// PostUpdate is a DB Hook, it stores the filter
+func (p *RepositoryPoller) PostUpdate(s gorp.SqlExecutor) error {
+	return p.PostInsert(s)
+}
+
 // PreDelete is a DB Hook
 func (p *RepositoryPoller) PreDelete(s gorp.SqlExecutor) error {
 	if _, err := s.Exec("delete from poller_execution where application_id = $1 and pipeline_id = $2", p.ApplicationID, p.PipelineID); err != nil {
@@ -56,6 +74,14 @@ func (p *RepositoryPoller) PostGet(db gorp.SqlExecutor) error {
 	p.Application = *app
 	p.Pipeline = *pip
 
+	var filter sql.NullString
+	if err := db.QueryRow("select filter from poller where application_id = $1 and pipeline_id = $2", p.ApplicationID, p.PipelineID).Scan(&filter); err != nil {
+		return sdk.WrapError(err, "PostGet> error loading filter of poller %d/%d", p.ApplicationID, p.PipelineID)
+	}
+	if err := gorpmapping.JSONNullString(filter, &p.Filter); err != nil {
+		return sdk.WrapError(err, "PostGet> error unmarshalling filter of poller %d/%d", p.ApplicationID, p.PipelineID)
+	}
+
 	next, errN := LoadNextExecution(db, app.ID, pip.ID)
 	if errN != nil {
 		return sdk.WrapError(errN, "PostGet> Cannot load nextexecution")
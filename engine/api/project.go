@@ -48,6 +48,10 @@ func updateProjectHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap
 		return sdk.WrapError(sdk.ErrInvalidProjectName, "updateProject> Project name must no be empty")
 	}
 
+	if err := sdk.IsValidJobPriority(proj.JobPriority); err != nil {
+		return sdk.WrapError(err, "updateProject> Invalid job priority")
+	}
+
 	// Check Request
 	if key != proj.Key {
 		return sdk.WrapError(sdk.ErrWrongRequest, "updateProject> bad Project key %s/%s ", key, proj.Key)
@@ -136,6 +140,10 @@ func addProjectHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 		return sdk.WrapError(sdk.ErrInvalidProjectName, "AddProject> Project name must no be empty")
 	}
 
+	if err := sdk.IsValidJobPriority(p.JobPriority); err != nil {
+		return sdk.WrapError(err, "AddProject> Invalid job priority")
+	}
+
 	// Check that project does not already exists
 	exist, errExist := project.Exist(db, p.Key)
 	if errExist != nil {
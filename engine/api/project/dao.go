@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/action"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/group"
@@ -184,6 +185,10 @@ func DeleteByID(db gorp.SqlExecutor, id int64) error {
 		return err
 	}
 
+	if err := action.DeleteProjectActions(db, id); err != nil {
+		return err
+	}
+
 	if _, err := db.Exec(`DELETE FROM repositories_manager_project WHERE id_project = $1`, id); err != nil {
 		return err
 	}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/action"
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/sdk"
+)
+
+// loadProjectAction loads an action private to the project
+func loadProjectAction(db gorp.SqlExecutor, key, name string, c *businesscontext.Ctx) (*sdk.Action, error) {
+	proj, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return nil, sdk.WrapError(errP, "loadProjectAction> Cannot load project %s", key)
+	}
+
+	a, errA := action.LoadProjectAction(db, proj.ID, name)
+	if errA != nil {
+		return nil, sdk.WrapError(errA, "loadProjectAction> Cannot load action %s in project %s", name, key)
+	}
+	return a, nil
+}
+
+func getProjectActionsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+
+	proj, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "getProjectActionsHandler> Cannot load project %s", key)
+	}
+
+	acts, errA := action.LoadProjectActions(db, proj.ID)
+	if errA != nil {
+		return sdk.WrapError(errA, "getProjectActionsHandler> Cannot load actions of project %s", key)
+	}
+	return WriteJSON(w, r, acts, http.StatusOK)
+}
+
+func getProjectActionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	a, err := loadProjectAction(db, vars["permProjectKey"], vars["actionName"], c)
+	if err != nil {
+		return err
+	}
+	return WriteJSON(w, r, a, http.StatusOK)
+}
+
+func addProjectActionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+
+	var a sdk.Action
+	if err := UnmarshalBody(r, &a); err != nil {
+		return err
+	}
+
+	proj, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "addProjectActionHandler> Cannot load project %s", key)
+	}
+
+	if _, err := action.LoadProjectAction(db, proj.ID, a.Name); err == nil {
+		return sdk.WrapError(sdk.ErrConflict, "addProjectActionHandler> Action %s already exists in project %s", a.Name, key)
+	} else if err != sdk.ErrNoAction {
+		return sdk.WrapError(err, "addProjectActionHandler> Cannot check if action %s exists", a.Name)
+	}
+
+	tx, errB := db.Begin()
+	if errB != nil {
+		return sdk.WrapError(errB, "addProjectActionHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	a.Type = sdk.DefaultAction
+	a.ProjectID = proj.ID
+	if err := action.ResolveChildren(tx, proj.ID, &a); err != nil {
+		return sdk.WrapError(err, "addProjectActionHandler> Cannot resolve steps of action %s", a.Name)
+	}
+	if err := action.InsertAction(tx, &a, false); err != nil {
+		return sdk.WrapError(err, "addProjectActionHandler> Cannot insert action %s", a.Name)
+	}
+
+	if err := project.UpdateLastModified(tx, c.User, proj); err != nil {
+		return sdk.WrapError(err, "addProjectActionHandler> Cannot update project last modified date")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "addProjectActionHandler> Cannot commit transaction")
+	}
+
+	return WriteJSON(w, r, a, http.StatusOK)
+}
+
+func updateProjectActionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+	name := vars["actionName"]
+
+	var a sdk.Action
+	if err := UnmarshalBody(r, &a); err != nil {
+		return err
+	}
+
+	proj, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "updateProjectActionHandler> Cannot load project %s", key)
+	}
+
+	actionDB, errA := action.LoadProjectAction(db, proj.ID, name)
+	if errA != nil {
+		return sdk.WrapError(errA, "updateProjectActionHandler> Cannot load action %s", name)
+	}
+	if actionDB.Source != "" {
+		return sdk.WrapError(sdk.ErrActionManagedBySource, "updateProjectActionHandler> Action %s is synchronized from source %s", name, actionDB.Source)
+	}
+
+	tx, errB := db.Begin()
+	if errB != nil {
+		return sdk.WrapError(errB, "updateProjectActionHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	a.ID = actionDB.ID
+	a.Type = actionDB.Type
+	a.ProjectID = proj.ID
+	if err := action.ResolveChildren(tx, proj.ID, &a); err != nil {
+		return sdk.WrapError(err, "updateProjectActionHandler> Cannot resolve steps of action %s", name)
+	}
+	if err := action.UpdateActionDB(tx, &a, c.User.ID); err != nil {
+		return sdk.WrapError(err, "updateProjectActionHandler> Cannot update action %s", name)
+	}
+
+	if err := project.UpdateLastModified(tx, c.User, proj); err != nil {
+		return sdk.WrapError(err, "updateProjectActionHandler> Cannot update project last modified date")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "updateProjectActionHandler> Cannot commit transaction")
+	}
+
+	return WriteJSON(w, r, a, http.StatusOK)
+}
+
+func deleteProjectActionHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+	name := vars["actionName"]
+
+	proj, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "deleteProjectActionHandler> Cannot load project %s", key)
+	}
+
+	a, errA := action.LoadProjectAction(db, proj.ID, name)
+	if errA != nil {
+		return sdk.WrapError(errA, "deleteProjectActionHandler> Cannot load action %s", name)
+	}
+
+	used, errU := action.Used(db, a.ID)
+	if errU != nil {
+		return sdk.WrapError(errU, "deleteProjectActionHandler> Cannot check if action %s is used", name)
+	}
+	if used {
+		return sdk.WrapError(sdk.ErrForbidden, "deleteProjectActionHandler> Cannot delete action %s: used in pipelines", name)
+	}
+
+	tx, errB := db.Begin()
+	if errB != nil {
+		return sdk.WrapError(errB, "deleteProjectActionHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	if err := action.DeleteAction(tx, a.ID, c.User.ID); err != nil {
+		return sdk.WrapError(err, "deleteProjectActionHandler> Cannot delete action %s", name)
+	}
+
+	if err := project.UpdateLastModified(tx, c.User, proj); err != nil {
+		return sdk.WrapError(err, "deleteProjectActionHandler> Cannot update project last modified date")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "deleteProjectActionHandler> Cannot commit transaction")
+	}
+
+	return nil
+}
+
+// postActionVersionDeprecateHandler deprecates a version of a public action or of an action of a project
+func postActionVersionDeprecateHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	version, errV := requestVarInt(r, "version")
+	if errV != nil {
+		return errV
+	}
+
+	_, _, id, err := historyObject(db, r, c)
+	if err != nil {
+		return err
+	}
+
+	if err := action.DeprecateVersion(db, id, version); err != nil {
+		return sdk.WrapError(err, "postActionVersionDeprecateHandler> Cannot deprecate version %d", version)
+	}
+
+	a, errA := action.LoadVersion(db, id, version)
+	if errA != nil {
+		return sdk.WrapError(errA, "postActionVersionDeprecateHandler> Cannot load version %d", version)
+	}
+	return WriteJSON(w, r, a, http.StatusOK)
+}
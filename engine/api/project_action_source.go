@@ -0,0 +1,153 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/actionsource"
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/sdk"
+)
+
+func getProjectActionSourcesHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+
+	proj, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "getProjectActionSourcesHandler> Cannot load project %s", key)
+	}
+
+	sources, errS := actionsource.LoadAll(db, proj.ID)
+	if errS != nil {
+		return sdk.WrapError(errS, "getProjectActionSourcesHandler> Cannot load action sources of project %s", key)
+	}
+	return WriteJSON(w, r, sources, http.StatusOK)
+}
+
+func getProjectActionSourceHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+	name := vars["sourceName"]
+
+	proj, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "getProjectActionSourceHandler> Cannot load project %s", key)
+	}
+
+	s, errS := actionsource.Load(db, proj.ID, name)
+	if errS != nil {
+		return sdk.WrapError(errS, "getProjectActionSourceHandler> Cannot load action source %s", name)
+	}
+	return WriteJSON(w, r, s, http.StatusOK)
+}
+
+func addProjectActionSourceHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+
+	var s sdk.ActionSource
+	if err := UnmarshalBody(r, &s); err != nil {
+		return err
+	}
+
+	proj, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "addProjectActionSourceHandler> Cannot load project %s", key)
+	}
+
+	s.ProjectID = proj.ID
+	if err := actionsource.Insert(db, &s); err != nil {
+		return sdk.WrapError(err, "addProjectActionSourceHandler> Cannot insert action source %s", s.Name)
+	}
+
+	// The first synchronization is done by the synchronizer, as cloning the repository can be long
+	return WriteJSON(w, r, s, http.StatusCreated)
+}
+
+func updateProjectActionSourceHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+	name := vars["sourceName"]
+
+	var s sdk.ActionSource
+	if err := UnmarshalBody(r, &s); err != nil {
+		return err
+	}
+
+	proj, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "updateProjectActionSourceHandler> Cannot load project %s", key)
+	}
+
+	sourceDB, errS := actionsource.Load(db, proj.ID, name)
+	if errS != nil {
+		return sdk.WrapError(errS, "updateProjectActionSourceHandler> Cannot load action source %s", name)
+	}
+	if s.Name != sourceDB.Name {
+		if _, err := actionsource.Load(db, proj.ID, s.Name); err == nil {
+			return sdk.WrapError(sdk.ErrActionSourceExists, "updateProjectActionSourceHandler> Action source %s already exists", s.Name)
+		}
+	}
+
+	// The synchronization status is kept until the next synchronization
+	s.ID = sourceDB.ID
+	s.ProjectID = proj.ID
+	s.LastCommit = sourceDB.LastCommit
+	s.LastSync = sourceDB.LastSync
+	s.SyncErrors = sourceDB.SyncErrors
+	if err := actionsource.Update(db, &s); err != nil {
+		return sdk.WrapError(err, "updateProjectActionSourceHandler> Cannot update action source %s", name)
+	}
+	return WriteJSON(w, r, s, http.StatusOK)
+}
+
+func deleteProjectActionSourceHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+	name := vars["sourceName"]
+
+	proj, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "deleteProjectActionSourceHandler> Cannot load project %s", key)
+	}
+
+	s, errS := actionsource.Load(db, proj.ID, name)
+	if errS != nil {
+		return sdk.WrapError(errS, "deleteProjectActionSourceHandler> Cannot load action source %s", name)
+	}
+
+	if err := actionsource.Delete(db, s); err != nil {
+		return sdk.WrapError(err, "deleteProjectActionSourceHandler> Cannot delete action source %s", name)
+	}
+	return nil
+}
+
+// postProjectActionSourceSyncHandler synchronizes an action source without waiting for the synchronizer
+func postProjectActionSourceSyncHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+	name := vars["sourceName"]
+
+	proj, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "postProjectActionSourceSyncHandler> Cannot load project %s", key)
+	}
+
+	s, errS := actionsource.Load(db, proj.ID, name)
+	if errS != nil {
+		return sdk.WrapError(errS, "postProjectActionSourceSyncHandler> Cannot load action source %s", name)
+	}
+
+	if err := actionsource.Synchronize(db, s, c.User.ID); err != nil {
+		return sdk.WrapError(err, "postProjectActionSourceSyncHandler> Cannot synchronize action source %s", name)
+	}
+
+	if err := project.UpdateLastModified(db, c.User, proj); err != nil {
+		return sdk.WrapError(err, "postProjectActionSourceSyncHandler> Cannot update project last modified date")
+	}
+	return WriteJSON(w, r, s, http.StatusOK)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/businesscontext"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/group"
@@ -26,6 +27,7 @@ func deleteGroupFromProjectHandler(w http.ResponseWriter, r *http.Request, db *g
 	if err != nil {
 		return sdk.WrapError(err, "deleteGroupFromProjectHandler: Cannot load %s", key)
 	}
+	c.AuditBefore = audit.Permissions(db, "project", p.ID)
 
 	g, err := group.LoadGroup(db, groupName)
 	if err != nil {
@@ -49,6 +51,7 @@ func deleteGroupFromProjectHandler(w http.ResponseWriter, r *http.Request, db *g
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "deleteGroupFromProjectHandler: Cannot commit transaction")
 	}
+	c.AuditAfter = audit.Permissions(db, "project", p.ID)
 
 	return WriteJSON(w, r, nil, http.StatusOK)
 }
@@ -72,6 +75,7 @@ func updateGroupRoleOnProjectHandler(w http.ResponseWriter, r *http.Request, db
 	if errl != nil {
 		return sdk.WrapError(errl, "updateGroupRoleHandler: Cannot load %s: %s", key)
 	}
+	c.AuditBefore = audit.Permissions(db, "project", p.ID)
 
 	g, errlg := group.LoadGroup(db, groupProject.Group.Name)
 	if errlg != nil {
@@ -115,6 +119,7 @@ func updateGroupRoleOnProjectHandler(w http.ResponseWriter, r *http.Request, db
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "updateGroupRoleHandler: Cannot start transaction: %s")
 	}
+	c.AuditAfter = audit.Permissions(db, "project", p.ID)
 	return WriteJSON(w, r, groupProject, http.StatusOK)
 }
 
@@ -148,6 +153,7 @@ func updateGroupsInProject(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 	if err != nil {
 		return sdk.WrapError(err, "updateGroupsInProject: Cannot load %s")
 	}
+	c.AuditBefore = audit.Permissions(db, "project", p.ID)
 
 	tx, err := db.Begin()
 	if err != nil {
@@ -174,6 +180,7 @@ func updateGroupsInProject(w http.ResponseWriter, r *http.Request, db *gorp.DbMa
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "updateGroupsInProject: Cannot commit transaction")
 	}
+	c.AuditAfter = audit.Permissions(db, "project", p.ID)
 	return nil
 }
 
@@ -191,6 +198,7 @@ func addGroupInProject(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 	if errl != nil {
 		return sdk.WrapError(errl, "AddGroupInProject: Cannot load %s", key)
 	}
+	c.AuditBefore = audit.Permissions(db, "project", p.ID)
 
 	g, errlg := group.LoadGroup(db, groupProject.Group.Name)
 	if errlg != nil {
@@ -288,6 +296,7 @@ func addGroupInProject(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "AddGroupInProject: Cannot commit transaction")
 	}
+	c.AuditAfter = audit.Permissions(db, "project", p.ID)
 
 	if err := group.LoadGroupByProject(db, p); err != nil {
 		return sdk.WrapError(err, "AddGroupInProject: Cannot load groups on project %s", p.Key)
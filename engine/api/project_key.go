@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-gorp/gorp"
@@ -45,6 +46,7 @@ func deleteKeyInProjectHandler(w http.ResponseWriter, r *http.Request, db *gorp.
 	defer tx.Rollback()
 	for _, k := range p.Keys {
 		if k.Name == keyName {
+			c.AuditBefore = fmt.Sprintf("key=%s type=%s", k.Name, k.Type)
 			if err := project.DeleteProjectKey(tx, p.ID, keyName); err != nil {
 				return sdk.WrapError(err, "deleteKeyInProjectHandler> Cannot delete key %s", k.Name)
 			}
@@ -104,6 +106,7 @@ func addKeyInProjectHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbM
 	if err := project.InsertKey(tx, &newKey); err != nil {
 		return sdk.WrapError(err, "addKeyInProjectHandler> Cannot insert project key")
 	}
+	c.AuditAfter = fmt.Sprintf("key=%s type=%s", newKey.Name, newKey.Type)
 
 	if err := project.UpdateLastModified(tx, c.User, p); err != nil {
 		return sdk.WrapError(err, "addKeyInProjectHandler> Cannot update project last modified date")
@@ -0,0 +1,31 @@
+package queue
+
+import (
+	"fmt"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/approval"
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertPipelineBuildApproval pauses the pipeline build started by the trigger, automatically or manually,
+// until it is approved
+func InsertPipelineBuildApproval(db gorp.SqlExecutor, t sdk.PipelineTrigger, pb *sdk.PipelineBuild) error {
+	description := fmt.Sprintf("%s/%s/%s[%s] #%d", t.DestProject.Key, t.DestApplication.Name, t.DestPipeline.Name, t.DestEnvironment.Name, pb.BuildNumber)
+	a := sdk.NewApproval(*t.Approval, t.DestProject.ID, description, pb.Trigger.VCSChangesAuthor)
+	a.PipelineBuildID = pb.ID
+	return approval.Insert(db, a)
+}
+
+// pipelineBuildApproval returns the status of the approval of the pipeline build, StatusSuccess if it has none
+func pipelineBuildApproval(db gorp.SqlExecutor, pb *sdk.PipelineBuild) (sdk.Status, error) {
+	a, err := approval.LoadByPipelineBuildID(db, pb.ID)
+	if err != nil {
+		return sdk.StatusUnknown, err
+	}
+	if a == nil {
+		return sdk.StatusSuccess, nil
+	}
+	return approval.Check(db, a)
+}
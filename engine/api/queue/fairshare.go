@@ -0,0 +1,116 @@
+package queue
+
+import (
+	"sort"
+	"time"
+)
+
+// Job is a job waiting in queue, as seen by the fair share scheduler
+type Job struct {
+	ID        int64
+	ProjectID int64
+	Priority  int
+	Queued    time.Time
+}
+
+// FairShare orders jobs by priority, highest first. Jobs with the same priority are served
+// in turn by project: the project with the fewest building and already served jobs is served
+// first, so that a project triggering many jobs does not starve the others.
+func FairShare(jobs []Job, building map[int64]int) []Job {
+	byPriority := map[int]map[int64][]Job{}
+	priorities := []int{}
+	for _, j := range jobs {
+		projects, ok := byPriority[j.Priority]
+		if !ok {
+			projects = map[int64][]Job{}
+			byPriority[j.Priority] = projects
+			priorities = append(priorities, j.Priority)
+		}
+		projects[j.ProjectID] = append(projects[j.ProjectID], j)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(priorities)))
+
+	served := map[int64]int{}
+	for projectID, n := range building {
+		served[projectID] = n
+	}
+
+	res := make([]Job, 0, len(jobs))
+	for _, p := range priorities {
+		projects := byPriority[p]
+		for _, js := range projects {
+			sort.Slice(js, func(i, j int) bool {
+				if js[i].Queued.Equal(js[j].Queued) {
+					return js[i].ID < js[j].ID
+				}
+				return js[i].Queued.Before(js[j].Queued)
+			})
+		}
+
+		for len(projects) > 0 {
+			var next int64
+			first := true
+			for projectID, js := range projects {
+				if first || before(projectID, js[0], next, projects[next][0], served) {
+					next = projectID
+					first = false
+				}
+			}
+			res = append(res, projects[next][0])
+			served[next]++
+			if len(projects[next]) == 1 {
+				delete(projects, next)
+			} else {
+				projects[next] = projects[next][1:]
+			}
+		}
+	}
+	return res
+}
+
+// before returns true if the next job of project a has to be served before the next job of project b
+func before(a int64, ja Job, b int64, jb Job, served map[int64]int) bool {
+	if served[a] != served[b] {
+		return served[a] < served[b]
+	}
+	if !ja.Queued.Equal(jb.Queued) {
+		return ja.Queued.Before(jb.Queued)
+	}
+	return a < b
+}
+
+// WithinQuotas returns the ordered jobs which can start without exceeding the concurrency quotas of groups.
+// projectGroups are the groups with a quota of each project, and quotas the max concurrent jobs of each group.
+func WithinQuotas(jobs []Job, building map[int64]int, projectGroups map[int64][]int64, quotas map[int64]int) []Job {
+	used := map[int64]int{}
+	for projectID, n := range building {
+		for _, g := range projectGroups[projectID] {
+			used[g] += n
+		}
+	}
+
+	res := []Job{}
+	for _, j := range jobs {
+		groups := projectGroups[j.ProjectID]
+		full := false
+		for _, g := range groups {
+			if used[g] >= quotas[g] {
+				full = true
+				break
+			}
+		}
+		if full {
+			continue
+		}
+		for _, g := range groups {
+			used[g]++
+		}
+		res = append(res, j)
+	}
+	return res
+}
+
+// CanStart returns true if a new job of the project can start without exceeding the concurrency quotas of its groups
+func CanStart(projectID int64, building map[int64]int, projectGroups map[int64][]int64, quotas map[int64]int) bool {
+	return len(WithinQuotas([]Job{{ProjectID: projectID}}, building, projectGroups, quotas)) == 1
+}
@@ -0,0 +1,78 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ids(jobs []Job) []int64 {
+	res := make([]int64, len(jobs))
+	for i, j := range jobs {
+		res[i] = j.ID
+	}
+	return res
+}
+
+func TestFairShare(t *testing.T) {
+	now := time.Now()
+	jobs := []Job{
+		// project 1 triggers many jobs first
+		{ID: 1, ProjectID: 1, Priority: 50, Queued: now},
+		{ID: 2, ProjectID: 1, Priority: 50, Queued: now.Add(1 * time.Second)},
+		{ID: 3, ProjectID: 1, Priority: 50, Queued: now.Add(2 * time.Second)},
+		{ID: 4, ProjectID: 1, Priority: 50, Queued: now.Add(3 * time.Second)},
+		// project 2 and 3 trigger jobs later
+		{ID: 5, ProjectID: 2, Priority: 50, Queued: now.Add(10 * time.Second)},
+		{ID: 6, ProjectID: 2, Priority: 50, Queued: now.Add(11 * time.Second)},
+		{ID: 7, ProjectID: 3, Priority: 50, Queued: now.Add(12 * time.Second)},
+		// an urgent job
+		{ID: 8, ProjectID: 3, Priority: 90, Queued: now.Add(20 * time.Second)},
+		// a low priority job
+		{ID: 9, ProjectID: 2, Priority: 10, Queued: now.Add(-time.Hour)},
+	}
+
+	assert.Equal(t, []int64{8, 1, 5, 2, 6, 7, 3, 4, 9}, ids(FairShare(jobs, nil)))
+
+	// project 1 already has 2 building jobs
+	assert.Equal(t, []int64{8, 5, 6, 7, 1, 2, 3, 4, 9}, ids(FairShare(jobs, map[int64]int{1: 2})))
+
+	assert.Empty(t, FairShare(nil, nil))
+}
+
+func TestWithinQuotas(t *testing.T) {
+	jobs := []Job{
+		{ID: 1, ProjectID: 1},
+		{ID: 2, ProjectID: 2},
+		{ID: 3, ProjectID: 1},
+		{ID: 4, ProjectID: 3},
+		{ID: 5, ProjectID: 2},
+		{ID: 6, ProjectID: 1},
+	}
+	// group 10 owns projects 1 and 2, with 3 concurrent jobs; group 20 owns project 2 with 1 concurrent job
+	projectGroups := map[int64][]int64{1: {10}, 2: {10, 20}}
+	quotas := map[int64]int{10: 3, 20: 1}
+
+	assert.Equal(t, []int64{1, 2, 3, 4}, ids(WithinQuotas(jobs, nil, projectGroups, quotas)))
+
+	// project 1 already has 2 building jobs
+	assert.Equal(t, []int64{1, 4}, ids(WithinQuotas(jobs, map[int64]int{1: 2}, projectGroups, quotas)))
+
+	// project 3 has no quota
+	assert.Equal(t, []int64{4}, ids(WithinQuotas(jobs, map[int64]int{1: 5}, projectGroups, quotas)))
+}
+
+func TestCanStart(t *testing.T) {
+	projectGroups := map[int64][]int64{1: {10}, 2: {10, 20}}
+	quotas := map[int64]int{10: 3, 20: 1}
+
+	assert.True(t, CanStart(1, nil, projectGroups, quotas))
+	assert.True(t, CanStart(1, map[int64]int{1: 2}, projectGroups, quotas))
+	// group 10 is full with the jobs of projects 1 and 2
+	assert.False(t, CanStart(1, map[int64]int{1: 2, 2: 1}, projectGroups, quotas))
+	// group 20 is full
+	assert.False(t, CanStart(2, map[int64]int{2: 1}, projectGroups, quotas))
+	// project 3 has no quota
+	assert.True(t, CanStart(3, map[int64]int{1: 5, 3: 100}, projectGroups, quotas))
+}
@@ -0,0 +1,74 @@
+package queue
+
+import (
+	"fmt"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func init() {
+	environment.RegisterPipelineBuildStopper(stopLockHolder)
+}
+
+// stopLockHolder stops the pipeline build holding the lock l, when another run takes the lock from it
+func stopLockHolder(db gorp.SqlExecutor, l *sdk.EnvironmentLock) error {
+	pb, err := pipeline.LoadPipelineBuildByID(db, l.PipelineBuildID)
+	if err != nil {
+		return sdk.WrapError(err, "stopLockHolder> Cannot load pipeline build %d", l.PipelineBuildID)
+	}
+	log.Info("stopLockHolder> pb %d lost the lock on environment %d, stopping it", pb.ID, l.EnvironmentID)
+	return pipeline.StopPipelineBuild(db, pb)
+}
+
+// pipelineBuildLock returns the environment lock the pipeline build needs, nil if its environment is not exclusive
+func pipelineBuildLock(db gorp.SqlExecutor, pb *sdk.PipelineBuild) (*sdk.EnvironmentLock, string, error) {
+	if pb.Environment.ID == 0 || pb.Environment.ID == sdk.DefaultEnv.ID {
+		return nil, "", nil
+	}
+
+	env := &sdk.Environment{ID: pb.Environment.ID}
+	if err := environment.LoadLockSettings(db, env); err != nil {
+		return nil, "", sdk.WrapError(err, "pipelineBuildLock> Cannot load lock settings of environment %d", env.ID)
+	}
+
+	appID, need := env.LockKey(pb.Application.ID, pb.Application.Name)
+	if !need {
+		return nil, "", nil
+	}
+
+	holder := fmt.Sprintf("%s/%s #%d", pb.Application.Name, pb.Pipeline.Name, pb.BuildNumber)
+	if pb.Trigger.TriggeredBy != nil && pb.Trigger.TriggeredBy.Username != "" {
+		holder += " by " + pb.Trigger.TriggeredBy.Username
+	}
+
+	return &sdk.EnvironmentLock{
+		EnvironmentID:   env.ID,
+		ApplicationID:   appID,
+		PipelineBuildID: pb.ID,
+		Holder:          holder,
+	}, env.LockPolicy, nil
+}
+
+// acquirePipelineBuildLock applies the lock policy before the first stage of the pipeline build is scheduled.
+// It returns true if the lock is acquired, and true as second value if the pipeline build has to be stopped.
+func acquirePipelineBuildLock(db gorp.SqlExecutor, l *sdk.EnvironmentLock, policy string) (bool, bool, error) {
+	current, ok, err := environment.AcquireLock(db, l, policy == sdk.EnvironmentLockCancelOlder)
+	if err != nil {
+		return false, false, err
+	}
+	if ok {
+		return true, false, nil
+	}
+
+	if policy == sdk.EnvironmentLockCancelNewer {
+		log.Info("acquirePipelineBuildLock> pipeline build %d stopped: environment %d locked by %s", l.PipelineBuildID, l.EnvironmentID, current.Holder)
+		return false, true, nil
+	}
+	log.Debug("acquirePipelineBuildLock> pipeline build %d waiting: environment %d locked by %s", l.PipelineBuildID, l.EnvironmentID, current.Holder)
+	return false, false, nil
+}
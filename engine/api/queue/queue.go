@@ -97,11 +97,66 @@ func runPipeline(db *gorp.DbMap, pbID int64) {
 		pbNewStatus = sdk.StatusSuccess
 	}
 
+	// Exclusive environment: check the lock is still held once the pipeline build has started
+	envLock, lockPolicy, errLock := pipelineBuildLock(tx, pb)
+	if errLock != nil {
+		log.Warning("queue.RunActions> Cannot compute environment lock of pb %d: %s", pb.ID, errLock)
+		return
+	}
+	var stop bool
+	if envLock != nil && len(pb.Stages) > 0 && pb.Stages[0].Status != sdk.StatusWaiting {
+		holds, err := environment.HoldsLock(tx, envLock)
+		if err != nil {
+			log.Warning("queue.RunActions> Cannot check environment lock of pb %d: %s", pb.ID, err)
+			return
+		}
+		if !holds {
+			log.Info("queue.RunActions> pb %d lost the lock on environment %d, stopping it", pb.ID, envLock.EnvironmentID)
+			if err := pipeline.StopBuildingPipelineBuildJob(tx, pb); err != nil {
+				log.Warning("queue.RunActions> Cannot stop pb %d: %s", pb.ID, err)
+				return
+			}
+			stop = true
+		}
+	}
+
 	// Browse Stage
 	for stageIndex := range pb.Stages {
 		stage := &pb.Stages[stageIndex]
 
 		if stage.Status == sdk.StatusWaiting {
+			if stageIndex == 0 {
+				approvalStatus, err := pipelineBuildApproval(tx, pb)
+				if err != nil {
+					log.Warning("queue.RunActions> Cannot check approval of pb %d: %s", pb.ID, err)
+					return
+				}
+				switch approvalStatus {
+				case sdk.StatusWaiting:
+					// Wait for the approvers
+					return
+				case sdk.StatusFail:
+					stop = true
+				}
+			}
+			if envLock != nil && stageIndex == 0 && !stop {
+				acquired, lockStop, err := acquirePipelineBuildLock(tx, envLock, lockPolicy)
+				if err != nil {
+					log.Warning("queue.RunActions> Cannot acquire environment lock for pb %d: %s", pb.ID, err)
+					return
+				}
+				if !acquired && !lockStop {
+					// Wait for the environment to be released
+					return
+				}
+				stop = lockStop
+			}
+			if stop {
+				stage.Status = sdk.StatusFail
+				pb.Done = time.Now()
+				pbNewStatus = sdk.StatusFail
+				break
+			}
 			if err := addJobsToQueue(tx, stage, pb); err != nil {
 				log.Warning("queue.RunActions> Cannot add job to queue: %s", err)
 				return
@@ -147,6 +202,13 @@ func runPipeline(db *gorp.DbMap, pbID int64) {
 		return
 	}
 
+	if envLock != nil && pbNewStatus != sdk.StatusBuilding {
+		if err := environment.ReleaseLock(tx, envLock); err != nil {
+			log.Warning("RunActions> Cannot release environment lock of pb %d: %s", pb.ID, err)
+			return
+		}
+	}
+
 	// If pipeline build succeed, run trigger
 	if pb.Status == sdk.StatusSuccess {
 		if err := pipelineBuildEnd(tx, pb); err != nil {
@@ -168,6 +230,11 @@ func addJobsToQueue(tx gorp.SqlExecutor, stage *sdk.Stage, pb *sdk.PipelineBuild
 	}
 	stage.Status = sdk.StatusBuilding
 
+	priority, errPrio := pipeline.GetPipelineBuildJobPriority(tx, pb.ID)
+	if errPrio != nil {
+		return sdk.WrapError(errPrio, "addJobsToQueue> Cannot compute priority of pipeline build %d", pb.ID)
+	}
+
 	for _, job := range stage.Jobs {
 		pbJobParams, errParam := getPipelineBuildJobParameters(tx, job, pb, stage)
 		if errParam != nil {
@@ -179,9 +246,10 @@ func addJobsToQueue(tx gorp.SqlExecutor, stage *sdk.Stage, pb *sdk.PipelineBuild
 			Job: sdk.ExecutedJob{
 				Job: job,
 			},
-			Queued: time.Now(),
-			Status: sdk.StatusWaiting.String(),
-			Start:  time.Now(),
+			Queued:   time.Now(),
+			Status:   sdk.StatusWaiting.String(),
+			Start:    time.Now(),
+			Priority: priority,
 		}
 
 		if !stage.Enabled || !pbJob.Job.Enabled {
@@ -321,10 +389,16 @@ func pipelineBuildEnd(tx gorp.SqlExecutor, pb *sdk.PipelineBuild) error {
 			ScheduledTrigger:    pb.Trigger.ScheduledTrigger,
 		}
 
-		_, err = RunPipeline(tx, t.DestProject.Key, app, t.DestPipeline.Name, t.DestEnvironment.Name, parameters, pb.Version, trigger, &sdk.User{Admin: true})
+		newPB, err := RunPipeline(tx, t.DestProject.Key, app, t.DestPipeline.Name, t.DestEnvironment.Name, parameters, pb.Version, trigger, &sdk.User{Admin: true})
 		if err != nil {
 			return sdk.WrapError(err, "pipelineScheduler> Cannot run pipeline on project %s, application %s, pipeline %s, env %s", t.DestProject.Key, t.DestApplication.Name, t.DestPipeline.Name, t.DestEnvironment.Name)
 		}
+
+		if t.Approval != nil {
+			if err := InsertPipelineBuildApproval(tx, t, newPB); err != nil {
+				return sdk.WrapError(err, "pipelineBuildEnd> Cannot insert approval of pipeline build %d", newPB.ID)
+			}
+		}
 	}
 	return nil
 }
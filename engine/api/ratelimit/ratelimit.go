@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ovh/cds/engine/api/cache"
+)
+
+// Kinds of callers, each one with its own limit
+const (
+	User     = "user"
+	Token    = "token"
+	Worker   = "worker"
+	Hatchery = "hatchery"
+)
+
+// Config sets the maximum number of requests of each kind of caller during a period in seconds. A limit of 0
+// disables the rate limiting of a kind of caller.
+type Config struct {
+	Enabled  bool
+	Period   int
+	User     int
+	Token    int
+	Worker   int
+	Hatchery int
+}
+
+var (
+	config Config
+	now    = time.Now
+)
+
+// Init sets the rate limits
+func Init(c Config) {
+	if c.Period <= 0 {
+		c.Period = 60
+	}
+	config = c
+}
+
+// Limit returns the maximum number of requests of a kind of caller during a period, 0 if it is not limited
+func Limit(kind string) int {
+	if !config.Enabled {
+		return 0
+	}
+	switch kind {
+	case User:
+		return config.User
+	case Token:
+		return config.Token
+	case Worker:
+		return config.Worker
+	case Hatchery:
+		return config.Hatchery
+	}
+	return 0
+}
+
+// Allow counts a request of a caller, and returns if it is allowed. The counters are shared by all the instances of
+// the API through the cache. If the request is not allowed, Allow also returns the duration before the caller can
+// retry, which is the end of the current period.
+func Allow(kind, id string) (bool, time.Duration) {
+	limit := Limit(kind)
+	if limit <= 0 {
+		return true, 0
+	}
+
+	t := now().Unix()
+	period := int64(config.Period)
+	window := t / period
+	key := cache.Key("api", "ratelimit", kind, id, fmt.Sprintf("%d", window))
+
+	if n := cache.Incr(key, config.Period); n <= int64(limit) {
+		return true, 0
+	}
+	return false, time.Duration((window+1)*period-t) * time.Second
+}
@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/cache"
+)
+
+func TestAllow(t *testing.T) {
+	cache.Initialize("local", "", "", 5)
+	Init(Config{Enabled: true, Period: 60, User: 2, Worker: 0})
+
+	current := time.Unix(6000+45, 0)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	ok, _ := Allow(User, "john")
+	assert.True(t, ok)
+	ok, _ = Allow(User, "john")
+	assert.True(t, ok)
+	ok, retry := Allow(User, "john")
+	assert.False(t, ok)
+	assert.Equal(t, 15*time.Second, retry)
+
+	// The other callers have their own counter
+	ok, _ = Allow(User, "jane")
+	assert.True(t, ok)
+
+	// The workers are not limited
+	for i := 0; i < 5; i++ {
+		ok, _ = Allow(Worker, "worker-1")
+		assert.True(t, ok)
+	}
+
+	// The counter is reset on the next period
+	current = current.Add(15 * time.Second)
+	ok, _ = Allow(User, "john")
+	assert.True(t, ok)
+}
+
+func TestAllowDisabled(t *testing.T) {
+	Init(Config{Enabled: false, User: 1})
+	assert.Equal(t, 0, Limit(User))
+	for i := 0; i < 5; i++ {
+		ok, _ := Allow(User, "john")
+		assert.True(t, ok)
+	}
+}
@@ -67,6 +67,7 @@ func addRepositoriesManagerHandler(w http.ResponseWriter, r *http.Request, db *g
 	if err := repositoriesmanager.Insert(db, rm); err != nil {
 		return sdk.WrapError(err, "addRepositoriesManagerHandler> cannot insert %s")
 	}
+	c.AuditAfter = fmt.Sprintf("repositories_manager=%s type=%s url=%s", name, t, url)
 	return WriteJSON(w, r, rm, http.StatusCreated)
 }
 
@@ -264,6 +265,7 @@ func repositoriesManagerAuthorizeCallback(w http.ResponseWriter, r *http.Request
 	if err := repositoriesmanager.SaveDataForProject(db, rm, projectKey, result); err != nil {
 		return sdk.WrapError(err, "repositoriesManagerAuthorizeCallback> Error with SaveDataForProject")
 	}
+	c.AuditAfter = fmt.Sprintf("repositories_manager=%s", rmName)
 
 	p, err := project.Load(db, projectKey, c.User, project.LoadOptions.WithRepositoriesManagers)
 	if err != nil {
@@ -289,6 +291,8 @@ func deleteRepositoriesManagerHandler(w http.ResponseWriter, r *http.Request, db
 		return sdk.WrapError(sdk.ErrNoReposManager, "deleteRepositoriesManagerHandler> error loading %s-%s: %s", projectKey, rmName, errlp)
 	}
 
+	c.AuditBefore = fmt.Sprintf("repositories_manager=%s", rmName)
+
 	tx, errb := db.Begin()
 	if errb != nil {
 		return sdk.WrapError(errb, "deleteRepositoriesManagerHandler> Cannot start transaction")
@@ -374,6 +378,7 @@ func attachRepositoriesManager(w http.ResponseWriter, r *http.Request, db *gorp.
 	if err != nil {
 		return sdk.WrapError(err, "attachRepositoriesManager> Cannot load application %s", appName)
 	}
+	c.AuditBefore = auditRepository(app)
 
 	//Load the repositoriesManager for the project
 	rm, err := repositoriesmanager.LoadForProject(db, projectKey, rmName)
@@ -411,6 +416,7 @@ func attachRepositoriesManager(w http.ResponseWriter, r *http.Request, db *gorp.
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "attachRepositoriesManager> Cannot commit transaction")
 	}
+	c.AuditAfter = auditRepository(app)
 
 	return WriteJSON(w, r, app, http.StatusOK)
 }
@@ -425,6 +431,7 @@ func detachRepositoriesManager(w http.ResponseWriter, r *http.Request, db *gorp.
 	if errl != nil {
 		return sdk.WrapError(errl, "detachRepositoriesManager> error on load project %s", projectKey)
 	}
+	c.AuditBefore = auditRepository(app)
 
 	client, erra := repositoriesmanager.AuthorizedClient(db, projectKey, rmName)
 	if erra != nil {
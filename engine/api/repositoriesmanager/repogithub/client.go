@@ -407,14 +407,36 @@ func (g *GithubClient) allCommitBetween(repo string, untilDate time.Time, sinceD
 // Commit Get a single commit
 // https://developer.github.com/v3/repos/commits/#get-a-single-commit
 func (g *GithubClient) Commit(repo, hash string) (sdk.VCSCommit, error) {
+	c, err := g.commit(repo, hash)
+	if err != nil {
+		return sdk.VCSCommit{}, err
+	}
+
+	commit := sdk.VCSCommit{
+		Timestamp: c.Commit.Author.Date.Unix() * 1000,
+		Message:   c.Commit.Message,
+		Hash:      c.Sha,
+		Author: sdk.VCSAuthor{
+			DisplayName: c.Commit.Author.Name,
+			Email:       c.Commit.Author.Email,
+			Name:        c.Author.Login,
+			Avatar:      c.Author.AvatarURL,
+		},
+		URL: c.HTMLURL,
+	}
+
+	return commit, nil
+}
+
+func (g *GithubClient) commit(repo, hash string) (Commit, error) {
 	url := "/repos/" + repo + "/commits/" + hash
 	status, body, _, err := g.get(url)
 	if err != nil {
 		log.Warning("GithubClient.Commit> Error %s", err)
-		return sdk.VCSCommit{}, err
+		return Commit{}, err
 	}
 	if status >= 400 {
-		return sdk.VCSCommit{}, sdk.NewError(sdk.ErrRepoNotFound, ErrorAPI(body))
+		return Commit{}, sdk.NewError(sdk.ErrRepoNotFound, ErrorAPI(body))
 	}
 	c := Commit{}
 
@@ -425,26 +447,54 @@ func (g *GithubClient) Commit(repo, hash string) (sdk.VCSCommit, error) {
 	} else {
 		if err := json.Unmarshal(body, &c); err != nil {
 			log.Warning("GithubClient.Commit> Unable to parse github commit: %s", err)
-			return sdk.VCSCommit{}, err
+			return Commit{}, err
 		}
 		//Put the body on cache for one hour and one minute
 		cache.SetWithTTL(cache.Key("reposmanager", "github", "commit", g.OAuthToken, url), c, 61*60)
 	}
+	return c, nil
+}
 
-	commit := sdk.VCSCommit{
-		Timestamp: c.Commit.Author.Date.Unix() * 1000,
-		Message:   c.Commit.Message,
-		Hash:      c.Sha,
-		Author: sdk.VCSAuthor{
-			DisplayName: c.Commit.Author.Name,
-			Email:       c.Commit.Author.Email,
-			Name:        c.Author.Login,
-			Avatar:      c.Author.AvatarURL,
-		},
-		URL: c.HTMLURL,
+// ChangedFiles returns the paths of the files changed between two commits, or by the commit to if from is empty
+// https://developer.github.com/v3/repos/commits/#compare-two-commits
+func (g *GithubClient) ChangedFiles(repo, from, to string) ([]string, error) {
+	var files []CommitFile
+	if sdk.IsGitNullHash(from) {
+		c, err := g.commit(repo, to)
+		if err != nil {
+			return nil, err
+		}
+		files = c.Files
+	} else {
+		url := "/repos/" + repo + "/compare/" + from + "..." + to
+		status, body, _, err := g.get(url)
+		if err != nil {
+			log.Warning("GithubClient.ChangedFiles> Error %s", err)
+			return nil, err
+		}
+		if status >= 400 {
+			return nil, sdk.NewError(sdk.ErrRepoNotFound, ErrorAPI(body))
+		}
+		c := Comparison{}
+
+		//Github may return 304 status because we are using conditional request with ETag based headers
+		if status == http.StatusNotModified {
+			cache.Get(cache.Key("reposmanager", "github", "compare", g.OAuthToken, url), &c)
+		} else {
+			if err := json.Unmarshal(body, &c); err != nil {
+				log.Warning("GithubClient.ChangedFiles> Unable to parse github comparison: %s", err)
+				return nil, err
+			}
+			cache.SetWithTTL(cache.Key("reposmanager", "github", "compare", g.OAuthToken, url), c, 61*60)
+		}
+		files = c.Files
 	}
 
-	return commit, nil
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Filename
+	}
+	return paths, nil
 }
 
 //CreateHook is not implemented
@@ -564,8 +614,13 @@ func (g *GithubClient) PushEvents(fullname string, iEvents []interface{}) ([]sdk
 	}
 
 	lastCommitPerBranch := map[string]sdk.VCSCommit{}
+	//The head of the branch before its first push, to get the files changed by all the pushes
+	firstPushPerBranch := map[string]Event{}
 	for _, e := range events {
 		branch := strings.Replace(e.Payload.Ref, "refs/heads/", "", 1)
+		if f, ok := firstPushPerBranch[branch]; !ok || e.CreatedAt.Before(f.CreatedAt.Time) {
+			firstPushPerBranch[branch] = e
+		}
 		for _, c := range e.Payload.Commits {
 			commit := sdk.VCSCommit{
 				Hash:      c.Sha,
@@ -594,9 +649,14 @@ func (g *GithubClient) PushEvents(fullname string, iEvents []interface{}) ([]sdk
 			log.Warning("GithubClient.PushEvents> Unable to find branch %s in %s : %s", b, fullname, err)
 			continue
 		}
+		before := firstPushPerBranch[b].Payload.Before
+		if sdk.IsGitNullHash(before) {
+			before = ""
+		}
 		res = append(res, sdk.VCSPushEvent{
 			Branch: *branch,
 			Commit: c,
+			Before: before,
 		})
 	}
 
@@ -617,6 +677,22 @@ func (g *GithubClient) CreateEvents(fullname string, iEvents []interface{}) ([]s
 	res := []sdk.VCSCreateEvent{}
 	for _, e := range events {
 		b := e.Payload.Ref
+		if e.Payload.RefType == "tag" {
+			c, err := g.Commit(fullname, b)
+			if err != nil {
+				log.Warning("GithubClient.CreateEvents> Unable to find commit of tag %s in %s : %s", b, fullname, err)
+				continue
+			}
+			res = append(res, sdk.VCSCreateEvent{
+				Branch: sdk.VCSBranch{
+					ID:           sdk.GitTagRefPrefix + b,
+					DisplayID:    b,
+					LatestCommit: c.Hash,
+				},
+				Commit: c,
+			})
+			continue
+		}
 		branch, err := g.Branch(fullname, b)
 		if err != nil || branch == nil {
 			log.Warning("GithubClient.CreateEvents> Unable to find branch %s in %s : %s", b, fullname, err)
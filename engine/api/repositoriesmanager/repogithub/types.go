@@ -277,18 +277,30 @@ type Commit struct {
 		Additions int `json:"additions"`
 		Deletions int `json:"deletions"`
 	} `json:"stats"`
-	Files []struct {
-		Sha         string `json:"sha"`
-		Filename    string `json:"filename"`
-		Status      string `json:"status"`
-		Additions   int    `json:"additions"`
-		Deletions   int    `json:"deletions"`
-		Changes     int    `json:"changes"`
-		BlobURL     string `json:"blob_url"`
-		RawURL      string `json:"raw_url"`
-		ContentsURL string `json:"contents_url"`
-		Patch       string `json:"patch"`
-	} `json:"files"`
+	Files []CommitFile `json:"files"`
+}
+
+// CommitFile represents a file changed by a GitHub commit or a comparison
+type CommitFile struct {
+	Sha         string `json:"sha"`
+	Filename    string `json:"filename"`
+	Status      string `json:"status"`
+	Additions   int    `json:"additions"`
+	Deletions   int    `json:"deletions"`
+	Changes     int    `json:"changes"`
+	BlobURL     string `json:"blob_url"`
+	RawURL      string `json:"raw_url"`
+	ContentsURL string `json:"contents_url"`
+	Patch       string `json:"patch"`
+}
+
+// Comparison represents the comparison of two GitHub commits
+type Comparison struct {
+	Status       string       `json:"status"`
+	AheadBy      int          `json:"ahead_by"`
+	BehindBy     int          `json:"behind_by"`
+	TotalCommits int          `json:"total_commits"`
+	Files        []CommitFile `json:"files"`
 }
 
 // Tree represents a GitHub tree.
@@ -334,6 +346,7 @@ type Event struct {
 		Size         int    `json:"size"`
 		DistinctSize int    `json:"distinct_size"`
 		Ref          string `json:"ref"`
+		RefType      string `json:"ref_type"`
 		Head         string `json:"head"`
 		Before       string `json:"before"`
 		Commits      []struct {
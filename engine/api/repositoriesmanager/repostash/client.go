@@ -1,13 +1,17 @@
 package repostash
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/facebookgo/httpcontrol"
+	"github.com/go-stash/go-stash/oauth1"
 	"github.com/go-stash/go-stash/stash"
 	"github.com/mitchellh/mapstructure"
 
@@ -288,6 +292,105 @@ func (s *StashClient) Commit(repo, hash string) (sdk.VCSCommit, error) {
 	return commit, nil
 }
 
+//stashChanges is a page of the changes API of Stash
+type stashChanges struct {
+	Values []struct {
+		Path struct {
+			ToString string `json:"toString"`
+		} `json:"path"`
+		Type string `json:"type"`
+	} `json:"values"`
+	NextPageStart int  `json:"nextPageStart"`
+	IsLastPage    bool `json:"isLastPage"`
+}
+
+//ChangedFiles returns the paths of the files changed between two commits, or by the commit to if from is empty
+func (s *StashClient) ChangedFiles(repo, from, to string) ([]string, error) {
+	t := strings.Split(repo, "/")
+	if len(t) != 2 {
+		return nil, fmt.Errorf("fullname %s must be <project>/<slug>", repo)
+	}
+
+	path := fmt.Sprintf("/projects/%s/repos/%s/commits/%s/changes", t[0], t[1], to)
+	params := url.Values{}
+	if !sdk.IsGitNullHash(from) {
+		params.Set("since", from)
+	}
+
+	files := []string{}
+	for {
+		changes := stashChanges{}
+		if err := s.do("GET", "core", path, params, &changes); err != nil {
+			return nil, err
+		}
+		for _, c := range changes.Values {
+			files = append(files, c.Path.ToString)
+		}
+		if changes.IsLastPage || changes.NextPageStart == 0 {
+			break
+		}
+		params.Set("start", strconv.Itoa(changes.NextPageStart))
+	}
+	return files, nil
+}
+
+//do sends a request signed with the credentials of the client to the Stash API
+func (s *StashClient) do(method, api, path string, params url.Values, v interface{}) error {
+	uri, err := url.Parse(s.client.GetFullApiUrl(api) + path)
+	if err != nil {
+		return err
+	}
+	if len(params) > 0 {
+		uri.RawQuery = params.Encode()
+	}
+
+	req := &http.Request{
+		URL:        uri,
+		Method:     method,
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Close:      true,
+		Header:     http.Header{},
+	}
+
+	consumer := oauth1.Consumer{
+		ConsumerKey:           s.client.ConsumerKey,
+		ConsumerSecret:        s.client.ConsumerSecret,
+		ConsumerPrivateKeyPem: s.client.ConsumerPrivateKeyPem,
+	}
+	token := oauth1.NewAccessToken(s.client.AccessToken, s.client.TokenSecret, nil)
+	if err := consumer.Sign(req, token); err != nil {
+		return err
+	}
+
+	resp, err := stash.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return stash.ErrNotFound
+	case http.StatusForbidden:
+		return stash.ErrForbidden
+	case http.StatusUnauthorized:
+		return stash.ErrNotAuthorized
+	case http.StatusBadRequest:
+		return stash.ErrBadRequest
+	}
+
+	if v != nil {
+		return json.Unmarshal(body, v)
+	}
+	return nil
+}
+
 //CreateHook enables the defaut HTTP POST Hook in Stash
 func (s *StashClient) CreateHook(repo, url string) error {
 	var branchFilter, tagFilter, userFilter string
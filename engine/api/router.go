@@ -53,6 +53,7 @@ type routerConfig struct {
 	needUsernameOrAdmin bool
 	needHatchery        bool
 	needWorker          bool
+	noWorkerRateLimit   bool
 }
 
 // ServeAbsoluteFile Serve file to download
@@ -147,6 +148,7 @@ var mapRouterConfigs = map[string]*routerConfig{}
 
 // Handle adds all handler for their specific verb in gorilla router for given uri
 func (r *Router) Handle(uri string, handlers ...RouterConfigParam) {
+	route := uri
 	uri = r.prefix + uri
 	rc := &routerConfig{auth: true, isExecution: false, needAdmin: false, needHatchery: false}
 	mapRouterConfigs[uri] = rc
@@ -190,6 +192,12 @@ func (r *Router) Handle(uri string, handlers ...RouterConfigParam) {
 			}
 		}
 
+		if ok, retry := checkRateLimit(rc, req, c); !ok {
+			w.Header().Set("Retry-After", fmt.Sprintf("%d", int(retry.Seconds())))
+			WriteError(w, req, sdk.WrapError(sdk.ErrTooManyRequests, "Router> Rate limit reached on %s %s for %s agent %s", req.Method, req.URL, c.User.Username, c.Agent))
+			return
+		}
+
 		if c.User != nil {
 			if err := loadUserPermissions(db, c.User); err != nil {
 				WriteError(w, req, sdk.WrapError(sdk.ErrUnauthorized, "Router> Unable to load user %s permission: %s", c.User.ID, err))
@@ -243,6 +251,12 @@ func (r *Router) Handle(uri string, handlers ...RouterConfigParam) {
 			}
 		}
 
+		if isAudited(req, c) {
+			aw := &auditResponseWriter{ResponseWriter: w, status: http.StatusOK}
+			w = aw
+			defer recordAudit(db, req, route, c, aw)
+		}
+
 		permissionOk := false
 		if !rc.auth {
 			permissionOk = true
@@ -388,6 +402,21 @@ func PUT(h Handler, cfg ...RouterConfigParam) RouterConfigParam {
 	return f
 }
 
+// PUTEXECUTE will set given handler only for PUT request and add a flag for execution permission
+func PUTEXECUTE(h Handler, cfg ...RouterConfigParam) RouterConfigParam {
+	f := func(rc *routerConfig) {
+		rc.put = h
+		rc.isExecution = true
+		for _, c := range cfg {
+			if reflect.ValueOf(c).Pointer() == reflect.ValueOf(DEPRECATED).Pointer() {
+				rc.putDeprecated = true
+				continue
+			}
+		}
+	}
+	return f
+}
+
 // DELETE will set given handler only for DELETE request
 func DELETE(h Handler, cfg ...RouterConfigParam) RouterConfigParam {
 	f := func(rc *routerConfig) {
@@ -434,6 +463,14 @@ func NeedWorker() RouterConfigParam {
 	return f
 }
 
+// NoWorkerRateLimit exempts the workers from the rate limits on the route
+func NoWorkerRateLimit() RouterConfigParam {
+	f := func(rc *routerConfig) {
+		rc.noWorkerRateLimit = true
+	}
+	return f
+}
+
 // Auth set manually whether authorisation layer should be applied
 // Authorization is enabled by default
 func Auth(v bool) RouterConfigParam {
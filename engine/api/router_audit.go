@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// auditResponseWriter keeps the status of the response of an audited call
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *auditResponseWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// isAudited returns true for the mutating calls of the users. The calls of the workers and of the hatcheries are
+// not audited.
+func isAudited(req *http.Request, c *businesscontext.Ctx) bool {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	return c.User != nil && c.Worker == nil && c.Hatchery == nil
+}
+
+// recordAudit records an audited call, including the calls denied by the permissions
+func recordAudit(db *gorp.DbMap, req *http.Request, route string, c *businesscontext.Ctx, w *auditResponseWriter) {
+	if db == nil {
+		return
+	}
+	e := sdk.AuditEvent{
+		Created:    time.Now(),
+		Username:   c.User.Username,
+		Admin:      c.User.Admin,
+		Method:     req.Method,
+		Route:      route,
+		Path:       req.URL.Path,
+		TargetType: audit.TargetType(route),
+		Target:     audit.Target(route, mux.Vars(req)),
+		Status:     w.status,
+		SourceIP:   audit.SourceIP(req),
+		Before:     c.AuditBefore,
+		After:      c.AuditAfter,
+	}
+	if err := audit.Insert(db, &e); err != nil {
+		log.Warning("recordAudit> Cannot record %s %s of %s: %s", req.Method, req.URL.Path, c.User.Username, err)
+	}
+}
+
+// auditModel summarizes a worker model for the audit trail
+func auditModel(m *sdk.Model) string {
+	return fmt.Sprintf("name=%s type=%s image=%s group=%d disabled=%t provision=%d", m.Name, m.Type, m.Image, m.GroupID, m.Disabled, m.Provision)
+}
+
+// auditRepository summarizes the repository an application is linked to for the audit trail
+func auditRepository(app *sdk.Application) string {
+	if app.RepositoriesManager == nil {
+		return ""
+	}
+	return fmt.Sprintf("repositories_manager=%s repository=%s", app.RepositoriesManager.Name, app.RepositoryFullname)
+}
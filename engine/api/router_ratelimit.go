@@ -0,0 +1,37 @@
+package main
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/engine/api/ratelimit"
+	"github.com/ovh/cds/sdk"
+)
+
+// rateLimitKey returns the kind and the identifier of the caller of an authenticated call. The users calling the API
+// with a persistent session token, like the CLI and the scripts, are limited per token.
+func rateLimitKey(req *http.Request, c *businesscontext.Ctx) (string, string) {
+	switch {
+	case c.Worker != nil:
+		return ratelimit.Worker, c.Worker.ID
+	case c.Hatchery != nil:
+		return ratelimit.Hatchery, fmt.Sprintf("%d", c.Hatchery.ID)
+	case req.Header.Get(sdk.RequestedWithHeader) == sdk.RequestedWithValue && req.Header.Get(sdk.SessionTokenHeader) != "":
+		// The token itself is not stored in the cache
+		return ratelimit.Token, fmt.Sprintf("%x", sha1.Sum([]byte(req.Header.Get(sdk.SessionTokenHeader))))
+	default:
+		return ratelimit.User, c.User.Username
+	}
+}
+
+// checkRateLimit counts an authenticated call, and returns false with the duration before the caller can retry if
+// the caller has reached its limit. The workers are not limited on the routes marked with NoWorkerRateLimit.
+func checkRateLimit(rc *routerConfig, req *http.Request, c *businesscontext.Ctx) (bool, time.Duration) {
+	if c.User == nil || (c.Worker != nil && rc.noWorkerRateLimit) {
+		return true, 0
+	}
+	return ratelimit.Allow(rateLimitKey(req, c))
+}
@@ -19,6 +19,9 @@ const (
 	EnvironmentVariableUsedInApplicationDoesNotExist
 	InvalidVariableFormatUsedInApplication
 	MissingEnvironment
+	InvalidRequirementValue
+	IncompatibleOSArchAndModelRequirements
+	DeprecatedActionVersion
 )
 
 var messageAmericanEnglish = map[int64]string{
@@ -37,4 +40,7 @@ var messageAmericanEnglish = map[int64]string{
 	GitURLWithoutKey:                                 `Action {{index . "ActionName"}}{{if index . "PipelineName"}} in pipeline {{index . "ProjectKey"}}/{{index . "PipelineName"}}{{end}} is used but no ssh key were found. Git clone will failed`,
 	MissingEnvironment:                               `Application {{index . "ApplicationName"}}: At least one environment with one variable should be defined`,
 	EnvironmentVariableUsedInApplicationDoesNotExist: `Application {{index . "ApplicationName"}}: Environment variable {{index . "VarName"}} used but doesn't exist in all environments`,
-	InvalidVariableFormatUsedInApplication:           `Application {{index . "ApplicationName"}}: Invalid variable format '{{index . "VarName"}}'`}
+	InvalidVariableFormatUsedInApplication:           `Application {{index . "ApplicationName"}}: Invalid variable format '{{index . "VarName"}}'`,
+	InvalidRequirementValue:                          `Action {{index . "ActionName"}}{{if index . "PipelineName"}} in pipeline {{index . "ProjectKey"}}/{{index . "PipelineName"}}{{end}}: Invalid value '{{index . "RequirementValue"}}' for {{index . "RequirementType"}} requirement`,
+	IncompatibleOSArchAndModelRequirements:           `Action {{index . "ActionName"}}{{if index . "PipelineName"}} in pipeline {{index . "ProjectKey"}}/{{index . "PipelineName"}}{{end}}: Model {{index . "ModelName"}} does not match os-arch requirement '{{index . "OSArchRequirement"}}'`,
+	DeprecatedActionVersion:                          `Action {{index . "ActionName"}}{{if index . "PipelineName"}} in pipeline {{index . "ProjectKey"}}/{{index . "PipelineName"}}{{end}}: Step {{index . "StepOrder"}} uses the deprecated version {{index . "Version"}} of action {{index . "StepName"}}`}
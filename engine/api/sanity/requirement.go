@@ -25,6 +25,12 @@ func checkActionRequirements(a *sdk.Action, proj string, pip string, wms []sdk.M
 	}
 	warns = append(warns, w...)
 
+	w, err = checkInvalidRequirementValue(proj, pip, a)
+	if err != nil {
+		return nil, err
+	}
+	warns = append(warns, w...)
+
 	w, err = checkNoWorkerModelMatchRequirement(proj, pip, a, wms, modelReq, hostnameReq)
 	if err != nil {
 		return nil, err
@@ -50,6 +56,12 @@ func checkActionRequirements(a *sdk.Action, proj string, pip string, wms []sdk.M
 			return nil, err
 		}
 		warns = append(warns, w...)
+
+		w, err = checkIncompatibleOSArchWithModelRequirement(proj, pip, a, wms, modelName)
+		if err != nil {
+			return nil, err
+		}
+		warns = append(warns, w...)
 	}
 
 	return warns, nil
@@ -118,6 +130,29 @@ func checkMultipleHostnameWarning(proj string, pip string, a *sdk.Action) ([]sdk
 	return warns, hostnameReq, nil
 }
 
+func checkInvalidRequirementValue(proj string, pip string, a *sdk.Action) ([]sdk.Warning, error) {
+	var warns []sdk.Warning
+	for _, r := range a.Requirements {
+		if err := r.IsValid(); err != nil {
+			w := sdk.Warning{
+				Action: sdk.Action{
+					ID: a.ID,
+				},
+				ID: InvalidRequirementValue,
+				MessageParam: map[string]string{
+					"ActionName":       a.Name,
+					"PipelineName":     pip,
+					"ProjectKey":       proj,
+					"RequirementType":  r.Type,
+					"RequirementValue": r.Value,
+				},
+			}
+			warns = append(warns, w)
+		}
+	}
+	return warns, nil
+}
+
 func checkNoWorkerModelMatchRequirement(proj string, pip string, a *sdk.Action, wms []sdk.Model, modelReq int, hostnameReq int) ([]sdk.Warning, error) {
 	var warns []sdk.Warning
 	areqs := a.Requirements
@@ -134,6 +169,11 @@ func checkNoWorkerModelMatchRequirement(proj string, pip string, a *sdk.Action,
 				break
 			}
 
+			if ar.Type == sdk.OSArchRequirement && !wm.MatchOSArch(ar.Value) {
+				ok = false
+				break
+			}
+
 			// We are only checkins binary requirement matching with binary capabilities
 			// so let's skip this other types of requirements
 			if ar.Type != sdk.BinaryRequirement {
@@ -329,3 +369,44 @@ func checkIncompatibleMemoryWithModelRequirement(proj string, pip string, a *sdk
 
 	return warns, nil
 }
+
+func checkIncompatibleOSArchWithModelRequirement(proj string, pip string, a *sdk.Action, wms []sdk.Model, modelName string) ([]sdk.Warning, error) {
+	var warns []sdk.Warning
+	var m sdk.Model
+	areqs := a.Requirements
+
+	// find worker model
+	for _, wm := range wms {
+		if wm.Name == modelName {
+			m = wm
+			break
+		}
+	}
+
+	if m.Name == "" {
+		log.Warning("checkIncompatibleOSArchWithModelRequirement> Model '%s' not found\n", modelName)
+		return nil, sdk.ErrNoWorkerModel
+	}
+
+	for _, b := range areqs {
+		if b.Type != sdk.OSArchRequirement || m.MatchOSArch(b.Value) {
+			continue
+		}
+		w := sdk.Warning{
+			Action: sdk.Action{
+				ID: a.ID,
+			},
+			ID: IncompatibleOSArchAndModelRequirements,
+			MessageParam: map[string]string{
+				"ActionName":        a.Name,
+				"PipelineName":      pip,
+				"ProjectKey":        proj,
+				"ModelName":         modelName,
+				"OSArchRequirement": b.Value,
+			},
+		}
+		warns = append(warns, w)
+	}
+
+	return warns, nil
+}
@@ -701,3 +701,114 @@ func Test_checkIncompatibleMemoryWithModelRequirement(t *testing.T) {
 		assert.EqualValues(t, tt.want, got)
 	}
 }
+
+func Test_checkInvalidRequirementValue(t *testing.T) {
+	a := &sdk.Action{
+		ID:   1,
+		Name: "Action Name 1",
+		Requirements: []sdk.Requirement{
+			{Name: "cpu", Type: sdk.CPURequirement, Value: "2"},
+			{Name: "disk", Type: sdk.DiskRequirement, Value: "10G"},
+			{Name: "os-arch", Type: sdk.OSArchRequirement, Value: "linux/amd64"},
+		},
+	}
+
+	got, err := checkInvalidRequirementValue("proj", "pipeline", a)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []sdk.Warning{
+		{
+			Action: sdk.Action{
+				ID: 1,
+			},
+			ID: InvalidRequirementValue,
+			MessageParam: map[string]string{
+				"ActionName":       "Action Name 1",
+				"PipelineName":     "pipeline",
+				"ProjectKey":       "proj",
+				"RequirementType":  sdk.DiskRequirement,
+				"RequirementValue": "10G",
+			},
+		},
+	}, got)
+}
+
+func Test_checkIncompatibleOSArchWithModelRequirement(t *testing.T) {
+	type args struct {
+		proj      string
+		pip       string
+		a         *sdk.Action
+		wms       []sdk.Model
+		modelName string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []sdk.Warning
+		wantErr bool
+	}{
+		{
+			name: "With a docker model and a linux requirement it should not return warning",
+			args: args{
+				proj: "proj",
+				pip:  "pipeline",
+				a: &sdk.Action{
+					ID:           1,
+					Name:         "Action Name 1",
+					Requirements: []sdk.Requirement{{Name: "os-arch", Type: sdk.OSArchRequirement, Value: "linux/amd64"}},
+				},
+				modelName: "model",
+				wms:       []sdk.Model{{Name: "model", Type: sdk.Docker}},
+			},
+			want: nil,
+		},
+		{
+			name: "With a model declaring another os-arch it should return 1 warning",
+			args: args{
+				proj: "proj",
+				pip:  "pipeline",
+				a: &sdk.Action{
+					ID:           1,
+					Name:         "Action Name 1",
+					Requirements: []sdk.Requirement{{Name: "os-arch", Type: sdk.OSArchRequirement, Value: "windows/amd64"}},
+				},
+				modelName: "model",
+				wms: []sdk.Model{{
+					Name:         "model",
+					Type:         sdk.Openstack,
+					Capabilities: []sdk.Requirement{{Name: "os-arch", Type: sdk.OSArchRequirement, Value: "linux/amd64"}},
+				}},
+			},
+			want: []sdk.Warning{
+				{
+					Action: sdk.Action{
+						ID: 1,
+					},
+					ID: IncompatibleOSArchAndModelRequirements,
+					MessageParam: map[string]string{
+						"ActionName":        "Action Name 1",
+						"PipelineName":      "pipeline",
+						"ProjectKey":        "proj",
+						"ModelName":         "model",
+						"OSArchRequirement": "windows/amd64",
+					},
+				},
+			},
+		},
+		{
+			name: "With an unknown model it should return an error",
+			args: args{
+				a:         &sdk.Action{ID: 1, Name: "Action Name 1"},
+				modelName: "unknown",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		got, err := checkIncompatibleOSArchWithModelRequirement(tt.args.proj, tt.args.pip, tt.args.a, tt.args.wms, tt.args.modelName)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q. checkIncompatibleOSArchWithModelRequirement() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		assert.EqualValues(t, tt.want, got)
+	}
+}
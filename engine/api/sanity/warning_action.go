@@ -81,5 +81,38 @@ func CheckAction(tx gorp.SqlExecutor, project *sdk.Project, pip *sdk.Pipeline, a
 	w = checkGitVariables(tx, gitvars, project, pip, a)
 	warnings = append(warnings, w...)
 
+	w = checkDeprecatedActionVersions(project.Key, pip.Name, a)
+	warnings = append(warnings, w...)
+
 	return warnings, nil
 }
+
+// checkDeprecatedActionVersions warns the steps using a deprecated version of an action
+func checkDeprecatedActionVersions(proj string, pip string, a *sdk.Action) []sdk.Warning {
+	var warns []sdk.Warning
+	for i, step := range a.Actions {
+		if !step.Deprecated {
+			continue
+		}
+		version := step.Version
+		if version == 0 {
+			version = step.LatestVersion
+		}
+		w := sdk.Warning{
+			Action: sdk.Action{
+				ID: a.ID,
+			},
+			ID: DeprecatedActionVersion,
+			MessageParam: map[string]string{
+				"ActionName":   a.Name,
+				"PipelineName": pip,
+				"ProjectKey":   proj,
+				"StepOrder":    fmt.Sprintf("%d", i+1),
+				"StepName":     step.Name,
+				"Version":      fmt.Sprintf("%d", version),
+			},
+		}
+		warns = append(warns, w)
+	}
+	return warns
+}
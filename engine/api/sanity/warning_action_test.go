@@ -0,0 +1,29 @@
+package sanity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_checkDeprecatedActionVersions(t *testing.T) {
+	a := &sdk.Action{
+		ID:   1,
+		Name: "build",
+		Actions: []sdk.Action{
+			{Name: "Script"},
+			{Name: "deploy", Version: 2, LatestVersion: 3, Deprecated: true},
+			{Name: "notify", LatestVersion: 4, Deprecated: true},
+		},
+	}
+
+	warns := checkDeprecatedActionVersions("KEY", "pip", a)
+	assert.Len(t, warns, 2)
+	assert.Equal(t, int64(DeprecatedActionVersion), warns[0].ID)
+	assert.Equal(t, "2", warns[0].MessageParam["StepOrder"])
+	assert.Equal(t, "2", warns[0].MessageParam["Version"])
+	assert.Equal(t, "notify", warns[1].MessageParam["StepName"])
+	assert.Equal(t, "4", warns[1].MessageParam["Version"])
+}
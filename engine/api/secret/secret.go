@@ -82,6 +82,10 @@ func Encrypt(data []byte) ([]byte, error) {
 		log.Error("Missing key, init failed?")
 		return nil, sdk.ErrSecretKeyFetchFailed
 	}
+	return encrypt(key, data)
+}
+
+func encrypt(key, data []byte) ([]byte, error) {
 	// generate nonce
 	nonce := make([]byte, nonceSize)
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
@@ -112,12 +116,16 @@ func Decrypt(data []byte) ([]byte, error) {
 	if !strings.HasPrefix(string(data), prefix) {
 		return data, nil
 	}
-	data = []byte(strings.TrimPrefix(string(data), prefix))
 
 	if key == nil {
 		log.Error("Missing key, init failed?")
 		return nil, sdk.ErrSecretKeyFetchFailed
 	}
+	return decrypt(key, data)
+}
+
+func decrypt(key, data []byte) ([]byte, error) {
+	data = []byte(strings.TrimPrefix(string(data), prefix))
 
 	if len(data) < (nonceSize + macSize) {
 		log.Error("cannot decrypt secret, got invalid data")
@@ -146,6 +154,28 @@ func Decrypt(data []byte) ([]byte, error) {
 	return out, nil
 }
 
+// Reencrypt deciphers data encrypted with a previous cipher key, and ciphers it with the current key. Data which is
+// not encrypted is returned unchanged.
+func Reencrypt(previousKey string, data []byte) ([]byte, error) {
+	if !strings.HasPrefix(string(data), prefix) {
+		return data, nil
+	}
+
+	if key == nil {
+		log.Error("Missing key, init failed?")
+		return nil, sdk.ErrSecretKeyFetchFailed
+	}
+	if len(previousKey) < ckeySize {
+		return nil, fmt.Errorf("invalid previous cipher key")
+	}
+
+	clear, err := decrypt([]byte(previousKey), data)
+	if err != nil {
+		return nil, err
+	}
+	return encrypt(key, clear)
+}
+
 //DecryptVariable decrypts variable value using aes+hmac algorithm
 func DecryptVariable(v *sdk.Variable) error {
 	if !sdk.NeedPlaceholder(v.Type) {
@@ -99,3 +99,38 @@ func TestDecryptS(t *testing.T) {
 	}
 
 }
+
+func TestReencrypt(t *testing.T) {
+	previousKey := "78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf"
+	key = []byte(previousKey)
+	data := []byte("Hello world !")
+
+	ct, err := Encrypt(data)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %s", err)
+	}
+
+	key = []byte("9fIkZ04mPq2xLcVb7RtYu1NsWe3GhJaD")
+	ct, err = Reencrypt(previousKey, ct)
+	if err != nil {
+		t.Fatalf("Reencrypt failed: %s", err)
+	}
+
+	clear, err := Decrypt(ct)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %s", err)
+	}
+
+	if bytes.Compare(clear, data) != 0 {
+		t.Fatalf("Fail: Expected '%s', got '%s'", data, clear)
+	}
+
+	// Data which is not encrypted is kept as is
+	clear, err = Reencrypt(previousKey, data)
+	if err != nil {
+		t.Fatalf("Reencrypt failed: %s", err)
+	}
+	if bytes.Compare(clear, data) != 0 {
+		t.Fatalf("Fail: Expected '%s', got '%s'", data, clear)
+	}
+}
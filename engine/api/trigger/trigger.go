@@ -2,6 +2,7 @@ package trigger
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
@@ -63,6 +64,10 @@ func InsertTrigger(tx gorp.SqlExecutor, t *sdk.PipelineTrigger) error {
 		return err
 	}
 
+	if err := updateTriggerApproval(tx, t); err != nil {
+		return err
+	}
+
 	// Insert parameters
 	for _, p := range t.Parameters {
 		err = InsertTriggerParameter(tx, t.ID, p)
@@ -171,6 +176,10 @@ func UpdateTrigger(db gorp.SqlExecutor, t *sdk.PipelineTrigger) error {
 		return err
 	}
 
+	if err := updateTriggerApproval(db, t); err != nil {
+		return err
+	}
+
 	// Update parameters
 	query = `DELETE FROM pipeline_trigger_parameter WHERE pipeline_trigger_id = $1`
 	if _, err := db.Exec(query, t.ID); err != nil {
@@ -591,9 +600,50 @@ func loadTrigger(db gorp.SqlExecutor, s *sql.Rows, subqueries bool) (sdk.Pipelin
 		return t, err
 	}
 
+	t.Approval, err = loadTriggerApproval(db, t.ID)
+	if err != nil {
+		return t, err
+	}
+
 	return t, nil
 }
 
+func updateTriggerApproval(db gorp.SqlExecutor, t *sdk.PipelineTrigger) error {
+	if err := t.Approval.IsValid(); err != nil {
+		return err
+	}
+
+	var approval sql.NullString
+	if t.Approval != nil {
+		b, err := json.Marshal(t.Approval)
+		if err != nil {
+			return sdk.WrapError(err, "updateTriggerApproval> Unable to marshal approval gate of trigger %d", t.ID)
+		}
+		approval = sql.NullString{String: string(b), Valid: true}
+	}
+
+	if _, err := db.Exec(`UPDATE pipeline_trigger SET approval = $1 WHERE id = $2`, approval, t.ID); err != nil {
+		return sdk.WrapError(err, "updateTriggerApproval> Unable to update approval gate of trigger %d", t.ID)
+	}
+	return nil
+}
+
+func loadTriggerApproval(db gorp.SqlExecutor, triggerID int64) (*sdk.ApprovalGate, error) {
+	var approval sql.NullString
+	if err := db.QueryRow(`SELECT approval FROM pipeline_trigger WHERE id = $1`, triggerID).Scan(&approval); err != nil {
+		return nil, err
+	}
+	if !approval.Valid {
+		return nil, nil
+	}
+
+	var gate sdk.ApprovalGate
+	if err := json.Unmarshal([]byte(approval.String), &gate); err != nil {
+		return nil, sdk.WrapError(err, "loadTriggerApproval> Unable to unmarshal approval gate of trigger %d", triggerID)
+	}
+	return &gate, nil
+}
+
 func loadTriggerPrerequisites(db gorp.SqlExecutor, triggerID int64) ([]sdk.Prerequisite, error) {
 	query := `SELECT parameter, expected_value FROM pipeline_trigger_prerequisite WHERE pipeline_trigger_id = $1`
 
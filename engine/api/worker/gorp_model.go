@@ -19,8 +19,17 @@ func (m *WorkerModel) PostInsert(s gorp.SqlExecutor) error {
 		return err
 	}
 
-	query := "update worker_model set created_by = $2 where id = $1"
-	if _, err := s.Exec(query, m.ID, btes); err != nil {
+	var pool interface{}
+	if m.PoolPolicy != nil {
+		btesPool, err := json.Marshal(m.PoolPolicy)
+		if err != nil {
+			return err
+		}
+		pool = string(btesPool)
+	}
+
+	query := "update worker_model set created_by = $2, pool_policy = $3 where id = $1"
+	if _, err := s.Exec(query, m.ID, btes, pool); err != nil {
 		return err
 	}
 
@@ -74,6 +83,19 @@ func (m *WorkerModel) PostSelect(s gorp.SqlExecutor) error {
 		})
 	}
 
+	//Load pool policy
+	pool, errPool := s.SelectNullStr("select pool_policy from worker_model where id = $1", &m.ID)
+	if errPool != nil {
+		return errPool
+	}
+	m.PoolPolicy = nil
+	if pool.Valid && pool.String != "" {
+		m.PoolPolicy = &sdk.ModelPoolPolicy{}
+		if err := json.Unmarshal([]byte(pool.String), m.PoolPolicy); err != nil {
+			return err
+		}
+	}
+
 	//Load created_by
 	m.CreatedBy = sdk.User{}
 	str, errSelect := s.SelectNullStr("select created_by from worker_model where id = $1", &m.ID)
@@ -36,6 +36,12 @@ func addWorkerModel(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *b
 		return sdk.WrapError(sdk.ErrWrongRequest, "addWorkerModel> groupID should be set")
 	}
 
+	if model.PoolPolicy != nil {
+		if err := model.PoolPolicy.IsValid(); err != nil {
+			return sdk.WrapError(err, "addWorkerModel> invalid pool policy")
+		}
+	}
+
 	//User must be admin of the group set in the model
 	var ok bool
 	for _, g := range c.User.Groups {
@@ -67,6 +73,7 @@ func addWorkerModel(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *b
 	if err := worker.InsertWorkerModel(db, &model); err != nil {
 		return sdk.WrapError(err, "addWorkerModel> cannot add worker model")
 	}
+	c.AuditAfter = auditModel(&model)
 
 	return WriteJSON(w, r, model, http.StatusOK)
 }
@@ -114,6 +121,7 @@ func updateWorkerModel(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 	if errLoad != nil {
 		return sdk.WrapError(errLoad, "updateWorkerModel> cannot load worker model by id")
 	}
+	c.AuditBefore = auditModel(old)
 
 	// Unmarshal body
 	var model sdk.Model
@@ -178,6 +186,12 @@ func updateWorkerModel(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 		return sdk.WrapError(sdk.ErrInvalidID, "updateWorkerModel> wrong ID")
 	}
 
+	if model.PoolPolicy != nil {
+		if err := model.PoolPolicy.IsValid(); err != nil {
+			return sdk.WrapError(err, "updateWorkerModel> invalid pool policy")
+		}
+	}
+
 	tx, errtx := db.Begin()
 	if errtx != nil {
 		return sdk.WrapError(errtx, "updateWorkerModel> unable to start transaction")
@@ -230,6 +244,7 @@ func updateWorkerModel(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "updateWorkerModel> unable to commit transaction")
 	}
+	c.AuditAfter = auditModel(&model)
 
 	// Recompute warnings
 	go func() {
@@ -252,6 +267,12 @@ func deleteWorkerModel(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c
 		return sdk.WrapError(errr, "deleteWorkerModel> Invalid permModelID")
 	}
 
+	old, errLoad := worker.LoadWorkerModelByID(db, workerModelID)
+	if errLoad != nil {
+		return sdk.WrapError(errLoad, "deleteWorkerModel> cannot load worker model by id")
+	}
+	c.AuditBefore = auditModel(old)
+
 	tx, err := db.Begin()
 	if err != nil {
 		return sdk.WrapError(err, "deleteWorkerModel> Cannot start transaction")
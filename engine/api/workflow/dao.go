@@ -8,6 +8,7 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/history"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -91,6 +92,12 @@ func load(db gorp.SqlExecutor, u *sdk.User, query string, args ...interface{}) (
 
 	res.Joins = joins
 
+	notifs, errN := loadNotifications(db, &res)
+	if errN != nil {
+		return nil, sdk.WrapError(errN, "Load> Unable to load workflow notifications")
+	}
+	res.Notifications = notifs
+
 	delta := time.Since(t0).Seconds()
 
 	log.Debug("Load> Load workflow (%s/%s)%d took %.3f seconds", res.ProjectKey, res.Name, res.ID, delta)
@@ -119,7 +126,7 @@ func Insert(db gorp.SqlExecutor, w *sdk.Workflow, u *sdk.User) error {
 	}
 
 	w.LastModified = time.Now()
-	if err := db.QueryRow("INSERT INTO workflow (name, description, project_id) VALUES ($1, $2, $3) RETURNING id", w.Name, w.Description, w.ProjectID).Scan(&w.ID); err != nil {
+	if err := db.QueryRow("INSERT INTO workflow (name, description, project_id, job_priority) VALUES ($1, $2, $3, $4) RETURNING id", w.Name, w.Description, w.ProjectID, w.JobPriority).Scan(&w.ID); err != nil {
 		return sdk.WrapError(err, "Insert> Unable to insert workflow %s/%s", w.ProjectKey, w.Name)
 	}
 
@@ -142,6 +149,13 @@ func Insert(db gorp.SqlExecutor, w *sdk.Workflow, u *sdk.User) error {
 		}
 	}
 
+	for i := range w.Notifications {
+		n := &w.Notifications[i]
+		if err := insertNotification(db, w, n); err != nil {
+			return sdk.WrapError(err, "Insert> Unable to insert workflow(%d) notification", w.ID)
+		}
+	}
+
 	return updateLastModified(db, w, u)
 }
 
@@ -151,6 +165,11 @@ func Update(db gorp.SqlExecutor, w *sdk.Workflow, oldWorkflow *sdk.Workflow, u *
 		return err
 	}
 
+	// Delete all OLD notifications
+	if err := deleteNotifications(db, oldWorkflow); err != nil {
+		return sdk.WrapError(err, "Update> unable to delete all notifications on workflow(%d)", w.ID)
+	}
+
 	// Delete all OLD JOIN
 	for _, j := range oldWorkflow.Joins {
 		if err := deleteJoin(db, j); err != nil {
@@ -183,6 +202,14 @@ func Update(db gorp.SqlExecutor, w *sdk.Workflow, oldWorkflow *sdk.Workflow, u *
 		}
 	}
 
+	// Insert new notifications
+	for i := range w.Notifications {
+		n := &w.Notifications[i]
+		if err := insertNotification(db, w, n); err != nil {
+			return sdk.WrapError(err, "Update> Unable to insert workflow(%d) notification", w.ID)
+		}
+	}
+
 	w.LastModified = time.Now()
 	dbw := Workflow(*w)
 	if _, err := db.Update(&dbw); err != nil {
@@ -230,7 +257,16 @@ func updateLastModified(db gorp.SqlExecutor, w *sdk.Workflow, u *sdk.User) error
 			LastModified: t.Unix(),
 		}, 0)
 	}
-	return nil
+	return recordHistory(db, w.ID, u)
+}
+
+// recordHistory stores the current graph of the workflow as a new version in its history
+func recordHistory(db gorp.SqlExecutor, id int64, u *sdk.User) error {
+	snapshot, err := LoadByID(db, id, u)
+	if err != nil {
+		return sdk.WrapError(err, "recordHistory> Unable to load workflow %d", id)
+	}
+	return history.Record(db, sdk.HistoryWorkflow, snapshot.ProjectID, snapshot.ID, snapshot.Name, snapshot, u)
 }
 
 // HasAccessTo checks if user has full r, rx or rwx access to the workflow
@@ -245,6 +281,11 @@ func IsValid(db gorp.SqlExecutor, w *sdk.Workflow, u *sdk.User) error {
 		return sdk.NewError(sdk.ErrWorkflowInvalid, fmt.Errorf("Invalid project key"))
 	}
 
+	//Check job priority
+	if err := sdk.IsValidJobPriority(w.JobPriority); err != nil {
+		return err
+	}
+
 	//Check duplicate refs
 	refs := w.References()
 	for i, ref1 := range refs {
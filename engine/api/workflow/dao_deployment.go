@@ -0,0 +1,201 @@
+package workflow
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+//PostInsert is a db hook on Deployment in table workflow_deployment, it stores the artifacts
+func (d *Deployment) PostInsert(db gorp.SqlExecutor) error {
+	artifacts, err := gorpmapping.JSONToNullString(d.Artifacts)
+	if err != nil {
+		return sdk.WrapError(err, "Deployment.PostInsert> unable to get json from artifacts")
+	}
+	if _, err := db.Exec("update workflow_deployment set artifacts = $2 where id = $1", d.ID, artifacts); err != nil {
+		return sdk.WrapError(err, "Deployment.PostInsert> unable to update workflow_deployment id=%d", d.ID)
+	}
+	return nil
+}
+
+//PostUpdate is a db hook on Deployment in table workflow_deployment, it stores the artifacts
+func (d *Deployment) PostUpdate(db gorp.SqlExecutor) error {
+	return d.PostInsert(db)
+}
+
+//PostGet is a db hook on Deployment in table workflow_deployment, it loads the artifacts
+func (d *Deployment) PostGet(db gorp.SqlExecutor) error {
+	var artifacts sql.NullString
+	if err := db.QueryRow("select artifacts from workflow_deployment where id = $1", d.ID).Scan(&artifacts); err != nil {
+		return sdk.WrapError(err, "Deployment.PostGet> Unable to load artifacts of workflow_deployment id=%d", d.ID)
+	}
+	if err := gorpmapping.JSONNullString(artifacts, &d.Artifacts); err != nil {
+		return sdk.WrapError(err, "Deployment.PostGet> Unable to unmarshal artifacts of workflow_deployment id=%d", d.ID)
+	}
+	return nil
+}
+
+// recordDeployment inserts or updates the deployment ledger entry of a node run.
+// Nothing is recorded if the node has no application or no environment in its context.
+func recordDeployment(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun) error {
+	d, err := loadDeployment(db, "select * from workflow_deployment where workflow_node_run_id = $1", n.ID)
+	if err != nil && err != sdk.ErrWorkflowDeploymentNotFound {
+		return sdk.WrapError(err, "workflow.recordDeployment> Unable to load deployment of node run %d", n.ID)
+	}
+
+	if d == nil {
+		query := `SELECT project.id, project.projectkey, workflow.id, workflow.name, application.id, application.name, environment.id, environment.name
+		FROM workflow_node_context
+		JOIN workflow_node ON workflow_node.id = workflow_node_context.workflow_node_id
+		JOIN workflow ON workflow.id = workflow_node.workflow_id
+		JOIN project ON project.id = workflow.project_id
+		JOIN application ON application.id = workflow_node_context.application_id
+		JOIN environment ON environment.id = workflow_node_context.environment_id
+		WHERE workflow_node_context.workflow_node_id = $1`
+		d = &sdk.WorkflowDeployment{
+			WorkflowRunID:     n.WorkflowRunID,
+			WorkflowNodeID:    n.WorkflowNodeID,
+			WorkflowNodeRunID: n.ID,
+			Number:            n.Number,
+			SubNumber:         n.SubNumber,
+		}
+		if err := db.QueryRow(query, n.WorkflowNodeID).Scan(&d.ProjectID, &d.ProjectKey, &d.WorkflowID, &d.WorkflowName,
+			&d.ApplicationID, &d.ApplicationName, &d.EnvironmentID, &d.EnvironmentName); err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			return sdk.WrapError(err, "workflow.recordDeployment> Unable to load context of node %d", n.WorkflowNodeID)
+		}
+		if d.EnvironmentID == sdk.DefaultEnv.ID {
+			return nil
+		}
+
+		d.Version = sdk.ParameterValue(n.BuildParameters, "cds.version")
+		d.GitBranch = sdk.ParameterValue(n.BuildParameters, "git.branch")
+		d.GitHash = sdk.ParameterValue(n.BuildParameters, "git.hash")
+		if n.Manual != nil && n.Manual.User.Username != "" {
+			d.TriggeredBy = n.Manual.User.Username
+		} else {
+			d.TriggeredBy = sdk.ParameterValue(n.BuildParameters, "git.author")
+		}
+	}
+
+	d.Status = n.Status
+	d.Start = n.Start
+	d.Done = n.Done
+	if n.Status == sdk.StatusSuccess.String() {
+		arts, err := loadArtifactByNodeRunID(db, n.ID)
+		if err != nil {
+			return sdk.WrapError(err, "workflow.recordDeployment> Unable to load artifacts of node run %d", n.ID)
+		}
+		d.Artifacts = make([]sdk.WorkflowDeploymentArtifact, len(arts))
+		for i, a := range arts {
+			d.Artifacts[i] = sdk.WorkflowDeploymentArtifact{ID: a.ID, Name: a.Name, Tag: a.Tag, SHA256Sum: a.SHA256Sum}
+		}
+	}
+
+	dd := Deployment(*d)
+	if d.ID == 0 {
+		if err := db.Insert(&dd); err != nil {
+			return sdk.WrapError(err, "workflow.recordDeployment> Unable to insert deployment of node run %d", n.ID)
+		}
+		return nil
+	}
+	if _, err := db.Update(&dd); err != nil {
+		return sdk.WrapError(err, "workflow.recordDeployment> Unable to update deployment %d", d.ID)
+	}
+	return nil
+}
+
+// LoadDeployments loads the last deployments of the given projects, filtered on the environment
+// and the application names if not empty
+func LoadDeployments(db gorp.SqlExecutor, projectIDs []int64, environmentName, applicationName string, limit int) ([]sdk.WorkflowDeployment, error) {
+	ids := make([]string, len(projectIDs))
+	for i, id := range projectIDs {
+		ids[i] = fmt.Sprintf("%d", id)
+	}
+
+	query := `select * from workflow_deployment
+	where project_id = ANY(string_to_array($1, ',')::bigint[])
+	and ($2 = '' or environment_name = $2)
+	and ($3 = '' or application_name = $3)
+	order by start desc
+	limit $4`
+	var res []Deployment
+	if _, err := db.Select(&res, query, strings.Join(ids, ","), environmentName, applicationName, limit); err != nil {
+		return nil, sdk.WrapError(err, "workflow.LoadDeployments> Unable to load deployments")
+	}
+
+	deployments := make([]sdk.WorkflowDeployment, len(res))
+	for i := range res {
+		deployments[i] = sdk.WorkflowDeployment(res[i])
+	}
+	return deployments, nil
+}
+
+// LoadDeploymentByID loads a deployment of a project
+func LoadDeploymentByID(db gorp.SqlExecutor, projectID, id int64) (*sdk.WorkflowDeployment, error) {
+	return loadDeployment(db, "select * from workflow_deployment where project_id = $1 and id = $2", projectID, id)
+}
+
+func loadDeployment(db gorp.SqlExecutor, query string, args ...interface{}) (*sdk.WorkflowDeployment, error) {
+	var dd Deployment
+	if err := db.SelectOne(&dd, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrWorkflowDeploymentNotFound
+		}
+		return nil, sdk.WrapError(err, "workflow.loadDeployment> Unable to load deployment")
+	}
+	d := sdk.WorkflowDeployment(dd)
+	return &d, nil
+}
+
+// RollbackDeployment runs again the node of a successful deployment, with its payload and its pipeline parameters,
+// in the workflow run of the deployment. The new ledger entry references the deployment it rolls back.
+func RollbackDeployment(db gorp.SqlExecutor, w *sdk.Workflow, d *sdk.WorkflowDeployment, u *sdk.User) (*sdk.WorkflowRun, error) {
+	if d.Status != sdk.StatusSuccess.String() {
+		return nil, sdk.WrapError(sdk.ErrNoPreviousSuccess, "workflow.RollbackDeployment> Deployment %d is %s", d.ID, d.Status)
+	}
+
+	if w.GetNode(d.WorkflowNodeID) == nil {
+		return nil, sdk.WrapError(sdk.ErrDeploymentNodeNotFound, "workflow.RollbackDeployment> Node %d of deployment %d is not in workflow %s", d.WorkflowNodeID, d.ID, w.Name)
+	}
+
+	n, err := LoadNodeRunByID(db, d.WorkflowNodeRunID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "workflow.RollbackDeployment> Unable to load node run %d", d.WorkflowNodeRunID)
+	}
+
+	manual := &sdk.WorkflowNodeRunManual{
+		Payload:            n.Payload,
+		PipelineParameters: n.PipelineParameters,
+		User:               *u,
+	}
+	wr, err := ManualRunFromNode(db, w, d.Number, manual, d.WorkflowNodeID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "workflow.RollbackDeployment> Unable to run node %d", d.WorkflowNodeID)
+	}
+
+	var last *sdk.WorkflowNodeRun
+	for i, nr := range wr.WorkflowNodeRuns[d.WorkflowNodeID] {
+		if last == nil || nr.SubNumber > last.SubNumber {
+			last = &wr.WorkflowNodeRuns[d.WorkflowNodeID][i]
+		}
+	}
+	if last == nil || last.ID == n.ID {
+		return wr, nil
+	}
+
+	if err := recordDeployment(db, last); err != nil {
+		return nil, sdk.WrapError(err, "workflow.RollbackDeployment> Unable to record deployment of node run %d", last.ID)
+	}
+	if _, err := db.Exec("update workflow_deployment set rollback_of = $2 where workflow_node_run_id = $1", last.ID, d.ID); err != nil {
+		return nil, sdk.WrapError(err, "workflow.RollbackDeployment> Unable to update deployment of node run %d", last.ID)
+	}
+	return wr, nil
+}
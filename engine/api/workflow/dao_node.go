@@ -100,6 +100,7 @@ type sqlContext struct {
 	EnvID                     sql.NullInt64  `db:"environment_id"`
 	DefaultPayload            sql.NullString `db:"default_payload"`
 	DefaultPipelineParameters sql.NullString `db:"default_pipeline_parameters"`
+	Approval                  sql.NullString `db:"approval"`
 }
 
 func insertNodeContext(db gorp.SqlExecutor, c *sdk.WorkflowNodeContext) error {
@@ -139,6 +140,18 @@ func insertNodeContext(db gorp.SqlExecutor, c *sdk.WorkflowNodeContext) error {
 		sqlContext.DefaultPipelineParameters = sql.NullString{String: string(b), Valid: true}
 	}
 
+	// Set Approval in context
+	if c.Approval != nil {
+		if err := c.Approval.IsValid(); err != nil {
+			return sdk.WrapError(err, "InsertOrUpdateNode> Invalid approval gate on workflow node context(%d)", c.ID)
+		}
+		b, errM := json.Marshal(c.Approval)
+		if errM != nil {
+			return sdk.WrapError(errM, "InsertOrUpdateNode> Unable to marshall workflow node context(%d) approval", c.ID)
+		}
+		sqlContext.Approval = sql.NullString{String: string(b), Valid: true}
+	}
+
 	if _, err := db.Update(&sqlContext); err != nil {
 		return sdk.WrapError(err, "InsertOrUpdateNode> Unable to update workflow node context(%d)", c.ID)
 	}
@@ -200,7 +213,7 @@ func loadNodeContext(db gorp.SqlExecutor, wn *sdk.WorkflowNode, u *sdk.User) (*s
 
 	var sqlContext = sqlContext{}
 	if err := db.SelectOne(&sqlContext,
-		"select application_id, environment_id, default_payload, default_pipeline_parameters from workflow_node_context where id = $1", ctx.ID); err != nil {
+		"select application_id, environment_id, default_payload, default_pipeline_parameters, approval from workflow_node_context where id = $1", ctx.ID); err != nil {
 		return nil, err
 	}
 	if sqlContext.AppID.Valid {
@@ -224,6 +237,14 @@ func loadNodeContext(db gorp.SqlExecutor, wn *sdk.WorkflowNode, u *sdk.User) (*s
 		}
 	}
 
+	//Unmarshal approval
+	if sqlContext.Approval.Valid {
+		ctx.Approval = &sdk.ApprovalGate{}
+		if err := json.Unmarshal([]byte(sqlContext.Approval.String), ctx.Approval); err != nil {
+			return nil, sdk.WrapError(err, "loadNodeContext> Unable to unmarshall context %d approval", ctx.ID)
+		}
+	}
+
 	//Load the application in the context
 	if ctx.ApplicationID != 0 {
 		app, err := application.LoadByID(db, ctx.ApplicationID, u)
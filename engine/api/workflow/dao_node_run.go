@@ -91,12 +91,13 @@ type sqlNodeRun struct {
 	PipelineParameters sql.NullString `db:"pipeline_parameters"`
 	BuildParameters    sql.NullString `db:"build_parameters"`
 	Tests              sql.NullString `db:"tests"`
+	StaticAnalysis     sql.NullString `db:"static_analysis"`
 	Commits            sql.NullString `db:"commits"`
 	Stages             sql.NullString `db:"stages"`
 }
 
 //PostInsert is a db hook on WorkflowNodeRun in table workflow_node_run
-//it stores columns hook_event, manual, trigger_id, payload, pipeline_parameters, tests, commits
+//it stores columns hook_event, manual, trigger_id, payload, pipeline_parameters, tests, static_analysis, commits
 func (r *NodeRun) PostInsert(db gorp.SqlExecutor) error {
 	var rr = sqlNodeRun{ID: r.ID}
 	if r.Stages != nil {
@@ -155,6 +156,13 @@ func (r *NodeRun) PostInsert(db gorp.SqlExecutor) error {
 		}
 		rr.Tests = s
 	}
+	if r.StaticAnalysis != nil {
+		s, err := gorpmapping.JSONToNullString(r.StaticAnalysis)
+		if err != nil {
+			return sdk.WrapError(err, "NodeRun.PostInsert> unable to get json from static_analysis")
+		}
+		rr.StaticAnalysis = s
+	}
 	if r.Commits != nil {
 		s, err := gorpmapping.JSONToNullString(r.Commits)
 		if err != nil {
@@ -228,6 +236,12 @@ func (r *NodeRun) PostGet(db gorp.SqlExecutor) error {
 	if err := gorpmapping.JSONNullString(rr.Tests, r.Tests); err != nil {
 		return sdk.WrapError(err, "NodeRun.PostGet> Error loading node run %d", r.ID)
 	}
+	if rr.StaticAnalysis.Valid {
+		r.StaticAnalysis = new(sdk.StaticAnalysisSummary)
+	}
+	if err := gorpmapping.JSONNullString(rr.StaticAnalysis, r.StaticAnalysis); err != nil {
+		return sdk.WrapError(err, "NodeRun.PostGet> Error loading node run %d", r.ID)
+	}
 
 	arts, errA := loadArtifactByNodeRunID(db, r.ID)
 	if errA != nil {
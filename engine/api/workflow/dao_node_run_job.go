@@ -69,6 +69,112 @@ func LoadNodeJobRunQueue(db gorp.SqlExecutor, groupsID []int64, since *time.Time
 	return jobs, nil
 }
 
+// LoadNodeJobRunProjectIDs returns the project ID of each given workflow_node_run_job
+func LoadNodeJobRunProjectIDs(db gorp.SqlExecutor, ids []int64) (map[int64]int64, error) {
+	res := map[int64]int64{}
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	strIDs := make([]string, len(ids))
+	for i, id := range ids {
+		strIDs[i] = strconv.FormatInt(id, 10)
+	}
+
+	query := `select workflow_node_run_job.id, workflow_run.project_id
+	from workflow_node_run_job
+	join workflow_node_run on workflow_node_run.id = workflow_node_run_job.workflow_node_run_id
+	join workflow_run on workflow_run.id = workflow_node_run.workflow_run_id
+	where workflow_node_run_job.id = ANY(string_to_array($1, ',')::bigint[])`
+	rows, err := db.Query(query, strings.Join(strIDs, ","))
+	if err != nil {
+		return nil, sdk.WrapError(err, "workflow.LoadNodeJobRunProjectIDs> Unable to load projects of job runs")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, projectID int64
+		if err := rows.Scan(&id, &projectID); err != nil {
+			return nil, sdk.WrapError(err, "workflow.LoadNodeJobRunProjectIDs> Unable to scan")
+		}
+		res[id] = projectID
+	}
+	return res, nil
+}
+
+// CountBuildingNodeJobRunsByProject returns the number of building workflow_node_run_job of each project
+func CountBuildingNodeJobRunsByProject(db gorp.SqlExecutor) (map[int64]int, error) {
+	query := `select workflow_run.project_id, count(workflow_node_run_job.id)
+	from workflow_node_run_job
+	join workflow_node_run on workflow_node_run.id = workflow_node_run_job.workflow_node_run_id
+	join workflow_run on workflow_run.id = workflow_node_run.workflow_run_id
+	where workflow_node_run_job.status = $1
+	group by workflow_run.project_id`
+	rows, err := db.Query(query, sdk.StatusBuilding.String())
+	if err != nil {
+		return nil, sdk.WrapError(err, "workflow.CountBuildingNodeJobRunsByProject> Unable to count job runs")
+	}
+	defer rows.Close()
+
+	res := map[int64]int{}
+	for rows.Next() {
+		var projectID int64
+		var count int
+		if err := rows.Scan(&projectID, &count); err != nil {
+			return nil, sdk.WrapError(err, "workflow.CountBuildingNodeJobRunsByProject> Unable to scan")
+		}
+		res[projectID] = count
+	}
+	return res, nil
+}
+
+// CountBookedNodeJobRunsByProject returns the number of waiting workflow_node_run_job booked by a hatchery of each
+// project, the given job run excepted
+func CountBookedNodeJobRunsByProject(db gorp.SqlExecutor, except int64) (map[int64]int, error) {
+	query := `select workflow_node_run_job.id, workflow_run.project_id
+	from workflow_node_run_job
+	join workflow_node_run on workflow_node_run.id = workflow_node_run_job.workflow_node_run_id
+	join workflow_run on workflow_run.id = workflow_node_run.workflow_run_id
+	where workflow_node_run_job.status = $1 and workflow_node_run_job.id <> $2`
+	rows, err := db.Query(query, sdk.StatusWaiting.String(), except)
+	if err != nil {
+		return nil, sdk.WrapError(err, "workflow.CountBookedNodeJobRunsByProject> Unable to load job runs")
+	}
+	defer rows.Close()
+
+	res := map[int64]int{}
+	for rows.Next() {
+		var id, projectID int64
+		if err := rows.Scan(&id, &projectID); err != nil {
+			return nil, sdk.WrapError(err, "workflow.CountBookedNodeJobRunsByProject> Unable to scan")
+		}
+		var h sdk.Hatchery
+		if cache.Get(keyBookJob(id), &h) {
+			res[projectID]++
+		}
+	}
+	return res, nil
+}
+
+// jobRunPriority returns the priority of the jobs of a node run, from the manual run, the workflow or the project.
+// The nodes triggered by a manual run have the priority of the manual run, which is kept on the workflow run
+func jobRunPriority(db gorp.SqlExecutor, run *sdk.WorkflowNodeRun) (int, error) {
+	query := `select project.job_priority, workflow.job_priority, workflow_run.priority
+	from workflow_run
+	join workflow on workflow.id = workflow_run.workflow_id
+	join project on project.id = workflow_run.project_id
+	where workflow_run.id = $1`
+	var projectPriority, workflowPriority, manualPriority *int
+	if err := db.QueryRow(query, run.WorkflowRunID).Scan(&projectPriority, &workflowPriority, &manualPriority); err != nil {
+		return 0, sdk.WrapError(err, "workflow.jobRunPriority> Unable to load priorities of workflow run %d", run.WorkflowRunID)
+	}
+
+	if run.Manual != nil && run.Manual.Priority != nil {
+		manualPriority = run.Manual.Priority
+	}
+	return sdk.EffectiveJobPriority(projectPriority, workflowPriority, manualPriority), nil
+}
+
 //LoadNodeJobRun load a NodeJobRun given its ID
 func LoadNodeJobRun(db gorp.SqlExecutor, id int64) (*sdk.WorkflowNodeJobRun, error) {
 	j := JobRun{}
@@ -102,9 +208,15 @@ func insertWorkflowNodeJobRun(db gorp.SqlExecutor, j *sdk.WorkflowNodeJobRun) er
 
 //DeleteNodeJobRuns deletes all workflow_node_run_job for a given workflow_node_run
 func DeleteNodeJobRuns(db gorp.SqlExecutor, nodeID int64) error {
-	query := `delete from workflow_node_run_job where workflow_node_run_id = $1`
-	_, err := db.Exec(query, nodeID)
-	return err
+	query := `delete from workflow_node_run_job where workflow_node_run_id = $1 returning id`
+	var ids []int64
+	if _, err := db.Select(&ids, query, nodeID); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		deleteJobRunMasker(id)
+	}
+	return nil
 }
 
 //UpdateNodeJobRun updates a workflow_node_run_job
@@ -0,0 +1,113 @@
+package workflow
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/notification"
+	"github.com/ovh/cds/sdk"
+)
+
+//PostInsert is a db hook on Notification in table workflow_notification, it stores the settings with the webhook urls encrypted
+func (n *Notification) PostInsert(db gorp.SqlExecutor) error {
+	encrypted, err := notification.EncryptSettings(n.Settings, nil)
+	if err != nil {
+		return sdk.WrapError(err, "Notification.PostInsert> unable to encrypt settings")
+	}
+	settings, err := gorpmapping.JSONToNullString(encrypted)
+	if err != nil {
+		return sdk.WrapError(err, "Notification.PostInsert> unable to get json from settings")
+	}
+	if _, err := db.Exec("update workflow_notification set settings = $2 where id = $1", n.ID, settings); err != nil {
+		return sdk.WrapError(err, "Notification.PostInsert> unable to update workflow_notification id=%d", n.ID)
+	}
+	return nil
+}
+
+//PostGet is a db hook on Notification in table workflow_notification, it loads the settings and the sources
+func (n *Notification) PostGet(db gorp.SqlExecutor) error {
+	var settings sql.NullString
+	if err := db.QueryRow("select settings from workflow_notification where id = $1", n.ID).Scan(&settings); err != nil {
+		return sdk.WrapError(err, "Notification.PostGet> Unable to load settings of workflow_notification id=%d", n.ID)
+	}
+	if settings.Valid {
+		typed, err := json.Marshal(map[sdk.UserNotificationSettingsType]json.RawMessage{n.Type: json.RawMessage(settings.String)})
+		if err != nil {
+			return sdk.WrapError(err, "Notification.PostGet> Unable to read settings of workflow_notification id=%d", n.ID)
+		}
+		all, err := sdk.ParseUserNotificationSettings(typed)
+		if err != nil {
+			return sdk.WrapError(err, "Notification.PostGet> Unable to parse settings of workflow_notification id=%d", n.ID)
+		}
+		n.Settings = all[n.Type]
+		if err := notification.DecryptSettings(n.Settings); err != nil {
+			return sdk.WrapError(err, "Notification.PostGet> Unable to decrypt settings of workflow_notification id=%d", n.ID)
+		}
+	}
+
+	if _, err := db.Select(&n.SourceNodeIDs, "select workflow_node_id from workflow_notification_source where workflow_notification_id = $1", n.ID); err != nil {
+		return sdk.WrapError(err, "Notification.PostGet> Unable to load sources of workflow_notification id=%d", n.ID)
+	}
+	n.SourceNodeRefs = nil
+	for _, id := range n.SourceNodeIDs {
+		n.SourceNodeRefs = append(n.SourceNodeRefs, fmt.Sprintf("%d", id))
+	}
+	return nil
+}
+
+func loadNotifications(db gorp.SqlExecutor, w *sdk.Workflow) ([]sdk.WorkflowNotification, error) {
+	dbNotifs := []Notification{}
+	if _, err := db.Select(&dbNotifs, "select * from workflow_notification where workflow_id = $1 order by id", w.ID); err != nil {
+		return nil, sdk.WrapError(err, "loadNotifications> Unable to load notifications on workflow %d", w.ID)
+	}
+
+	notifs := make([]sdk.WorkflowNotification, len(dbNotifs))
+	for i := range dbNotifs {
+		notifs[i] = sdk.WorkflowNotification(dbNotifs[i])
+	}
+	return notifs, nil
+}
+
+// insertNotification inserts a notification rule. Its sources are given by the references of the nodes in the workflow.
+func insertNotification(db gorp.SqlExecutor, w *sdk.Workflow, n *sdk.WorkflowNotification) error {
+	n.WorkflowID = w.ID
+	n.ID = 0
+	n.SourceNodeIDs = nil
+
+	if n.Settings == nil {
+		return sdk.WrapError(sdk.ErrParseUserNotification, "insertNotification> Invalid notification settings")
+	}
+
+	for _, s := range n.SourceNodeRefs {
+		foundRef := findNodeByRefInWorkflow(s, w)
+		if foundRef == nil || foundRef.ID == 0 {
+			return sdk.WrapError(sdk.ErrWorkflowNodeRef, "insertNotification> Invalid notification references")
+		}
+		n.SourceNodeIDs = append(n.SourceNodeIDs, foundRef.ID)
+	}
+
+	dbNotif := Notification(*n)
+	if err := db.Insert(&dbNotif); err != nil {
+		return sdk.WrapError(err, "insertNotification> Unable to insert workflow notification")
+	}
+	n.ID = dbNotif.ID
+
+	query := "insert into workflow_notification_source(workflow_node_id, workflow_notification_id) values ($1, $2)"
+	for _, source := range n.SourceNodeIDs {
+		if _, err := db.Exec(query, source, n.ID); err != nil {
+			return sdk.WrapError(err, "insertNotification> Unable to insert associations between node %d and notification %d", source, n.ID)
+		}
+	}
+	return nil
+}
+
+func deleteNotifications(db gorp.SqlExecutor, w *sdk.Workflow) error {
+	if _, err := db.Exec("delete from workflow_notification where workflow_id = $1", w.ID); err != nil {
+		return sdk.WrapError(err, "deleteNotifications> Unable to delete notifications on workflow %d", w.ID)
+	}
+	return nil
+}
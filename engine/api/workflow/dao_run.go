@@ -109,6 +109,53 @@ func LoadRun(db gorp.SqlExecutor, projectkey, workflowname string, number int64)
 	return loadRun(db, query, projectkey, workflowname, number)
 }
 
+// LoadLastSuccessfulRun returns the last ended and successful run of a workflow: like endedRunStatus, the last run
+// of each node must be neither running nor failed. If branch is not empty, only the runs built on this git branch
+// or tag are considered
+func LoadLastSuccessfulRun(db gorp.SqlExecutor, projectkey, workflowname, branch string) (*sdk.WorkflowRun, error) {
+	// build_parameters are filtered with the jsonb containment operator, so only name and value are marshalled.
+	// An empty array is contained by any build_parameters
+	gitBranch, gitTag := "[]", "[]"
+	if branch != "" {
+		b, errB := json.Marshal([]map[string]string{{"name": "git.branch", "value": branch}})
+		if errB != nil {
+			return nil, sdk.WrapError(errB, "LoadLastSuccessfulRun> Unable to marshal branch filter")
+		}
+		t, errT := json.Marshal([]map[string]string{{"name": "git.tag", "value": branch}})
+		if errT != nil {
+			return nil, sdk.WrapError(errT, "LoadLastSuccessfulRun> Unable to marshal tag filter")
+		}
+		gitBranch, gitTag = string(b), string(t)
+	}
+
+	query := `select workflow_run.*
+	from workflow_run
+	join project on workflow_run.project_id = project.id
+	join workflow on workflow_run.workflow_id = workflow.id
+	where project.projectkey = $1
+	and workflow.name = $2
+	and not exists (
+		select 1 from workflow_node_run node_run
+		where node_run.workflow_run_id = workflow_run.id
+		and node_run.status in ($3, $4, $5, $6, $7)
+		and node_run.sub_num = (
+			select max(last_run.sub_num) from workflow_node_run last_run
+			where last_run.workflow_run_id = node_run.workflow_run_id
+			and last_run.workflow_node_id = node_run.workflow_node_id
+		)
+	)
+	and exists (
+		select 1 from workflow_node_run node_run
+		where node_run.workflow_run_id = workflow_run.id
+		and (node_run.build_parameters @> $8::jsonb or node_run.build_parameters @> $9::jsonb)
+	)
+	order by workflow_run.num desc
+	limit 1`
+	return loadRun(db, query, projectkey, workflowname,
+		sdk.StatusWaiting.String(), sdk.StatusChecking.String(), sdk.StatusBuilding.String(), sdk.StatusFail.String(), sdk.StatusStopped.String(),
+		gitBranch, gitTag)
+}
+
 // LoadRunByID returns a specific run
 func LoadRunByID(db gorp.SqlExecutor, projectkey string, id int64) (*sdk.WorkflowRun, error) {
 	query := `select workflow_run.* 
@@ -0,0 +1,118 @@
+package workflow
+
+import (
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertStaticAnalysisFindings inserts findings reported on a node run. Findings which were not reported by the
+// previous run of the same workflow node are flagged as new. Then the summary and the build parameters
+// of the node run are updated, so they can be used in trigger conditions.
+func InsertStaticAnalysisFindings(db gorp.SqlExecutor, nodeRun *sdk.WorkflowNodeRun, findings []sdk.StaticAnalysisFinding) error {
+	run, errR := loadRunByID(db, nodeRun.WorkflowRunID)
+	if errR != nil {
+		return sdk.WrapError(errR, "InsertStaticAnalysisFindings> Unable to load workflow run %d", nodeRun.WorkflowRunID)
+	}
+
+	node := run.Workflow.GetNode(nodeRun.WorkflowNodeID)
+	if node == nil {
+		return sdk.WrapError(sdk.ErrWorkflowNodeNotFound, "InsertStaticAnalysisFindings> Unable to find node %d", nodeRun.WorkflowNodeID)
+	}
+
+	previous, errP := loadPreviousFingerprints(db, run.WorkflowID, node.Name, nodeRun.ID)
+	if errP != nil {
+		return sdk.WrapError(errP, "InsertStaticAnalysisFindings> Unable to load previous findings")
+	}
+
+	current, errC := LoadStaticAnalysisFindings(db, nodeRun.ID)
+	if errC != nil {
+		return sdk.WrapError(errC, "InsertStaticAnalysisFindings> Unable to load current findings")
+	}
+	known := make(map[string]bool, len(current))
+	for _, f := range current {
+		known[f.Fingerprint] = true
+	}
+
+	// The occurrences of identical findings are numbered among all the findings sent together
+	sdk.ComputeFingerprints(findings)
+	for i := range findings {
+		f := &findings[i]
+		// Same finding reported twice on the same node run (by two jobs for instance)
+		if known[f.Fingerprint] {
+			continue
+		}
+		known[f.Fingerprint] = true
+
+		f.WorkflowRunID = run.ID
+		f.WorkflowNodeRunID = nodeRun.ID
+		f.WorkflowID = run.WorkflowID
+		f.WorkflowNodeName = node.Name
+		f.New = !previous[f.Fingerprint]
+		f.Created = time.Now()
+
+		dbFinding := NodeRunFinding(*f)
+		if err := db.Insert(&dbFinding); err != nil {
+			return sdk.WrapError(err, "InsertStaticAnalysisFindings> Unable to insert finding")
+		}
+		f.ID = dbFinding.ID
+		current = append(current, *f)
+	}
+
+	nodeRun.StaticAnalysis = sdk.NewStaticAnalysisSummary(current)
+	for _, p := range nodeRun.StaticAnalysis.Parameters() {
+		sdk.AddParameter(&nodeRun.BuildParameters, p.Name, p.Type, p.Value)
+	}
+
+	return UpdateNodeRun(db, nodeRun)
+}
+
+// LoadStaticAnalysisFindings loads all findings reported on a node run
+func LoadStaticAnalysisFindings(db gorp.SqlExecutor, nodeRunID int64) ([]sdk.StaticAnalysisFinding, error) {
+	var dbFindings []NodeRunFinding
+	query := `SELECT * FROM workflow_node_run_finding WHERE workflow_node_run_id = $1 ORDER BY id`
+	if _, err := db.Select(&dbFindings, query, nodeRunID); err != nil {
+		return nil, sdk.WrapError(err, "LoadStaticAnalysisFindings> Unable to load findings for node run %d", nodeRunID)
+	}
+
+	findings := make([]sdk.StaticAnalysisFinding, len(dbFindings))
+	for i := range dbFindings {
+		findings[i] = sdk.StaticAnalysisFinding(dbFindings[i])
+	}
+	return findings, nil
+}
+
+// loadPreviousFingerprints returns the fingerprints of the findings reported by the last node run
+// of the same workflow node, excluding the given node run. The previous node run is picked among the ended
+// node runs with an analysis, so that a previous analysis without any finding is taken into account.
+func loadPreviousFingerprints(db gorp.SqlExecutor, workflowID int64, nodeName string, nodeRunID int64) (map[string]bool, error) {
+	query := `SELECT workflow_node_run.id FROM workflow_node_run
+	JOIN workflow_node ON workflow_node.id = workflow_node_run.workflow_node_id
+	JOIN workflow_run ON workflow_run.id = workflow_node_run.workflow_run_id
+	WHERE workflow_run.workflow_id = $1 AND workflow_node.name = $2 AND workflow_node_run.id < $3
+	AND workflow_node_run.status IN ($4, $5, $6)
+	AND workflow_node_run.static_analysis IS NOT NULL
+	ORDER BY workflow_node_run.id DESC LIMIT 1`
+	previousID, err := db.SelectNullInt(query, workflowID, nodeName, nodeRunID,
+		sdk.StatusSuccess.String(), sdk.StatusFail.String(), sdk.StatusStopped.String())
+	if err != nil {
+		return nil, err
+	}
+	if !previousID.Valid {
+		return map[string]bool{}, nil
+	}
+
+	query = `SELECT fingerprint FROM workflow_node_run_finding WHERE workflow_node_run_id = $1`
+	var fingerprints []string
+	if _, err := db.Select(&fingerprints, query, previousID.Int64); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]bool, len(fingerprints))
+	for _, f := range fingerprints {
+		res[f] = true
+	}
+	return res, nil
+}
@@ -3,6 +3,7 @@ package workflow
 import (
 	"database/sql"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-gorp/gorp"
@@ -43,6 +44,7 @@ func UpdateNodeJobRunStatus(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, st
 		}
 		job.Done = time.Now()
 		job.Status = status.String()
+		deleteJobRunMasker(job.ID)
 	default:
 		return fmt.Errorf("workflow.UpdateNodeJobRunStatus> Cannot update WorkflowNodeJobRun %d to status %v", job.ID, status.String())
 	}
@@ -74,10 +76,12 @@ func UpdateNodeJobRunStatus(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, st
 	}
 
 	if stageUpdated {
+		oldStatus := node.Status
 		node.Status = sdk.StatusBuilding.String()
 		if err := UpdateNodeRun(db, node); err != nil {
 			return sdk.WrapError(err, "workflow.UpdateNodeJobRunStatus> Unable to update workflow node run %d", node.ID)
 		}
+		sendNotifications(db, nil, node, oldStatus)
 	} else {
 		if errE := execute(db, node); errE != nil {
 			return sdk.WrapError(errE, "workflow.UpdateNodeJobRunStatus> Cannot execute sync node")
@@ -187,7 +191,7 @@ func LoadNodeJobRunSecrets(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun) ([]
 	av := []sdk.Variable{}
 	if n.Context != nil && n.Context.Application != nil {
 		av = sdk.VariablesFilter(n.Context.Application.Variable, sdk.SecretVariable, sdk.KeyVariable)
-		av = sdk.VariablesPrefix(pv, "cds.app")
+		av = sdk.VariablesPrefix(av, "cds.app")
 	}
 	secrets = append(secrets, av...)
 
@@ -195,7 +199,7 @@ func LoadNodeJobRunSecrets(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun) ([]
 	ev := []sdk.Variable{}
 	if n.Context != nil && n.Context.Environment != nil {
 		ev = sdk.VariablesFilter(n.Context.Environment.Variable, sdk.SecretVariable, sdk.KeyVariable)
-		ev = sdk.VariablesPrefix(pv, "cds.env")
+		ev = sdk.VariablesPrefix(ev, "cds.env")
 	}
 	secrets = append(secrets, ev...)
 
@@ -222,24 +226,28 @@ func BookNodeJobRun(id int64, hatchery *sdk.Hatchery) (*sdk.Hatchery, error) {
 	return &h, sdk.WrapError(sdk.ErrJobAlreadyBooked, "BookNodeJobRun> job %d already booked by %s (%d)", id, h.Name, h.ID)
 }
 
-//AddLog adds a build log
+//AddLog adds a build log. Secrets of the job are masked, in case the worker did not
 func AddLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log) error {
 	if job != nil {
 		logs.PipelineBuildJobID = job.ID
 		logs.PipelineBuildID = job.WorkflowNodeRunID
 	}
 
+	masker := loadJobRunMasker(db, job, logs.PipelineBuildJobID)
+
 	existingLogs, errLog := LoadStepLogs(db, logs.PipelineBuildJobID, logs.StepOrder)
 	if errLog != nil && errLog != sql.ErrNoRows {
 		return sdk.WrapError(errLog, "AddLog> Cannot load existing logs")
 	}
 
 	if existingLogs == nil {
+		logs.Val = masker.Mask(logs.Val)
 		if err := insertLog(db, logs); err != nil {
 			return sdk.WrapError(err, "AddLog> Cannot insert log")
 		}
 	} else {
-		existingLogs.Val += logs.Val
+		// Only the new chunk is masked, with the end of the step logs as a secret may be split over several chunks
+		existingLogs.Val = masker.MaskAppend(existingLogs.Val, logs.Val)
 		existingLogs.LastModified = logs.LastModified
 		existingLogs.Done = logs.Done
 		if err := updateLog(db, existingLogs); err != nil {
@@ -248,3 +256,64 @@ func AddLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log) err
 	}
 	return nil
 }
+
+// jobRunMaskerTTL is the duration the secrets of a job run are kept to mask its logs. The secrets are deleted
+// when the job ends, the TTL bounds the secrets kept for the jobs ended on another API instance.
+const jobRunMaskerTTL = 10 * time.Minute
+
+type jobRunMasker struct {
+	masker *sdk.SecretMasker
+	loaded time.Time
+}
+
+// jobRunMaskers are the maskers of the job runs sending logs, so that the secrets are not loaded for each chunk
+var jobRunMaskers = struct {
+	sync.Mutex
+	m map[int64]jobRunMasker
+}{m: map[int64]jobRunMasker{}}
+
+// loadJobRunMasker returns the masker of the secrets of a job run. The secrets are loaded once per job run and kept
+// until the job ends, at most for jobRunMaskerTTL. If they cannot be loaded, the masker already known for the job
+// run is returned, which is nil if there is none.
+func loadJobRunMasker(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, jobID int64) *sdk.SecretMasker {
+	jobRunMaskers.Lock()
+	known, ok := jobRunMaskers.m[jobID]
+	jobRunMaskers.Unlock()
+	if ok && time.Since(known.loaded) < jobRunMaskerTTL {
+		return known.masker
+	}
+
+	if job == nil {
+		var errJ error
+		job, errJ = LoadNodeJobRun(db, jobID)
+		if errJ != nil {
+			log.Warning("loadJobRunMasker> Unable to load job %d, secrets may not be masked: %s", jobID, errJ)
+			return known.masker
+		}
+	}
+
+	secrets, errS := LoadNodeJobRunSecrets(db, job)
+	if errS != nil {
+		log.Warning("loadJobRunMasker> Unable to load secrets of job %d, secrets may not be masked: %s", jobID, errS)
+		return known.masker
+	}
+	masker := sdk.NewSecretMasker(secrets)
+
+	jobRunMaskers.Lock()
+	for id, m := range jobRunMaskers.m {
+		if time.Since(m.loaded) >= jobRunMaskerTTL {
+			delete(jobRunMaskers.m, id)
+		}
+	}
+	jobRunMaskers.m[jobID] = jobRunMasker{masker: masker, loaded: time.Now()}
+	jobRunMaskers.Unlock()
+
+	return masker
+}
+
+// deleteJobRunMasker deletes the masker of an ended job run
+func deleteJobRunMasker(jobID int64) {
+	jobRunMaskers.Lock()
+	delete(jobRunMaskers.m, jobID)
+	jobRunMaskers.Unlock()
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -64,6 +65,7 @@ func execute(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun) (err error) {
 		return nil
 	}
 
+	var oldStatus = n.Status
 	var newStatus = n.Status
 
 	//If no stages ==> success
@@ -71,12 +73,62 @@ func execute(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun) (err error) {
 		newStatus = sdk.StatusSuccess.String()
 	}
 
+	//Exclusive environment: check the lock is still held once the node run has started
+	envLock, lockPolicy, errLock := nodeRunLock(db, n)
+	if errLock != nil {
+		return errLock
+	}
+	var stop bool
+	if envLock != nil && len(n.Stages) > 0 && n.Stages[0].Status.String() != "" {
+		holds, err := environment.HoldsLock(db, envLock)
+		if err != nil {
+			return sdk.WrapError(err, "workflow.execute> Unable to check environment lock of node run %d", n.ID)
+		}
+		if !holds {
+			log.Info("workflow.execute> node run %d lost the lock on environment %d, stopping it", n.ID, envLock.EnvironmentID)
+			if err := stopNodeJobRuns(db, n); err != nil {
+				return err
+			}
+			stop = true
+		}
+	}
+
 	//Browse stages
 	for stageIndex := range n.Stages {
 		stage := &n.Stages[stageIndex]
 		log.Debug("workflow.execute> checking stage %s (status=%s)", stage.Name, stage.Status)
 		//Initialize stage status at waiting
 		if stage.Status.String() == "" {
+			if stageIndex == 0 {
+				approvalStatus, err := nodeRunApproval(db, n)
+				if err != nil {
+					return err
+				}
+				switch approvalStatus {
+				case sdk.StatusWaiting:
+					//Wait for the approvers
+					return nil
+				case sdk.StatusFail:
+					stop = true
+				}
+			}
+			if envLock != nil && stageIndex == 0 && !stop {
+				acquired, lockStop, err := acquireNodeRunLock(db, envLock, lockPolicy)
+				if err != nil {
+					return sdk.WrapError(err, "workflow.execute> Unable to acquire environment lock for node run %d", n.ID)
+				}
+				if !acquired && !lockStop {
+					//Wait for the environment to be released
+					return nil
+				}
+				stop = lockStop
+			}
+			if stop {
+				stage.Status = sdk.StatusFail
+				n.Done = time.Now()
+				newStatus = sdk.StatusFail.String()
+				break
+			}
 			if stageIndex == 0 {
 				newStatus = sdk.StatusWaiting.String()
 			}
@@ -131,6 +183,19 @@ func execute(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun) (err error) {
 		return sdk.WrapError(fmt.Errorf("Unable to update node id=%d at status %s", n.ID, n.Status), "workflow.execute> Unable to execute node")
 	}
 
+	//Keep the deployment ledger up to date
+	if n.Status != oldStatus {
+		if err := recordDeployment(db, n); err != nil {
+			return sdk.WrapError(err, "workflow.execute> Unable to record deployment of node run %d", n.ID)
+		}
+	}
+
+	if envLock != nil && n.Status != sdk.StatusWaiting.String() && n.Status != sdk.StatusBuilding.String() {
+		if err := environment.ReleaseLock(db, envLock); err != nil {
+			return sdk.WrapError(err, "workflow.execute> Unable to release environment lock of node run %d", n.ID)
+		}
+	}
+
 	//Reload the workflow
 	updatedWorkflowRun, err := loadRunByID(db, n.WorkflowRunID)
 	if err != nil {
@@ -152,6 +217,9 @@ func execute(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun) (err error) {
 		}
 	}
 
+	//Send the notifications of the workflow
+	sendNotifications(db, updatedWorkflowRun, n, oldStatus)
+
 	return nil
 }
 
@@ -171,6 +239,11 @@ func addJobsToQueue(db gorp.SqlExecutor, stage *sdk.Stage, run *sdk.WorkflowNode
 		stage.Status = sdk.StatusDisabled
 	}
 
+	priority, errPrio := jobRunPriority(db, run)
+	if errPrio != nil {
+		return errPrio
+	}
+
 	//Browse the jobs
 	for _, job := range stage.Jobs {
 		//Process variables for the jobs
@@ -183,6 +256,7 @@ func addJobsToQueue(db gorp.SqlExecutor, stage *sdk.Stage, run *sdk.WorkflowNode
 			Queued:            time.Now(),
 			Status:            sdk.StatusWaiting.String(),
 			Parameters:        jobParams,
+			Priority:          priority,
 			Job: sdk.ExecutedJob{
 				Job: job,
 			},
@@ -0,0 +1,60 @@
+package workflow
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/approval"
+	"github.com/ovh/cds/sdk"
+)
+
+// nodeRunApproval returns the status of the approval of the node run, StatusSuccess if its node has no approval gate.
+// The approval is created, and the approvers notified, the first time the node run is executed.
+func nodeRunApproval(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun) (sdk.Status, error) {
+	a, err := approval.LoadByWorkflowNodeRunID(db, n.ID)
+	if err != nil {
+		return sdk.StatusUnknown, sdk.WrapError(err, "workflow.nodeRunApproval> Unable to load approval of node run %d", n.ID)
+	}
+	if a != nil {
+		return approval.Check(db, a)
+	}
+
+	query := `SELECT workflow_node_context.approval, workflow.name, workflow_node.name, project.id, project.projectkey
+	FROM workflow_node_context
+	JOIN workflow_node ON workflow_node.id = workflow_node_context.workflow_node_id
+	JOIN workflow ON workflow.id = workflow_node.workflow_id
+	JOIN project ON project.id = workflow.project_id
+	WHERE workflow_node_context.workflow_node_id = $1`
+	var gate sql.NullString
+	var workflowName, projectKey string
+	var nodeName sql.NullString
+	var projectID int64
+	if err := db.QueryRow(query, n.WorkflowNodeID).Scan(&gate, &workflowName, &nodeName, &projectID, &projectKey); err != nil {
+		if err == sql.ErrNoRows {
+			return sdk.StatusSuccess, nil
+		}
+		return sdk.StatusUnknown, sdk.WrapError(err, "workflow.nodeRunApproval> Unable to load approval gate of node %d", n.WorkflowNodeID)
+	}
+	if !gate.Valid {
+		return sdk.StatusSuccess, nil
+	}
+
+	var g sdk.ApprovalGate
+	if err := json.Unmarshal([]byte(gate.String), &g); err != nil {
+		return sdk.StatusUnknown, sdk.WrapError(err, "workflow.nodeRunApproval> Unable to unmarshal approval gate of node %d", n.WorkflowNodeID)
+	}
+
+	description := fmt.Sprintf("%s/%s #%d.%d", projectKey, workflowName, n.Number, n.SubNumber)
+	if nodeName.String != "" {
+		description += " " + nodeName.String
+	}
+	a = sdk.NewApproval(g, projectID, description, sdk.ParameterValue(n.BuildParameters, "git.author"))
+	a.WorkflowNodeRunID = n.ID
+	if err := approval.Insert(db, a); err != nil {
+		return sdk.StatusUnknown, sdk.WrapError(err, "workflow.nodeRunApproval> Unable to insert approval of node run %d", n.ID)
+	}
+	return sdk.StatusWaiting, nil
+}
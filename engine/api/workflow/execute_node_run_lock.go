@@ -0,0 +1,165 @@
+package workflow
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func init() {
+	environment.RegisterNodeRunStopper(stopLockHolder)
+}
+
+// stopLockHolder stops the node run holding the lock l, when another run takes the lock from it.
+// If the node run is being executed, the lock is not taken and the other run will try again later
+func stopLockHolder(db gorp.SqlExecutor, l *sdk.EnvironmentLock) error {
+	n, err := LoadAndLockNodeRunByID(db, l.WorkflowNodeRunID)
+	if err != nil {
+		return sdk.WrapError(err, "workflow.stopLockHolder> Unable to load node run %d", l.WorkflowNodeRunID)
+	}
+	if n.Status != sdk.StatusWaiting.String() && n.Status != sdk.StatusBuilding.String() {
+		return nil
+	}
+	log.Info("workflow.stopLockHolder> node run %d lost the lock on environment %d, stopping it", n.ID, l.EnvironmentID)
+	return stopNodeRun(db, nil, n)
+}
+
+// nodeRunLock returns the environment lock the node run needs, nil if the environment of the node is not exclusive
+func nodeRunLock(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun) (*sdk.EnvironmentLock, string, error) {
+	query := `SELECT workflow_node_context.environment_id, workflow_node_context.application_id, application.name, workflow.name
+	FROM workflow_node_context
+	JOIN workflow_node ON workflow_node.id = workflow_node_context.workflow_node_id
+	JOIN workflow ON workflow.id = workflow_node.workflow_id
+	LEFT JOIN application ON application.id = workflow_node_context.application_id
+	WHERE workflow_node_context.workflow_node_id = $1`
+	var envID, appID sql.NullInt64
+	var appName sql.NullString
+	var workflowName string
+	if err := db.QueryRow(query, n.WorkflowNodeID).Scan(&envID, &appID, &appName, &workflowName); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, "", nil
+		}
+		return nil, "", sdk.WrapError(err, "nodeRunLock> Unable to load context of node %d", n.WorkflowNodeID)
+	}
+	if !envID.Valid || envID.Int64 == sdk.DefaultEnv.ID {
+		return nil, "", nil
+	}
+
+	env := &sdk.Environment{ID: envID.Int64}
+	if err := environment.LoadLockSettings(db, env); err != nil {
+		return nil, "", sdk.WrapError(err, "nodeRunLock> Unable to load lock settings of environment %d", env.ID)
+	}
+
+	key, need := env.LockKey(appID.Int64, appName.String)
+	if !need {
+		return nil, "", nil
+	}
+
+	holder := fmt.Sprintf("%s #%d.%d", workflowName, n.Number, n.SubNumber)
+	if n.Manual != nil && n.Manual.User.Username != "" {
+		holder += " by " + n.Manual.User.Username
+	}
+
+	return &sdk.EnvironmentLock{
+		EnvironmentID:     env.ID,
+		ApplicationID:     key,
+		WorkflowNodeRunID: n.ID,
+		Holder:            holder,
+	}, env.LockPolicy, nil
+}
+
+// acquireNodeRunLock applies the lock policy before the first stage of the node run is scheduled.
+// It returns true if the lock is acquired, and true as second value if the node run has to be stopped.
+func acquireNodeRunLock(db gorp.SqlExecutor, l *sdk.EnvironmentLock, policy string) (bool, bool, error) {
+	current, ok, err := environment.AcquireLock(db, l, policy == sdk.EnvironmentLockCancelOlder)
+	if err != nil {
+		return false, false, err
+	}
+	if ok {
+		return true, false, nil
+	}
+
+	if policy == sdk.EnvironmentLockCancelNewer {
+		log.Info("workflow.acquireNodeRunLock> node run %d stopped: environment %d locked by %s", l.WorkflowNodeRunID, l.EnvironmentID, current.Holder)
+		return false, true, nil
+	}
+	log.Debug("workflow.acquireNodeRunLock> node run %d waiting: environment %d locked by %s", l.WorkflowNodeRunID, l.EnvironmentID, current.Holder)
+	return false, false, nil
+}
+
+// stopNodeJobRuns stops all the waiting and building jobs of the node run
+func stopNodeJobRuns(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun) error {
+	for i := range n.Stages {
+		for _, rj := range n.Stages[i].RunJobs {
+			if rj.Status != sdk.StatusWaiting.String() && rj.Status != sdk.StatusBuilding.String() {
+				continue
+			}
+			j, err := LoadAndLockNodeJobRun(db, rj.ID)
+			if err != nil {
+				return sdk.WrapError(err, "workflow.stopNodeJobRuns> Unable to load job %d", rj.ID)
+			}
+			j.Status = sdk.StatusStopped.String()
+			j.Done = time.Now()
+			if err := UpdateNodeJobRun(db, j); err != nil {
+				return sdk.WrapError(err, "workflow.stopNodeJobRuns> Unable to stop job %d", rj.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// executePendingNodeRuns executes again the node runs waiting for an approval or for an environment lock
+func executePendingNodeRuns(db *gorp.DbMap) {
+	query := `SELECT workflow_node_run.id
+	FROM workflow_node_run
+	JOIN workflow_node_context ON workflow_node_context.workflow_node_id = workflow_node_run.workflow_node_id
+	LEFT JOIN environment ON environment.id = workflow_node_context.environment_id
+	WHERE workflow_node_run.status = $1
+	AND NOT EXISTS (SELECT 1 FROM workflow_node_run_job WHERE workflow_node_run_job.workflow_node_run_id = workflow_node_run.id)
+	AND (
+		EXISTS (SELECT 1 FROM approval WHERE approval.workflow_node_run_id = workflow_node_run.id)
+		OR environment.exclusive
+		OR EXISTS (
+			SELECT 1 FROM environment_exclusive_application
+			WHERE environment_exclusive_application.environment_id = environment.id
+			AND environment_exclusive_application.application_id = workflow_node_context.application_id
+		)
+	)`
+	var ids []int64
+	if _, err := db.Select(&ids, query, sdk.StatusWaiting.String()); err != nil {
+		log.Warning("workflow.executePendingNodeRuns> Unable to load node runs: %s", err)
+		return
+	}
+
+	for _, id := range ids {
+		if err := executePendingNodeRun(db, id); err != nil {
+			log.Debug("workflow.executePendingNodeRuns> Unable to execute node run %d: %s", id, err)
+		}
+	}
+}
+
+func executePendingNodeRun(db *gorp.DbMap, id int64) error {
+	tx, errtx := db.Begin()
+	if errtx != nil {
+		return errtx
+	}
+	defer tx.Rollback()
+
+	n, err := LoadAndLockNodeRunByID(tx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := loadAndLockRunByID(tx, n.WorkflowRunID); err != nil {
+		return err
+	}
+	if err := execute(tx, n); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
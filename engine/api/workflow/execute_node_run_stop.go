@@ -0,0 +1,97 @@
+package workflow
+
+import (
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// StopWorkflowRun stops all the waiting and building node runs of a workflow run
+func StopWorkflowRun(db gorp.SqlExecutor, wr *sdk.WorkflowRun, u *sdk.User) error {
+	run, err := loadAndLockRunByID(db, wr.ID)
+	if err != nil {
+		return sdk.WrapError(err, "StopWorkflowRun> Unable to lock workflow run %d", wr.ID)
+	}
+
+	var stopped bool
+	for _, runs := range run.WorkflowNodeRuns {
+		//Node runs are sorted by sub number desc
+		if len(runs) == 0 {
+			continue
+		}
+		n := &runs[0]
+		if n.Status != sdk.StatusWaiting.String() && n.Status != sdk.StatusBuilding.String() {
+			continue
+		}
+		if err := stopNodeRun(db, run, n); err != nil {
+			return sdk.WrapError(err, "StopWorkflowRun> Unable to stop node run %d", n.ID)
+		}
+		stopped = true
+	}
+
+	if !stopped {
+		return nil
+	}
+
+	AddWorkflowRunInfo(run, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowStopped.ID,
+		Args: []interface{}{u.Username},
+	})
+	if err := updateWorkflowRun(db, run); err != nil {
+		return sdk.WrapError(err, "StopWorkflowRun> Unable to update workflow run %d", run.ID)
+	}
+	*wr = *run
+	return nil
+}
+
+// stopNodeRun stops a node run and all its waiting and building jobs. wr is the workflow run of the node run,
+// nil if it has not been loaded
+func stopNodeRun(db gorp.SqlExecutor, wr *sdk.WorkflowRun, n *sdk.WorkflowNodeRun) error {
+	log.Debug("workflow.stopNodeRun> stopping node run %d", n.ID)
+	oldStatus := n.Status
+
+	if err := stopNodeJobRuns(db, n); err != nil {
+		return err
+	}
+
+	for i := range n.Stages {
+		s := &n.Stages[i]
+		for j := range s.RunJobs {
+			rj := &s.RunJobs[j]
+			if rj.Status == sdk.StatusWaiting.String() || rj.Status == sdk.StatusBuilding.String() {
+				rj.Status = sdk.StatusStopped.String()
+				rj.Done = time.Now()
+			}
+		}
+		if s.Status == sdk.StatusWaiting || s.Status == sdk.StatusBuilding {
+			s.Status = sdk.StatusStopped
+		}
+	}
+
+	n.Status = sdk.StatusStopped.String()
+	n.Done = time.Now()
+	if err := UpdateNodeRun(db, n); err != nil {
+		return sdk.WrapError(err, "workflow.stopNodeRun> Unable to update node run %d", n.ID)
+	}
+
+	envLock, _, err := nodeRunLock(db, n)
+	if err != nil {
+		return err
+	}
+	if envLock != nil {
+		if err := environment.ReleaseLock(db, envLock); err != nil {
+			return sdk.WrapError(err, "workflow.stopNodeRun> Unable to release environment lock of node run %d", n.ID)
+		}
+	}
+
+	if err := DeleteNodeJobRuns(db, n.ID); err != nil {
+		return sdk.WrapError(err, "workflow.stopNodeRun> Unable to delete node %d job runs", n.ID)
+	}
+
+	sendNotifications(db, wr, n, oldStatus)
+	return nil
+}
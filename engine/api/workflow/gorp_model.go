@@ -36,6 +36,15 @@ type JobRun sdk.WorkflowNodeJobRun
 // NodeRunArtifact is a gorp wrapper around sdk.WorkflowNodeRunArtifact
 type NodeRunArtifact sdk.WorkflowNodeRunArtifact
 
+// NodeRunFinding is a gorp wrapper around sdk.StaticAnalysisFinding
+type NodeRunFinding sdk.StaticAnalysisFinding
+
+// Deployment is a gorp wrapper around sdk.WorkflowDeployment
+type Deployment sdk.WorkflowDeployment
+
+// Notification is a gorp wrapper around sdk.WorkflowNotification
+type Notification sdk.WorkflowNotification
+
 func init() {
 	gorpmapping.Register(gorpmapping.New(Workflow{}, "workflow", true, "id"))
 	gorpmapping.Register(gorpmapping.New(Node{}, "workflow_node", true, "id"))
@@ -49,4 +58,7 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(sqlNodeRun{}, "workflow_node_run", true, "id"))
 	gorpmapping.Register(gorpmapping.New(JobRun{}, "workflow_node_run_job", true, "id"))
 	gorpmapping.Register(gorpmapping.New(NodeRunArtifact{}, "workflow_node_run_artifacts", true, "id"))
+	gorpmapping.Register(gorpmapping.New(NodeRunFinding{}, "workflow_node_run_finding", true, "id"))
+	gorpmapping.Register(gorpmapping.New(Deployment{}, "workflow_deployment", true, "id"))
+	gorpmapping.Register(gorpmapping.New(Notification{}, "workflow_notification", true, "id"))
 }
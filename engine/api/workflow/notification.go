@@ -0,0 +1,180 @@
+package workflow
+
+import (
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/notification"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// sendNotifications evaluates the notification rules of the workflow when a node run starts or ends.
+// Rules on nodes are evaluated on the node run, rules on the whole workflow are evaluated when the run starts
+// from its root node and when its last node run ends. wr is the workflow run of the node run when the caller
+// has already loaded it, it is loaded only if the status of the node run has to be notified otherwise.
+func sendNotifications(db gorp.SqlExecutor, wr *sdk.WorkflowRun, n *sdk.WorkflowNodeRun, oldStatus string) {
+	if n.Status == oldStatus {
+		return
+	}
+	status := sdk.StatusFromString(n.Status)
+	if status != sdk.StatusBuilding && status != sdk.StatusSuccess && status != sdk.StatusFail {
+		return
+	}
+
+	if wr == nil {
+		var err error
+		wr, err = loadRunByID(db, n.WorkflowRunID)
+		if err != nil {
+			log.Warning("sendNotifications> Unable to load workflow run %d: %s", n.WorkflowRunID, err)
+			return
+		}
+	}
+	if len(wr.Workflow.Notifications) == 0 {
+		return
+	}
+	//The workflow run may have been loaded before the node run was updated
+	runs := wr.WorkflowNodeRuns[n.WorkflowNodeID]
+	for i := range runs {
+		if runs[i].ID == n.ID {
+			runs[i] = *n
+		}
+	}
+
+	for _, wn := range wr.Workflow.Notifications {
+		var events []sdk.EventNotif
+		if len(wn.SourceNodeIDs) > 0 {
+			if !containsNodeID(wn.SourceNodeIDs, n.WorkflowNodeID) {
+				continue
+			}
+			previous, err := previousNodeRunStatus(db, wr, n)
+			if err != nil {
+				log.Warning("sendNotifications> Unable to load previous status of node %d: %s", n.WorkflowNodeID, err)
+				continue
+			}
+			events = notification.GetWorkflowEvents(db, wr, n, wn, status, previous)
+		} else {
+			runStatus, previous, ok := workflowRunStatus(db, wr, n, status)
+			if !ok {
+				continue
+			}
+			events = notification.GetWorkflowEvents(db, wr, n, wn, runStatus, previous)
+		}
+
+		for _, e := range events {
+			event.Publish(e)
+		}
+	}
+}
+
+// workflowRunStatus returns the status of the whole workflow run if the node run starts or ends it,
+// and the status of the previous workflow run
+func workflowRunStatus(db gorp.SqlExecutor, wr *sdk.WorkflowRun, n *sdk.WorkflowNodeRun, status sdk.Status) (sdk.Status, *sdk.Status, bool) {
+	if status == sdk.StatusBuilding {
+		if wr.Workflow.Root == nil || n.WorkflowNodeID != wr.Workflow.Root.ID || n.SubNumber != 0 {
+			return status, nil, false
+		}
+		return status, nil, true
+	}
+
+	runStatus, ended := endedRunStatus(wr)
+	if !ended {
+		return runStatus, nil, false
+	}
+
+	if wr.Number <= 1 {
+		return runStatus, nil, true
+	}
+	previousRun, err := LoadRun(db, wr.Workflow.ProjectKey, wr.Workflow.Name, wr.Number-1)
+	if err != nil {
+		log.Debug("workflowRunStatus> Unable to load previous run of %s/%s #%d: %s", wr.Workflow.ProjectKey, wr.Workflow.Name, wr.Number, err)
+		return runStatus, nil, true
+	}
+	previous, previousEnded := endedRunStatus(previousRun)
+	if !previousEnded {
+		return runStatus, nil, true
+	}
+	return runStatus, &previous, true
+}
+
+// endedRunStatus computes the status of a workflow run from the last run of each node, and returns false
+// if some nodes are still running
+func endedRunStatus(wr *sdk.WorkflowRun) (sdk.Status, bool) {
+	status := sdk.StatusSuccess
+	for _, runs := range wr.WorkflowNodeRuns {
+		last := lastNodeRun(runs)
+		if last == nil {
+			continue
+		}
+		switch sdk.StatusFromString(last.Status) {
+		case sdk.StatusWaiting, sdk.StatusBuilding:
+			return sdk.StatusBuilding, false
+		case sdk.StatusFail, sdk.StatusStopped:
+			status = sdk.StatusFail
+		}
+	}
+	return status, true
+}
+
+// lastNodeRun returns the run of a node with the highest sub number. Loaded node runs are sorted by sub number desc,
+// but the node runs processed since the workflow run has been loaded are appended.
+func lastNodeRun(runs []sdk.WorkflowNodeRun) *sdk.WorkflowNodeRun {
+	var last *sdk.WorkflowNodeRun
+	for i := range runs {
+		if last == nil || runs[i].SubNumber > last.SubNumber {
+			last = &runs[i]
+		}
+	}
+	return last
+}
+
+// previousNodeRunStatus returns the status of the previous ended run of the node, nil if there is none.
+// The previous runs of the node in the same workflow run are looked up first, the previous workflow runs are
+// loaded only if there is none.
+func previousNodeRunStatus(db gorp.SqlExecutor, wr *sdk.WorkflowRun, n *sdk.WorkflowNodeRun) (*sdk.Status, error) {
+	var previous *sdk.WorkflowNodeRun
+	runs := wr.WorkflowNodeRuns[n.WorkflowNodeID]
+	for i := range runs {
+		r := &runs[i]
+		if r.SubNumber >= n.SubNumber || (r.Status != sdk.StatusSuccess.String() && r.Status != sdk.StatusFail.String()) {
+			continue
+		}
+		if previous == nil || r.SubNumber > previous.SubNumber {
+			previous = r
+		}
+	}
+	if previous != nil {
+		status := sdk.StatusFromString(previous.Status)
+		return &status, nil
+	}
+	if n.Number <= 1 {
+		return nil, nil
+	}
+
+	query := `select status from workflow_node_run
+	where workflow_node_id = $1
+	and num < $2
+	and status in ($3, $4)
+	order by num desc, sub_num desc
+	limit 1`
+	var s string
+	if err := db.QueryRow(query, n.WorkflowNodeID, n.Number, sdk.StatusSuccess.String(), sdk.StatusFail.String()).Scan(&s); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	status := sdk.StatusFromString(s)
+	return &status, nil
+}
+
+func containsNodeID(ids []int64, id int64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,261 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func nodeRun(id, nodeID int64, subNumber int64, status sdk.Status) sdk.WorkflowNodeRun {
+	return sdk.WorkflowNodeRun{ID: id, WorkflowRunID: 1, WorkflowNodeID: nodeID, Number: 1, SubNumber: subNumber, Status: status.String()}
+}
+
+func notificationWorkflowRun(notifs []sdk.WorkflowNotification, runs ...sdk.WorkflowNodeRun) *sdk.WorkflowRun {
+	wr := &sdk.WorkflowRun{
+		ID:     1,
+		Number: 1,
+		Workflow: sdk.Workflow{
+			Name:       "w",
+			ProjectKey: "KEY",
+			Root: &sdk.WorkflowNode{
+				ID:       1,
+				Name:     "root",
+				Triggers: []sdk.WorkflowNodeTrigger{{WorkflowDestNode: sdk.WorkflowNode{ID: 2, Name: "child"}}},
+			},
+			Notifications: notifs,
+		},
+		WorkflowNodeRuns: map[int64][]sdk.WorkflowNodeRun{},
+	}
+	for _, r := range runs {
+		wr.WorkflowNodeRuns[r.WorkflowNodeID] = append(wr.WorkflowNodeRuns[r.WorkflowNodeID], r)
+	}
+	return wr
+}
+
+func TestEndedRunStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		runs   []sdk.WorkflowNodeRun
+		status sdk.Status
+		ended  bool
+	}{
+		{"success", []sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusSuccess), nodeRun(2, 2, 0, sdk.StatusSuccess)}, sdk.StatusSuccess, true},
+		{"fail", []sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusSuccess), nodeRun(2, 2, 0, sdk.StatusFail)}, sdk.StatusFail, true},
+		{"stopped", []sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusSuccess), nodeRun(2, 2, 0, sdk.StatusStopped)}, sdk.StatusFail, true},
+		{"building", []sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusFail), nodeRun(2, 2, 0, sdk.StatusBuilding)}, sdk.StatusBuilding, false},
+		{"waiting", []sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusSuccess), nodeRun(2, 2, 0, sdk.StatusWaiting)}, sdk.StatusBuilding, false},
+		{"rerun succeeded", []sdk.WorkflowNodeRun{nodeRun(2, 1, 1, sdk.StatusSuccess), nodeRun(1, 1, 0, sdk.StatusFail)}, sdk.StatusSuccess, true},
+		{"appended rerun", []sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusFail), nodeRun(2, 1, 1, sdk.StatusWaiting)}, sdk.StatusBuilding, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, ended := endedRunStatus(notificationWorkflowRun(nil, tt.runs...))
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.ended, ended)
+		})
+	}
+}
+
+func TestWorkflowRunStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		runs   []sdk.WorkflowNodeRun
+		n      sdk.WorkflowNodeRun
+		status sdk.Status
+		ok     bool
+	}{
+		{"root starts", []sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusBuilding)}, nodeRun(1, 1, 0, sdk.StatusBuilding), sdk.StatusBuilding, true},
+		{"root rerun starts", []sdk.WorkflowNodeRun{nodeRun(2, 1, 1, sdk.StatusBuilding)}, nodeRun(2, 1, 1, sdk.StatusBuilding), sdk.StatusBuilding, false},
+		{"child starts", []sdk.WorkflowNodeRun{nodeRun(2, 2, 0, sdk.StatusBuilding)}, nodeRun(2, 2, 0, sdk.StatusBuilding), sdk.StatusBuilding, false},
+		{"child ends while root builds", []sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusBuilding), nodeRun(2, 2, 0, sdk.StatusSuccess)}, nodeRun(2, 2, 0, sdk.StatusSuccess), sdk.StatusBuilding, false},
+		{"last node ends", []sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusSuccess), nodeRun(2, 2, 0, sdk.StatusFail)}, nodeRun(2, 2, 0, sdk.StatusFail), sdk.StatusFail, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := tt.n
+			status, previous, ok := workflowRunStatus(nil, notificationWorkflowRun(nil, tt.runs...), &n, sdk.StatusFromString(n.Status))
+			assert.Equal(t, tt.ok, ok)
+			if ok {
+				assert.Equal(t, tt.status, status)
+			}
+			assert.Nil(t, previous, "the first workflow run has no previous status")
+		})
+	}
+}
+
+func TestPreviousNodeRunStatusInRun(t *testing.T) {
+	success, fail := sdk.StatusSuccess, sdk.StatusFail
+	tests := []struct {
+		name     string
+		runs     []sdk.WorkflowNodeRun
+		n        sdk.WorkflowNodeRun
+		previous *sdk.Status
+	}{
+		{"first run", nil, nodeRun(1, 1, 0, sdk.StatusSuccess), nil},
+		{"previous sub run", []sdk.WorkflowNodeRun{nodeRun(2, 1, 1, sdk.StatusSuccess), nodeRun(1, 1, 0, sdk.StatusFail)}, nodeRun(3, 1, 2, sdk.StatusSuccess), &success},
+		{"appended sub run", []sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusSuccess), nodeRun(2, 1, 1, sdk.StatusFail)}, nodeRun(3, 1, 2, sdk.StatusSuccess), &fail},
+		{"not ended sub run", []sdk.WorkflowNodeRun{nodeRun(2, 1, 1, sdk.StatusBuilding), nodeRun(1, 1, 0, sdk.StatusFail)}, nodeRun(3, 1, 2, sdk.StatusSuccess), &fail},
+		{"next sub run", []sdk.WorkflowNodeRun{nodeRun(2, 1, 1, sdk.StatusSuccess)}, nodeRun(1, 1, 0, sdk.StatusFail), nil},
+		{"other node", []sdk.WorkflowNodeRun{nodeRun(1, 2, 0, sdk.StatusSuccess)}, nodeRun(2, 1, 1, sdk.StatusSuccess), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := tt.n
+			previous, err := previousNodeRunStatus(nil, notificationWorkflowRun(nil, tt.runs...), &n)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.previous, previous)
+		})
+	}
+}
+
+func TestSendNotifications(t *testing.T) {
+	cache.Initialize("local", "", "", 30)
+
+	jabber := func(onSuccess, onFailure sdk.UserNotificationEventType, onStart bool, sources ...int64) sdk.WorkflowNotification {
+		return sdk.WorkflowNotification{
+			Type:          sdk.JabberUserNotification,
+			SourceNodeIDs: sources,
+			Settings: &sdk.JabberEmailUserNotificationSettings{
+				OnSuccess:  onSuccess,
+				OnFailure:  onFailure,
+				OnStart:    onStart,
+				Recipients: []string{"john"},
+				Template:   sdk.UserNotificationTemplate{Subject: "{{.cds.status}}"},
+			},
+		}
+	}
+	always, change, never := sdk.UserNotificationAlways, sdk.UserNotificationChange, sdk.UserNotificationNever
+
+	tests := []struct {
+		name      string
+		notifs    []sdk.WorkflowNotification
+		runs      []sdk.WorkflowNodeRun
+		n         sdk.WorkflowNodeRun
+		oldStatus sdk.Status
+		subjects  []string
+	}{
+		{"same status", []sdk.WorkflowNotification{jabber(always, always, true, 1)},
+			[]sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusBuilding)}, nodeRun(1, 1, 0, sdk.StatusBuilding), sdk.StatusBuilding, nil},
+		{"waiting", []sdk.WorkflowNotification{jabber(always, always, true, 1)},
+			[]sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusWaiting)}, nodeRun(1, 1, 0, sdk.StatusWaiting), "", nil},
+		{"node start", []sdk.WorkflowNotification{jabber(always, always, true, 1)},
+			[]sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusBuilding)}, nodeRun(1, 1, 0, sdk.StatusBuilding), sdk.StatusWaiting, []string{"Building"}},
+		{"other node", []sdk.WorkflowNotification{jabber(always, always, true, 2)},
+			[]sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusSuccess)}, nodeRun(1, 1, 0, sdk.StatusSuccess), sdk.StatusBuilding, nil},
+		{"node success never", []sdk.WorkflowNotification{jabber(never, always, false, 1)},
+			[]sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusSuccess)}, nodeRun(1, 1, 0, sdk.StatusSuccess), sdk.StatusBuilding, nil},
+		{"node success after success", []sdk.WorkflowNotification{jabber(change, change, false, 1)},
+			[]sdk.WorkflowNodeRun{nodeRun(2, 1, 1, sdk.StatusSuccess), nodeRun(1, 1, 0, sdk.StatusSuccess)}, nodeRun(2, 1, 1, sdk.StatusSuccess), sdk.StatusBuilding, nil},
+		{"node success after failure", []sdk.WorkflowNotification{jabber(change, change, false, 1)},
+			[]sdk.WorkflowNodeRun{nodeRun(2, 1, 1, sdk.StatusSuccess), nodeRun(1, 1, 0, sdk.StatusFail)}, nodeRun(2, 1, 1, sdk.StatusSuccess), sdk.StatusBuilding, []string{"Success"}},
+		{"node failure after failure", []sdk.WorkflowNotification{jabber(change, change, false, 1)},
+			[]sdk.WorkflowNodeRun{nodeRun(2, 1, 1, sdk.StatusFail), nodeRun(1, 1, 0, sdk.StatusFail)}, nodeRun(2, 1, 1, sdk.StatusFail), sdk.StatusBuilding, nil},
+		{"first node failure on change", []sdk.WorkflowNotification{jabber(change, change, false, 1)},
+			[]sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusFail)}, nodeRun(1, 1, 0, sdk.StatusFail), sdk.StatusBuilding, []string{"Fail"}},
+		{"workflow start", []sdk.WorkflowNotification{jabber(always, always, true)},
+			[]sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusBuilding)}, nodeRun(1, 1, 0, sdk.StatusBuilding), sdk.StatusWaiting, []string{"Building"}},
+		{"workflow not ended", []sdk.WorkflowNotification{jabber(always, always, false)},
+			[]sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusSuccess), nodeRun(2, 2, 0, sdk.StatusBuilding)}, nodeRun(1, 1, 0, sdk.StatusSuccess), sdk.StatusBuilding, nil},
+		{"workflow ended", []sdk.WorkflowNotification{jabber(always, always, false)},
+			[]sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusSuccess), nodeRun(2, 2, 0, sdk.StatusFail)}, nodeRun(2, 2, 0, sdk.StatusFail), sdk.StatusBuilding, []string{"Fail"}},
+		{"workflow run loaded before the update", []sdk.WorkflowNotification{jabber(always, always, false)},
+			[]sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusSuccess), nodeRun(2, 2, 0, sdk.StatusBuilding)}, nodeRun(2, 2, 0, sdk.StatusSuccess), sdk.StatusBuilding, []string{"Success"}},
+		{"node and workflow rules", []sdk.WorkflowNotification{jabber(always, always, false, 2), jabber(always, always, false)},
+			[]sdk.WorkflowNodeRun{nodeRun(1, 1, 0, sdk.StatusSuccess), nodeRun(2, 2, 0, sdk.StatusSuccess)}, nodeRun(2, 2, 0, sdk.StatusSuccess), sdk.StatusBuilding, []string{"Success", "Success"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for cache.QueueLen("events") > 0 {
+				cache.Dequeue("events", &sdk.Event{})
+			}
+
+			n := tt.n
+			sendNotifications(nil, notificationWorkflowRun(tt.notifs, tt.runs...), &n, tt.oldStatus.String())
+
+			subjects := []string{}
+			for cache.QueueLen("events") > 0 {
+				e := sdk.Event{}
+				cache.Dequeue("events", &e)
+				subjects = append(subjects, e.Payload["Subject"].(string))
+			}
+			if tt.subjects == nil {
+				tt.subjects = []string{}
+			}
+			assert.Equal(t, tt.subjects, subjects)
+		})
+	}
+}
+
+func TestPreviousNodeRunStatus(t *testing.T) {
+	db := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u, _ := assets.InsertAdminUser(db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, key, key, u)
+
+	pip := sdk.Pipeline{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "pip1",
+		Type:       sdk.BuildPipeline,
+	}
+	test.NoError(t, pipeline.InsertPipeline(db, proj, &pip, u))
+
+	w := sdk.Workflow{
+		Name:       "test_notification",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Root: &sdk.WorkflowNode{
+			Pipeline: pip,
+		},
+	}
+	test.NoError(t, Insert(db, &w, u))
+	w1, err := Load(db, key, "test_notification", u)
+	test.NoError(t, err)
+
+	//Runs the workflow and sets the status of its root node run
+	run := func(status sdk.Status) (*sdk.WorkflowRun, *sdk.WorkflowNodeRun) {
+		wr, err := ManualRun(db, w1, &sdk.WorkflowNodeRunManual{User: *u})
+		test.NoError(t, err)
+		wr, err = LoadRun(db, key, w1.Name, wr.Number)
+		test.NoError(t, err)
+		n := &wr.WorkflowNodeRuns[w1.RootID][0]
+		n.Status = status.String()
+		test.NoError(t, UpdateNodeRun(db, n))
+		return wr, n
+	}
+
+	wr1, n1 := run(sdk.StatusFail)
+	previous, err := previousNodeRunStatus(db, wr1, n1)
+	test.NoError(t, err)
+	assert.Nil(t, previous)
+
+	wr2, n2 := run(sdk.StatusBuilding)
+	previous, err = previousNodeRunStatus(db, wr2, n2)
+	test.NoError(t, err)
+	if assert.NotNil(t, previous) {
+		assert.Equal(t, sdk.StatusFail, *previous)
+	}
+
+	//The run #2 is not ended, the run #1 is the previous one
+	wr3, n3 := run(sdk.StatusSuccess)
+	previous, err = previousNodeRunStatus(db, wr3, n3)
+	test.NoError(t, err)
+	if assert.NotNil(t, previous) {
+		assert.Equal(t, sdk.StatusFail, *previous)
+	}
+
+	n2.Status = sdk.StatusSuccess.String()
+	test.NoError(t, UpdateNodeRun(db, n2))
+	previous, err = previousNodeRunStatus(db, wr3, n3)
+	test.NoError(t, err)
+	if assert.NotNil(t, previous) {
+		assert.Equal(t, sdk.StatusSuccess, *previous)
+	}
+}
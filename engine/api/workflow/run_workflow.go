@@ -19,6 +19,10 @@ func ManualRunFromNode(db gorp.SqlExecutor, w *sdk.Workflow, number int64, e *sd
 	if err != nil {
 		return nil, sdk.WrapError(err, "ManualRunFromNode> Unable to load last run")
 	}
+	// The priority applies to the nodes triggered by the node
+	if e.Priority != nil {
+		lastWorkflowRun.Priority = e.Priority
+	}
 
 	if err := processWorkflowRun(db, lastWorkflowRun, nil, e, &nodeID); err != nil {
 		return nil, sdk.WrapError(err, "ManualRunFromNode> Unable to process workflow run")
@@ -53,6 +57,7 @@ func ManualRun(db gorp.SqlExecutor, w *sdk.Workflow, e *sdk.WorkflowNodeRunManua
 		Start:        time.Now(),
 		LastModified: time.Now(),
 		ProjectID:    w.ProjectID,
+		Priority:     e.Priority,
 	}
 
 	if err := insertWorkflowRun(db, wr); err != nil {
@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-gorp/gorp"
 	"github.com/ovh/cds/engine/api/cache"
@@ -31,6 +32,9 @@ func dequeueWorkflows(c context.Context) {
 //Scheduler schedules workflow_node_run
 func Scheduler(c context.Context, DBFunc func() *gorp.DbMap) error {
 	go dequeueWorkflows(c)
+	// Node runs waiting for an approval or an environment lock are executed again periodically
+	tick := time.NewTicker(5 * time.Second)
+	defer tick.Stop()
 	for {
 		select {
 		case <-c.Done():
@@ -44,6 +48,8 @@ func Scheduler(c context.Context, DBFunc func() *gorp.DbMap) error {
 			if err := lockAndExecute(db, n); err != nil {
 				log.Error("Error workflow.Scheduler executing node: %s", err)
 			}
+		case <-tick.C:
+			executePendingNodeRuns(DBFunc())
 		}
 	}
 }
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+const defaultDeploymentsLimit = 50
+
+// getDeploymentsHandler returns the deployments of all the projects the user can read,
+// filtered on the query parameters project, environment and application
+func getDeploymentsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	limit, errL := deploymentsLimit(r)
+	if errL != nil {
+		return errL
+	}
+
+	projs, errP := project.LoadAll(db, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "getDeploymentsHandler> Cannot load projects")
+	}
+
+	projectKey := r.FormValue("project")
+	ids := []int64{}
+	for _, p := range projs {
+		if projectKey == "" || p.Key == projectKey {
+			ids = append(ids, p.ID)
+		}
+	}
+
+	deployments, errD := workflow.LoadDeployments(db, ids, r.FormValue("environment"), r.FormValue("application"), limit)
+	if errD != nil {
+		return sdk.WrapError(errD, "getDeploymentsHandler> Cannot load deployments")
+	}
+
+	return WriteJSON(w, r, deployments, http.StatusOK)
+}
+
+func getProjectDeploymentsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+
+	limit, errL := deploymentsLimit(r)
+	if errL != nil {
+		return errL
+	}
+
+	p, errP := project.Load(db, key, c.User)
+	if errP != nil {
+		return sdk.WrapError(errP, "getProjectDeploymentsHandler> Cannot load project")
+	}
+
+	deployments, errD := workflow.LoadDeployments(db, []int64{p.ID}, r.FormValue("environment"), r.FormValue("application"), limit)
+	if errD != nil {
+		return sdk.WrapError(errD, "getProjectDeploymentsHandler> Cannot load deployments")
+	}
+
+	return WriteJSON(w, r, deployments, http.StatusOK)
+}
+
+func postWorkflowDeploymentRollbackHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+	name := vars["workflowName"]
+
+	id, errID := requestVarInt(r, "id")
+	if errID != nil {
+		return sdk.WrapError(errID, "postWorkflowDeploymentRollbackHandler> Invalid deployment ID")
+	}
+
+	tx, errB := db.Begin()
+	if errB != nil {
+		return sdk.WrapError(errB, "postWorkflowDeploymentRollbackHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	wf, errW := workflow.Load(tx, key, name, c.User)
+	if errW != nil {
+		return sdk.WrapError(errW, "postWorkflowDeploymentRollbackHandler> Unable to load workflow")
+	}
+
+	d, errD := workflow.LoadDeploymentByID(tx, wf.ProjectID, id)
+	if errD != nil {
+		return sdk.WrapError(errD, "postWorkflowDeploymentRollbackHandler> Unable to load deployment %d", id)
+	}
+	if d.WorkflowID != wf.ID {
+		return sdk.WrapError(sdk.ErrWorkflowDeploymentNotFound, "postWorkflowDeploymentRollbackHandler> Deployment %d is not a deployment of workflow %s", id, name)
+	}
+
+	wr, errR := workflow.RollbackDeployment(tx, wf, d, c.User)
+	if errR != nil {
+		return sdk.WrapError(errR, "postWorkflowDeploymentRollbackHandler> Unable to rollback deployment %d", id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "postWorkflowDeploymentRollbackHandler> Cannot commit transaction")
+	}
+
+	wr.Translate(r.Header.Get("Accept-Language"))
+	return WriteJSON(w, r, wr, http.StatusOK)
+}
+
+func deploymentsLimit(r *http.Request) (int, error) {
+	limitS := r.FormValue("limit")
+	if limitS == "" {
+		return defaultDeploymentsLimit, nil
+	}
+	limit, err := strconv.Atoi(limitS)
+	if err != nil || limit <= 0 {
+		return 0, sdk.ErrWrongRequest
+	}
+	return limit, nil
+}
@@ -15,7 +15,10 @@ import (
 
 	"github.com/ovh/cds/engine/api/artifact"
 	"github.com/ovh/cds/engine/api/businesscontext"
+	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/queue"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
@@ -69,6 +72,15 @@ func postTakeWorkflowJobHandler(w http.ResponseWriter, r *http.Request, db *gorp
 		workerModel = wm.Name
 	}
 
+	//Check concurrency quotas of the groups of the project
+	projects, errP := workflow.LoadNodeJobRunProjectIDs(tx, []int64{id})
+	if errP != nil {
+		return sdk.WrapError(errP, "postTakeWorkflowJobHandler> Cannot load project of job %d", id)
+	}
+	if err := checkConcurrencyQuotas(tx, projects[id], id); err != nil {
+		return sdk.WrapError(err, "postTakeWorkflowJobHandler> Cannot take job %d", id)
+	}
+
 	//Prepare spawn infos
 	infos := []sdk.SpawnInfo{{
 		RemoteTime: takeForm.Time,
@@ -129,9 +141,27 @@ func postBookWorkflowJobHandler(w http.ResponseWriter, r *http.Request, db *gorp
 		return sdk.WrapError(errc, "postBookWorkflowJobHandler> invalid id")
 	}
 
+	tx, errBegin := db.Begin()
+	if errBegin != nil {
+		return sdk.WrapError(errBegin, "postBookWorkflowJobHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	projects, errP := workflow.LoadNodeJobRunProjectIDs(tx, []int64{id})
+	if errP != nil {
+		return sdk.WrapError(errP, "postBookWorkflowJobHandler> Cannot load project of job %d", id)
+	}
+	if err := checkConcurrencyQuotas(tx, projects[id], id); err != nil {
+		return sdk.WrapError(err, "postBookWorkflowJobHandler> Cannot book job %d", id)
+	}
+
 	if _, err := workflow.BookNodeJobRun(id, c.Hatchery); err != nil {
 		return sdk.WrapError(err, "postBookWorkflowJobHandler> job already booked")
 	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "postBookWorkflowJobHandler> Cannot commit transaction")
+	}
 	return WriteJSON(w, r, nil, http.StatusOK)
 }
 
@@ -313,6 +343,13 @@ func getWorkflowJobQueueHandler(w http.ResponseWriter, r *http.Request, db *gorp
 		return sdk.WrapError(err, "getWorkflowJobQueueHandler> Unable to load queue")
 	}
 
+	// Hatcheries and workers only get jobs within concurrency quotas
+	applyQuotas := c.Agent == sdk.HatcheryAgent || c.Agent == sdk.WorkerAgent
+	jobs, err = sortNodeJobRuns(db, jobs, applyQuotas)
+	if err != nil {
+		return sdk.WrapError(err, "getWorkflowJobQueueHandler> Unable to sort queue")
+	}
+
 	return WriteJSON(w, r, jobs, http.StatusOK)
 }
 
@@ -383,6 +420,50 @@ func postWorkflowJobTestsResultsHandler(w http.ResponseWriter, r *http.Request,
 	return nil
 }
 
+func postWorkflowJobStaticAnalysisHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	id, errI := requestVarInt(r, "permID")
+	if errI != nil {
+		return sdk.WrapError(errI, "postWorkflowJobStaticAnalysisHandler> Invalid node job run ID")
+	}
+
+	var findings []sdk.StaticAnalysisFinding
+	if err := UnmarshalBody(r, &findings); err != nil {
+		return sdk.WrapError(err, "postWorkflowJobStaticAnalysisHandler> cannot unmarshal request")
+	}
+
+	for _, f := range findings {
+		if !sdk.IsValidSeverity(f.Severity) {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postWorkflowJobStaticAnalysisHandler> Invalid severity %s", f.Severity)
+		}
+	}
+
+	nodeRunJob, errJobRun := workflow.LoadNodeJobRun(db, id)
+	if errJobRun != nil {
+		return sdk.WrapError(errJobRun, "postWorkflowJobStaticAnalysisHandler> Cannot load node run job")
+	}
+
+	tx, errB := db.Begin()
+	if errB != nil {
+		return sdk.WrapError(errB, "postWorkflowJobStaticAnalysisHandler> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	nodeRun, errN := workflow.LoadAndLockNodeRunByID(tx, nodeRunJob.WorkflowNodeRunID)
+	if errN != nil {
+		return sdk.WrapError(errN, "postWorkflowJobStaticAnalysisHandler> Cannot load node run")
+	}
+
+	if err := workflow.InsertStaticAnalysisFindings(tx, nodeRun, findings); err != nil {
+		return sdk.WrapError(err, "postWorkflowJobStaticAnalysisHandler> Cannot insert findings")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "postWorkflowJobStaticAnalysisHandler> Cannot commit transaction")
+	}
+
+	return WriteJSON(w, r, nodeRun.StaticAnalysis, http.StatusOK)
+}
+
 func postWorkflowJobVariableHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
 	id, errr := requestVarInt(r, "permID")
 	if errr != nil {
@@ -455,7 +536,7 @@ func postWorkflowJobArtifactHandler(w http.ResponseWriter, r *http.Request, db *
 	//get a ref to the parsed multipart form
 	m := r.MultipartForm
 
-	var sizeStr, permStr, md5sum string
+	var sizeStr, permStr, md5sum, sha256sum string
 	if len(m.Value["size"]) > 0 {
 		sizeStr = m.Value["size"][0]
 	}
@@ -465,6 +546,9 @@ func postWorkflowJobArtifactHandler(w http.ResponseWriter, r *http.Request, db *
 	if len(m.Value["md5sum"]) > 0 {
 		md5sum = m.Value["md5sum"][0]
 	}
+	if len(m.Value["sha256sum"]) > 0 {
+		sha256sum = m.Value["sha256sum"][0]
+	}
 
 	if fileName == "" {
 		log.Warning("uploadArtifactHandler> %s header is not set", "Content-Disposition")
@@ -504,6 +588,7 @@ func postWorkflowJobArtifactHandler(w http.ResponseWriter, r *http.Request, db *
 		Size:              size,
 		Perm:              uint32(perm),
 		MD5sum:            md5sum,
+		SHA256Sum:         sha256sum,
 		WorkflowNodeRunID: nodeRun.ID,
 		WorkflowID:        nodeRun.WorkflowRunID,
 		Created:           time.Now(),
@@ -518,6 +603,7 @@ func postWorkflowJobArtifactHandler(w http.ResponseWriter, r *http.Request, db *
 		}
 
 		if err := artifact.SaveWorkflowFile(&art, file); err != nil {
+			file.Close()
 			return sdk.WrapError(err, "postWorkflowJobArtifactHandler> Cannot save artifact in store")
 		}
 		file.Close()
@@ -530,3 +616,225 @@ func postWorkflowJobArtifactHandler(w http.ResponseWriter, r *http.Request, db *
 	}
 	return nil
 }
+
+// loadWorkflowJobArtifactUpload loads the chunked upload and builds the artifact being uploaded
+func loadWorkflowJobArtifactUpload(db gorp.SqlExecutor, r *http.Request) (*sdk.ArtifactChunkedUpload, *sdk.WorkflowNodeRunArtifact, error) {
+	id, errI := requestVarInt(r, "permID")
+	if errI != nil {
+		return nil, nil, sdk.WrapError(sdk.ErrInvalidID, "loadWorkflowJobArtifactUpload> Invalid node job run ID")
+	}
+	vars := mux.Vars(r)
+
+	up, errU := artifact.LoadWorkflowUpload(id, vars["ref"])
+	if errU != nil {
+		return nil, nil, sdk.WrapError(errU, "loadWorkflowJobArtifactUpload> Cannot load upload %s", vars["ref"])
+	}
+	if up.Tag != vars["tag"] {
+		return nil, nil, sdk.WrapError(sdk.ErrArtifactUploadNotFound, "loadWorkflowJobArtifactUpload> Upload %s is not for tag %s", vars["ref"], vars["tag"])
+	}
+
+	nodeJobRun, errJ := workflow.LoadNodeJobRun(db, id)
+	if errJ != nil {
+		return nil, nil, sdk.WrapError(errJ, "loadWorkflowJobArtifactUpload> Cannot load node job run")
+	}
+
+	nodeRun, errR := workflow.LoadNodeRunByID(db, nodeJobRun.WorkflowNodeRunID)
+	if errR != nil {
+		return nil, nil, sdk.WrapError(errR, "loadWorkflowJobArtifactUpload> Cannot load node run")
+	}
+
+	art := &sdk.WorkflowNodeRunArtifact{
+		Name:              up.Name,
+		Tag:               up.Tag,
+		Size:              up.Size,
+		Perm:              up.Perm,
+		MD5sum:            up.MD5sum,
+		SHA256Sum:         up.SHA256Sum,
+		WorkflowNodeRunID: nodeRun.ID,
+		WorkflowID:        nodeRun.WorkflowRunID,
+	}
+	return up, art, nil
+}
+
+func postWorkflowJobArtifactUploadHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	id, errI := requestVarInt(r, "permID")
+	if errI != nil {
+		return sdk.WrapError(sdk.ErrInvalidID, "postWorkflowJobArtifactUploadHandler> Invalid node job run ID")
+	}
+
+	var up sdk.ArtifactChunkedUpload
+	if err := UnmarshalBody(r, &up); err != nil {
+		return sdk.WrapError(err, "postWorkflowJobArtifactUploadHandler> Cannot unmarshal request")
+	}
+	up.Tag = mux.Vars(r)["tag"]
+
+	if up.Name == "" || up.SHA256Sum == "" {
+		return sdk.WrapError(sdk.ErrWrongRequest, "postWorkflowJobArtifactUploadHandler> name and sha256sum are mandatory")
+	}
+
+	if _, err := workflow.LoadNodeJobRun(db, id); err != nil {
+		return sdk.WrapError(err, "postWorkflowJobArtifactUploadHandler> Cannot load node job run")
+	}
+
+	artifact.StartWorkflowUpload(id, &up)
+	return WriteJSON(w, r, up, http.StatusOK)
+}
+
+func putWorkflowJobArtifactChunkHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	index, errC := strconv.Atoi(r.FormValue("chunk"))
+	if errC != nil {
+		return sdk.WrapError(sdk.ErrWrongRequest, "putWorkflowJobArtifactChunkHandler> Invalid chunk index")
+	}
+
+	up, art, errL := loadWorkflowJobArtifactUpload(db, r)
+	if errL != nil {
+		return sdk.WrapError(errL, "putWorkflowJobArtifactChunkHandler")
+	}
+
+	if err := artifact.SaveWorkflowFileChunk(art, up, index, r.Header.Get(sdk.ArtifactChunkSHA256), r.Body); err != nil {
+		return sdk.WrapError(err, "putWorkflowJobArtifactChunkHandler> Cannot save chunk")
+	}
+	return WriteJSON(w, r, up, http.StatusOK)
+}
+
+func postWorkflowJobArtifactUploadCompleteHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	up, art, errL := loadWorkflowJobArtifactUpload(db, r)
+	if errL != nil {
+		return sdk.WrapError(errL, "postWorkflowJobArtifactUploadCompleteHandler")
+	}
+
+	hash, errG := generateHash()
+	if errG != nil {
+		return sdk.WrapError(errG, "postWorkflowJobArtifactUploadCompleteHandler> Could not generate hash")
+	}
+	art.DownloadHash = hash
+	art.Created = time.Now()
+
+	if err := artifact.AssembleWorkflowFile(art, up); err != nil {
+		return sdk.WrapError(err, "postWorkflowJobArtifactUploadCompleteHandler> Cannot save artifact in store")
+	}
+
+	if err := workflow.InsertArtifact(db, art); err != nil {
+		_ = objectstore.DeleteArtifact(art)
+		return sdk.WrapError(err, "postWorkflowJobArtifactUploadCompleteHandler> Cannot update workflow node run")
+	}
+	return WriteJSON(w, r, art, http.StatusOK)
+}
+
+// orderQueue orders jobs by priority and fair share between projects, and keeps only jobs within
+// concurrency quotas of groups if applyQuotas is true
+func orderQueue(db gorp.SqlExecutor, jobs []queue.Job, applyQuotas bool) ([]queue.Job, error) {
+	building, err := countBuildingJobsByProject(db)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := queue.FairShare(jobs, building)
+	if !applyQuotas {
+		return ordered, nil
+	}
+
+	projectGroups, quotas, err := group.LoadConcurrencyQuotas(db)
+	if err != nil {
+		return nil, err
+	}
+	return queue.WithinQuotas(ordered, building, projectGroups, quotas), nil
+}
+
+// countBuildingJobsByProject returns the number of building workflow and pipeline jobs of each project
+func countBuildingJobsByProject(db gorp.SqlExecutor) (map[int64]int, error) {
+	building, err := workflow.CountBuildingNodeJobRunsByProject(db)
+	if err != nil {
+		return nil, err
+	}
+	buildingPipelines, err := pipeline.CountBuildingPipelineBuildJobsByProject(db)
+	if err != nil {
+		return nil, err
+	}
+	for projectID, n := range buildingPipelines {
+		building[projectID] += n
+	}
+	return building, nil
+}
+
+// countBookedJobsByProject returns the number of waiting workflow and pipeline jobs booked by a hatchery of each
+// project, the given job excepted
+func countBookedJobsByProject(db gorp.SqlExecutor, except int64) (map[int64]int, error) {
+	booked, err := workflow.CountBookedNodeJobRunsByProject(db, except)
+	if err != nil {
+		return nil, err
+	}
+	bookedPipelines, err := pipeline.CountBookedPipelineBuildJobsByProject(db, except)
+	if err != nil {
+		return nil, err
+	}
+	for projectID, n := range bookedPipelines {
+		booked[projectID] += n
+	}
+	return booked, nil
+}
+
+// checkConcurrencyQuotas returns ErrConcurrencyQuotaReached if the job of the project would exceed the concurrency
+// quota of one of its groups. The building jobs and the jobs booked by a hatchery, other than the given one, are
+// counted. In a transaction, the groups of the project are locked until the end of the transaction, so that jobs of
+// the same groups are booked and taken one after the other
+func checkConcurrencyQuotas(db gorp.SqlExecutor, projectID, jobID int64) error {
+	n, err := group.LockConcurrencyQuotas(db, projectID)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return nil
+	}
+
+	building, err := countBuildingJobsByProject(db)
+	if err != nil {
+		return err
+	}
+	booked, err := countBookedJobsByProject(db, jobID)
+	if err != nil {
+		return err
+	}
+	for id, n := range booked {
+		building[id] += n
+	}
+	projectGroups, quotas, err := group.LoadConcurrencyQuotas(db)
+	if err != nil {
+		return err
+	}
+	if !queue.CanStart(projectID, building, projectGroups, quotas) {
+		return sdk.WrapError(sdk.ErrConcurrencyQuotaReached, "checkConcurrencyQuotas> Project %d", projectID)
+	}
+	return nil
+}
+
+// sortNodeJobRuns orders workflow jobs in queue, see orderQueue
+func sortNodeJobRuns(db gorp.SqlExecutor, jobs []sdk.WorkflowNodeJobRun, applyQuotas bool) ([]sdk.WorkflowNodeJobRun, error) {
+	ids := make([]int64, len(jobs))
+	byID := make(map[int64]sdk.WorkflowNodeJobRun, len(jobs))
+	for i, j := range jobs {
+		ids[i] = j.ID
+		byID[j.ID] = j
+	}
+
+	projects, err := workflow.LoadNodeJobRunProjectIDs(db, ids)
+	if err != nil {
+		return nil, sdk.WrapError(err, "sortNodeJobRuns> Unable to load projects")
+	}
+
+	queued := make([]queue.Job, len(jobs))
+	for i, j := range jobs {
+		queued[i] = queue.Job{ID: j.ID, ProjectID: projects[j.ID], Priority: j.Priority, Queued: j.Queued}
+	}
+
+	ordered, err := orderQueue(db, queued, applyQuotas)
+	if err != nil {
+		return nil, sdk.WrapError(err, "sortNodeJobRuns> Unable to order queue")
+	}
+
+	res := make([]sdk.WorkflowNodeJobRun, len(ordered))
+	for i, j := range ordered {
+		res[i] = byID[j.ID]
+	}
+	return res, nil
+}
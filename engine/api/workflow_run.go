@@ -191,27 +191,37 @@ func postWorkflowRunHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbM
 	key := vars["permProjectKey"]
 	name := vars["workflowName"]
 
-	tx, err := db.Begin()
-	if err != nil {
+	opts := &postWorkflowRunHandlerOption{}
+	if err := UnmarshalBody(r, opts); err != nil {
 		return err
 	}
-	defer tx.Rollback()
 
-	opts := &postWorkflowRunHandlerOption{}
-	if err := UnmarshalBody(r, opts); err != nil {
+	wr, err := runWorkflow(db, c.User, key, name, opts)
+	if err != nil {
 		return err
 	}
 
-	wf, err := workflow.Load(tx, key, name, c.User)
+	wr.Translate(r.Header.Get("Accept-Language"))
+	return WriteJSON(w, r, wr, http.StatusOK)
+}
+
+func runWorkflow(db *gorp.DbMap, u *sdk.User, key, name string, opts *postWorkflowRunHandlerOption) (*sdk.WorkflowRun, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	wf, err := workflow.Load(tx, key, name, u)
 	if err != nil {
-		return sdk.WrapError(err, "postWorkflowRunHandler> Unable to load workflow")
+		return nil, sdk.WrapError(err, "runWorkflow> Unable to load workflow")
 	}
 
 	var lastRun *sdk.WorkflowRun
 	if opts.Number != nil {
 		lastRun, err = workflow.LoadRun(tx, key, name, *opts.Number)
 		if err != nil {
-			return sdk.WrapError(err, "postWorkflowRunHandler> Unable to load workflow run")
+			return nil, sdk.WrapError(err, "runWorkflow> Unable to load workflow run")
 		}
 	}
 
@@ -221,23 +231,27 @@ func postWorkflowRunHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbM
 	if opts.Hook != nil {
 		wr, err = workflow.RunFromHook(tx, wf, opts.Hook)
 		if err != nil {
-			return sdk.WrapError(err, "postWorkflowRunHandler> Unable to run workflow")
+			return nil, sdk.WrapError(err, "runWorkflow> Unable to run workflow")
 		}
 	} else {
 		//Default manual run
 		if opts.Manual == nil {
 			opts.Manual = &sdk.WorkflowNodeRunManual{
-				User: *c.User,
+				User: *u,
 			}
 		}
 
+		if err := sdk.IsValidJobPriority(opts.Manual.Priority); err != nil {
+			return nil, sdk.WrapError(err, "runWorkflow> Invalid priority")
+		}
+
 		//If payload is not set, keep the default payload
 		if opts.Manual.Payload == interface{}(nil) {
 			n := wf.Root
 			if opts.FromNodeID != nil {
 				n = wf.GetNode(*opts.FromNodeID)
 				if n == nil {
-					return sdk.WrapError(sdk.ErrWorkflowNotFound, "postWorkflowRunHandler> Unable to run workflow")
+					return nil, sdk.WrapError(sdk.ErrWorkflowNotFound, "runWorkflow> Unable to run workflow")
 				}
 			}
 			opts.Manual.Payload = n.Context.DefaultPayload
@@ -249,7 +263,7 @@ func postWorkflowRunHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbM
 			if opts.FromNodeID != nil {
 				n = wf.GetNode(*opts.FromNodeID)
 				if n == nil {
-					return sdk.WrapError(sdk.ErrWorkflowNotFound, "postWorkflowRunHandler> Unable to run workflow")
+					return nil, sdk.WrapError(sdk.ErrWorkflowNotFound, "runWorkflow> Unable to run workflow")
 				}
 			}
 			opts.Manual.PipelineParameters = n.Context.DefaultPipelineParameters
@@ -264,23 +278,61 @@ func postWorkflowRunHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbM
 			}
 			wr, err = workflow.ManualRunFromNode(tx, wf, lastRun.Number, opts.Manual, *opts.FromNodeID)
 			if err != nil {
-				return sdk.WrapError(err, "postWorkflowRunHandler> Unable to run workflow")
+				return nil, sdk.WrapError(err, "runWorkflow> Unable to run workflow")
 			}
 		} else {
 			wr, err = workflow.ManualRun(tx, wf, opts.Manual)
 			if err != nil {
-				return sdk.WrapError(err, "postWorkflowRunHandler> Unable to run workflow")
+				return nil, sdk.WrapError(err, "runWorkflow> Unable to run workflow")
 			}
 		}
 	}
 
 	//Commit and return success
 	if err := tx.Commit(); err != nil {
-		return sdk.WrapError(err, "postWorkflowRunHandler> Unable to run workflow")
+		return nil, sdk.WrapError(err, "runWorkflow> Unable to run workflow")
 	}
 
-	wr.Translate(r.Header.Get("Accept-Language"))
-	return WriteJSON(w, r, wr, http.StatusOK)
+	return wr, nil
+}
+
+func postStopWorkflowRunHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+	name := vars["workflowName"]
+	number, err := requestVarInt(r, "number")
+	if err != nil {
+		return err
+	}
+
+	run, err := stopWorkflowRun(db, c.User, key, name, number)
+	if err != nil {
+		return sdk.WrapError(err, "postStopWorkflowRunHandler> Unable to stop workflow run")
+	}
+	run.Translate(r.Header.Get("Accept-Language"))
+	return WriteJSON(w, r, run, http.StatusOK)
+}
+
+func stopWorkflowRun(db *gorp.DbMap, u *sdk.User, key, name string, number int64) (*sdk.WorkflowRun, error) {
+	run, err := workflow.LoadRun(db, key, name, number)
+	if err != nil {
+		return nil, sdk.WrapError(err, "stopWorkflowRun> Unable to load workflow run")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := workflow.StopWorkflowRun(tx, run, u); err != nil {
+		return nil, sdk.WrapError(err, "stopWorkflowRun> Unable to stop workflow run %d", run.ID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, sdk.WrapError(err, "stopWorkflowRun> Unable to commit transaction")
+	}
+	return run, nil
 }
 
 func getWorkflowNodeRunArtifactsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
@@ -305,6 +357,47 @@ func getWorkflowNodeRunArtifactsHandler(w http.ResponseWriter, r *http.Request,
 	return WriteJSON(w, r, nodeRun.Artifacts, http.StatusOK)
 }
 
+func getWorkflowNodeRunStaticAnalysisHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+	name := vars["workflowName"]
+
+	number, errNu := requestVarInt(r, "number")
+	if errNu != nil {
+		return sdk.WrapError(errNu, "getWorkflowNodeRunStaticAnalysisHandler> Invalid number")
+	}
+
+	id, errI := requestVarInt(r, "id")
+	if errI != nil {
+		return sdk.WrapError(sdk.ErrInvalidID, "getWorkflowNodeRunStaticAnalysisHandler> Invalid node run ID")
+	}
+	nodeRun, errR := workflow.LoadNodeRun(db, key, name, number, id)
+	if errR != nil {
+		return sdk.WrapError(errR, "getWorkflowNodeRunStaticAnalysisHandler> Cannot load node run")
+	}
+
+	findings, errF := workflow.LoadStaticAnalysisFindings(db, nodeRun.ID)
+	if errF != nil {
+		return sdk.WrapError(errF, "getWorkflowNodeRunStaticAnalysisHandler> Cannot load findings")
+	}
+
+	// Filter on severity and on new findings only
+	severity := r.FormValue("severity")
+	onlyNew := FormBool(r, "new")
+	res := []sdk.StaticAnalysisFinding{}
+	for _, f := range findings {
+		if severity != "" && f.Severity != severity {
+			continue
+		}
+		if onlyNew && !f.New {
+			continue
+		}
+		res = append(res, f)
+	}
+
+	return WriteJSON(w, r, res, http.StatusOK)
+}
+
 func getDownloadArtifactHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
 	vars := mux.Vars(r)
 	key := vars["permProjectKey"]
@@ -349,6 +442,27 @@ func getWorkflowRunArtifactsHandler(w http.ResponseWriter, r *http.Request, db *
 		return errW
 	}
 
+	return WriteJSON(w, r, workflowRunArtifacts(wr), http.StatusOK)
+}
+
+// getWorkflowLatestArtifactsHandler returns the artifacts of the last successful run of a workflow,
+// optionally built on a given git branch or tag
+func getWorkflowLatestArtifactsHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
+	vars := mux.Vars(r)
+	key := vars["permProjectKey"]
+	name := vars["workflowName"]
+	branch := r.FormValue("branch")
+
+	wr, errW := workflow.LoadLastSuccessfulRun(db, key, name, branch)
+	if errW != nil {
+		return sdk.WrapError(errW, "getWorkflowLatestArtifactsHandler> Cannot load last successful run of workflow %s", name)
+	}
+
+	return WriteJSON(w, r, workflowRunArtifacts(wr), http.StatusOK)
+}
+
+// workflowRunArtifacts returns the artifacts of the last run of each node of a workflow run
+func workflowRunArtifacts(wr *sdk.WorkflowRun) []sdk.WorkflowNodeRunArtifact {
 	arts := []sdk.WorkflowNodeRunArtifact{}
 	for _, runs := range wr.WorkflowNodeRuns {
 		if len(runs) == 0 {
@@ -361,8 +475,7 @@ func getWorkflowRunArtifactsHandler(w http.ResponseWriter, r *http.Request, db *
 
 		arts = append(arts, runs[0].Artifacts...)
 	}
-
-	return WriteJSON(w, r, arts, http.StatusOK)
+	return arts
 }
 
 func getWorkflowNodeRunJobStepHandler(w http.ResponseWriter, r *http.Request, db *gorp.DbMap, c *businesscontext.Ctx) error {
@@ -12,6 +12,9 @@ func init() {
 	Cmd.Flags().StringVarP(&hatcheryDocker.addhost, "docker-add-host", "", "", "Start worker with a custom host-to-IP mapping (host:ip)")
 	viper.BindPFlag("docker-add-host", Cmd.Flags().Lookup("docker-add-host"))
 
+	Cmd.Flags().IntVarP(&hatcheryDocker.serviceTimeout, "docker-service-timeout", "", 120, "Timeout (seconds) waiting for services required by a job to be running, and healthy if their image has a health check")
+	viper.BindPFlag("docker-service-timeout", Cmd.Flags().Lookup("docker-service-timeout"))
+
 	Cmd.Flags().Int("spawn-threshold-critical", 10, "log critical if spawn take more than this value (in seconds)")
 	viper.BindPFlag("spawn-threshold-critical", Cmd.Flags().Lookup("spawn-threshold-critical"))
 
@@ -45,6 +48,7 @@ $ hatchery docker --api=https://<api.domain> --token=<token>
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
 		hatcheryDocker.addhost = viper.GetString("docker-add-host")
+		hatcheryDocker.serviceTimeout = viper.GetInt("docker-service-timeout")
 		hatchery.Create(hatcheryDocker,
 			viper.GetString("api"),
 			viper.GetString("token"),
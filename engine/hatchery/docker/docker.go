@@ -21,9 +21,13 @@ var hatcheryDocker *HatcheryDocker
 // by directly using available docker daemon
 type HatcheryDocker struct {
 	sync.Mutex
-	workers map[string]*exec.Cmd
-	hatch   *sdk.Hatchery
-	addhost string
+	workers  map[string]*exec.Cmd
+	spawning map[string]bool
+	hatch    *sdk.Hatchery
+	addhost  string
+
+	serviceTimeout       int
+	serviceCheckInterval time.Duration
 }
 
 // ID must returns hatchery id
@@ -45,14 +49,7 @@ func (*HatcheryDocker) ModelType() string {
 }
 
 // CanSpawn return wether or not hatchery can spawn model
-// requirement are not supported
 func (hd *HatcheryDocker) CanSpawn(model *sdk.Model, job *sdk.PipelineBuildJob) bool {
-	for _, r := range job.Job.Action.Requirements {
-		if r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement {
-			return false
-		}
-	}
-
 	return true
 }
 
@@ -60,6 +57,10 @@ func (hd *HatcheryDocker) CanSpawn(model *sdk.Model, job *sdk.PipelineBuildJob)
 // and check hatchery can run in docker mode with given configuration
 func (hd *HatcheryDocker) Init() error {
 	hd.workers = make(map[string]*exec.Cmd)
+	hd.spawning = make(map[string]bool)
+	if hd.serviceCheckInterval == 0 {
+		hd.serviceCheckInterval = time.Second
+	}
 
 	ok, err := hatchery.CheckRequirement(sdk.Requirement{Type: sdk.BinaryRequirement, Value: "docker"})
 	if err != nil {
@@ -103,6 +104,7 @@ func (hd *HatcheryDocker) killAwolWorkerRoutine() {
 	for {
 		time.Sleep(5 * time.Second)
 		hd.killAwolWorker()
+		hd.killAwolServices()
 	}
 }
 
@@ -191,6 +193,37 @@ func (hd *HatcheryDocker) SpawnWorker(wm *sdk.Model, job *sdk.PipelineBuildJob,
 		name = "register-" + name
 	}
 
+	var memory int64
+	var cpus float64
+	var services []service
+	if job != nil {
+		var errReq error
+		memory, cpus, services, errReq = workerRequirements(name, job.Job.Action.Requirements)
+		if errReq != nil {
+			return "", errReq
+		}
+	}
+
+	// Services are started on a network dedicated to the worker, the worker is started once they are ready
+	var network string
+	if len(services) > 0 {
+		network = name + "-net"
+		hd.Lock()
+		hd.spawning[name] = true
+		hd.Unlock()
+		defer func() {
+			hd.Lock()
+			delete(hd.spawning, name)
+			hd.Unlock()
+		}()
+
+		if err := hd.startServices(name, network, services); err != nil {
+			log.Warning("spawnWorker> cannot start services of worker %s: %s", name, err)
+			removeWorkerContainers(name)
+			return "", err
+		}
+	}
+
 	var args []string
 	args = append(args, "run", "--rm", "-a", "STDOUT", "-a", "STDERR")
 	args = append(args, fmt.Sprintf("--name=%s", name))
@@ -226,6 +259,16 @@ func (hd *HatcheryDocker) SpawnWorker(wm *sdk.Model, job *sdk.PipelineBuildJob,
 	if hd.addhost != "" {
 		args = append(args, fmt.Sprintf("--add-host=%s", hd.addhost))
 	}
+	if network != "" {
+		args = append(args, fmt.Sprintf("--network=%s", network), "--network-alias=worker")
+	}
+	if memory > 0 {
+		//Moaaaaar memory
+		args = append(args, fmt.Sprintf("--memory=%dm", memory*110/100))
+	}
+	if cpus > 0 {
+		args = append(args, fmt.Sprintf("--cpus=%g", cpus))
+	}
 	args = append(args, wm.Image)
 	args = append(args, "sh", "-c", fmt.Sprintf("rm -f worker && echo 'Download worker' && curl %s/download/worker/`uname -m` -o worker && echo 'chmod worker' && chmod +x worker && echo 'starting worker' && ./worker", sdk.Host))
 
@@ -236,6 +279,9 @@ func (hd *HatcheryDocker) SpawnWorker(wm *sdk.Model, job *sdk.PipelineBuildJob,
 	log.Debug("Running %s", cmd.Args)
 
 	if err := cmd.Start(); err != nil {
+		if network != "" {
+			removeWorkerContainers(name)
+		}
 		return "", err
 	}
 	hd.Lock()
@@ -244,8 +290,12 @@ func (hd *HatcheryDocker) SpawnWorker(wm *sdk.Model, job *sdk.PipelineBuildJob,
 
 	// Wait in a goroutine so that when process exits, Wait() update cmd.ProcessState
 	// ProcessState is then checked in nextAvailableLocalID
+	// When the worker dies, its services and network are removed
 	go func() {
 		cmd.Wait()
+		if network != "" {
+			removeWorkerContainers(name)
+		}
 	}()
 
 	// Do not spam docker daemon
@@ -0,0 +1,201 @@
+package docker
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// dockerCmd runs a docker command and returns its combined output
+var dockerCmd = func(args ...string) (string, error) {
+	out, err := exec.Command("docker", args...).CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
+// service is a container started next to a worker from a service requirement
+type service struct {
+	name   string
+	alias  string
+	image  string
+	env    []string
+	memory int64
+}
+
+// workerRequirements returns the memory, the cpus and the services needed by the worker from the job requirements
+func workerRequirements(workerName string, reqs []sdk.Requirement) (int64, float64, []service, error) {
+	var memory int64
+	var cpus float64
+	var services []service
+	for _, r := range reqs {
+		switch r.Type {
+		case sdk.MemoryRequirement:
+			var err error
+			memory, err = strconv.ParseInt(r.Value, 10, 64)
+			if err != nil {
+				return 0, 0, nil, fmt.Errorf("invalid memory requirement %s: %s", r.Value, err)
+			}
+		case sdk.CPURequirement:
+			var err error
+			cpus, err = sdk.ParseCPURequirement(r.Value)
+			if err != nil {
+				return 0, 0, nil, err
+			}
+		case sdk.ServiceRequirement:
+			//name= <alias> => the hostname of the service on the worker network
+			//value= "postgres:latest env_1=blabla env_2=blabla" => we can add env variables in requirement value
+			tuple := strings.Fields(r.Value)
+			if len(tuple) == 0 {
+				return 0, 0, nil, fmt.Errorf("invalid service requirement %s: image is empty", r.Name)
+			}
+			s := service{
+				name:  r.Name + "-" + workerName,
+				alias: r.Name,
+				image: tuple[0],
+			}
+			for _, e := range tuple[1:] {
+				//option for power user : set the service memory with CDS_SERVICE_MEMORY=1024
+				if strings.HasPrefix(e, "CDS_SERVICE_MEMORY=") {
+					m, err := strconv.ParseInt(strings.TrimPrefix(e, "CDS_SERVICE_MEMORY="), 10, 64)
+					if err != nil {
+						return 0, 0, nil, fmt.Errorf("invalid service option %s: %s", e, err)
+					}
+					s.memory = m
+					continue
+				}
+				s.env = append(s.env, e)
+			}
+			services = append(services, s)
+		}
+	}
+	return memory, cpus, services, nil
+}
+
+// startServices creates the network of the worker and starts its services on it.
+// Services are labeled with the worker name, so that they can be removed with the worker,
+// and with the hatchery name, so that a hatchery only removes its own services.
+func (hd *HatcheryDocker) startServices(workerName, network string, services []service) error {
+	hatcheryLabel := "service_hatchery=" + hd.hatch.Name
+	if out, err := dockerCmd("network", "create", "--label", "worker_net="+workerName, "--label", hatcheryLabel, network); err != nil {
+		return fmt.Errorf("cannot create network %s: %s", network, out)
+	}
+
+	for _, s := range services {
+		args := []string{"run", "-d", "--name=" + s.name, "--network=" + network, "--network-alias=" + s.alias, "--label", "service_worker=" + workerName, "--label", hatcheryLabel}
+		if s.memory > 0 {
+			args = append(args, fmt.Sprintf("--memory=%dm", s.memory))
+		}
+		for _, e := range s.env {
+			args = append(args, "-e", e)
+		}
+		args = append(args, s.image)
+
+		log.Info("startServices> starting service %s (%s) for worker %s", s.alias, s.image, workerName)
+		if out, err := dockerCmd(args...); err != nil {
+			return fmt.Errorf("cannot start service %s: %s", s.alias, out)
+		}
+	}
+
+	for _, s := range services {
+		if err := hd.waitService(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitService waits for the service container to be running, and healthy if the image has a health check
+func (hd *HatcheryDocker) waitService(s service) error {
+	timeout := time.Duration(hd.serviceTimeout) * time.Second
+	for start := time.Now(); ; time.Sleep(hd.serviceCheckInterval) {
+		out, err := dockerCmd("inspect", "--format", "{{.State.Status}} {{if .State.Health}}{{.State.Health.Status}}{{end}}", s.name)
+		if err != nil {
+			return fmt.Errorf("cannot inspect service %s: %s", s.alias, out)
+		}
+
+		state := strings.Fields(out)
+		var status, health string
+		if len(state) > 0 {
+			status = state[0]
+		}
+		if len(state) > 1 {
+			health = state[1]
+		}
+
+		switch {
+		case status == "exited" || status == "dead":
+			return fmt.Errorf("service %s (%s) is %s", s.alias, s.image, status)
+		case health == "unhealthy":
+			return fmt.Errorf("service %s (%s) is unhealthy", s.alias, s.image)
+		case status == "running" && (health == "" || health == "healthy"):
+			log.Debug("waitService> service %s is ready", s.name)
+			return nil
+		}
+
+		if time.Since(start) > timeout {
+			return fmt.Errorf("service %s (%s) is not ready after %s", s.alias, s.image, timeout)
+		}
+	}
+}
+
+// removeWorkerContainers removes the worker container, its services and its network
+func removeWorkerContainers(workerName string) {
+	if out, err := dockerCmd("rm", "-f", workerName); err != nil && !strings.Contains(out, "No such container") {
+		log.Warning("removeWorkerContainers> cannot rm container %s: %s", workerName, out)
+	}
+
+	ids, err := dockerCmd("ps", "-a", "-q", "--filter", "label=service_worker="+workerName)
+	if err != nil {
+		log.Warning("removeWorkerContainers> cannot list services of worker %s: %s", workerName, ids)
+	}
+	for _, id := range strings.Fields(ids) {
+		if out, err := dockerCmd("rm", "-f", "-v", id); err != nil && !strings.Contains(out, "No such container") {
+			log.Warning("removeWorkerContainers> cannot rm service container %s of worker %s: %s", id, workerName, out)
+		}
+	}
+
+	nets, err := dockerCmd("network", "ls", "-q", "--filter", "label=worker_net="+workerName)
+	if err != nil {
+		log.Warning("removeWorkerContainers> cannot list networks of worker %s: %s", workerName, nets)
+	}
+	for _, id := range strings.Fields(nets) {
+		if out, err := dockerCmd("network", "rm", id); err != nil {
+			log.Warning("removeWorkerContainers> cannot rm network %s of worker %s: %s", id, workerName, out)
+		}
+	}
+}
+
+// killAwolServices removes services and networks of workers of the hatchery which are not running anymore,
+// for instance after a restart of the hatchery. Services started by other hatcheries on the same docker daemon are ignored
+func (hd *HatcheryDocker) killAwolServices() {
+	hatcheryFilter := "label=service_hatchery=" + hd.hatch.Name
+	out, err := dockerCmd("ps", "-a", "--filter", "label=service_worker", "--filter", hatcheryFilter, "--format", `{{.Label "service_worker"}}`)
+	if err != nil {
+		log.Warning("killAwolServices> cannot list services: %s", out)
+		return
+	}
+	nets, err := dockerCmd("network", "ls", "--filter", "label=worker_net", "--filter", hatcheryFilter, "--format", `{{.Label "worker_net"}}`)
+	if err != nil {
+		log.Warning("killAwolServices> cannot list networks: %s", nets)
+		return
+	}
+
+	awol := map[string]bool{}
+	hd.Lock()
+	for _, name := range append(strings.Fields(out), strings.Fields(nets)...) {
+		_, running := hd.workers[name]
+		if !running && !hd.spawning[name] {
+			awol[name] = true
+		}
+	}
+	hd.Unlock()
+
+	for name := range awol {
+		log.Info("killAwolServices> remove services of worker %s", name)
+		removeWorkerContainers(name)
+	}
+}
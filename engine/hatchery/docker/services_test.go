@@ -0,0 +1,154 @@
+package docker
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// fakeDocker records docker commands and answers with the given responses
+type fakeDocker struct {
+	cmds      []string
+	responses map[string][]string
+}
+
+func (f *fakeDocker) run(args ...string) (string, error) {
+	cmd := strings.Join(args, " ")
+	f.cmds = append(f.cmds, cmd)
+	for prefix, outs := range f.responses {
+		if strings.HasPrefix(cmd, prefix) && len(outs) > 0 {
+			out := outs[0]
+			if len(outs) > 1 {
+				f.responses[prefix] = outs[1:]
+			}
+			return out, nil
+		}
+	}
+	return "", nil
+}
+
+// withFakeDocker replaces docker commands by a fake, until the returned func is called
+func withFakeDocker(responses map[string][]string) (*fakeDocker, func()) {
+	f := &fakeDocker{responses: responses}
+	old := dockerCmd
+	dockerCmd = f.run
+	return f, func() { dockerCmd = old }
+}
+
+func TestWorkerRequirements(t *testing.T) {
+	memory, cpus, services, err := workerRequirements("w1", []sdk.Requirement{
+		{Name: "mem", Type: sdk.MemoryRequirement, Value: "2048"},
+		{Name: "cpu", Type: sdk.CPURequirement, Value: "1.5"},
+		{Name: "pg", Type: sdk.ServiceRequirement, Value: "postgres:9.5 POSTGRES_USER=cds CDS_SERVICE_MEMORY=512"},
+		{Name: "redis", Type: sdk.ServiceRequirement, Value: "redis:latest"},
+		{Name: "go", Type: sdk.BinaryRequirement, Value: "go"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2048), memory)
+	assert.Equal(t, 1.5, cpus)
+	assert.Equal(t, []service{
+		{name: "pg-w1", alias: "pg", image: "postgres:9.5", env: []string{"POSTGRES_USER=cds"}, memory: 512},
+		{name: "redis-w1", alias: "redis", image: "redis:latest"},
+	}, services)
+
+	_, _, _, err = workerRequirements("w1", []sdk.Requirement{{Name: "mem", Type: sdk.MemoryRequirement, Value: "a lot"}})
+	assert.Error(t, err)
+
+	_, _, _, err = workerRequirements("w1", []sdk.Requirement{{Name: "cpu", Type: sdk.CPURequirement, Value: "-1"}})
+	assert.Error(t, err)
+
+	_, _, _, err = workerRequirements("w1", []sdk.Requirement{{Name: "pg", Type: sdk.ServiceRequirement, Value: " "}})
+	assert.Error(t, err)
+}
+
+func TestStartServices(t *testing.T) {
+	f, restore := withFakeDocker(map[string][]string{
+		"inspect --format {{.State.Status}} {{if .State.Health}}{{.State.Health.Status}}{{end}} pg-w1":    {"created", "running starting", "running healthy"},
+		"inspect --format {{.State.Status}} {{if .State.Health}}{{.State.Health.Status}}{{end}} redis-w1": {"running"},
+	})
+	defer restore()
+	hd := &HatcheryDocker{serviceTimeout: 10, hatch: &sdk.Hatchery{Name: "h1"}}
+
+	err := hd.startServices("w1", "w1-net", []service{
+		{name: "pg-w1", alias: "pg", image: "postgres:9.5", env: []string{"POSTGRES_USER=cds"}, memory: 512},
+		{name: "redis-w1", alias: "redis", image: "redis:latest"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "network create --label worker_net=w1 --label service_hatchery=h1 w1-net", f.cmds[0])
+	assert.Equal(t, "run -d --name=pg-w1 --network=w1-net --network-alias=pg --label service_worker=w1 --label service_hatchery=h1 --memory=512m -e POSTGRES_USER=cds postgres:9.5", f.cmds[1])
+	assert.Equal(t, "run -d --name=redis-w1 --network=w1-net --network-alias=redis --label service_worker=w1 --label service_hatchery=h1 redis:latest", f.cmds[2])
+	// 3 inspects for pg, 1 for redis
+	assert.Len(t, f.cmds, 7)
+}
+
+func TestWaitServiceFailures(t *testing.T) {
+	hd := &HatcheryDocker{serviceTimeout: 0}
+	s := service{name: "pg-w1", alias: "pg", image: "postgres:9.5"}
+
+	_, restore := withFakeDocker(map[string][]string{"inspect": {"exited"}})
+	assert.Error(t, hd.waitService(s))
+	restore()
+
+	_, restore = withFakeDocker(map[string][]string{"inspect": {"running unhealthy"}})
+	assert.Error(t, hd.waitService(s))
+	restore()
+
+	_, restore = withFakeDocker(map[string][]string{"inspect": {"running starting"}})
+	defer restore()
+	err := hd.waitService(s)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is not ready")
+}
+
+func TestKillAwolServices(t *testing.T) {
+	f, restore := withFakeDocker(map[string][]string{
+		"ps -a --filter label=service_worker --filter label=service_hatchery=h1 --format":  {"running\nspawning\nawol"},
+		"network ls --filter label=worker_net --filter label=service_hatchery=h1 --format": {"running\nspawning\nawol\nawolnet"},
+		"ps -a -q --filter label=service_worker=awol":                                      {"c1\nc2"},
+		"network ls -q --filter label=worker_net=awol":                                     {"n1"},
+	})
+	defer restore()
+	hd := &HatcheryDocker{
+		hatch:    &sdk.Hatchery{Name: "h1"},
+		workers:  map[string]*exec.Cmd{"running": {}},
+		spawning: map[string]bool{"spawning": true},
+	}
+
+	hd.killAwolServices()
+
+	var removed []string
+	for _, c := range f.cmds {
+		if strings.HasPrefix(c, "rm") || strings.HasPrefix(c, "network rm") {
+			removed = append(removed, c)
+		}
+	}
+	assert.Contains(t, removed, "rm -f awol")
+	assert.Contains(t, removed, "rm -f -v c1")
+	assert.Contains(t, removed, "rm -f -v c2")
+	assert.Contains(t, removed, "network rm n1")
+	assert.Contains(t, removed, "rm -f awolnet")
+	for _, c := range removed {
+		assert.NotContains(t, c, "running")
+		assert.NotContains(t, c, "spawning")
+	}
+}
+
+func TestKillAwolServicesOtherHatchery(t *testing.T) {
+	// services of other hatcheries are filtered out by docker, so nothing is listed for this hatchery
+	f, restore := withFakeDocker(map[string][]string{
+		"ps -a --filter label=service_worker --format":  {"other"},
+		"network ls --filter label=worker_net --format": {"other"},
+	})
+	defer restore()
+	hd := &HatcheryDocker{hatch: &sdk.Hatchery{Name: "h1"}}
+
+	hd.killAwolServices()
+
+	for _, c := range f.cmds {
+		assert.False(t, strings.HasPrefix(c, "rm") || strings.HasPrefix(c, "network rm"), "unexpected command %s", c)
+	}
+}
@@ -0,0 +1,200 @@
+package kubernetes
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Pod phases, see https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle/
+const (
+	podPending   = "Pending"
+	podRunning   = "Running"
+	podSucceeded = "Succeeded"
+	podFailed    = "Failed"
+)
+
+// The following types are the subset of the Kubernetes v1 API used by the hatchery
+type objectMeta struct {
+	Name              string            `json:"name,omitempty"`
+	Namespace         string            `json:"namespace,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	CreationTimestamp time.Time         `json:"creationTimestamp,omitempty"`
+}
+
+type envVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type resourceRequirements struct {
+	Limits   map[string]string `json:"limits,omitempty"`
+	Requests map[string]string `json:"requests,omitempty"`
+}
+
+type container struct {
+	Name            string               `json:"name"`
+	Image           string               `json:"image"`
+	Command         []string             `json:"command,omitempty"`
+	Env             []envVar             `json:"env,omitempty"`
+	Resources       resourceRequirements `json:"resources,omitempty"`
+	ImagePullPolicy string               `json:"imagePullPolicy,omitempty"`
+}
+
+type hostAlias struct {
+	IP        string   `json:"ip"`
+	Hostnames []string `json:"hostnames"`
+}
+
+type podSpec struct {
+	Containers         []container       `json:"containers"`
+	RestartPolicy      string            `json:"restartPolicy,omitempty"`
+	ServiceAccountName string            `json:"serviceAccountName,omitempty"`
+	NodeSelector       map[string]string `json:"nodeSelector,omitempty"`
+	HostAliases        []hostAlias       `json:"hostAliases,omitempty"`
+}
+
+type podStatus struct {
+	Phase   string `json:"phase,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type pod struct {
+	APIVersion string     `json:"apiVersion,omitempty"`
+	Kind       string     `json:"kind,omitempty"`
+	Metadata   objectMeta `json:"metadata"`
+	Spec       podSpec    `json:"spec"`
+	Status     podStatus  `json:"status,omitempty"`
+}
+
+type podList struct {
+	Items []pod `json:"items"`
+}
+
+type apiStatus struct {
+	Message string `json:"message"`
+	Reason  string `json:"reason"`
+	Code    int    `json:"code"`
+}
+
+// podsClient is the interface of the pods API of a namespace
+type podsClient interface {
+	Create(p *pod) (*pod, error)
+	List(labelSelector string) ([]pod, error)
+	Delete(name string) error
+}
+
+// restClient calls the Kubernetes API over HTTP
+type restClient struct {
+	host       string
+	token      string
+	namespace  string
+	httpClient *http.Client
+}
+
+func newRestClient(host, token, namespace, caCert string, insecure bool) (*restClient, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecure}
+	if caCert != "" {
+		pem, err := ioutil.ReadFile(caCert)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CA certificate %s: %s", caCert, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("invalid CA certificate %s", caCert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &restClient{
+		host:      host,
+		token:     token,
+		namespace: namespace,
+		httpClient: &http.Client{
+			Timeout:   time.Minute,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}, nil
+}
+
+func (c *restClient) podsPath() string {
+	return fmt.Sprintf("/api/v1/namespaces/%s/pods", c.namespace)
+}
+
+func (c *restClient) request(method, path string, in, out interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		body, err = json.Marshal(in)
+		if err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, c.host+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 300 {
+		var s apiStatus
+		if err := json.Unmarshal(data, &s); err == nil && s.Message != "" {
+			return fmt.Errorf("%s %s: %s (HTTP %d)", method, path, s.Message, resp.StatusCode)
+		}
+		return fmt.Errorf("%s %s: HTTP %d", method, path, resp.StatusCode)
+	}
+
+	if out != nil {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+func (c *restClient) Create(p *pod) (*pod, error) {
+	p.APIVersion = "v1"
+	p.Kind = "Pod"
+	res := &pod{}
+	if err := c.request(http.MethodPost, c.podsPath(), p, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *restClient) List(labelSelector string) ([]pod, error) {
+	path := c.podsPath()
+	if labelSelector != "" {
+		path += "?labelSelector=" + url.QueryEscape(labelSelector)
+	}
+	var list podList
+	if err := c.request(http.MethodGet, path, nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func (c *restClient) Delete(name string) error {
+	return c.request(http.MethodDelete, c.podsPath()+"/"+name, nil, nil)
+}
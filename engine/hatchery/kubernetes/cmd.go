@@ -0,0 +1,143 @@
+package kubernetes
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+// Paths of the service account credentials when the hatchery runs inside a kubernetes cluster
+const (
+	inClusterTokenFile  = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	inClusterCACertFile = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+)
+
+func init() {
+	hatcheryKubernetes = &HatcheryKubernetes{}
+
+	Cmd.Flags().StringVar(&hatcheryKubernetes.kubernetesMasterURL, "kubernetes-master-url", "", "Kubernetes API URL, example: https://kubernetes.domain:6443. If empty, the in-cluster configuration is used")
+	viper.BindPFlag("kubernetes-master-url", Cmd.Flags().Lookup("kubernetes-master-url"))
+
+	Cmd.Flags().StringVar(&hatcheryKubernetes.kubernetesToken, "kubernetes-token", "", "Bearer token used to authenticate on the Kubernetes API")
+	viper.BindPFlag("kubernetes-token", Cmd.Flags().Lookup("kubernetes-token"))
+
+	Cmd.Flags().StringVar(&hatcheryKubernetes.kubernetesCACert, "kubernetes-ca-cert", "", "Path of the CA certificate of the Kubernetes API")
+	viper.BindPFlag("kubernetes-ca-cert", Cmd.Flags().Lookup("kubernetes-ca-cert"))
+
+	Cmd.Flags().BoolVar(&hatcheryKubernetes.kubernetesInsecure, "kubernetes-insecure", false, "Skip the verification of the certificate of the Kubernetes API")
+	viper.BindPFlag("kubernetes-insecure", Cmd.Flags().Lookup("kubernetes-insecure"))
+
+	Cmd.Flags().StringVar(&hatcheryKubernetes.namespace, "kubernetes-namespace", "default", "Namespace in which workers pods are started")
+	viper.BindPFlag("kubernetes-namespace", Cmd.Flags().Lookup("kubernetes-namespace"))
+
+	Cmd.Flags().StringVar(&hatcheryKubernetes.serviceAccount, "kubernetes-service-account", "", "Service account of workers pods")
+	viper.BindPFlag("kubernetes-service-account", Cmd.Flags().Lookup("kubernetes-service-account"))
+
+	Cmd.Flags().StringVar(&hatcheryKubernetes.nodeSelectorString, "kubernetes-node-selector", "", "Node selector of workers pods, example: disktype=ssd,zone=eu")
+	viper.BindPFlag("kubernetes-node-selector", Cmd.Flags().Lookup("kubernetes-node-selector"))
+
+	Cmd.Flags().IntVar(&hatcheryKubernetes.defaultMemory, "worker-memory", 1024, "Worker default memory")
+	viper.BindPFlag("worker-memory", Cmd.Flags().Lookup("worker-memory"))
+
+	Cmd.Flags().IntVar(&hatcheryKubernetes.workerTTL, "worker-ttl", 10, "Worker TTL (minutes)")
+	viper.BindPFlag("worker-ttl", Cmd.Flags().Lookup("worker-ttl"))
+
+	Cmd.Flags().IntVar(&hatcheryKubernetes.workerSpawnTimeout, "worker-spawn-timeout", 120, "Worker Timeout Spawning (seconds). Pods without registered worker after this timeout are deleted")
+	viper.BindPFlag("worker-spawn-timeout", Cmd.Flags().Lookup("worker-spawn-timeout"))
+
+	Cmd.Flags().Int("spawn-threshold-critical", 10, "log critical if spawn take more than this value (in seconds)")
+	viper.BindPFlag("spawn-threshold-critical", Cmd.Flags().Lookup("spawn-threshold-critical"))
+
+	Cmd.Flags().Int("spawn-threshold-warning", 4, "log warning if spawn take more than this value (in seconds)")
+	viper.BindPFlag("spawn-threshold-warning", Cmd.Flags().Lookup("spawn-threshold-warning"))
+}
+
+// Cmd configures comamnd for HatcheryKubernetes
+var Cmd = &cobra.Command{
+	Use:   "kubernetes",
+	Short: "Hatchery kubernetes commands: hatchery kubernetes --help",
+	Long: `Hatchery kubernetes commands: hatchery kubernetes <command>
+Start worker model instances as pods on a kubernetes cluster
+
+$ cds generate token --group shared.infra --expiration persistent
+2706bda13748877c57029598b915d46236988c7c57ea0d3808524a1e1a3adef4
+
+$ hatchery kubernetes --api=https://<api.domain> --token=<token> --kubernetes-namespace=cds
+
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		hatchery.Create(hatcheryKubernetes,
+			viper.GetString("api"),
+			viper.GetString("token"),
+			viper.GetInt("max-worker"),
+			viper.GetBool("provision-disabled"),
+			viper.GetInt("request-api-timeout"),
+			viper.GetInt("max-failures-heartbeat"),
+			viper.GetBool("insecure"),
+			viper.GetInt("provision-seconds"),
+			viper.GetInt("register-seconds"),
+			viper.GetInt("spawn-threshold-warning"),
+			viper.GetInt("spawn-threshold-critical"),
+			viper.GetInt("grace-time-queued"),
+		)
+	},
+	PreRun: func(cmd *cobra.Command, args []string) {
+		hatcheryKubernetes.token = viper.GetString("token")
+		hatcheryKubernetes.namespace = viper.GetString("kubernetes-namespace")
+		hatcheryKubernetes.serviceAccount = viper.GetString("kubernetes-service-account")
+		hatcheryKubernetes.defaultMemory = viper.GetInt("worker-memory")
+		hatcheryKubernetes.workerTTL = viper.GetInt("worker-ttl")
+		hatcheryKubernetes.workerSpawnTimeout = viper.GetInt("worker-spawn-timeout")
+
+		if hatcheryKubernetes.namespace == "" {
+			sdk.Exit("flag or environment variable kubernetes-namespace not provided, aborting\n")
+		}
+
+		hatcheryKubernetes.nodeSelectorString = viper.GetString("kubernetes-node-selector")
+		hatcheryKubernetes.nodeSelector = map[string]string{}
+		if hatcheryKubernetes.nodeSelectorString != "" {
+			for _, s := range strings.Split(hatcheryKubernetes.nodeSelectorString, ",") {
+				tuple := strings.Split(s, "=")
+				if len(tuple) != 2 {
+					sdk.Exit("malformatted flag or environment variable kubernetes-node-selector")
+				}
+				hatcheryKubernetes.nodeSelector[tuple[0]] = tuple[1]
+			}
+		}
+
+		masterURL := viper.GetString("kubernetes-master-url")
+		token := viper.GetString("kubernetes-token")
+		caCert := viper.GetString("kubernetes-ca-cert")
+
+		// In-cluster configuration: use the service account of the hatchery pod
+		if masterURL == "" {
+			host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+			if host == "" || port == "" {
+				sdk.Exit("flag or environment variable kubernetes-master-url not provided and hatchery is not running in a kubernetes cluster, aborting\n")
+			}
+			masterURL = "https://" + host + ":" + port
+			if token == "" {
+				b, err := ioutil.ReadFile(inClusterTokenFile)
+				if err != nil {
+					sdk.Exit("Cannot read service account token: %s\n", err)
+				}
+				token = strings.TrimSpace(string(b))
+			}
+			if caCert == "" {
+				caCert = inClusterCACertFile
+			}
+		}
+
+		client, err := newRestClient(strings.TrimSuffix(masterURL, "/"), token, hatcheryKubernetes.namespace, caCert, viper.GetBool("kubernetes-insecure"))
+		if err != nil {
+			sdk.Exit("Cannot create kubernetes client: %s\n", err)
+		}
+		hatcheryKubernetes.client = client
+	},
+}
@@ -0,0 +1,439 @@
+package kubernetes
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/pkg/namesgenerator"
+	"github.com/spf13/viper"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	labelHatchery    = "cds-hatchery"
+	labelWorker      = "cds-worker"
+	labelWorkerModel = "cds-worker-model"
+	labelNodeOS      = "beta.kubernetes.io/os"
+	labelNodeArch    = "beta.kubernetes.io/arch"
+	workerContainer  = "worker"
+)
+
+var hatcheryKubernetes *HatcheryKubernetes
+
+// HatcheryKubernetes implements HatcheryMode interface for kubernetes mode. Each worker runs in a pod,
+// service requirements run as sidecar containers of the pod
+type HatcheryKubernetes struct {
+	hatch *sdk.Hatchery
+	token string
+
+	client podsClient
+
+	kubernetesMasterURL string
+	kubernetesToken     string
+	kubernetesCACert    string
+	kubernetesInsecure  bool
+	namespace           string
+	serviceAccount      string
+	nodeSelectorString  string
+	nodeSelector        map[string]string
+	defaultMemory       int
+	workerTTL           int
+	workerSpawnTimeout  int
+}
+
+// ID must returns hatchery id
+func (h *HatcheryKubernetes) ID() int64 {
+	if h.hatch == nil {
+		return 0
+	}
+	return h.hatch.ID
+}
+
+// Hatchery returns hatchery instance
+func (h *HatcheryKubernetes) Hatchery() *sdk.Hatchery {
+	return h.hatch
+}
+
+// ModelType returns type of hatchery
+func (*HatcheryKubernetes) ModelType() string {
+	return sdk.Docker
+}
+
+// NeedRegistration return true if worker model need regsitration
+func (h *HatcheryKubernetes) NeedRegistration(m *sdk.Model) bool {
+	if m.NeedRegistration || m.LastRegistration.Unix() < m.UserLastModified.Unix() {
+		return true
+	}
+	return false
+}
+
+// Init registers the hatchery and starts killing routine of worker not registered
+func (h *HatcheryKubernetes) Init() error {
+	h.hatch = &sdk.Hatchery{
+		Name: hatchery.GenerateName("kubernetes", viper.GetString("name")),
+		UID:  viper.GetString("token"),
+	}
+
+	if err := hatchery.Register(h.hatch, viper.GetString("token")); err != nil {
+		log.Warning("Cannot register hatchery: %s", err)
+	}
+
+	// Start cleaning routines
+	h.startKillAwolWorkerRoutine()
+	return nil
+}
+
+// CanSpawn return wether or not hatchery can spawn model
+func (h *HatcheryKubernetes) CanSpawn(model *sdk.Model, job *sdk.PipelineBuildJob) bool {
+	if model.Type != sdk.Docker {
+		return false
+	}
+
+	pods, err := h.pods()
+	if err != nil {
+		log.Warning("CanSpawn> Cannot list pods: %s", err)
+		return false
+	}
+	if len(pods) >= viper.GetInt("max-worker") {
+		log.Info("CanSpawn> max number of pods reached, aborting. Current: %d. Max: %d", len(pods), viper.GetInt("max-worker"))
+		return false
+	}
+	return true
+}
+
+// SpawnWorker creates a pod running a worker. Services requirements are started as sidecar containers
+func (h *HatcheryKubernetes) SpawnWorker(model *sdk.Model, job *sdk.PipelineBuildJob, registerOnly bool, logInfo string) (string, error) {
+	if job != nil {
+		log.Info("SpawnWorker> spawning worker %s (%s) for job %d - %s", model.Name, model.Image, job.ID, logInfo)
+	} else {
+		log.Info("SpawnWorker> spawning worker %s (%s) - %s", model.Name, model.Image, logInfo)
+	}
+
+	p, err := h.workerPod(model, job, registerOnly)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := h.client.Create(p); err != nil {
+		return "", sdk.WrapError(err, "SpawnWorker> Cannot create pod %s", p.Metadata.Name)
+	}
+
+	return p.Metadata.Name, nil
+}
+
+// workerPod returns the definition of the pod of a worker
+func (h *HatcheryKubernetes) workerPod(model *sdk.Model, job *sdk.PipelineBuildJob, registerOnly bool) (*pod, error) {
+	workerName := fmt.Sprintf("%s-%s", model.Name, namesgenerator.GetRandomName(0))
+	if registerOnly {
+		workerName = "register-" + workerName
+	}
+	workerName = podName(workerName)
+
+	cmd := "rm -f worker && curl ${CDS_API}/download/worker/$(uname -m) -o worker && chmod +x worker && exec ./worker"
+	if registerOnly {
+		cmd += " register"
+	}
+
+	env := map[string]string{
+		"CDS_API":           sdk.Host,
+		"CDS_TOKEN":         h.token,
+		"CDS_NAME":          workerName,
+		"CDS_MODEL":         fmt.Sprintf("%d", model.ID),
+		"CDS_HATCHERY":      fmt.Sprintf("%d", h.hatch.ID),
+		"CDS_HATCHERY_NAME": h.hatch.Name,
+		"CDS_SINGLE_USE":    "1",
+		"CDS_TTL":           fmt.Sprintf("%d", h.workerTTL),
+	}
+
+	if viper.GetString("worker_graylog_host") != "" {
+		env["CDS_GRAYLOG_HOST"] = viper.GetString("worker_graylog_host")
+	}
+	if viper.GetString("worker_graylog_port") != "" {
+		env["CDS_GRAYLOG_PORT"] = viper.GetString("worker_graylog_port")
+	}
+	if viper.GetString("worker_graylog_extra_key") != "" {
+		env["CDS_GRAYLOG_EXTRA_KEY"] = viper.GetString("worker_graylog_extra_key")
+	}
+	if viper.GetString("worker_graylog_extra_value") != "" {
+		env["CDS_GRAYLOG_EXTRA_VALUE"] = viper.GetString("worker_graylog_extra_value")
+	}
+	if viper.GetString("grpc_api") != "" && model.Communication == sdk.GRPC {
+		env["CDS_GRPC_API"] = viper.GetString("grpc_api")
+		env["CDS_GRPC_INSECURE"] = strconv.FormatBool(viper.GetBool("grpc_insecure"))
+	}
+
+	memory := h.defaultMemory
+	var cpus float64
+	nodeSelector := map[string]string{}
+	for k, v := range h.nodeSelector {
+		nodeSelector[k] = v
+	}
+	services := []container{}
+	aliases := []string{}
+	if job != nil {
+		env["CDS_BOOKED_JOB_ID"] = fmt.Sprintf("%d", job.ID)
+
+		for _, r := range job.Job.Action.Requirements {
+			switch r.Type {
+			case sdk.MemoryRequirement:
+				var err error
+				memory, err = strconv.Atoi(r.Value)
+				if err != nil {
+					return nil, sdk.WrapError(err, "SpawnWorker> Unable to parse memory requirement %s", r.Value)
+				}
+			case sdk.CPURequirement:
+				var err error
+				cpus, err = sdk.ParseCPURequirement(r.Value)
+				if err != nil {
+					return nil, sdk.WrapError(err, "SpawnWorker> Unable to parse cpu requirement %s", r.Value)
+				}
+			case sdk.OSArchRequirement:
+				reqOS, arch, err := sdk.ParseOSArchRequirement(r.Value)
+				if err != nil {
+					return nil, sdk.WrapError(err, "SpawnWorker> Unable to parse os-arch requirement %s", r.Value)
+				}
+				nodeSelector[labelNodeOS] = reqOS
+				if arch != "" {
+					nodeSelector[labelNodeArch] = arch
+				}
+			case sdk.ServiceRequirement:
+				c, err := serviceContainer(r)
+				if err != nil {
+					return nil, err
+				}
+				services = append(services, *c)
+				aliases = append(aliases, r.Name)
+			}
+		}
+	}
+
+	worker := container{
+		Name:    workerContainer,
+		Image:   model.Image,
+		Command: []string{"sh", "-c", cmd},
+		Env:     envVars(env),
+		Resources: resourceRequirements{
+			Requests: map[string]string{"memory": fmt.Sprintf("%dMi", memory)},
+			Limits:   map[string]string{"memory": fmt.Sprintf("%dMi", memory*110/100)},
+		},
+	}
+	if cpus > 0 {
+		millicpus := fmt.Sprintf("%dm", int64(cpus*1000))
+		worker.Resources.Requests["cpu"] = millicpus
+		worker.Resources.Limits["cpu"] = millicpus
+	}
+	if strings.HasSuffix(model.Image, ":latest") {
+		worker.ImagePullPolicy = "Always"
+	}
+
+	p := &pod{
+		Metadata: objectMeta{
+			Name:      workerName,
+			Namespace: h.namespace,
+			Labels: map[string]string{
+				labelHatchery:    labelValue(h.hatch.Name),
+				labelWorker:      labelValue(workerName),
+				labelWorkerModel: labelValue(model.Name),
+			},
+		},
+		Spec: podSpec{
+			Containers:         append([]container{worker}, services...),
+			RestartPolicy:      "Never",
+			ServiceAccountName: h.serviceAccount,
+			NodeSelector:       nodeSelector,
+		},
+	}
+
+	// Containers of a pod share the same network, services are reachable on localhost with their name
+	if len(aliases) > 0 {
+		p.Spec.HostAliases = []hostAlias{{IP: "127.0.0.1", Hostnames: aliases}}
+	}
+
+	return p, nil
+}
+
+// serviceContainer returns the sidecar container of a service requirement.
+// name= <alias> => the hostname of the service
+// value= "postgres:latest env_1=blabla env_2=blabla" => env variables can be added in requirement value
+func serviceContainer(r sdk.Requirement) (*container, error) {
+	tuple := strings.Fields(r.Value)
+	if len(tuple) == 0 {
+		return nil, fmt.Errorf("serviceContainer> Invalid service requirement %s: image is missing", r.Name)
+	}
+	c := &container{
+		Name:  podName(r.Name),
+		Image: tuple[0],
+		Resources: resourceRequirements{
+			Requests: map[string]string{"memory": "1024Mi"},
+		},
+	}
+
+	env := map[string]string{}
+	for _, e := range tuple[1:] {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		// option for power user : set the service memory with CDS_SERVICE_MEMORY=1024
+		if kv[0] == "CDS_SERVICE_MEMORY" {
+			if _, err := strconv.Atoi(kv[1]); err != nil {
+				log.Warning("serviceContainer> Unable to parse service option %s : %s", e, err)
+				continue
+			}
+			c.Resources.Requests["memory"] = kv[1] + "Mi"
+			continue
+		}
+		env[kv[0]] = kv[1]
+	}
+	c.Env = envVars(env)
+	return c, nil
+}
+
+// envVars converts a map to a list of environment variables sorted by name
+func envVars(env map[string]string) []envVar {
+	names := make([]string, 0, len(env))
+	for k := range env {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	vars := make([]envVar, len(names))
+	for i, k := range names {
+		vars[i] = envVar{Name: k, Value: env[k]}
+	}
+	return vars
+}
+
+var invalidNameChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// podName returns a valid kubernetes name (RFC 1123 label)
+func podName(s string) string {
+	s = invalidNameChars.ReplaceAllString(strings.ToLower(s), "-")
+	if len(s) > 63 {
+		s = s[:63]
+	}
+	return strings.Trim(s, "-")
+}
+
+var invalidLabelChars = regexp.MustCompile("[^a-zA-Z0-9_.-]+")
+
+// labelValue returns a valid kubernetes label value
+func labelValue(s string) string {
+	s = invalidLabelChars.ReplaceAllString(s, "-")
+	if len(s) > 63 {
+		s = s[:63]
+	}
+	return strings.Trim(s, "-_.")
+}
+
+// pods returns the pods started by the hatchery
+func (h *HatcheryKubernetes) pods() ([]pod, error) {
+	return h.client.List(labelHatchery + "=" + labelValue(h.hatch.Name))
+}
+
+// WorkersStarted returns the number of instances started but
+// not necessarily register on CDS yet
+func (h *HatcheryKubernetes) WorkersStarted() int {
+	pods, err := h.pods()
+	if err != nil {
+		log.Warning("WorkersStarted> Cannot list pods: %s", err)
+		return 0
+	}
+	return len(pods)
+}
+
+// WorkersStartedByModel returns the number of instances of given model started but
+// not necessarily register on CDS yet
+func (h *HatcheryKubernetes) WorkersStartedByModel(model *sdk.Model) int {
+	pods, err := h.pods()
+	if err != nil {
+		log.Warning("WorkersStartedByModel> Cannot list pods: %s", err)
+		return 0
+	}
+
+	var x int
+	for _, p := range pods {
+		if p.Metadata.Labels[labelWorkerModel] == labelValue(model.Name) {
+			x++
+		}
+	}
+	return x
+}
+
+// KillWorker deletes the pod of a worker
+func (h *HatcheryKubernetes) KillWorker(worker sdk.Worker) error {
+	log.Info("KillWorker> Killing %s", worker.Name)
+	return h.client.Delete(worker.Name)
+}
+
+func (h *HatcheryKubernetes) startKillAwolWorkerRoutine() {
+	go func() {
+		for {
+			time.Sleep(10 * time.Second)
+			if err := h.killAwolWorkers(); err != nil {
+				log.Warning("Cannot kill awol workers: %s", err)
+			}
+		}
+	}()
+}
+
+func (h *HatcheryKubernetes) killAwolWorkers() error {
+	workers, err := sdk.GetWorkers()
+	if err != nil {
+		return err
+	}
+	return h.killAwolPods(workers)
+}
+
+// killAwolPods deletes the terminated pods, the pods of the disabled workers and the pods
+// which have not registered a worker after the spawn timeout
+func (h *HatcheryKubernetes) killAwolPods(workers []sdk.Worker) error {
+	pods, err := h.pods()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pods {
+		var reason string
+		switch p.Status.Phase {
+		case podSucceeded, podFailed:
+			reason = fmt.Sprintf("pod is %s", strings.ToLower(p.Status.Phase))
+		default:
+			w := findWorker(workers, p.Metadata.Name)
+			switch {
+			case w != nil && w.Status == sdk.StatusDisabled:
+				reason = "worker is disabled"
+			case w == nil && time.Since(p.Metadata.CreationTimestamp) > time.Duration(h.workerSpawnTimeout)*time.Second:
+				reason = "no worker registered"
+			}
+		}
+
+		if reason == "" {
+			continue
+		}
+
+		log.Info("killAwolPods> Deleting pod %s: %s", p.Metadata.Name, reason)
+		if err := h.client.Delete(p.Metadata.Name); err != nil {
+			log.Warning("killAwolPods> Cannot delete pod %s: %s", p.Metadata.Name, err)
+			// continue to next pod
+		}
+	}
+
+	return nil
+}
+
+func findWorker(workers []sdk.Worker, name string) *sdk.Worker {
+	for i := range workers {
+		if workers[i].Name == name {
+			return &workers[i]
+		}
+	}
+	return nil
+}
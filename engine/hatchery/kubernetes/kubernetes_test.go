@@ -0,0 +1,235 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// fakeClientset is an in-memory implementation of the pods API
+type fakeClientset struct {
+	pods    map[string]pod
+	deleted []string
+}
+
+func newFakeClientset(pods ...pod) *fakeClientset {
+	c := &fakeClientset{pods: map[string]pod{}}
+	for _, p := range pods {
+		c.pods[p.Metadata.Name] = p
+	}
+	return c
+}
+
+func (c *fakeClientset) Create(p *pod) (*pod, error) {
+	if _, ok := c.pods[p.Metadata.Name]; ok {
+		return nil, fmt.Errorf("pods %q already exists", p.Metadata.Name)
+	}
+	res := *p
+	res.Metadata.CreationTimestamp = time.Now()
+	res.Status.Phase = podPending
+	c.pods[p.Metadata.Name] = res
+	return &res, nil
+}
+
+func (c *fakeClientset) List(labelSelector string) ([]pod, error) {
+	res := []pod{}
+	for _, p := range c.pods {
+		match := true
+		for _, s := range strings.Split(labelSelector, ",") {
+			kv := strings.SplitN(s, "=", 2)
+			if len(kv) == 2 && p.Metadata.Labels[kv[0]] != kv[1] {
+				match = false
+			}
+		}
+		if match {
+			res = append(res, p)
+		}
+	}
+	return res, nil
+}
+
+func (c *fakeClientset) Delete(name string) error {
+	if _, ok := c.pods[name]; !ok {
+		return fmt.Errorf("pods %q not found", name)
+	}
+	delete(c.pods, name)
+	c.deleted = append(c.deleted, name)
+	return nil
+}
+
+func newTestHatchery(client podsClient) *HatcheryKubernetes {
+	return &HatcheryKubernetes{
+		hatch:              &sdk.Hatchery{ID: 1, Name: "my-hatchery"},
+		token:              "token",
+		client:             client,
+		namespace:          "cds",
+		serviceAccount:     "cds-worker",
+		nodeSelector:       map[string]string{"disktype": "ssd"},
+		defaultMemory:      1024,
+		workerTTL:          10,
+		workerSpawnTimeout: 120,
+	}
+}
+
+func testPod(name, model, phase string, created time.Time) pod {
+	return pod{
+		Metadata: objectMeta{
+			Name:              name,
+			Labels:            map[string]string{labelHatchery: "my-hatchery", labelWorker: name, labelWorkerModel: model},
+			CreationTimestamp: created,
+		},
+		Status: podStatus{Phase: phase},
+	}
+}
+
+func TestSpawnWorker(t *testing.T) {
+	client := newFakeClientset()
+	h := newTestHatchery(client)
+
+	model := &sdk.Model{ID: 42, Name: "Go_Official", Image: "golang:latest", Type: sdk.Docker}
+	job := &sdk.PipelineBuildJob{ID: 666}
+	job.Job.Action.Requirements = []sdk.Requirement{
+		{Name: "mem", Type: sdk.MemoryRequirement, Value: "4096"},
+		{Name: "cpu", Type: sdk.CPURequirement, Value: "1.5"},
+		{Name: "os-arch", Type: sdk.OSArchRequirement, Value: "linux/arm64"},
+		{Name: "pg", Type: sdk.ServiceRequirement, Value: "postgres:9.5 POSTGRES_USER=cds CDS_SERVICE_MEMORY=512"},
+	}
+
+	name, err := h.SpawnWorker(model, job, false, "")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(name, "go-official-"), name)
+
+	p, ok := client.pods[name]
+	assert.True(t, ok)
+	assert.Equal(t, "cds", p.Metadata.Namespace)
+	assert.Equal(t, "my-hatchery", p.Metadata.Labels[labelHatchery])
+	assert.Equal(t, "Go_Official", p.Metadata.Labels[labelWorkerModel])
+	assert.Equal(t, "cds-worker", p.Spec.ServiceAccountName)
+	assert.Equal(t, "ssd", p.Spec.NodeSelector["disktype"])
+	assert.Equal(t, "linux", p.Spec.NodeSelector[labelNodeOS])
+	assert.Equal(t, "arm64", p.Spec.NodeSelector[labelNodeArch])
+	assert.Len(t, h.nodeSelector, 1)
+	assert.Equal(t, "Never", p.Spec.RestartPolicy)
+
+	assert.Len(t, p.Spec.Containers, 2)
+	w := p.Spec.Containers[0]
+	assert.Equal(t, "golang:latest", w.Image)
+	assert.Equal(t, "Always", w.ImagePullPolicy)
+	assert.Equal(t, "4096Mi", w.Resources.Requests["memory"])
+	assert.Equal(t, "1500m", w.Resources.Requests["cpu"])
+	assert.Equal(t, "1500m", w.Resources.Limits["cpu"])
+	assert.Contains(t, w.Env, envVar{Name: "CDS_NAME", Value: name})
+	assert.Contains(t, w.Env, envVar{Name: "CDS_BOOKED_JOB_ID", Value: "666"})
+
+	s := p.Spec.Containers[1]
+	assert.Equal(t, "pg", s.Name)
+	assert.Equal(t, "postgres:9.5", s.Image)
+	assert.Equal(t, "512Mi", s.Resources.Requests["memory"])
+	assert.Equal(t, []envVar{{Name: "POSTGRES_USER", Value: "cds"}}, s.Env)
+	assert.Equal(t, []hostAlias{{IP: "127.0.0.1", Hostnames: []string{"pg"}}}, p.Spec.HostAliases)
+
+	assert.Equal(t, 1, h.WorkersStarted())
+	assert.Equal(t, 1, h.WorkersStartedByModel(model))
+	assert.Equal(t, 0, h.WorkersStartedByModel(&sdk.Model{Name: "other"}))
+
+	assert.NoError(t, h.KillWorker(sdk.Worker{Name: name}))
+	assert.Equal(t, 0, h.WorkersStarted())
+}
+
+func TestSpawnWorkerInvalidRequirement(t *testing.T) {
+	h := newTestHatchery(newFakeClientset())
+	model := &sdk.Model{Name: "go", Image: "golang:1.8"}
+
+	job := &sdk.PipelineBuildJob{ID: 1}
+	job.Job.Action.Requirements = []sdk.Requirement{{Name: "mem", Type: sdk.MemoryRequirement, Value: "a lot"}}
+	_, err := h.SpawnWorker(model, job, false, "")
+	assert.Error(t, err)
+
+	job.Job.Action.Requirements = []sdk.Requirement{{Name: "pg", Type: sdk.ServiceRequirement, Value: ""}}
+	_, err = h.SpawnWorker(model, job, false, "")
+	assert.Error(t, err)
+
+	assert.Equal(t, 0, h.WorkersStarted())
+}
+
+func TestKillAwolPods(t *testing.T) {
+	old := time.Now().Add(-10 * time.Minute)
+	client := newFakeClientset(
+		testPod("running", "go", podRunning, old),
+		testPod("succeeded", "go", podSucceeded, old),
+		testPod("failed", "go", podFailed, time.Now()),
+		testPod("disabled", "go", podRunning, old),
+		testPod("awol", "go", podPending, old),
+		testPod("spawning", "go", podPending, time.Now()),
+	)
+	// pod of another hatchery
+	other := testPod("other", "go", podSucceeded, old)
+	other.Metadata.Labels[labelHatchery] = "other-hatchery"
+	client.pods["other"] = other
+
+	h := newTestHatchery(client)
+	workers := []sdk.Worker{
+		{Name: "running", Status: sdk.StatusBuilding},
+		{Name: "disabled", Status: sdk.StatusDisabled},
+	}
+
+	assert.NoError(t, h.killAwolPods(workers))
+	sort.Strings(client.deleted)
+	assert.Equal(t, []string{"awol", "disabled", "failed", "succeeded"}, client.deleted)
+	assert.Contains(t, client.pods, "running")
+	assert.Contains(t, client.pods, "spawning")
+	assert.Contains(t, client.pods, "other")
+}
+
+func TestPodName(t *testing.T) {
+	assert.Equal(t, "go-official-happy-turing", podName("Go_Official-happy_turing"))
+	assert.Equal(t, 63, len(podName(strings.Repeat("a", 100))))
+	assert.Equal(t, "my.hatchery_1", labelValue("my.hatchery_1"))
+	assert.Equal(t, "host-kubernetes", labelValue("host/kubernetes"))
+}
+
+func TestRestClient(t *testing.T) {
+	var created pod
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/namespaces/cds/pods":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			json.NewEncoder(w).Encode(created)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/namespaces/cds/pods":
+			assert.Equal(t, "cds-hatchery=my-hatchery", r.URL.Query().Get("labelSelector"))
+			json.NewEncoder(w).Encode(podList{Items: []pod{created}})
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/namespaces/cds/pods/unknown":
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(apiStatus{Message: `pods "unknown" not found`, Code: 404})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := newRestClient(ts.URL, "secret", "cds", "", false)
+	assert.NoError(t, err)
+
+	_, err = c.Create(&pod{Metadata: objectMeta{Name: "worker"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Pod", created.Kind)
+	assert.Equal(t, "v1", created.APIVersion)
+
+	pods, err := c.List("cds-hatchery=my-hatchery")
+	assert.NoError(t, err)
+	assert.Len(t, pods, 1)
+
+	err = c.Delete("unknown")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"runtime"
 	"strings"
 	"sync"
 	"time"
@@ -45,7 +46,7 @@ func (*HatcheryLocal) ModelType() string {
 }
 
 // CanSpawn return wether or not hatchery can spawn model.
-// requirements are not supported
+// service and memory requirements are not supported, cpu and os-arch requirements are checked on local host
 func (h *HatcheryLocal) CanSpawn(model *sdk.Model, job *sdk.PipelineBuildJob) bool {
 	if h.Hatchery() == nil {
 		log.Debug("CanSpawn false Hatchery nil")
@@ -56,8 +57,20 @@ func (h *HatcheryLocal) CanSpawn(model *sdk.Model, job *sdk.PipelineBuildJob) bo
 		return false
 	}
 	for _, r := range job.Job.Action.Requirements {
-		if r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement {
+		switch r.Type {
+		case sdk.ServiceRequirement, sdk.MemoryRequirement:
 			return false
+		case sdk.CPURequirement:
+			cpus, err := sdk.ParseCPURequirement(r.Value)
+			if err != nil || cpus > float64(runtime.NumCPU()) {
+				log.Debug("CanSpawn false cpu requirement %s for job %d", r.Value, job.ID)
+				return false
+			}
+		case sdk.OSArchRequirement:
+			if !sdk.MatchOSArch(r.Value, runtime.GOOS, runtime.GOARCH) {
+				log.Debug("CanSpawn false os-arch requirement %s for job %d", r.Value, job.ID)
+				return false
+			}
 		}
 	}
 	log.Debug("CanSpawn true for job %d", job.ID)
@@ -8,6 +8,7 @@ import (
 	"github.com/google/gops/agent"
 
 	"github.com/ovh/cds/engine/hatchery/docker"
+	"github.com/ovh/cds/engine/hatchery/kubernetes"
 	"github.com/ovh/cds/engine/hatchery/local"
 	"github.com/ovh/cds/engine/hatchery/marathon"
 	"github.com/ovh/cds/engine/hatchery/openstack"
@@ -80,6 +81,7 @@ func addCommands() {
 	rootCmd.AddCommand(marathon.Cmd)
 	rootCmd.AddCommand(swarm.Cmd)
 	rootCmd.AddCommand(openstack.Cmd)
+	rootCmd.AddCommand(kubernetes.Cmd)
 	rootCmd.AddCommand(cmdVersion)
 }
 
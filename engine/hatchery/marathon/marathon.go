@@ -156,8 +156,9 @@ func (m *HatcheryMarathon) SpawnWorker(model *sdk.Model, job *sdk.PipelineBuildJ
 		env["CDS_GRPC_INSECURE"] = strconv.FormatBool(viper.GetBool("grpc_insecure"))
 	}
 
-	//Check if there is a memory requirement
+	//Check if there is a memory or a cpu requirement
 	//if there is a service requirement: exit
+	cpus := 0.5
 	if job != nil {
 		logJob = fmt.Sprintf("for job %d,", job.ID)
 		env["CDS_BOOKED_JOB_ID"] = fmt.Sprintf("%d", job.ID)
@@ -175,6 +176,15 @@ func (m *HatcheryMarathon) SpawnWorker(model *sdk.Model, job *sdk.PipelineBuildJ
 					return "", err
 				}
 			}
+
+			if r.Type == sdk.CPURequirement {
+				var err error
+				cpus, err = sdk.ParseCPURequirement(r.Value)
+				if err != nil {
+					log.Warning("spawnMarathonDockerWorker> %s unable to parse cpu requirement %s:%s", logJob, r.Value, err)
+					return "", err
+				}
+			}
 		}
 	}
 
@@ -191,7 +201,7 @@ func (m *HatcheryMarathon) SpawnWorker(model *sdk.Model, job *sdk.PipelineBuildJ
 			},
 			Type: "DOCKER",
 		},
-		CPUs:      0.5,
+		CPUs:      cpus,
 		Env:       &env,
 		Instances: &instance,
 		Mem:       &mem,
@@ -2,12 +2,15 @@ package openstack
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/images"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 
+	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -31,6 +34,68 @@ func (h *HatcheryCloud) flavorID(flavor string) (string, error) {
 	return "", fmt.Errorf("flavorID> flavor '%s' not found", flavor)
 }
 
+// Find the flavor to use for a job: the flavor of the worker model if it matches cpu and disk
+// requirements of the job, else the smallest flavor matching requirements with at least the RAM of the model flavor
+func (h *HatcheryCloud) flavorForRequirements(flavor string, reqs []sdk.Requirement) (string, error) {
+	var cpus float64
+	var disk int64
+	for _, r := range reqs {
+		var err error
+		switch r.Type {
+		case sdk.CPURequirement:
+			cpus, err = sdk.ParseCPURequirement(r.Value)
+		case sdk.DiskRequirement:
+			disk, err = sdk.ParseDiskRequirement(r.Value)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return selectFlavor(h.flavors, flavor, cpus, disk)
+}
+
+func selectFlavor(all []flavors.Flavor, flavor string, cpus float64, disk int64) (string, error) {
+	var model *flavors.Flavor
+	for i := range all {
+		if all[i].Name == flavor {
+			model = &all[i]
+			break
+		}
+	}
+	if model == nil {
+		return "", fmt.Errorf("selectFlavor> flavor '%s' not found", flavor)
+	}
+
+	// disk of flavors is in GB, disk requirement in MB
+	match := func(f *flavors.Flavor) bool {
+		return float64(f.VCPUs) >= cpus && int64(f.Disk)*1024 >= disk
+	}
+	if match(model) {
+		return model.Name, nil
+	}
+
+	candidates := []flavors.Flavor{}
+	for i := range all {
+		if match(&all[i]) && all[i].RAM >= model.RAM {
+			candidates = append(candidates, all[i])
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("selectFlavor> no flavor with %.1f vcpus and %d MB disk found", cpus, disk)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].VCPUs != candidates[j].VCPUs {
+			return candidates[i].VCPUs < candidates[j].VCPUs
+		}
+		if candidates[i].RAM != candidates[j].RAM {
+			return candidates[i].RAM < candidates[j].RAM
+		}
+		return candidates[i].Disk < candidates[j].Disk
+	})
+	return candidates[0].Name, nil
+}
+
 //This a embeded cache for images list
 var limages = struct {
 	mu   sync.RWMutex
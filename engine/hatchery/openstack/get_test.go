@@ -0,0 +1,45 @@
+package openstack
+
+import (
+	"testing"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+)
+
+func Test_selectFlavor(t *testing.T) {
+	all := []flavors.Flavor{
+		{Name: "b2-7", VCPUs: 2, RAM: 7000, Disk: 50},
+		{Name: "b2-15", VCPUs: 4, RAM: 15000, Disk: 100},
+		{Name: "c2-7", VCPUs: 2, RAM: 7000, Disk: 50},
+		{Name: "s1-2", VCPUs: 1, RAM: 2000, Disk: 10},
+		{Name: "s1-8", VCPUs: 2, RAM: 8000, Disk: 40},
+		{Name: "b2-30", VCPUs: 8, RAM: 30000, Disk: 200},
+	}
+
+	tests := []struct {
+		name    string
+		flavor  string
+		cpus    float64
+		disk    int64
+		want    string
+		wantErr bool
+	}{
+		{name: "no requirement", flavor: "s1-2", want: "s1-2"},
+		{name: "model flavor matches", flavor: "b2-7", cpus: 2, disk: 20 * 1024, want: "b2-7"},
+		{name: "smallest flavor with cpus", flavor: "s1-2", cpus: 2, want: "b2-7"},
+		{name: "smallest flavor with disk", flavor: "s1-2", disk: 60 * 1024, want: "b2-15"},
+		{name: "keep ram of model flavor", flavor: "b2-15", cpus: 6, want: "b2-30"},
+		{name: "no flavor", flavor: "s1-2", cpus: 16, wantErr: true},
+		{name: "unknown flavor", flavor: "unknown", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := selectFlavor(all, tt.flavor, tt.cpus, tt.disk)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q. selectFlavor() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q. selectFlavor() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package openstack
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"sync"
@@ -75,13 +76,22 @@ func (*HatcheryCloud) ModelType() string {
 }
 
 // CanSpawn return wether or not hatchery can spawn model
-// requirements are not supported
+// service and memory requirements are not supported, cpu and disk requirements need a matching flavor
 func (h *HatcheryCloud) CanSpawn(model *sdk.Model, job *sdk.PipelineBuildJob) bool {
 	for _, r := range job.Job.Action.Requirements {
 		if r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement {
 			return false
 		}
 	}
+
+	var omd sdk.OpenstackModelData
+	if err := json.Unmarshal([]byte(model.Image), &omd); err != nil {
+		return false
+	}
+	if _, err := h.flavorForRequirements(omd.Flavor, job.Job.Action.Requirements); err != nil {
+		log.Debug("CanSpawn> %s cannot spawn job %d: %s", model.Name, job.ID, err)
+		return false
+	}
 	return true
 }
 
@@ -53,8 +53,16 @@ func (h *HatcheryCloud) SpawnWorker(model *sdk.Model, job *sdk.PipelineBuildJob,
 		return "", erri
 	}
 
-	// Get flavor ID
-	flavorID, errf := h.flavorID(omd.Flavor)
+	// Get flavor ID, a bigger flavor may be used for cpu and disk requirements of the job
+	flavor := omd.Flavor
+	if job != nil {
+		var errfr error
+		flavor, errfr = h.flavorForRequirements(omd.Flavor, job.Job.Action.Requirements)
+		if errfr != nil {
+			return "", errfr
+		}
+	}
+	flavorID, errf := h.flavorID(flavor)
 	if errf != nil {
 		return "", errf
 	}
@@ -204,6 +204,8 @@ func (h *HatcherySwarm) SpawnWorker(model *sdk.Model, job *sdk.PipelineBuildJob,
 
 	//Memory for the worker
 	memory := int64(h.defaultMemory)
+	//CPUs for the worker, no quota by default
+	var cpus float64
 
 	services := []string{}
 
@@ -216,6 +218,13 @@ func (h *HatcherySwarm) SpawnWorker(model *sdk.Model, job *sdk.PipelineBuildJob,
 					log.Warning("SpawnWorker>Unable to parse memory requirement %s :s", memory, err)
 					return "", err
 				}
+			} else if r.Type == sdk.CPURequirement {
+				var err error
+				cpus, err = sdk.ParseCPURequirement(r.Value)
+				if err != nil {
+					log.Warning("SpawnWorker>Unable to parse cpu requirement %s :%s", r.Value, err)
+					return "", err
+				}
 			} else if r.Type == sdk.ServiceRequirement {
 				//name= <alias> => the name of the host put in /etc/hosts of the worker
 				//value= "postgres:latest env_1=blabla env_2=blabla"" => we can add env variables in requirement name
@@ -246,7 +255,7 @@ func (h *HatcherySwarm) SpawnWorker(model *sdk.Model, job *sdk.PipelineBuildJob,
 					"service_name":   serviceName,
 				}
 				//Start the services
-				if err := h.createAndStartContainer(serviceName, img, network, r.Name, []string{}, env, labels, serviceMemory); err != nil {
+				if err := h.createAndStartContainer(serviceName, img, network, r.Name, []string{}, env, labels, serviceMemory, 0); err != nil {
 					log.Warning("SpawnWorker>Unable to start required container: %s", err)
 					return "", err
 				}
@@ -304,7 +313,7 @@ func (h *HatcherySwarm) SpawnWorker(model *sdk.Model, job *sdk.PipelineBuildJob,
 	}
 
 	//start the worker
-	if err := h.createAndStartContainer(name, model.Image, network, "worker", cmd, env, labels, memory); err != nil {
+	if err := h.createAndStartContainer(name, model.Image, network, "worker", cmd, env, labels, memory, cpus); err != nil {
 		log.Warning("SpawnWorker> Unable to start container named %s with image %s err:%s", name, model.Image, err)
 	}
 
@@ -330,8 +339,11 @@ func (h *HatcherySwarm) createNetwork(name string) error {
 	return err
 }
 
+//cpuPeriod is the CFS period in microseconds used for cpu quotas of containers
+const cpuPeriod = 100000
+
 //shortcut to create+start(=run) a container
-func (h *HatcherySwarm) createAndStartContainer(name, image, network, networkAlias string, cmd, env []string, labels map[string]string, memory int64) error {
+func (h *HatcherySwarm) createAndStartContainer(name, image, network, networkAlias string, cmd, env []string, labels map[string]string, memory int64, cpus float64) error {
 	//Memory is set to 1GB by default
 	if memory <= 4 {
 		memory = 1024
@@ -339,7 +351,7 @@ func (h *HatcherySwarm) createAndStartContainer(name, image, network, networkAli
 		//Moaaaaar memory
 		memory = memory * 110 / 100
 	}
-	log.Info("createAndStartContainer> Create container %s from %s on network %s as %s (memory=%dMB)", name, image, network, networkAlias, memory)
+	log.Info("createAndStartContainer> Create container %s from %s on network %s as %s (memory=%dMB cpus=%.2f)", name, image, network, networkAlias, memory, cpus)
 	opts := docker.CreateContainerOptions{
 		Name: name,
 		Config: &docker.Config{
@@ -359,6 +371,14 @@ func (h *HatcherySwarm) createAndStartContainer(name, image, network, networkAli
 		},
 	}
 
+	//CPU quota, no limit if cpus is 0
+	if cpus > 0 {
+		opts.HostConfig = &docker.HostConfig{
+			CPUPeriod: cpuPeriod,
+			CPUQuota:  int64(cpus * cpuPeriod),
+		}
+	}
+
 	c, err := h.dockerClient.CreateContainer(opts)
 	if err != nil {
 		log.Warning("startAndCreateContainer> Unable to create container with opts: %+v err:%s", opts, err)
@@ -0,0 +1,28 @@
+-- +migrate Up
+ALTER TABLE workflow_node_run ADD COLUMN static_analysis JSONB;
+
+CREATE TABLE IF NOT EXISTS "workflow_node_run_finding" (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_run_id BIGINT NOT NULL,
+    workflow_node_run_id BIGINT NOT NULL,
+    workflow_id BIGINT NOT NULL,
+    workflow_node_name TEXT NOT NULL,
+    tool TEXT,
+    rule_id TEXT,
+    severity TEXT NOT NULL,
+    message TEXT,
+    file TEXT,
+    line INT DEFAULT 0,
+    col INT DEFAULT 0,
+    fingerprint TEXT NOT NULL,
+    is_new BOOLEAN DEFAULT false,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_FINDING_WORKFLOW_RUN', 'workflow_node_run_finding', 'workflow_run', 'workflow_run_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_FINDING_WORKFLOW_NODE_RUN', 'workflow_node_run_finding', 'workflow_node_run', 'workflow_node_run_id', 'id');
+SELECT create_index('workflow_node_run_finding', 'IDX_WORKFLOW_NODE_RUN_FINDING_WORKFLOW_NODE', 'workflow_id, workflow_node_name');
+
+-- +migrate Down
+DROP TABLE workflow_node_run_finding;
+ALTER TABLE workflow_node_run DROP COLUMN static_analysis;
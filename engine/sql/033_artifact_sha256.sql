@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE artifact ADD COLUMN sha256sum TEXT;
+ALTER TABLE workflow_node_run_artifacts ADD COLUMN sha256sum TEXT;
+
+-- +migrate Down
+ALTER TABLE artifact DROP COLUMN sha256sum;
+ALTER TABLE workflow_node_run_artifacts DROP COLUMN sha256sum;
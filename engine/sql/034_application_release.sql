@@ -0,0 +1,30 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "application_release" (
+    id BIGSERIAL PRIMARY KEY,
+    application_id BIGINT NOT NULL,
+    version TEXT NOT NULL,
+    workflow_name TEXT NOT NULL,
+    workflow_run_number BIGINT NOT NULL,
+    author TEXT,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_APPLICATION_RELEASE_APPLICATION', 'application_release', 'application', 'application_id', 'id');
+SELECT create_unique_index('application_release', 'IDX_APPLICATION_RELEASE_VERSION', 'application_id,version');
+
+CREATE TABLE IF NOT EXISTS "application_release_artifact" (
+    id BIGSERIAL PRIMARY KEY,
+    release_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    size BIGINT,
+    perm INT,
+    md5sum TEXT,
+    sha256sum TEXT,
+    object_path TEXT
+);
+
+SELECT create_foreign_key_idx_cascade('FK_APPLICATION_RELEASE_ARTIFACT_RELEASE', 'application_release_artifact', 'application_release', 'release_id', 'id');
+
+-- +migrate Down
+DROP TABLE application_release_artifact;
+DROP TABLE application_release;
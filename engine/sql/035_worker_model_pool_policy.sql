@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE worker_model ADD COLUMN pool_policy JSONB;
+
+-- +migrate Down
+ALTER TABLE worker_model DROP COLUMN pool_policy;
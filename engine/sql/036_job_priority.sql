@@ -0,0 +1,15 @@
+-- +migrate Up
+ALTER TABLE project ADD COLUMN job_priority INT;
+ALTER TABLE workflow ADD COLUMN job_priority INT;
+ALTER TABLE pipeline_build ADD COLUMN priority INT;
+ALTER TABLE pipeline_build_job ADD COLUMN priority INT NOT NULL DEFAULT 50;
+ALTER TABLE workflow_node_run_job ADD COLUMN priority INT NOT NULL DEFAULT 50;
+ALTER TABLE "group" ADD COLUMN max_concurrent_jobs INT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE project DROP COLUMN job_priority;
+ALTER TABLE workflow DROP COLUMN job_priority;
+ALTER TABLE pipeline_build DROP COLUMN priority;
+ALTER TABLE pipeline_build_job DROP COLUMN priority;
+ALTER TABLE workflow_node_run_job DROP COLUMN priority;
+ALTER TABLE "group" DROP COLUMN max_concurrent_jobs;
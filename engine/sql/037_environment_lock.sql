@@ -0,0 +1,28 @@
+-- +migrate Up
+ALTER TABLE environment ADD COLUMN exclusive BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE environment ADD COLUMN lock_policy VARCHAR(50) NOT NULL DEFAULT 'queue';
+
+CREATE TABLE IF NOT EXISTS "environment_exclusive_application" (
+    environment_id BIGINT NOT NULL,
+    application_id BIGINT NOT NULL,
+    PRIMARY KEY (environment_id, application_id)
+);
+SELECT create_foreign_key_idx_cascade('FK_ENVIRONMENT_EXCLUSIVE_APPLICATION_ENVIRONMENT', 'environment_exclusive_application', 'environment', 'environment_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_ENVIRONMENT_EXCLUSIVE_APPLICATION_APPLICATION', 'environment_exclusive_application', 'application', 'application_id', 'id');
+
+CREATE TABLE IF NOT EXISTS "environment_lock" (
+    environment_id BIGINT NOT NULL,
+    application_id BIGINT NOT NULL DEFAULT 0,
+    pipeline_build_id BIGINT NOT NULL DEFAULT 0,
+    workflow_node_run_id BIGINT NOT NULL DEFAULT 0,
+    holder VARCHAR(256) NOT NULL DEFAULT '',
+    since TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    PRIMARY KEY (environment_id, application_id)
+);
+SELECT create_foreign_key_idx_cascade('FK_ENVIRONMENT_LOCK_ENVIRONMENT', 'environment_lock', 'environment', 'environment_id', 'id');
+
+-- +migrate Down
+DROP TABLE environment_lock;
+DROP TABLE environment_exclusive_application;
+ALTER TABLE environment DROP COLUMN exclusive;
+ALTER TABLE environment DROP COLUMN lock_policy;
@@ -0,0 +1,25 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "approval" (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    pipeline_build_id BIGINT NOT NULL DEFAULT 0,
+    workflow_node_run_id BIGINT NOT NULL DEFAULT 0,
+    description VARCHAR(256) NOT NULL DEFAULT '',
+    status VARCHAR(50) NOT NULL,
+    committer VARCHAR(256) NOT NULL DEFAULT '',
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    last_modified TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    gate JSONB,
+    votes JSONB
+);
+SELECT create_foreign_key_idx_cascade('FK_APPROVAL_PROJECT', 'approval', 'project', 'project_id', 'id');
+SELECT create_index('approval', 'IDX_APPROVAL_PIPELINE_BUILD_ID', 'pipeline_build_id');
+SELECT create_index('approval', 'IDX_APPROVAL_WORKFLOW_NODE_RUN_ID', 'workflow_node_run_id');
+
+ALTER TABLE pipeline_trigger ADD COLUMN approval JSONB;
+ALTER TABLE workflow_node_context ADD COLUMN approval JSONB;
+
+-- +migrate Down
+DROP TABLE approval;
+ALTER TABLE pipeline_trigger DROP COLUMN approval;
+ALTER TABLE workflow_node_context DROP COLUMN approval;
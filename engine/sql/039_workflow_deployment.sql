@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_deployment" (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    project_key VARCHAR(256) NOT NULL,
+    workflow_id BIGINT NOT NULL,
+    workflow_name VARCHAR(256) NOT NULL,
+    workflow_run_id BIGINT NOT NULL,
+    workflow_node_id BIGINT NOT NULL,
+    workflow_node_run_id BIGINT NOT NULL,
+    num BIGINT NOT NULL,
+    sub_num BIGINT NOT NULL,
+    application_id BIGINT NOT NULL,
+    application_name VARCHAR(256) NOT NULL,
+    environment_id BIGINT NOT NULL,
+    environment_name VARCHAR(256) NOT NULL,
+    version VARCHAR(256) NOT NULL DEFAULT '',
+    git_branch VARCHAR(256) NOT NULL DEFAULT '',
+    git_hash VARCHAR(256) NOT NULL DEFAULT '',
+    triggered_by VARCHAR(256) NOT NULL DEFAULT '',
+    status VARCHAR(50) NOT NULL,
+    start TIMESTAMP WITH TIME ZONE,
+    done TIMESTAMP WITH TIME ZONE,
+    rollback_of BIGINT NOT NULL DEFAULT 0,
+    artifacts JSONB
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_DEPLOYMENT_PROJECT', 'workflow_deployment', 'project', 'project_id', 'id');
+SELECT create_unique_index('workflow_deployment', 'IDX_WORKFLOW_DEPLOYMENT_NODE_RUN', 'workflow_node_run_id');
+SELECT create_index('workflow_deployment', 'IDX_WORKFLOW_DEPLOYMENT_ENVIRONMENT', 'environment_name, application_name');
+
+-- +migrate Down
+DROP TABLE workflow_deployment;
@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "object_history" (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    object_type VARCHAR(50) NOT NULL,
+    object_id BIGINT NOT NULL,
+    object_name VARCHAR(256) NOT NULL,
+    version BIGINT NOT NULL,
+    author VARCHAR(256) NOT NULL DEFAULT '',
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    snapshot JSONB
+);
+SELECT create_foreign_key_idx_cascade('FK_OBJECT_HISTORY_PROJECT', 'object_history', 'project', 'project_id', 'id');
+SELECT create_unique_index('object_history', 'IDX_OBJECT_HISTORY_VERSION', 'object_type, object_id, version');
+
+-- +migrate Down
+DROP TABLE object_history;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE poller ADD COLUMN filter JSONB;
+ALTER TABLE hook ADD COLUMN filter JSONB;
+
+-- +migrate Down
+ALTER TABLE poller DROP COLUMN filter;
+ALTER TABLE hook DROP COLUMN filter;
@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "chatops_account" (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    team_id VARCHAR(256) NOT NULL,
+    chat_user_id VARCHAR(256) NOT NULL,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_unique_index('chatops_account', 'IDX_CHATOPS_ACCOUNT_PROVIDER_TEAM_USER', 'provider,team_id,chat_user_id');
+SELECT create_foreign_key_idx_cascade('FK_CHATOPS_ACCOUNT_USER', 'chatops_account', 'user', 'user_id', 'id');
+
+-- +migrate Down
+DROP TABLE chatops_account;
@@ -0,0 +1,21 @@
+-- +migrate Up
+ALTER TABLE "plugin" ADD COLUMN version TEXT NOT NULL DEFAULT '';
+ALTER TABLE "plugin" ADD COLUMN os TEXT NOT NULL DEFAULT '';
+ALTER TABLE "plugin" ADD COLUMN arch TEXT NOT NULL DEFAULT '';
+ALTER TABLE "plugin" ADD COLUMN sha256sum TEXT NOT NULL DEFAULT '';
+ALTER TABLE "plugin" ADD COLUMN is_default BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE "plugin" ADD COLUMN deprecated BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE "plugin" ADD COLUMN created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP;
+UPDATE "plugin" SET is_default = true;
+
+SELECT create_unique_index('plugin', 'IDX_PLUGIN_NAME_VERSION_OS_ARCH', 'name,version,os,arch');
+
+-- +migrate Down
+DROP INDEX IDX_PLUGIN_NAME_VERSION_OS_ARCH;
+ALTER TABLE "plugin" DROP COLUMN version;
+ALTER TABLE "plugin" DROP COLUMN os;
+ALTER TABLE "plugin" DROP COLUMN arch;
+ALTER TABLE "plugin" DROP COLUMN sha256sum;
+ALTER TABLE "plugin" DROP COLUMN is_default;
+ALTER TABLE "plugin" DROP COLUMN deprecated;
+ALTER TABLE "plugin" DROP COLUMN created;
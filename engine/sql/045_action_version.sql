@@ -0,0 +1,18 @@
+-- +migrate Up
+ALTER TABLE "action" ADD COLUMN project_id BIGINT;
+ALTER TABLE "action_edge" ADD COLUMN child_version BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS "action_version_deprecated" (
+    action_id BIGINT NOT NULL,
+    version BIGINT NOT NULL,
+    PRIMARY KEY(action_id, version)
+);
+
+SELECT create_foreign_key_idx_cascade('FK_ACTION_PROJECT', 'action', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_ACTION_VERSION_DEPRECATED_ACTION', 'action_version_deprecated', 'action', 'action_id', 'id');
+
+-- +migrate Down
+DROP TABLE action_version_deprecated;
+ALTER TABLE "action_edge" DROP COLUMN child_version;
+ALTER TABLE "action" DROP CONSTRAINT FK_ACTION_PROJECT;
+ALTER TABLE "action" DROP COLUMN project_id;
@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "action_source" (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    name VARCHAR(256) NOT NULL,
+    repositories_manager VARCHAR(256) NOT NULL DEFAULT '',
+    repository_fullname VARCHAR(512) NOT NULL DEFAULT '',
+    url VARCHAR(1024) NOT NULL DEFAULT '',
+    key_name VARCHAR(256) NOT NULL DEFAULT '',
+    branch VARCHAR(256) NOT NULL DEFAULT '',
+    directory VARCHAR(1024) NOT NULL DEFAULT '',
+    last_commit VARCHAR(256) NOT NULL DEFAULT '',
+    last_sync TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT LOCALTIMESTAMP,
+    sync_errors JSONB
+);
+
+CREATE TABLE IF NOT EXISTS "action_source_action" (
+    action_id BIGINT PRIMARY KEY,
+    source_id BIGINT NOT NULL,
+    path VARCHAR(1024) NOT NULL,
+    commit VARCHAR(256) NOT NULL,
+    hash VARCHAR(256) NOT NULL
+);
+
+SELECT create_unique_index('action_source', 'IDX_ACTION_SOURCE_PROJECT_NAME', 'project_id,name');
+SELECT create_foreign_key_idx_cascade('FK_ACTION_SOURCE_PROJECT', 'action_source', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_ACTION_SOURCE_ACTION_ACTION', 'action_source_action', 'action', 'action_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_ACTION_SOURCE_ACTION_SOURCE', 'action_source_action', 'action_source', 'source_id', 'id');
+
+-- +migrate Down
+DROP TABLE action_source_action;
+DROP TABLE action_source;
@@ -0,0 +1,23 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "audit_event" (
+    id BIGSERIAL PRIMARY KEY,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    username VARCHAR(256) NOT NULL,
+    admin BOOLEAN NOT NULL DEFAULT false,
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(512) NOT NULL,
+    path TEXT NOT NULL,
+    target_type VARCHAR(256) NOT NULL DEFAULT '',
+    target TEXT NOT NULL DEFAULT '',
+    status INT NOT NULL,
+    source_ip VARCHAR(256) NOT NULL DEFAULT '',
+    before_summary TEXT NOT NULL DEFAULT '',
+    after_summary TEXT NOT NULL DEFAULT ''
+);
+
+SELECT create_index('audit_event', 'IDX_AUDIT_EVENT_CREATED', 'created');
+SELECT create_index('audit_event', 'IDX_AUDIT_EVENT_USERNAME', 'username');
+SELECT create_index('audit_event', 'IDX_AUDIT_EVENT_TARGET_TYPE', 'target_type');
+
+-- +migrate Down
+DROP TABLE audit_event;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE approval ADD COLUMN notified BOOLEAN NOT NULL DEFAULT true;
+
+-- +migrate Down
+ALTER TABLE approval DROP COLUMN notified;
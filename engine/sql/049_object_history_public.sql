@@ -0,0 +1,6 @@
+-- +migrate Up
+ALTER TABLE "object_history" ALTER COLUMN project_id DROP NOT NULL;
+
+-- +migrate Down
+DELETE FROM "object_history" WHERE project_id IS NULL;
+ALTER TABLE "object_history" ALTER COLUMN project_id SET NOT NULL;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE workflow_run ADD COLUMN priority INT;
+
+-- +migrate Down
+ALTER TABLE workflow_run DROP COLUMN priority;
@@ -3,8 +3,8 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
-	"path"
 	"strings"
 
 	"github.com/ovh/cds/sdk"
@@ -20,6 +20,7 @@ func init() {
 	mapBuiltinActions[sdk.ScriptAction] = runScriptAction
 	mapBuiltinActions[sdk.JUnitAction] = runParseJunitTestResultAction
 	mapBuiltinActions[sdk.GitCloneAction] = runGitClone
+	mapBuiltinActions[sdk.StaticAnalysisAction] = runStaticAnalysis
 }
 
 // BuiltInAction defines builtin action signature
@@ -59,35 +60,42 @@ func (w *currentWorker) runBuiltin(ctx context.Context, a *sdk.Action, buildID i
 }
 
 func (w *currentWorker) runPlugin(ctx context.Context, a *sdk.Action, buildID int64, params []sdk.Parameter, stepOrder int, sendLog LoggerFunc) sdk.Result {
-	chanRes := make(chan sdk.Result)
+	chanRes := make(chan sdk.Result, 1)
 
 	go func(buildID int64, params []sdk.Parameter) {
 		res := sdk.Result{Status: sdk.StatusFail.String()}
 
-		//For the moment we consider that plugin name = action name = plugin binary file name
+		//For the moment we consider that plugin name = action name
 		pluginName := a.Name
-		//The binary file has been downloaded during requirement check in /tmp
-		pluginBinary := path.Join(w.basedir, a.Name)
+		//The binary file has been downloaded during requirement check, get the one of the pinned version
+		pluginBinary, err := w.installPlugin(pluginName, w.pluginVersion(pluginName))
+		if err != nil {
+			result := sdk.Result{
+				Status: sdk.StatusFail.String(),
+				Reason: fmt.Sprintf("Unable to get plugin %s: %s\n", pluginName, err),
+			}
+			sendLog(result.Reason)
+			chanRes <- result
+			return
+		}
 
 		var tlsskipverify bool
 		if os.Getenv("CDS_SKIP_VERIFY") != "" {
 			tlsskipverify = true
 		}
 
-		//Create the rpc server
-		pluginClient := plugin.NewClient(ctx, pluginName, pluginBinary, w.id, w.apiEndpoint, tlsskipverify)
-		defer pluginClient.Kill()
-
-		//Get the plugin interface
-		_plugin, err := pluginClient.Instance()
+		//Start the plugin and negotiate the protocol
+		pluginConn, err := plugin.Dial(ctx, pluginName, pluginBinary, w.id, w.apiEndpoint, tlsskipverify)
 		if err != nil {
 			result := sdk.Result{
 				Status: sdk.StatusFail.String(),
-				Reason: fmt.Sprintf("Unable to init plugin %s: %s\n", pluginName, err),
+				Reason: fmt.Sprintf("Unable to start plugin %s: %s\n", pluginName, err),
 			}
 			sendLog(result.Reason)
 			chanRes <- result
+			return
 		}
+		defer pluginConn.Close()
 
 		//Manage all parameters
 		pluginArgs := plugin.Arguments{
@@ -109,6 +117,31 @@ func (w *currentWorker) runPlugin(ctx context.Context, a *sdk.Action, buildID in
 			id = w.currentJob.wJob.WorkflowNodeRunID
 		}
 
+		if pluginConn.ProtocolVersion == plugin.GRPCProtocolVersion {
+			chanRes <- w.runGRPCPlugin(ctx, pluginConn, &plugin.RunQuery{
+				JobID:         buildID,
+				BuildID:       id,
+				StepOrder:     int32(stepOrder),
+				Arguments:     pluginArgs.Data,
+				Url:           w.apiEndpoint,
+				Hash:          w.id,
+				TlsSkipVerify: tlsskipverify,
+			}, sendLog)
+			return
+		}
+
+		//Get the plugin interface
+		_plugin, err := pluginConn.Client.Instance()
+		if err != nil {
+			result := sdk.Result{
+				Status: sdk.StatusFail.String(),
+				Reason: fmt.Sprintf("Unable to init plugin %s: %s\n", pluginName, err),
+			}
+			sendLog(result.Reason)
+			chanRes <- result
+			return
+		}
+
 		pluginAction := plugin.Job{
 			IDPipelineBuild:    id,
 			IDPipelineJobBuild: buildID,
@@ -139,3 +172,57 @@ func (w *currentWorker) runPlugin(ctx context.Context, a *sdk.Action, buildID in
 		}
 	}
 }
+
+//runGRPCPlugin runs a plugin served with the gRPC protocol: logs are streamed into the step log and outputs are
+//exported as build variables. The plugin is canceled with the context.
+func (w *currentWorker) runGRPCPlugin(ctx context.Context, conn *plugin.Conn, q *plugin.RunQuery, sendLog LoggerFunc) sdk.Result {
+	stream, err := conn.Run(ctx, q)
+	if err != nil {
+		res := sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: fmt.Sprintf("Unable to run plugin: %s\n", err),
+		}
+		sendLog(res.Reason)
+		return res
+	}
+
+	for {
+		e, err := stream.Recv()
+		if err != nil {
+			res := sdk.Result{
+				Status: sdk.StatusFail.String(),
+				Reason: fmt.Sprintf("Plugin has stopped unexpectedly: %s\n", err),
+			}
+			if err == io.EOF {
+				res.Reason = "Plugin has stopped without result\n"
+			}
+			sendLog(res.Reason)
+			return res
+		}
+
+		switch {
+		case e.Result != nil:
+			if e.Result.Status == plugin.Success {
+				return sdk.Result{Status: sdk.StatusSuccess.String()}
+			}
+			if e.Result.Reason != "" {
+				sendLog(e.Result.Reason)
+			}
+			return sdk.Result{Status: sdk.StatusFail.String(), Reason: e.Result.Reason}
+		case e.Output != nil:
+			v := sdk.Variable{
+				Name:  e.Output.Name,
+				Type:  sdk.StringVariable,
+				Value: e.Output.Value,
+			}
+			if err := w.addBuildVariable(v); err != nil {
+				log.Error("runGRPCPlugin> Cannot export variable %s: %s", v.Name, err)
+				sendLog(fmt.Sprintf("Unable to export variable %s: %s", v.Name, err))
+			}
+		case e.Progress != nil:
+			sendLog(fmt.Sprintf("[%d%%] %s", e.Progress.Percent, e.Progress.Message))
+		default:
+			sendLog(e.Log)
+		}
+	}
+}
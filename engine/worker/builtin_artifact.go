@@ -1,84 +1,27 @@
 package main
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
 
 func runArtifactUpload(w *currentWorker) BuiltInAction {
-	if w.currentJob.wJob == nil {
-		return func(ctx context.Context, a *sdk.Action, buildID int64, params []sdk.Parameter, sendLog LoggerFunc) sdk.Result {
-			res := sdk.Result{Status: sdk.StatusSuccess.String()}
-
-			pipeline := sdk.ParameterValue(params, "cds.pipeline")
-			project := sdk.ParameterValue(params, "cds.project")
-			application := sdk.ParameterValue(params, "cds.application")
-			environment := sdk.ParameterValue(params, "cds.environment")
-			buildNumberString := sdk.ParameterValue(params, "cds.buildNumber")
-
-			path := sdk.ParameterValue(a.Parameters, "path")
-			if path == "" {
-				path = "."
-			}
-
-			tag := sdk.ParameterFind(a.Parameters, "tag")
-			if tag == nil {
-				res.Status = sdk.StatusFail.String()
-				res.Reason = fmt.Sprintf("tag variable is empty. aborting")
-				sendLog(res.Reason)
-				return res
-			}
-			tag.Value = strings.Replace(tag.Value, "/", "-", -1)
-			tag.Value = url.QueryEscape(tag.Value)
-
-			// Global all files matching filePath
-			filesPath, err := filepath.Glob(path)
-			if err != nil {
-				res.Status = sdk.StatusFail.String()
-				res.Reason = fmt.Sprintf("cannot perform globbing of pattern '%s': %s", path, err)
-				sendLog(res.Reason)
-				return res
-			}
-
-			if len(filesPath) == 0 {
-				res.Status = sdk.StatusFail.String()
-				res.Reason = fmt.Sprintf("Pattern '%s' matched no file", path)
-				sendLog(res.Reason)
-				return res
-			}
-
-			buildNumber, errBN := strconv.Atoi(buildNumberString)
-			if errBN != nil {
-				res.Status = sdk.StatusFail.String()
-				res.Reason = fmt.Sprintf("BuilNumber is not an integer %s\n", errBN)
-				sendLog(res.Reason)
-				return res
-			}
-
-			for _, filePath := range filesPath {
-				filename := filepath.Base(filePath)
-				sendLog(fmt.Sprintf("Uploading '%s'\n", filename))
-				//TODO send artifact on workflow
-				if err := sdk.UploadArtifact(project, pipeline, application, tag.Value, filePath, buildNumber, environment); err != nil {
-					res.Status = sdk.StatusFail.String()
-					res.Reason = fmt.Sprintf("Error while uploading artefact: %s\n", err)
-					sendLog(res.Reason)
-					return res
-				}
-			}
-
-			return res
-		}
-	}
-
 	return func(ctx context.Context, a *sdk.Action, buildID int64, params []sdk.Parameter, sendLog LoggerFunc) sdk.Result {
 		res := sdk.Result{Status: sdk.StatusSuccess.String()}
 
@@ -86,6 +29,7 @@ func runArtifactUpload(w *currentWorker) BuiltInAction {
 		if path == "" {
 			path = "."
 		}
+		archive := sdk.ParameterValue(a.Parameters, "archive")
 
 		tag := sdk.ParameterFind(a.Parameters, "tag")
 		if tag == nil {
@@ -98,7 +42,7 @@ func runArtifactUpload(w *currentWorker) BuiltInAction {
 		tag.Value = url.QueryEscape(tag.Value)
 
 		// Global all files matching filePath
-		filesPath, err := filepath.Glob(path)
+		filesPath, err := artifactFiles(path)
 		if err != nil {
 			res.Status = sdk.StatusFail.String()
 			res.Reason = fmt.Sprintf("cannot perform globbing of pattern '%s': %s", path, err)
@@ -113,11 +57,59 @@ func runArtifactUpload(w *currentWorker) BuiltInAction {
 			return res
 		}
 
+		if archive != "" {
+			sendLog(fmt.Sprintf("Archiving %d file(s) in '%s'\n", len(filesPath), archive))
+			archivePath, errA := createArtifactArchive(filesPath, archive)
+			if errA != nil {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = fmt.Sprintf("Error while creating archive %s: %s\n", archive, errA)
+				sendLog(res.Reason)
+				return res
+			}
+			defer os.RemoveAll(filepath.Dir(archivePath))
+			filesPath = []string{archivePath}
+		} else {
+			// Artifacts are stored by name, files with the same name in different directories would overwrite each other
+			names := map[string]string{}
+			for _, filePath := range filesPath {
+				filename := filepath.Base(filePath)
+				if other, ok := names[filename]; ok {
+					res.Status = sdk.StatusFail.String()
+					res.Reason = fmt.Sprintf("Files '%s' and '%s' have the same name, use the archive parameter to upload them\n", other, filePath)
+					sendLog(res.Reason)
+					return res
+				}
+				names[filename] = filePath
+			}
+		}
+
+		upload := func(filePath string) error {
+			return w.client.QueueArtifactUpload(buildID, tag.Value, filePath)
+		}
+		if w.currentJob.wJob == nil {
+			pipeline := sdk.ParameterValue(params, "cds.pipeline")
+			project := sdk.ParameterValue(params, "cds.project")
+			application := sdk.ParameterValue(params, "cds.application")
+			environment := sdk.ParameterValue(params, "cds.environment")
+			buildNumberString := sdk.ParameterValue(params, "cds.buildNumber")
+
+			buildNumber, errBN := strconv.Atoi(buildNumberString)
+			if errBN != nil {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = fmt.Sprintf("BuilNumber is not an integer %s\n", errBN)
+				sendLog(res.Reason)
+				return res
+			}
+
+			upload = func(filePath string) error {
+				return sdk.UploadArtifact(project, pipeline, application, tag.Value, filePath, buildNumber, environment)
+			}
+		}
+
 		for _, filePath := range filesPath {
 			filename := filepath.Base(filePath)
 			sendLog(fmt.Sprintf("Uploading '%s'\n", filename))
-			//TODO send artifact on workflow
-			if err := w.client.QueueArtifactUpload(buildID, tag.Value, filename); err != nil {
+			if err := upload(filePath); err != nil {
 				res.Status = sdk.StatusFail.String()
 				res.Reason = fmt.Sprintf("Error while uploading artefact: %s\n", err)
 				sendLog(res.Reason)
@@ -187,64 +179,274 @@ func runArtifactDownload(w *currentWorker) BuiltInAction {
 
 		path := sdk.ParameterValue(a.Parameters, "path")
 		tag := sdk.ParameterValue(a.Parameters, "tag")
+		fromWorkflow := sdk.ParameterValue(a.Parameters, "workflow")
+		branch := sdk.ParameterValue(a.Parameters, "branch")
+		release := sdk.ParameterValue(a.Parameters, "release")
+		application := sdk.ParameterValue(a.Parameters, "application")
+		if application == "" {
+			application = sdk.ParameterValue(params, "cds.application")
+		}
 
 		if !enabled {
 			sendLog("Artifact Download is disabled.")
 			return res
 		}
 
-		if tag == "" {
+		fail := func(err error) sdk.Result {
 			res.Status = sdk.StatusFail.String()
-			res.Reason = fmt.Sprintf("tag variable is empty. aborting")
+			res.Reason = err.Error()
+			log.Warning("Cannot download artifacts: %s", err)
 			sendLog(res.Reason)
 			return res
 		}
-		tag = strings.Replace(tag, "/", "-", -1)
-		tag = url.QueryEscape(tag)
 
-		sendLog(fmt.Sprintf("Downloading artifacts from into '%s'...", path))
-
-		n, err := strconv.ParseInt(number, 10, 64)
-		if err != nil {
-			res.Status = sdk.StatusFail.String()
-			res.Reason = fmt.Sprintf("cds.run.nubmer variable is not valid. aborting\n")
-			sendLog(res.Reason)
-			return res
+		if path != "" {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return fail(err)
+			}
 		}
-		artifacts, err := w.client.WorkflowRunArtifacts(project, workflow, n)
-		if err != nil {
-			res.Status = sdk.StatusFail.String()
-			res.Reason = err.Error()
-			log.Warning("Cannot download artifacts: %s", err)
-			sendLog(res.Reason)
+
+		// Download the artifacts of a release of the application
+		if release != "" {
+			if application == "" {
+				return fail(fmt.Errorf("application variable is empty. aborting"))
+			}
+			sendLog(fmt.Sprintf("Downloading artifacts of release %s of application %s into '%s'...", release, application, path))
+			rel, err := w.client.ApplicationReleaseGet(project, application, release)
+			if err != nil {
+				return fail(err)
+			}
+			for _, ra := range rel.Artifacts {
+				destPath := filepath.Join(path, ra.Name)
+				if err := downloadFile(destPath, ra.Perm, ra.SHA256Sum, func(wr io.Writer) error {
+					return w.client.ApplicationReleaseArtifactDownload(project, application, release, ra.Name, wr)
+				}); err != nil {
+					return fail(fmt.Errorf("Cannot download artifact %s: %s", ra.Name, err))
+				}
+			}
 			return res
 		}
 
-		for _, a := range artifacts {
-			f, err := os.OpenFile(a.Name, os.O_RDWR|os.O_CREATE, os.FileMode(a.Perm))
+		// Artifact tags are stored as escaped by the upload
+		tag = strings.Replace(tag, "/", "-", -1)
+		tag = url.QueryEscape(tag)
+
+		var artifacts []sdk.Artifact
+		if fromWorkflow != "" || branch != "" {
+			// Download the artifacts of the last successful run of a workflow
+			if fromWorkflow == "" {
+				fromWorkflow = workflow
+			}
+			sendLog(fmt.Sprintf("Downloading artifacts of the last successful run of workflow %s into '%s'...", fromWorkflow, path))
+			arts, err := w.client.WorkflowLatestArtifacts(project, fromWorkflow, branch)
 			if err != nil {
-				res.Status = sdk.StatusFail.String()
-				res.Reason = err.Error()
-				log.Warning("Cannot download artifacts: %s", err)
-				sendLog(res.Reason)
-				return res
+				return fail(err)
 			}
-			if err := w.client.WorkflowNodeRunArtifactDownload(project, workflow, a.ID, f); err != nil {
-				res.Status = sdk.StatusFail.String()
-				res.Reason = err.Error()
-				log.Warning("Cannot download artifacts: %s", err)
-				sendLog(res.Reason)
-				return res
+			for _, art := range arts {
+				if tag == "" || art.Tag == tag {
+					artifacts = append(artifacts, art)
+				}
 			}
-			if err := f.Close(); err != nil {
-				res.Status = sdk.StatusFail.String()
-				res.Reason = err.Error()
-				log.Warning("Cannot download artifacts: %s", err)
-				sendLog(res.Reason)
-				return res
+			workflow = fromWorkflow
+		} else {
+			if tag == "" {
+				return fail(fmt.Errorf("tag variable is empty. aborting"))
+			}
+
+			sendLog(fmt.Sprintf("Downloading artifacts from into '%s'...", path))
+
+			n, err := strconv.ParseInt(number, 10, 64)
+			if err != nil {
+				return fail(fmt.Errorf("cds.run.nubmer variable is not valid. aborting"))
+			}
+			artifacts, err = w.client.WorkflowRunArtifacts(project, workflow, n)
+			if err != nil {
+				return fail(err)
+			}
+		}
+
+		for _, a := range artifacts {
+			destPath := filepath.Join(path, a.Name)
+			if err := w.downloadArtifact(project, workflow, a, destPath); err != nil {
+				return fail(fmt.Errorf("Cannot download artifact %s: %s", a.Name, err))
 			}
 		}
 
 		return res
 	}
 }
+
+// downloadArtifact downloads a workflow artifact and checks its sha256 sum
+func (w *currentWorker) downloadArtifact(project, workflow string, a sdk.Artifact, destPath string) error {
+	return downloadFile(destPath, a.Perm, a.SHA256Sum, func(wr io.Writer) error {
+		return w.client.WorkflowNodeRunArtifactDownload(project, workflow, a.ID, wr)
+	})
+}
+
+// downloadFile writes a downloaded file to destPath and checks its sha256 sum. The file is removed on failure
+func downloadFile(destPath string, perm uint32, sha256sum string, download func(io.Writer) error) error {
+	mode := os.FileMode(0644)
+	if perm != 0 {
+		mode = os.FileMode(perm)
+	}
+	f, err := os.OpenFile(destPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	h := sha256.New()
+	err = download(io.MultiWriter(f, h))
+	if errC := f.Close(); err == nil {
+		err = errC
+	}
+	if err == nil {
+		err = sdk.CheckSHA256(sha256sum, h.Sum(nil))
+	}
+	if err != nil {
+		os.Remove(destPath)
+		return err
+	}
+	return nil
+}
+
+// artifactFiles returns the files matching the patterns. Directories are walked recursively
+func artifactFiles(patterns string) ([]string, error) {
+	matches, err := globFiles(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, m := range matches {
+		fi, err := os.Stat(m)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			files = append(files, m)
+			continue
+		}
+		if err := filepath.Walk(m, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.Mode().IsRegular() {
+				files = append(files, p)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// createArtifactArchive creates a tar.gz archive in a temporary directory, containing the files and a manifest
+// listing them with their sha256 sum. It returns the path of the archive
+func createArtifactArchive(files []string, name string) (string, error) {
+	dir, err := ioutil.TempDir("", "cds-artifact")
+	if err != nil {
+		return "", err
+	}
+	archivePath := filepath.Join(dir, filepath.Base(name))
+
+	// The files are archived relative to the workspace root
+	root, err := os.Getwd()
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+
+	if err := writeArtifactArchive(archivePath, root, files); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return archivePath, nil
+}
+
+// artifactEntryName returns the name of a file in an artifact archive, relative to the root directory. Files outside
+// of the root directory are rejected
+func artifactEntryName(root, file string) (string, error) {
+	abs := file
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(root, file)
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return "", err
+	}
+	name := filepath.ToSlash(rel)
+	if err := sdk.CheckArtifactEntryName(name); err != nil {
+		return "", fmt.Errorf("%s is outside of the workspace: %s", file, err)
+	}
+	return name, nil
+}
+
+func writeArtifactArchive(archivePath, root string, files []string) error {
+	out, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+
+	manifest := sdk.ArtifactManifest{}
+	for _, file := range files {
+		fi, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		// Paths are relative to the archive root
+		hdr.Name, err = artifactEntryName(root, file)
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		h := sha256.New()
+		_, err = io.Copy(io.MultiWriter(tw, h), f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+
+		manifest.Files = append(manifest.Files, sdk.ArtifactManifestFile{
+			Path:      hdr.Name,
+			Size:      fi.Size(),
+			Perm:      uint32(fi.Mode().Perm()),
+			SHA256Sum: hex.EncodeToString(h.Sum(nil)),
+		})
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: sdk.ArtifactManifestName, Mode: 0644, Size: int64(len(data)), ModTime: time.Now()}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
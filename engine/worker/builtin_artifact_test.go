@@ -0,0 +1,91 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_artifactFilesAndArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "artifact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	wd, _ := os.Getwd()
+	assert.NoError(t, os.Chdir(dir))
+	defer os.Chdir(wd)
+
+	for _, f := range []string{"dist/app.js", "dist/lib/lib.js", "dist/lib/README", "report.xml"} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(f), 0755))
+		assert.NoError(t, ioutil.WriteFile(f, []byte(f), 0644))
+	}
+
+	files, err := artifactFiles("dist,*.xml")
+	assert.NoError(t, err)
+	assert.Len(t, files, 4)
+
+	files, err = artifactFiles("**/*.js")
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	archivePath, err := createArtifactArchive(files, "dist.tar.gz")
+	assert.NoError(t, err)
+	defer os.RemoveAll(filepath.Dir(archivePath))
+	assert.Equal(t, "dist.tar.gz", filepath.Base(archivePath))
+
+	f, err := os.Open(archivePath)
+	assert.NoError(t, err)
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	assert.NoError(t, err)
+	tr := tar.NewReader(gr)
+
+	var names []string
+	var manifest sdk.ArtifactManifest
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		assert.NoError(t, sdk.CheckArtifactEntryName(hdr.Name))
+		names = append(names, hdr.Name)
+		if hdr.Name == sdk.ArtifactManifestName {
+			assert.NoError(t, json.NewDecoder(tr).Decode(&manifest))
+		}
+	}
+
+	assert.Equal(t, []string{"dist/app.js", "dist/lib/lib.js", sdk.ArtifactManifestName}, names)
+	assert.Len(t, manifest.Files, 2)
+	for _, m := range manifest.Files {
+		_, sum, err := sdk.FileChecksums(m.Path)
+		assert.NoError(t, err)
+		assert.Equal(t, sum, m.SHA256Sum)
+	}
+}
+
+func Test_artifactEntryName(t *testing.T) {
+	root := filepath.FromSlash("/tmp/workspace")
+
+	name, err := artifactEntryName(root, "dist/../dist/app.js")
+	assert.NoError(t, err)
+	assert.Equal(t, "dist/app.js", name)
+
+	name, err = artifactEntryName(root, filepath.Join(root, "report.xml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "report.xml", name)
+
+	_, err = artifactEntryName(root, "../secret")
+	assert.Error(t, err)
+	_, err = artifactEntryName(root, filepath.FromSlash("/etc/passwd"))
+	assert.Error(t, err)
+}
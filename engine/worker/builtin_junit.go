@@ -13,7 +13,7 @@ import (
 	"github.com/runabove/venom"
 )
 
-func runParseJunitTestResultAction(*currentWorker) BuiltInAction {
+func runParseJunitTestResultAction(w *currentWorker) BuiltInAction {
 	return func(ctx context.Context, a *sdk.Action, buildID int64, params []sdk.Parameter, sendLog LoggerFunc) sdk.Result {
 		var res sdk.Result
 		res.Status = sdk.StatusFail.String()
@@ -30,8 +30,9 @@ func runParseJunitTestResultAction(*currentWorker) BuiltInAction {
 			sendLog(res.Reason)
 			return res
 		}
+		format := sdk.ParameterValue(a.Parameters, "format")
 
-		files, errg := filepath.Glob(p)
+		files, errg := globFiles(p)
 		if errg != nil {
 			res.Reason = fmt.Sprintf("UnitTest parser: Cannot find requested files, invalid pattern")
 			sendLog(res.Reason)
@@ -41,9 +42,8 @@ func runParseJunitTestResultAction(*currentWorker) BuiltInAction {
 		var tests venom.Tests
 		sendLog(fmt.Sprintf("%d", len(files)) + " file(s) to analyze")
 
+		var parsed int
 		for _, f := range files {
-			var ftests venom.Tests
-
 			data, errRead := ioutil.ReadFile(f)
 			if errRead != nil {
 				res.Reason = fmt.Sprintf("UnitTest parser: cannot read file %s (%s)", f, errRead)
@@ -51,16 +51,19 @@ func runParseJunitTestResultAction(*currentWorker) BuiltInAction {
 				return res
 			}
 
-			var vf venom.Tests
-			if err := xml.Unmarshal(data, &vf); err != nil {
-				// Check if file contains testsuite only (and no testsuites)
-				if s, ok := parseTestsuiteAlone(data); ok {
-					ftests.TestSuites = append(ftests.TestSuites, s)
-				}
-				tests.TestSuites = append(tests.TestSuites, ftests.TestSuites...)
-			} else {
-				tests.TestSuites = append(tests.TestSuites, vf.TestSuites...)
+			suites, errParse := parseTestReport(filepath.Base(f), data, format)
+			if errParse != nil {
+				sendLog(fmt.Sprintf("UnitTest parser: cannot parse file %s (%s)", f, errParse))
+				continue
 			}
+			tests.TestSuites = append(tests.TestSuites, suites...)
+			parsed++
+		}
+
+		if len(files) > 0 && parsed == 0 {
+			res.Reason = fmt.Sprintf("UnitTest parser: no report could be parsed")
+			sendLog(res.Reason)
+			return res
 		}
 
 		sendLog(fmt.Sprintf("%d", len(tests.TestSuites)) + " Total Testsuite(s)")
@@ -69,6 +72,15 @@ func runParseJunitTestResultAction(*currentWorker) BuiltInAction {
 			sendLog(r)
 		}
 
+		if w.currentJob.wJob != nil {
+			if err := w.client.QueueSendUnitTests(buildID, tests); err != nil {
+				res.Reason = fmt.Sprintf("JUnit parse: failed to send tests details: %s", err)
+				res.Status = sdk.StatusFail.String()
+				sendLog(res.Reason)
+			}
+			return res
+		}
+
 		data, err := json.Marshal(tests)
 		if err != nil {
 			res.Reason = fmt.Sprintf("JUnit parse: failed to send tests details: %s", err)
@@ -0,0 +1,595 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/runabove/venom"
+)
+
+// Supported test report formats
+const (
+	testReportJUnit  = "junit"
+	testReportGoTest = "gotest"
+	testReportTAP    = "tap"
+	testReportXUnit  = "xunit"
+	testReportNUnit  = "nunit"
+	testReportTRX    = "trx"
+)
+
+// parseTestReport converts a test report into venom testsuites.
+// If format is empty, it is detected from the content of the report
+func parseTestReport(name string, data []byte, format string) ([]venom.TestSuite, error) {
+	if format == "" {
+		format = detectTestReportFormat(data)
+		if format == "" {
+			return nil, fmt.Errorf("unable to detect test report format")
+		}
+	}
+
+	switch strings.ToLower(format) {
+	case testReportJUnit:
+		return parseJUnitReport(data)
+	case testReportGoTest:
+		return parseGoTestJSONReport(data)
+	case testReportTAP:
+		return parseTAPReport(name, data)
+	case testReportXUnit:
+		return parseXUnitReport(data)
+	case testReportNUnit:
+		return parseNUnitReport(data)
+	case testReportTRX:
+		return parseTRXReport(data)
+	}
+	return nil, fmt.Errorf("unsupported test report format %s", format)
+}
+
+var tapLineRegexp = regexp.MustCompile(`^(TAP version \d+|1\.\.\d+|(not )?ok\b)`)
+
+// detectTestReportFormat returns the format of the report, or an empty string if unknown
+func detectTestReportFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		return testReportGoTest
+	}
+
+	if bytes.HasPrefix(trimmed, []byte("<")) {
+		decoder := xml.NewDecoder(bytes.NewReader(trimmed))
+		for {
+			t, err := decoder.Token()
+			if err != nil {
+				return ""
+			}
+			if start, ok := t.(xml.StartElement); ok {
+				switch start.Name.Local {
+				case "testsuites", "testsuite":
+					return testReportJUnit
+				case "assemblies", "assembly":
+					return testReportXUnit
+				case "test-run", "test-results":
+					return testReportNUnit
+				case "TestRun":
+					return testReportTRX
+				}
+				return ""
+			}
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if tapLineRegexp.MatchString(line) {
+			return testReportTAP
+		}
+		return ""
+	}
+	return ""
+}
+
+func parseJUnitReport(data []byte) ([]venom.TestSuite, error) {
+	var vf venom.Tests
+	if err := xml.Unmarshal(data, &vf); err != nil {
+		// Check if file contains testsuite only (and no testsuites)
+		if s, ok := parseTestsuiteAlone(data); ok {
+			return []venom.TestSuite{s}, nil
+		}
+		return nil, err
+	}
+	return vf.TestSuites, nil
+}
+
+type goTestEvent struct {
+	Action  string  `json:"Action"`
+	Package string  `json:"Package"`
+	Test    string  `json:"Test"`
+	Elapsed float64 `json:"Elapsed"`
+	Output  string  `json:"Output"`
+}
+
+// parseGoTestJSONReport converts the output of go test -json: one testsuite per package
+func parseGoTestJSONReport(data []byte) ([]venom.TestSuite, error) {
+	suites := []*venom.TestSuite{}
+	suitesByPkg := map[string]*venom.TestSuite{}
+	cases := map[string]*venom.TestCase{}
+	outputs := map[string]*bytes.Buffer{}
+	order := map[string][]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		// go test -json may be mixed with build output
+		if line[0] != '{' {
+			continue
+		}
+		var e goTestEvent
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, err
+		}
+
+		ts, ok := suitesByPkg[e.Package]
+		if !ok {
+			ts = &venom.TestSuite{Name: e.Package, Package: e.Package}
+			suitesByPkg[e.Package] = ts
+			suites = append(suites, ts)
+		}
+
+		key := e.Package + "\x00" + e.Test
+		if outputs[key] == nil {
+			outputs[key] = new(bytes.Buffer)
+		}
+
+		switch e.Action {
+		case "run":
+			if e.Test != "" && cases[key] == nil {
+				cases[key] = &venom.TestCase{Name: e.Test, Classname: e.Package}
+				order[e.Package] = append(order[e.Package], key)
+			}
+		case "output":
+			outputs[key].WriteString(e.Output)
+		case "pass", "fail", "skip":
+			if e.Test == "" {
+				ts.Time = formatSeconds(e.Elapsed)
+				// A package failing without any test means it has not been compiled or has panicked
+				if e.Action == "fail" && len(order[e.Package]) == 0 {
+					cases[key] = &venom.TestCase{
+						Name:      e.Package,
+						Classname: e.Package,
+						Errors:    []venom.Failure{{Value: outputs[key].String(), Message: "package failed"}},
+					}
+					order[e.Package] = append(order[e.Package], key)
+				}
+				continue
+			}
+			tc := cases[key]
+			if tc == nil {
+				tc = &venom.TestCase{Name: e.Test, Classname: e.Package}
+				cases[key] = tc
+				order[e.Package] = append(order[e.Package], key)
+			}
+			tc.Time = formatSeconds(e.Elapsed)
+			switch e.Action {
+			case "fail":
+				tc.Failures = append(tc.Failures, venom.Failure{Value: outputs[key].String()})
+			case "skip":
+				tc.Skipped = 1
+				tc.Systemout = venom.InnerResult{Value: outputs[key].String()}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	res := make([]venom.TestSuite, 0, len(suites))
+	for _, ts := range suites {
+		for _, key := range order[ts.Package] {
+			tc := cases[key]
+			ts.TestCases = append(ts.TestCases, *tc)
+			ts.Total++
+			if len(tc.Failures) > 0 {
+				ts.Failures++
+			}
+			if len(tc.Errors) > 0 {
+				ts.Errors++
+			}
+			if tc.Skipped > 0 {
+				ts.Skipped++
+			}
+		}
+		res = append(res, *ts)
+	}
+	return res, nil
+}
+
+var tapResultRegexp = regexp.MustCompile(`^(not )?ok\b\s*(\d+)?\s*-?\s*([^#]*)(#\s*(\w+)\s*(.*))?$`)
+
+// parseTAPReport converts a Test Anything Protocol report: one testsuite per file
+func parseTAPReport(name string, data []byte) ([]venom.TestSuite, error) {
+	ts := venom.TestSuite{Name: name}
+	var current *venom.TestCase
+	var diag bytes.Buffer
+	var inYAML bool
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		if len(current.Failures) > 0 && diag.Len() > 0 {
+			current.Failures[0].Value = diag.String()
+		}
+		ts.TestCases = append(ts.TestCases, *current)
+		current = nil
+		diag.Reset()
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+
+		if inYAML {
+			if line == "..." {
+				inYAML = false
+				continue
+			}
+			diag.WriteString(raw + "\n")
+			continue
+		}
+
+		switch {
+		case line == "---" && current != nil:
+			inYAML = true
+		case strings.HasPrefix(line, "#"):
+			if current != nil {
+				diag.WriteString(strings.TrimSpace(strings.TrimPrefix(line, "#")) + "\n")
+			}
+		case strings.HasPrefix(line, "Bail out!"):
+			flush()
+			ts.TestCases = append(ts.TestCases, venom.TestCase{
+				Name:   "Bail out",
+				Errors: []venom.Failure{{Value: strings.TrimSpace(strings.TrimPrefix(line, "Bail out!"))}},
+			})
+		default:
+			m := tapResultRegexp.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			flush()
+			tc := venom.TestCase{Name: strings.TrimSpace(m[3])}
+			if tc.Name == "" {
+				tc.Name = fmt.Sprintf("test %s", m[2])
+			}
+			directive := strings.ToUpper(m[5])
+			switch {
+			case directive == "SKIP":
+				tc.Skipped = 1
+			case directive == "TODO":
+				// TODO tests are not expected to succeed
+			case m[1] != "":
+				tc.Failures = []venom.Failure{{Message: "not ok"}}
+			}
+			current = &tc
+		}
+	}
+	flush()
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, tc := range ts.TestCases {
+		ts.Total++
+		if len(tc.Failures) > 0 {
+			ts.Failures++
+		}
+		if len(tc.Errors) > 0 {
+			ts.Errors++
+		}
+		if tc.Skipped > 0 {
+			ts.Skipped++
+		}
+	}
+	return []venom.TestSuite{ts}, nil
+}
+
+type xunitAssemblies struct {
+	Assemblies []xunitAssembly `xml:"assembly"`
+}
+
+type xunitAssembly struct {
+	Name        string `xml:"name,attr"`
+	Time        string `xml:"time,attr"`
+	Collections []struct {
+		Tests []xunitTest `xml:"test"`
+	} `xml:"collection"`
+}
+
+type xunitTest struct {
+	Name    string `xml:"name,attr"`
+	Type    string `xml:"type,attr"`
+	Result  string `xml:"result,attr"`
+	Time    string `xml:"time,attr"`
+	Reason  string `xml:"reason"`
+	Output  string `xml:"output"`
+	Failure *struct {
+		ExceptionType string `xml:"exception-type,attr"`
+		Message       string `xml:"message"`
+		StackTrace    string `xml:"stack-trace"`
+	} `xml:"failure"`
+}
+
+// parseXUnitReport converts a xUnit.net v2 report: one testsuite per assembly
+func parseXUnitReport(data []byte) ([]venom.TestSuite, error) {
+	var assemblies xunitAssemblies
+	if err := xml.Unmarshal(data, &assemblies); err != nil {
+		// Report containing a single assembly
+		var a xunitAssembly
+		if errA := xml.Unmarshal(data, &a); errA != nil {
+			return nil, err
+		}
+		assemblies.Assemblies = []xunitAssembly{a}
+	}
+
+	suites := []venom.TestSuite{}
+	for _, a := range assemblies.Assemblies {
+		ts := venom.TestSuite{Name: a.Name, Time: a.Time}
+		for _, c := range a.Collections {
+			for _, t := range c.Tests {
+				tc := venom.TestCase{Name: t.Name, Classname: t.Type, Time: t.Time}
+				if t.Output != "" {
+					tc.Systemout = venom.InnerResult{Value: t.Output}
+				}
+				switch strings.ToLower(t.Result) {
+				case "fail":
+					f := venom.Failure{}
+					if t.Failure != nil {
+						f.Type = t.Failure.ExceptionType
+						f.Message = t.Failure.Message
+						f.Value = t.Failure.StackTrace
+					}
+					tc.Failures = []venom.Failure{f}
+					ts.Failures++
+				case "skip":
+					tc.Skipped = 1
+					tc.Systemout = venom.InnerResult{Value: t.Reason}
+					ts.Skipped++
+				}
+				ts.Total++
+				ts.TestCases = append(ts.TestCases, tc)
+			}
+		}
+		suites = append(suites, ts)
+	}
+	return suites, nil
+}
+
+type nunitSuite struct {
+	Type     string       `xml:"type,attr"`
+	Name     string       `xml:"name,attr"`
+	FullName string       `xml:"fullname,attr"`
+	Duration string       `xml:"duration,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []nunitSuite `xml:"test-suite"`
+	Cases    []nunitCase  `xml:"test-case"`
+	Results  *nunitSuite  `xml:"results"`
+}
+
+type nunitCase struct {
+	Name      string `xml:"name,attr"`
+	FullName  string `xml:"fullname,attr"`
+	ClassName string `xml:"classname,attr"`
+	Result    string `xml:"result,attr"`
+	Executed  string `xml:"executed,attr"`
+	Duration  string `xml:"duration,attr"`
+	Time      string `xml:"time,attr"`
+	Failure   *struct {
+		Message    string `xml:"message"`
+		StackTrace string `xml:"stack-trace"`
+	} `xml:"failure"`
+	Reason *struct {
+		Message string `xml:"message"`
+	} `xml:"reason"`
+	Output string `xml:"output"`
+}
+
+type nunitRun struct {
+	Suites []nunitSuite `xml:"test-suite"`
+}
+
+// parseNUnitReport converts NUnit 2 and NUnit 3 reports: one testsuite per fixture
+func parseNUnitReport(data []byte) ([]venom.TestSuite, error) {
+	var run nunitRun
+	if err := xml.Unmarshal(data, &run); err != nil {
+		return nil, err
+	}
+
+	suites := []venom.TestSuite{}
+	var walk func(s nunitSuite)
+	walk = func(s nunitSuite) {
+		children := s.Suites
+		cases := s.Cases
+		// NUnit 2 wraps children in a results element
+		if s.Results != nil {
+			children = append(children, s.Results.Suites...)
+			cases = append(cases, s.Results.Cases...)
+		}
+
+		if len(cases) > 0 {
+			name := s.FullName
+			if name == "" {
+				name = s.Name
+			}
+			ts := venom.TestSuite{Name: name, Time: firstNonEmpty(s.Duration, s.Time)}
+			for _, c := range cases {
+				tc := venom.TestCase{
+					Name:      c.Name,
+					Classname: firstNonEmpty(c.ClassName, name),
+					Time:      firstNonEmpty(c.Duration, c.Time),
+				}
+				if c.Output != "" {
+					tc.Systemout = venom.InnerResult{Value: c.Output}
+				}
+				switch strings.ToLower(c.Result) {
+				case "failed", "failure":
+					f := venom.Failure{}
+					if c.Failure != nil {
+						f.Message = c.Failure.Message
+						f.Value = c.Failure.StackTrace
+					}
+					tc.Failures = []venom.Failure{f}
+					ts.Failures++
+				case "error":
+					f := venom.Failure{}
+					if c.Failure != nil {
+						f.Message = c.Failure.Message
+						f.Value = c.Failure.StackTrace
+					}
+					tc.Errors = []venom.Failure{f}
+					ts.Errors++
+				case "skipped", "ignored", "notrunnable", "inconclusive":
+					tc.Skipped = 1
+					if c.Reason != nil {
+						tc.Systemout = venom.InnerResult{Value: c.Reason.Message}
+					}
+					ts.Skipped++
+				}
+				ts.Total++
+				ts.TestCases = append(ts.TestCases, tc)
+			}
+			suites = append(suites, ts)
+		}
+
+		for _, child := range children {
+			walk(child)
+		}
+	}
+
+	for _, s := range run.Suites {
+		walk(s)
+	}
+	return suites, nil
+}
+
+type trxRun struct {
+	Name    string `xml:"name,attr"`
+	Results struct {
+		UnitTestResults []struct {
+			TestID   string `xml:"testId,attr"`
+			TestName string `xml:"testName,attr"`
+			Outcome  string `xml:"outcome,attr"`
+			Duration string `xml:"duration,attr"`
+			Output   struct {
+				StdOut    string `xml:"StdOut"`
+				ErrorInfo *struct {
+					Message    string `xml:"Message"`
+					StackTrace string `xml:"StackTrace"`
+				} `xml:"ErrorInfo"`
+			} `xml:"Output"`
+		} `xml:"UnitTestResult"`
+	} `xml:"Results"`
+	TestDefinitions struct {
+		UnitTests []struct {
+			ID         string `xml:"id,attr"`
+			TestMethod struct {
+				ClassName string `xml:"className,attr"`
+			} `xml:"TestMethod"`
+		} `xml:"UnitTest"`
+	} `xml:"TestDefinitions"`
+}
+
+// parseTRXReport converts a Visual Studio TRX report: one testsuite per test class
+func parseTRXReport(data []byte) ([]venom.TestSuite, error) {
+	var run trxRun
+	if err := xml.Unmarshal(data, &run); err != nil {
+		return nil, err
+	}
+
+	classes := map[string]string{}
+	for _, t := range run.TestDefinitions.UnitTests {
+		classes[t.ID] = t.TestMethod.ClassName
+	}
+
+	suites := []*venom.TestSuite{}
+	suitesByClass := map[string]*venom.TestSuite{}
+	for _, r := range run.Results.UnitTestResults {
+		class := classes[r.TestID]
+		if class == "" {
+			class = run.Name
+		}
+		ts, ok := suitesByClass[class]
+		if !ok {
+			ts = &venom.TestSuite{Name: class}
+			suitesByClass[class] = ts
+			suites = append(suites, ts)
+		}
+
+		tc := venom.TestCase{Name: r.TestName, Classname: class, Time: trxDuration(r.Duration)}
+		if r.Output.StdOut != "" {
+			tc.Systemout = venom.InnerResult{Value: r.Output.StdOut}
+		}
+		switch strings.ToLower(r.Outcome) {
+		case "failed", "error", "timeout", "aborted":
+			f := venom.Failure{}
+			if r.Output.ErrorInfo != nil {
+				f.Message = r.Output.ErrorInfo.Message
+				f.Value = r.Output.ErrorInfo.StackTrace
+			}
+			tc.Failures = []venom.Failure{f}
+			ts.Failures++
+		case "notexecuted", "inconclusive", "pending":
+			tc.Skipped = 1
+			ts.Skipped++
+		}
+		ts.Total++
+		ts.TestCases = append(ts.TestCases, tc)
+	}
+
+	res := make([]venom.TestSuite, len(suites))
+	for i := range suites {
+		res[i] = *suites[i]
+	}
+	return res, nil
+}
+
+// trxDuration converts a TRX duration (hh:mm:ss.fffffff) in seconds
+func trxDuration(d string) string {
+	parts := strings.Split(d, ":")
+	if len(parts) != 3 {
+		return d
+	}
+	h, errH := strconv.Atoi(parts[0])
+	m, errM := strconv.Atoi(parts[1])
+	s, errS := strconv.ParseFloat(parts[2], 64)
+	if errH != nil || errM != nil || errS != nil {
+		return d
+	}
+	dur := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s*float64(time.Second))
+	return formatSeconds(dur.Seconds())
+}
+
+func formatSeconds(s float64) string {
+	return strconv.FormatFloat(s, 'f', 3, 64)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
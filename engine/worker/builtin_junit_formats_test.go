@@ -0,0 +1,260 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const goTestJSONSample = `{"Action":"run","Package":"github.com/ovh/cds/sdk","Test":"TestA"}
+{"Action":"output","Package":"github.com/ovh/cds/sdk","Test":"TestA","Output":"=== RUN   TestA\n"}
+{"Action":"pass","Package":"github.com/ovh/cds/sdk","Test":"TestA","Elapsed":0.01}
+{"Action":"run","Package":"github.com/ovh/cds/sdk","Test":"TestB"}
+{"Action":"output","Package":"github.com/ovh/cds/sdk","Test":"TestB","Output":"foo_test.go:12: boom\n"}
+{"Action":"fail","Package":"github.com/ovh/cds/sdk","Test":"TestB","Elapsed":0.02}
+{"Action":"run","Package":"github.com/ovh/cds/sdk","Test":"TestC"}
+{"Action":"skip","Package":"github.com/ovh/cds/sdk","Test":"TestC","Elapsed":0}
+{"Action":"fail","Package":"github.com/ovh/cds/sdk","Elapsed":0.5}
+{"Action":"output","Package":"github.com/ovh/cds/engine","Output":"panic: oops\n"}
+{"Action":"fail","Package":"github.com/ovh/cds/engine","Elapsed":0.1}
+`
+
+const tapSample = `TAP version 13
+1..4
+ok 1 - first
+not ok 2 - second
+  ---
+  message: expected 1
+  ...
+ok 3 - third # SKIP not on linux
+not ok 4 - fourth # TODO not implemented
+`
+
+const xunitSample = `<?xml version="1.0" encoding="utf-8"?>
+<assemblies>
+  <assembly name="Tests.dll" time="0.5">
+    <collection name="Test collection">
+      <test name="Tests.A" type="Tests" result="Pass" time="0.1" />
+      <test name="Tests.B" type="Tests" result="Fail" time="0.2">
+        <failure exception-type="Xunit.Sdk.EqualException">
+          <message>Assert.Equal() Failure</message>
+          <stack-trace>at Tests.B()</stack-trace>
+        </failure>
+      </test>
+      <test name="Tests.C" type="Tests" result="Skip" time="0"><reason>later</reason></test>
+    </collection>
+  </assembly>
+</assemblies>`
+
+const nunit3Sample = `<?xml version="1.0" encoding="utf-8"?>
+<test-run id="2" testcasecount="3" result="Failed">
+  <test-suite type="Assembly" name="Tests.dll">
+    <test-suite type="TestFixture" name="MyFixture" fullname="Tests.MyFixture" duration="0.3">
+      <test-case name="A" fullname="Tests.MyFixture.A" classname="Tests.MyFixture" result="Passed" duration="0.1" />
+      <test-case name="B" fullname="Tests.MyFixture.B" classname="Tests.MyFixture" result="Failed" duration="0.2">
+        <failure><message>Expected 2</message><stack-trace>at B</stack-trace></failure>
+      </test-case>
+      <test-case name="C" fullname="Tests.MyFixture.C" classname="Tests.MyFixture" result="Skipped">
+        <reason><message>ignored</message></reason>
+      </test-case>
+    </test-suite>
+  </test-suite>
+</test-run>`
+
+const nunit2Sample = `<?xml version="1.0" encoding="utf-8"?>
+<test-results name="Tests.dll" total="2" failures="1">
+  <test-suite type="Assembly" name="Tests.dll">
+    <results>
+      <test-suite type="TestFixture" name="MyFixture" time="0.3">
+        <results>
+          <test-case name="Tests.MyFixture.A" executed="True" result="Success" time="0.1" />
+          <test-case name="Tests.MyFixture.B" executed="True" result="Failure" time="0.2">
+            <failure><message>Expected 2</message></failure>
+          </test-case>
+        </results>
+      </test-suite>
+    </results>
+  </test-suite>
+</test-results>`
+
+const trxSample = `<?xml version="1.0" encoding="UTF-8"?>
+<TestRun id="1" name="run" xmlns="http://microsoft.com/schemas/VisualStudio/TeamTest/2010">
+  <Results>
+    <UnitTestResult testId="t1" testName="A" outcome="Passed" duration="00:00:00.1000000" />
+    <UnitTestResult testId="t2" testName="B" outcome="Failed" duration="00:00:01.5000000">
+      <Output><ErrorInfo><Message>Assert failed</Message><StackTrace>at B</StackTrace></ErrorInfo></Output>
+    </UnitTestResult>
+    <UnitTestResult testId="t3" testName="C" outcome="NotExecuted" />
+  </Results>
+  <TestDefinitions>
+    <UnitTest id="t1"><TestMethod className="Tests.Class1" /></UnitTest>
+    <UnitTest id="t2"><TestMethod className="Tests.Class1" /></UnitTest>
+    <UnitTest id="t3"><TestMethod className="Tests.Class2" /></UnitTest>
+  </TestDefinitions>
+</TestRun>`
+
+const junitSample = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuite name="suite" tests="2" failures="1">
+  <testcase name="a" classname="c" />
+  <testcase name="b" classname="c"><failure message="ko">boom</failure></testcase>
+</testsuite>`
+
+func Test_detectTestReportFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"junit", junitSample, testReportJUnit},
+		{"gotest", goTestJSONSample, testReportGoTest},
+		{"tap", tapSample, testReportTAP},
+		{"tap with comments", "# a comment\nok 1\n", testReportTAP},
+		{"xunit", xunitSample, testReportXUnit},
+		{"nunit3", nunit3Sample, testReportNUnit},
+		{"nunit2", nunit2Sample, testReportNUnit},
+		{"trx", trxSample, testReportTRX},
+		{"unknown xml", "<foo/>", ""},
+		{"unknown text", "hello world", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectTestReportFormat([]byte(tt.data)); got != tt.want {
+				t.Errorf("detectTestReportFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseTestReport(t *testing.T) {
+	type suite struct {
+		name                             string
+		total, failures, errors, skipped int
+	}
+	tests := []struct {
+		name    string
+		data    string
+		format  string
+		want    []suite
+		wantErr bool
+	}{
+		{
+			name: "gotest",
+			data: goTestJSONSample,
+			want: []suite{
+				{"github.com/ovh/cds/sdk", 3, 1, 0, 1},
+				{"github.com/ovh/cds/engine", 1, 0, 1, 0},
+			},
+		},
+		{
+			name: "tap",
+			data: tapSample,
+			want: []suite{{"report.tap", 4, 1, 0, 1}},
+		},
+		{
+			name: "xunit",
+			data: xunitSample,
+			want: []suite{{"Tests.dll", 3, 1, 0, 1}},
+		},
+		{
+			name: "nunit3",
+			data: nunit3Sample,
+			want: []suite{{"Tests.MyFixture", 3, 1, 0, 1}},
+		},
+		{
+			name: "nunit2",
+			data: nunit2Sample,
+			want: []suite{{"MyFixture", 2, 1, 0, 0}},
+		},
+		{
+			name: "trx",
+			data: trxSample,
+			want: []suite{{"Tests.Class1", 2, 1, 0, 0}, {"Tests.Class2", 1, 0, 0, 1}},
+		},
+		{
+			name:   "forced format",
+			data:   tapSample,
+			format: "TAP",
+			want:   []suite{{"report.tap", 4, 1, 0, 1}},
+		},
+		{
+			name:    "unsupported format",
+			data:    tapSample,
+			format:  "cucumber",
+			wantErr: true,
+		},
+		{
+			name:    "unknown content",
+			data:    "hello world",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTestReport("report.tap", []byte(tt.data), tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTestReport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseTestReport() returned %d testsuites, want %d", len(got), len(tt.want))
+			}
+			for i, w := range tt.want {
+				g := suite{got[i].Name, got[i].Total, got[i].Failures, got[i].Errors, got[i].Skipped}
+				if g != w {
+					t.Errorf("testsuite %d = %+v, want %+v", i, g, w)
+				}
+				if len(got[i].TestCases) != w.total {
+					t.Errorf("testsuite %d has %d testcases, want %d", i, len(got[i].TestCases), w.total)
+				}
+			}
+		})
+	}
+}
+
+func Test_trxDuration(t *testing.T) {
+	if got := trxDuration("00:01:01.5000000"); got != "61.500" {
+		t.Errorf("trxDuration() = %s, want 61.500", got)
+	}
+	if got := trxDuration("1.2"); got != "1.2" {
+		t.Errorf("trxDuration() = %s, want 1.2", got)
+	}
+}
+
+func Test_globFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, f := range []string{"a.xml", "b.tap", "sub/c.xml", "sub/deep/d.xml", "sub/deep/e.txt"} {
+		p := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		pattern string
+		want    int
+	}{
+		{filepath.Join(dir, "*.xml"), 1},
+		{filepath.Join(dir, "**/*.xml"), 3},
+		{filepath.Join(dir, "sub/**/*.xml"), 2},
+		{filepath.Join(dir, "**/*.xml") + "," + filepath.Join(dir, "*.tap"), 4},
+		{filepath.Join(dir, "*.xml") + "," + filepath.Join(dir, "**/*.xml"), 3},
+		{filepath.Join(dir, "nothing/**/*.xml"), 0},
+	}
+	for _, tt := range tests {
+		got, err := globFiles(tt.pattern)
+		if err != nil {
+			t.Fatalf("globFiles(%s) error = %v", tt.pattern, err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("globFiles(%s) = %v, want %d files", tt.pattern, got, tt.want)
+		}
+	}
+}
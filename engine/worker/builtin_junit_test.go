@@ -1,6 +1,10 @@
 package main
 
 import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -216,3 +220,23 @@ func Test_computeStats(t *testing.T) {
 		})
 	}
 }
+
+func Test_runParseJunitTestResultActionNoReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "junit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "report.xml"), []byte("not a report"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &sdk.Action{Parameters: []sdk.Parameter{{Name: "path", Value: filepath.Join(dir, "*.xml")}}}
+	res := runParseJunitTestResultAction(&currentWorker{})(context.Background(), a, 0, nil, func(string) {})
+	if res.Status != sdk.StatusFail.String() {
+		t.Errorf("status = %v, want %v", res.Status, sdk.StatusFail)
+	}
+	if res.Reason != "UnitTest parser: no report could be parsed" {
+		t.Errorf("reason = %v", res.Reason)
+	}
+}
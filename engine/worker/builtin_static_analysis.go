@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func runStaticAnalysis(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params []sdk.Parameter, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusFail.String()}
+
+		if w.currentJob.wJob == nil {
+			res.Reason = "Static analysis: only available on workflows"
+			sendLog(res.Reason)
+			return res
+		}
+
+		p := sdk.ParameterValue(a.Parameters, "path")
+		if p == "" {
+			res.Reason = "Static analysis: path not provided"
+			sendLog(res.Reason)
+			return res
+		}
+		format := sdk.ParameterValue(a.Parameters, "format")
+
+		files, errg := globFiles(p)
+		if errg != nil {
+			res.Reason = fmt.Sprintf("Static analysis: Cannot find requested files, invalid pattern")
+			sendLog(res.Reason)
+			return res
+		}
+		sendLog(fmt.Sprintf("Static analysis: %d file(s) to analyze", len(files)))
+
+		findings := []sdk.StaticAnalysisFinding{}
+		for _, f := range files {
+			data, errRead := ioutil.ReadFile(f)
+			if errRead != nil {
+				res.Reason = fmt.Sprintf("Static analysis: cannot read file %s (%s)", f, errRead)
+				sendLog(res.Reason)
+				return res
+			}
+
+			ff, err := parseStaticAnalysisReport(data, format)
+			if err != nil {
+				res.Reason = fmt.Sprintf("Static analysis: cannot parse file %s (%s)", f, err)
+				sendLog(res.Reason)
+				return res
+			}
+			sendLog(fmt.Sprintf("Static analysis: %d finding(s) in %s", len(ff), f))
+			findings = append(findings, ff...)
+		}
+
+		summary, err := w.client.QueueSendStaticAnalysis(buildID, findings)
+		if err != nil {
+			res.Reason = fmt.Sprintf("Static analysis: failed to send findings: %s", err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		for _, sev := range sdk.StaticAnalysisSeverities {
+			sendLog(fmt.Sprintf("Static analysis: %d %s severity finding(s), %d new", summary.Severity[sev], sev, summary.NewBy[sev]))
+		}
+
+		res.Status = sdk.StatusSuccess.String()
+		return res
+	}
+}
+
+// parseStaticAnalysisReport parses a report. If format is empty, it is detected from the content
+func parseStaticAnalysisReport(data []byte, format string) ([]sdk.StaticAnalysisFinding, error) {
+	if format == "" {
+		trimmed := bytes.TrimSpace(data)
+		switch {
+		case bytes.HasPrefix(trimmed, []byte("{")):
+			format = sdk.StaticAnalysisFormatSARIF
+		case bytes.HasPrefix(trimmed, []byte("<")):
+			format = sdk.StaticAnalysisFormatCheckstyle
+		default:
+			return nil, fmt.Errorf("unable to detect report format")
+		}
+	}
+
+	var findings []sdk.StaticAnalysisFinding
+	var err error
+	switch strings.ToLower(format) {
+	case sdk.StaticAnalysisFormatSARIF:
+		findings, err = parseSARIF(data)
+	case sdk.StaticAnalysisFormatCheckstyle:
+		findings, err = parseCheckstyle(data)
+	default:
+		return nil, fmt.Errorf("unsupported format %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sdk.ComputeFingerprints(findings)
+	return findings, nil
+}
+
+type sarifReport struct {
+	Runs []struct {
+		Tool struct {
+			Driver struct {
+				Name  string      `json:"name"`
+				Rules []sarifRule `json:"rules"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Results []struct {
+			RuleID  string `json:"ruleId"`
+			Level   string `json:"level"`
+			Message struct {
+				Text string `json:"text"`
+			} `json:"message"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+					Region struct {
+						StartLine   int `json:"startLine"`
+						StartColumn int `json:"startColumn"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+type sarifRule struct {
+	ID                   string `json:"id"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+func parseSARIF(data []byte) ([]sdk.StaticAnalysisFinding, error) {
+	var report sarifReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+
+	findings := []sdk.StaticAnalysisFinding{}
+	for _, run := range report.Runs {
+		rules := map[string]sarifRule{}
+		for _, r := range run.Tool.Driver.Rules {
+			rules[r.ID] = r
+		}
+
+		for _, r := range run.Results {
+			rule := rules[r.RuleID]
+			f := sdk.StaticAnalysisFinding{
+				Tool:     run.Tool.Driver.Name,
+				RuleID:   r.RuleID,
+				Severity: sarifSeverity(r.Level, rule),
+				Message:  r.Message.Text,
+			}
+			if len(r.Locations) > 0 {
+				loc := r.Locations[0].PhysicalLocation
+				f.File = loc.ArtifactLocation.URI
+				f.Line = loc.Region.StartLine
+				f.Column = loc.Region.StartColumn
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+// sarifSeverity computes the severity of a result. The security-severity property (CVSS score)
+// of the rule takes precedence over the level of the result
+func sarifSeverity(level string, rule sarifRule) string {
+	if s, ok := rule.Properties["security-severity"]; ok {
+		if score, err := strconv.ParseFloat(fmt.Sprintf("%v", s), 64); err == nil {
+			switch {
+			case score >= 7:
+				return sdk.SeverityHigh
+			case score >= 4:
+				return sdk.SeverityMedium
+			case score > 0:
+				return sdk.SeverityLow
+			}
+			return sdk.SeverityInfo
+		}
+	}
+
+	if level == "" {
+		level = rule.DefaultConfiguration.Level
+	}
+	switch level {
+	case "error":
+		return sdk.SeverityHigh
+	case "note":
+		return sdk.SeverityLow
+	case "none":
+		return sdk.SeverityInfo
+	}
+	// warning is the default level in SARIF
+	return sdk.SeverityMedium
+}
+
+type checkstyleReport struct {
+	Files []struct {
+		Name   string `xml:"name,attr"`
+		Errors []struct {
+			Line     int    `xml:"line,attr"`
+			Column   int    `xml:"column,attr"`
+			Severity string `xml:"severity,attr"`
+			Message  string `xml:"message,attr"`
+			Source   string `xml:"source,attr"`
+		} `xml:"error"`
+	} `xml:"file"`
+}
+
+func parseCheckstyle(data []byte) ([]sdk.StaticAnalysisFinding, error) {
+	var report checkstyleReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+
+	findings := []sdk.StaticAnalysisFinding{}
+	for _, file := range report.Files {
+		for _, e := range file.Errors {
+			f := sdk.StaticAnalysisFinding{
+				Tool:    "checkstyle",
+				RuleID:  e.Source,
+				Message: e.Message,
+				File:    file.Name,
+				Line:    e.Line,
+				Column:  e.Column,
+			}
+			switch e.Severity {
+			case "error":
+				f.Severity = sdk.SeverityHigh
+			case "warning":
+				f.Severity = sdk.SeverityMedium
+			case "info":
+				f.Severity = sdk.SeverityLow
+			default:
+				f.Severity = sdk.SeverityInfo
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
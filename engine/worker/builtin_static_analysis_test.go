@@ -0,0 +1,99 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const sarifSample = `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "gosec", "rules": [
+      {"id": "G101", "properties": {"security-severity": "7.5"}},
+      {"id": "G104", "defaultConfiguration": {"level": "note"}}
+    ]}},
+    "results": [
+      {"ruleId": "G101", "level": "warning", "message": {"text": "Potential hardcoded credentials"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "main.go"}, "region": {"startLine": 12, "startColumn": 3}}}]},
+      {"ruleId": "G104", "message": {"text": "Errors unhandled"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "util.go"}, "region": {"startLine": 4}}}]},
+      {"ruleId": "G999", "message": {"text": "Unknown rule"}}
+    ]
+  }]
+}`
+
+const checkstyleSample = `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="8.0">
+  <file name="src/Main.java">
+    <error line="10" column="5" severity="error" message="Missing javadoc" source="com.puppycrawl.tools.checkstyle.checks.javadoc.JavadocMethodCheck"/>
+    <error line="20" severity="warning" message="Line is longer than 100 characters" source="LineLengthCheck"/>
+  </file>
+</checkstyle>`
+
+func Test_parseStaticAnalysisReport(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		format     string
+		severities []string
+		wantErr    bool
+	}{
+		{
+			name:       "sarif detected",
+			data:       sarifSample,
+			severities: []string{sdk.SeverityHigh, sdk.SeverityLow, sdk.SeverityMedium},
+		},
+		{
+			name:       "checkstyle detected",
+			data:       checkstyleSample,
+			severities: []string{sdk.SeverityHigh, sdk.SeverityMedium},
+		},
+		{
+			name:       "checkstyle forced",
+			data:       checkstyleSample,
+			format:     "checkstyle",
+			severities: []string{sdk.SeverityHigh, sdk.SeverityMedium},
+		},
+		{
+			name:    "unknown content",
+			data:    "not a report",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported format",
+			data:    sarifSample,
+			format:  "pmd",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseStaticAnalysisReport([]byte(tt.data), tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseStaticAnalysisReport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.severities) {
+				t.Fatalf("parseStaticAnalysisReport() returned %d findings, want %d", len(got), len(tt.severities))
+			}
+			for i := range got {
+				if got[i].Severity != tt.severities[i] {
+					t.Errorf("finding %d severity = %s, want %s", i, got[i].Severity, tt.severities[i])
+				}
+				if got[i].Fingerprint == "" {
+					t.Errorf("finding %d has no fingerprint", i)
+				}
+			}
+		})
+	}
+}
+
+func Test_parseSARIFLocation(t *testing.T) {
+	got, err := parseSARIF([]byte(sarifSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Tool != "gosec" || got[0].RuleID != "G101" || got[0].File != "main.go" || got[0].Line != 12 || got[0].Column != 3 {
+		t.Errorf("unexpected finding %+v", got[0])
+	}
+}
@@ -77,39 +77,50 @@ func (wk *currentWorker) addBuildVarHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	if err := wk.addBuildVariable(v); err != nil {
+		log.Error("addBuildVarHandler> Cannot export variable: %s", err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+}
+
+// addBuildVariable adds the variable in the current job and exports it as a build variable in API
+func (wk *currentWorker) addBuildVariable(v sdk.Variable) error {
 	// OK, so now we got our new variable. We need to:
 	// - add it as a build var in API
 	wk.currentJob.buildVariables = append(wk.currentJob.buildVariables, v)
 	// - add it in current building Action
-	data, err = json.Marshal(v)
+	data, err := json.Marshal(v)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		return err
 	}
-	// Retrieve build info
-	var proj, app, pip, bnS, env string
-	for _, p := range wk.currentJob.pbJob.Parameters {
-		switch p.Name {
-		case "cds.pipeline":
-			pip = p.Value
-		case "cds.project":
-			proj = p.Value
-		case "cds.application":
-			app = p.Value
-		case "cds.buildNumber":
-			bnS = p.Value
-		case "cds.environment":
-			env = p.Value
+
+	var uri string
+	if wk.currentJob.wJob != nil {
+		uri = fmt.Sprintf("/queue/workflows/%d/variable", wk.currentJob.wJob.ID)
+	} else {
+		// Retrieve build info
+		var proj, app, pip, bnS, env string
+		for _, p := range wk.currentJob.pbJob.Parameters {
+			switch p.Name {
+			case "cds.pipeline":
+				pip = p.Value
+			case "cds.project":
+				proj = p.Value
+			case "cds.application":
+				app = p.Value
+			case "cds.buildNumber":
+				bnS = p.Value
+			case "cds.environment":
+				env = p.Value
+			}
 		}
+		uri = fmt.Sprintf("/project/%s/application/%s/pipeline/%s/build/%s/variable?envName=%s", proj, app, pip, bnS, url.QueryEscape(env))
 	}
 
-	uri := fmt.Sprintf("/project/%s/application/%s/pipeline/%s/build/%s/variable?envName=%s", proj, app, pip, bnS, url.QueryEscape(env))
 	_, code, err := sdk.Request("POST", uri, data)
 	if err == nil && code > 300 {
 		err = fmt.Errorf("HTTP %d", code)
 	}
-	if err != nil {
-		log.Error("addBuildVarHandler> Cannot export variable: %s", err)
-		w.WriteHeader(http.StatusServiceUnavailable)
-		return
-	}
+	return err
 }
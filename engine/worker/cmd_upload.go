@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -16,15 +18,21 @@ import (
 	"github.com/ovh/cds/sdk"
 )
 
-var cmdUploadTag string
+var (
+	cmdUploadTag     string
+	cmdUploadArchive string
+)
 
 func cmdUpload(w *currentWorker) *cobra.Command {
 	c := &cobra.Command{
 		Use:   "upload",
-		Short: "worker upload --tag=<tag> <path>",
-		Run:   uploadCmd(w),
+		Short: "worker upload --tag=<tag> [--archive=<archive.tar.gz>] <path>",
+		Long: `Upload files as artifacts. Glob patterns are allowed, ** matches any number of directories.
+Directories are uploaded recursively. With --archive, all the files are uploaded in a single tar.gz archive.`,
+		Run: uploadCmd(w),
 	}
 	c.Flags().StringVar(&cmdUploadTag, "tag", "", "Tag for artifact Upload. Tag is mandatory")
+	c.Flags().StringVar(&cmdUploadArchive, "archive", "", "Name of a tar.gz archive in which files are uploaded")
 	return c
 }
 
@@ -45,7 +53,12 @@ func uploadCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 		}
 
 		if len(args) == 0 {
-			sdk.Exit("Wrong usage: Example : worker upload --tag={{.cds.version}} filea fileb filec*")
+			sdk.Exit("Wrong usage: Example : worker upload --tag={{.cds.version}} filea fileb filec* dir/**/*.xml")
+		}
+
+		// All files are sent in the same archive
+		if cmdUploadArchive != "" {
+			args = []string{strings.Join(args, ",")}
 		}
 
 		for _, arg := range args {
@@ -59,7 +72,7 @@ func uploadCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 				sdk.Exit("internal error (%s)\n", errMarshal)
 			}
 
-			req, errRequest := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/upload", port), bytes.NewReader(data))
+			req, errRequest := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/upload?archive=%s", port, url.QueryEscape(cmdUploadArchive)), bytes.NewReader(data))
 			if errRequest != nil {
 				sdk.Exit("cannot post worker upload (Request): %s\n", errRequest)
 			}
@@ -106,12 +119,21 @@ func (wk *currentWorker) uploadHandler(w http.ResponseWriter, r *http.Request) {
 				Type:  sdk.StringParameter,
 				Value: a.Tag,
 			},
+			{
+				Name:  "archive",
+				Type:  sdk.StringParameter,
+				Value: r.FormValue("archive"),
+			},
 		},
 	}
 
-	sendLog := getLogger(wk, wk.currentJob.pbJob.ID, wk.currentJob.currentStep)
+	buildID, params := wk.currentJob.pbJob.ID, wk.currentJob.pbJob.Parameters
+	if wk.currentJob.wJob != nil {
+		buildID, params = wk.currentJob.wJob.ID, wk.currentJob.params
+	}
+	sendLog := getLogger(wk, buildID, wk.currentJob.currentStep)
 
-	if result := runArtifactUpload(wk)(context.Background(), &action, wk.currentJob.pbJob.ID, wk.currentJob.pbJob.Parameters, sendLog); result.Status != sdk.StatusSuccess.String() {
+	if result := runArtifactUpload(wk)(context.Background(), &action, buildID, params, sendLog); result.Status != sdk.StatusSuccess.String() {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// globFiles returns the files matching the given patterns. Several patterns can be separated by commas.
+// In addition to filepath.Match syntax, the ** pattern matches any number of directories.
+func globFiles(patterns string) ([]string, error) {
+	seen := map[string]bool{}
+	files := []string{}
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		var matches []string
+		var err error
+		if strings.Contains(pattern, "**") {
+			matches, err = globRecursive(pattern)
+		} else {
+			matches, err = filepath.Glob(pattern)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern '%s': %s", pattern, err)
+		}
+
+		for _, m := range matches {
+			if !seen[m] {
+				seen[m] = true
+				files = append(files, m)
+			}
+		}
+	}
+	return files, nil
+}
+
+// globRecursive walks the directory tree from the static prefix of the pattern and returns the files
+// matching the pattern
+func globRecursive(pattern string) ([]string, error) {
+	pattern = filepath.ToSlash(filepath.Clean(pattern))
+
+	// Compute the root directory to walk: the longest prefix without any meta character
+	root := "."
+	segments := strings.Split(pattern, "/")
+	for i, s := range segments {
+		if strings.ContainsAny(s, "*?[") {
+			if i > 0 {
+				root = strings.Join(segments[:i], "/")
+				if root == "" {
+					root = "/"
+				}
+			}
+			break
+		}
+	}
+
+	re, err := globToRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []string{}
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if re.MatchString(filepath.ToSlash(filepath.Clean(path))) {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return matches, nil
+	}
+	sort.Strings(matches)
+	return matches, err
+}
+
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var buf bytes.Buffer
+	buf.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				// **/ matches zero or more directories
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					buf.WriteString("(.*/)?")
+				} else {
+					buf.WriteString(".*")
+				}
+			} else {
+				buf.WriteString("[^/]*")
+			}
+		case '?':
+			buf.WriteString("[^/]")
+		case '[':
+			j := strings.IndexByte(pattern[i:], ']')
+			if j < 0 {
+				return nil, filepath.ErrBadPattern
+			}
+			class := pattern[i+1 : i+j]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			buf.WriteString("[" + class + "]")
+			i += j
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	buf.WriteString("$")
+	return regexp.Compile(buf.String())
+}
@@ -16,15 +16,60 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-var logsecrets []sdk.Variable
+// pendingLog is the end of a log chunk which may be the beginning of a secret
+type pendingLog struct {
+	buildID int64
+	value   string
+}
+
+// setLogSecrets sets the secrets to mask in the logs of the current job. Logs still pending
+// for the previous job are sent before
+func (w *currentWorker) setLogSecrets(secrets []sdk.Variable) {
+	w.logger.masking.Lock()
+	defer w.logger.masking.Unlock()
+
+	for stepOrder, p := range w.logger.masking.pending {
+		w.pushLog(p.buildID, w.logger.masking.masker.Mask(p.value), stepOrder, false)
+	}
+	w.logger.masking.pending = map[int]pendingLog{}
+	w.logger.masking.masker = nil
+	if len(secrets) > 0 {
+		w.logger.masking.masker = sdk.NewSecretMasker(secrets)
+	}
+}
 
 func (w *currentWorker) sendLog(buildID int64, value string, stepOrder int, final bool) error {
-	for i := range logsecrets {
-		if len(logsecrets[i].Value) >= 6 {
-			value = strings.Replace(value, logsecrets[i].Value, "**"+logsecrets[i].Name+"**", -1)
+	w.logger.masking.Lock()
+	defer w.logger.masking.Unlock()
+
+	// A secret may be split over several chunks: the end of the previous chunk which may be the beginning
+	// of a secret has been kept aside
+	if p, ok := w.logger.masking.pending[stepOrder]; ok {
+		value = p.value + value
+		delete(w.logger.masking.pending, stepOrder)
+	}
+
+	if final {
+		value = w.logger.masking.masker.Mask(value)
+	} else {
+		var rest string
+		value, rest = w.logger.masking.masker.MaskChunk(value)
+		if rest != "" {
+			if w.logger.masking.pending == nil {
+				w.logger.masking.pending = map[int]pendingLog{}
+			}
+			w.logger.masking.pending[stepOrder] = pendingLog{buildID: buildID, value: rest}
+		}
+		if value == "" {
+			return nil
 		}
 	}
 
+	w.pushLog(buildID, value, stepOrder, final)
+	return nil
+}
+
+func (w *currentWorker) pushLog(buildID int64, value string, stepOrder int, final bool) {
 	var id = w.currentJob.pbJob.PipelineBuildID
 	if w.currentJob.wJob != nil {
 		id = w.currentJob.wJob.WorkflowNodeRunID
@@ -37,7 +82,6 @@ func (w *currentWorker) sendLog(buildID int64, value string, stepOrder int, fina
 		l.Done = &timestamp.Timestamp{}
 	}
 	w.logger.logChan <- *l
-	return nil
 }
 
 func (w *currentWorker) logProcessor(ctx context.Context) error {
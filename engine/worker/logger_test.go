@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_sendLogMaskSecrets(t *testing.T) {
+	w := &currentWorker{}
+	w.logger.logChan = make(chan sdk.Log, 10)
+	w.setLogSecrets([]sdk.Variable{{Name: "cds.proj.password", Value: "mysecretvalue"}})
+
+	w.sendLog(1, "password: mysec", 0, false)
+	w.sendLog(1, "retvalue\n", 0, false)
+	w.sendLog(1, "other: mysecr", 1, false)
+	w.sendLog(1, "End of step", 0, true)
+	w.setLogSecrets(nil)
+	close(w.logger.logChan)
+
+	var logs []string
+	for l := range w.logger.logChan {
+		logs = append(logs, l.Val)
+	}
+
+	want := []string{"password: ", "**cds.proj.password**\n", "other: ", "End of step", "mysecr"}
+	if len(logs) != len(want) {
+		t.Fatalf("got logs %q, want %q", logs, want)
+	}
+	for i := range want {
+		if logs[i] != want[i] {
+			t.Errorf("log %d = %q, want %q", i, logs[i], want[i])
+		}
+	}
+}
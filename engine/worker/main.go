@@ -2,6 +2,7 @@ package main
 
 import (
 	"container/list"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc"
@@ -22,6 +23,11 @@ type currentWorker struct {
 	logger        struct {
 		logChan chan sdk.Log
 		llist   *list.List
+		masking struct {
+			sync.Mutex
+			masker  *sdk.SecretMasker
+			pending map[int]pendingLog
+		}
 	}
 	exportPort int
 	hatchery   struct {
@@ -2,11 +2,15 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"os/exec"
 	"path"
+	"runtime"
 	"strconv"
 	"time"
 
@@ -25,6 +29,9 @@ var requirementCheckFuncs = map[string]func(w *currentWorker, r sdk.Requirement)
 	sdk.PluginRequirement:        checkPluginRequirement,
 	sdk.ServiceRequirement:       checkServiceRequirement,
 	sdk.MemoryRequirement:        checkMemoryRequirement,
+	sdk.CPURequirement:           checkCPURequirement,
+	sdk.DiskRequirement:          checkDiskRequirement,
+	sdk.OSArchRequirement:        checkOSArchRequirement,
 }
 
 func checkRequirements(w *currentWorker, a *sdk.Action) (bool, []sdk.Requirement) {
@@ -55,22 +62,29 @@ func checkRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
 }
 
 func checkPluginRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
-	pluginBinary := path.Join(w.basedir, r.Name)
+	name, version := sdk.ParsePluginRequirement(r.Value)
+	if name == "" {
+		name = r.Name
+	}
 
-	if _, err := os.Stat(pluginBinary); os.IsNotExist(err) {
-		//If the file doesn't exist. Download it.
-		if err := sdk.DownloadPlugin(r.Name, w.basedir); err != nil {
-			return false, err
-		}
-		if err := os.Chmod(pluginBinary, 0700); err != nil {
-			return false, err
-		}
+	pluginBinary, err := w.installPlugin(name, version)
+	if err != nil {
+		return false, err
 	}
 
-	pluginClient := plugin.NewClient(context.Background(), r.Name, pluginBinary, "", "", false)
-	defer pluginClient.Kill()
+	pluginConn, err := plugin.Dial(context.Background(), name, pluginBinary, "", "", false)
+	if err != nil {
+		log.Warning("[WARNING] Error Checkin %s requirement : %s", r.Name, err)
+		return false, err
+	}
+	defer pluginConn.Close()
+
+	if pluginConn.ProtocolVersion == plugin.GRPCProtocolVersion {
+		log.Warning("[NOTICE] Plugin %s successfully started", r.Value)
+		return true, nil
+	}
 
-	_plugin, err := pluginClient.Instance()
+	_plugin, err := pluginConn.Client.Instance()
 	if err != nil {
 		log.Warning("[WARNING] Error Checkin %s requirement : %s", r.Name, err)
 		return false, err
@@ -80,6 +94,68 @@ func checkPluginRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
 	return true, nil
 }
 
+//installPlugin downloads the binary of the plugin built for the platform of the worker, unless it has already
+//been downloaded, and returns its path
+func (w *currentWorker) installPlugin(name, version string) (string, error) {
+	p, err := sdk.GetPluginBinary(name, version, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return "", err
+	}
+
+	pluginBinary := path.Join(w.basedir, name)
+	if p.Version != "" {
+		pluginBinary += "@" + p.Version
+	}
+
+	//Binaries uploaded before plugins were versioned have no checksum
+	if _, err := os.Stat(pluginBinary); err == nil {
+		if p.SHA256sum == "" {
+			return pluginBinary, nil
+		}
+		if sum, err := fileSHA256(pluginBinary); err == nil && sum == p.SHA256sum {
+			return pluginBinary, nil
+		}
+	}
+
+	if err := downloadFile(pluginBinary, 0700, p.SHA256sum, func(wr io.Writer) error {
+		return sdk.DownloadPluginBinary(p, runtime.GOOS, runtime.GOARCH, wr)
+	}); err != nil {
+		return "", err
+	}
+	return pluginBinary, nil
+}
+
+//pluginVersion returns the version of the plugin pinned in the requirements of the current job
+func (w *currentWorker) pluginVersion(name string) string {
+	a := w.currentJob.pbJob.Job.Action
+	if w.currentJob.wJob != nil {
+		a = w.currentJob.wJob.Job.Action
+	}
+	for _, r := range a.Requirements {
+		if r.Type != sdk.PluginRequirement {
+			continue
+		}
+		if n, v := sdk.ParsePluginRequirement(r.Value); n == name && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 func checkHostnameRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
 	h, err := os.Hostname()
 	if err != nil {
@@ -149,3 +225,36 @@ func checkMemoryRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
 	//If we have more than 90% of neededMemory, lets do it
 	return int64(totalMemory) >= (neededMemory*1024*1024)*90/100, nil
 }
+
+func checkCPURequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
+	neededCPU, err := sdk.ParseCPURequirement(r.Value)
+	if err != nil {
+		return false, err
+	}
+	return float64(runtime.NumCPU()) >= neededCPU, nil
+}
+
+func checkDiskRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
+	neededDisk, err := sdk.ParseDiskRequirement(r.Value)
+	if err != nil {
+		return false, err
+	}
+
+	dir := os.TempDir()
+	if w != nil && w.basedir != "" {
+		dir = w.basedir
+	}
+	free, err := freeDiskSpace(dir)
+	if err != nil {
+		return false, err
+	}
+	//Assuming disk is in megabytes
+	return free >= uint64(neededDisk)*1024*1024, nil
+}
+
+func checkOSArchRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
+	if _, _, err := sdk.ParseOSArchRequirement(r.Value); err != nil {
+		return false, err
+	}
+	return sdk.MatchOSArch(r.Value, runtime.GOOS, runtime.GOARCH), nil
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"os"
+	"runtime"
+	"strconv"
 	"testing"
 
 	"github.com/ovh/cds/sdk"
@@ -89,3 +91,75 @@ func TestNetworkAccessRequirement(t *testing.T) {
 		t.Fatalf("Requirement should not be ok")
 	}
 }
+
+func TestCheckCPURequirement(t *testing.T) {
+	r := sdk.Requirement{
+		Type:  sdk.CPURequirement,
+		Value: "0.5",
+	}
+
+	ok, err := checkRequirement(nil, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if !ok {
+		t.Fatalf("Requirement should be ok")
+	}
+
+	r.Value = strconv.Itoa(runtime.NumCPU() + 1)
+	ok, err = checkRequirement(nil, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if ok {
+		t.Fatalf("Requirement should not be ok")
+	}
+}
+
+func TestCheckDiskRequirement(t *testing.T) {
+	r := sdk.Requirement{
+		Type:  sdk.DiskRequirement,
+		Value: "1",
+	}
+
+	ok, err := checkRequirement(nil, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if !ok {
+		t.Fatalf("Requirement should be ok")
+	}
+
+	r.Value = "1000000000000"
+	ok, err = checkRequirement(nil, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if ok {
+		t.Fatalf("Requirement should not be ok")
+	}
+}
+
+func TestCheckOSArchRequirement(t *testing.T) {
+	r := sdk.Requirement{
+		Type:  sdk.OSArchRequirement,
+		Value: runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	ok, err := checkRequirement(nil, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if !ok {
+		t.Fatalf("Requirement should be ok")
+	}
+
+	r.Value = "plan9/mips"
+	ok, err = checkRequirement(nil, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if ok {
+		t.Fatalf("Requirement should not be ok")
+	}
+}
@@ -399,9 +399,9 @@ func (w *currentWorker) processJob(ctx context.Context, jobInfo *worker.Workflow
 		}
 	}
 
-	logsecrets = jobInfo.Secrets
+	w.setLogSecrets(jobInfo.Secrets)
 	res := w.startAction(ctx, &jobInfo.NodeJobRun.Job.Action, jobInfo.NodeJobRun.ID, jobInfo.NodeJobRun.Parameters, -1, "")
-	logsecrets = nil
+	w.setLogSecrets(nil)
 
 	log.Debug("processJob> call teardownBuildDirectory wd:%s", wd)
 	if err := teardownBuildDirectory(wd); err != nil {
@@ -509,11 +509,11 @@ func (w *currentWorker) run(ctx context.Context, pbji *worker.PipelineBuildJobIn
 		}
 	}
 
-	logsecrets = pbji.Secrets
+	w.setLogSecrets(pbji.Secrets)
 
 	log.Debug("run> run startAction")
 	res := w.startAction(ctx, &pbji.PipelineBuildJob.Job.Action, pbji.PipelineBuildJob.ID, pbji.PipelineBuildJob.Parameters, -1, "")
-	logsecrets = nil
+	w.setLogSecrets(nil)
 
 	if err := teardownBuildDirectory(wd); err != nil {
 		log.Error("Cannot remove build directory: %s", err)
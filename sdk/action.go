@@ -7,7 +7,8 @@ import (
 	"time"
 )
 
-// Action is the base element of CDS pipeline
+// Action is the base element of CDS pipeline. Version is the current version of an action, or for the steps
+// of a job or of an action, the version pinned by the step: 0 if the step follows the latest version.
 type Action struct {
 	ID             int64         `json:"id" yaml:"-"`
 	Name           string        `json:"name"`
@@ -20,6 +21,12 @@ type Action struct {
 	Optional       bool          `json:"optional" yaml:"-"`
 	AlwaysExecuted bool          `json:"always_executed" yaml:"-"`
 	LastModified   int64         `json:"last_modified"`
+	ProjectID      int64         `json:"project_id,omitempty" yaml:"-"`
+	Version        int64         `json:"version,omitempty" yaml:"-"`
+	LatestVersion  int64         `json:"latest_version,omitempty" yaml:"-"`
+	Deprecated     bool          `json:"deprecated,omitempty" yaml:"-"`
+	Source         string        `json:"source,omitempty" yaml:"-"`
+	SourceCommit   string        `json:"source_commit,omitempty" yaml:"-"`
 }
 
 // ActionAudit Audit on action
@@ -43,12 +50,27 @@ type ActionPlugin struct {
 	Size       int64  `json:"size,omitempty"`
 	Perm       uint32 `json:"perm,omitempty"`
 	MD5sum     string `json:"md5sum,omitempty"`
+	SHA256sum  string `json:"sha256sum,omitempty"`
 	ObjectPath string `json:"object_path,omitempty"`
+
+	Version    string    `json:"version,omitempty"`
+	OS         string    `json:"os,omitempty"`
+	Arch       string    `json:"arch,omitempty"`
+	Default    bool      `json:"default"`
+	Deprecated bool      `json:"deprecated"`
+	Created    time.Time `json:"created"`
 }
 
-//GetName returns the name the action plugin
+//GetName returns the name the action plugin, suffixed by its version and its platform
 func (a *ActionPlugin) GetName() string {
-	return a.Name
+	name := a.Name
+	if a.Version != "" {
+		name += "@" + a.Version
+	}
+	if a.OS != "" {
+		name += "_" + a.OS + "_" + a.Arch
+	}
+	return name
 }
 
 //GetPath returns the storage path of the action plugin
@@ -66,9 +88,10 @@ const (
 
 // Builtin Action
 const (
-	ScriptAction   = "Script"
-	JUnitAction    = "JUnit"
-	GitCloneAction = "GitClone"
+	ScriptAction         = "Script"
+	JUnitAction          = "JUnit"
+	GitCloneAction       = "GitClone"
+	StaticAnalysisAction = "StaticAnalysis"
 )
 
 const (
@@ -86,6 +109,12 @@ const (
 	ServiceRequirement = "service"
 	//MemoryRequirement set memory limit on a container
 	MemoryRequirement = "memory"
+	//CPURequirement refers to the need of a number of CPUs, ex: 2 or 0.5
+	CPURequirement = "cpu"
+	//DiskRequirement refers to the need of free disk space in MB
+	DiskRequirement = "disk"
+	//OSArchRequirement refers to the need of an operating system and an architecture, ex: linux/amd64
+	OSArchRequirement = "os-arch"
 )
 
 var (
@@ -98,6 +127,9 @@ var (
 		PluginRequirement,
 		ServiceRequirement,
 		MemoryRequirement,
+		CPURequirement,
+		DiskRequirement,
+		OSArchRequirement,
 	}
 )
 
@@ -9,24 +9,25 @@ import (
 
 	"github.com/facebookgo/httpcontrol"
 	"github.com/hashicorp/hcl"
+	"gopkg.in/yaml.v2"
 )
 
-//ActionScript represents the structure of a HCL action file
+//ActionScript represents the structure of a HCL or YAML action file
 type ActionScript struct {
-	Name         string                 `json:"name"`
-	Description  string                 `json:"description,omitempty"`
-	Requirements map[string]Requirement `json:"requirement,omitempty"`
-	Parameters   map[string]Parameter   `json:"parameters,omitempty"`
+	Name         string                 `json:"name" yaml:"name"`
+	Description  string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Requirements map[string]Requirement `json:"requirement,omitempty" yaml:"requirements,omitempty"`
+	Parameters   map[string]Parameter   `json:"parameters,omitempty" yaml:"parameters,omitempty"`
 	Steps        []struct {
-		Enabled          *bool                        `json:"enabled"`
-		AlwaysExecuted   bool                         `json:"always_executed"`
-		ArtifactUpload   map[string]string            `json:"artifactUpload,omitempty"`
-		ArtifactDownload map[string]string            `json:"artifactDownload,omitempty"`
-		GitClone         map[string]string            `json:"gitClone,omitempty"`
-		Script           string                       `json:"script,omitempty"`
-		JUnitReport      string                       `json:"jUnitReport,omitempty"`
-		Plugin           map[string]map[string]string `json:"plugin,omitempty"`
-	} `json:"steps"`
+		Enabled          *bool                        `json:"enabled" yaml:"enabled,omitempty"`
+		AlwaysExecuted   bool                         `json:"always_executed" yaml:"always_executed,omitempty"`
+		ArtifactUpload   map[string]string            `json:"artifactUpload,omitempty" yaml:"artifactUpload,omitempty"`
+		ArtifactDownload map[string]string            `json:"artifactDownload,omitempty" yaml:"artifactDownload,omitempty"`
+		GitClone         map[string]string            `json:"gitClone,omitempty" yaml:"gitClone,omitempty"`
+		Script           string                       `json:"script,omitempty" yaml:"script,omitempty"`
+		JUnitReport      string                       `json:"jUnitReport,omitempty" yaml:"jUnitReport,omitempty"`
+		Plugin           map[string]map[string]string `json:"plugin,omitempty" yaml:"plugin,omitempty"`
+	} `json:"steps" yaml:"steps"`
 }
 
 // NewStepScript returns an action (basically used as a step of a job) of Script type
@@ -134,7 +135,19 @@ func NewActionFromScript(btes []byte) (*Action, error) {
 	if err := hcl.Decode(&as, string(btes)); err != nil {
 		return nil, err
 	}
+	return newActionFromActionScript(as, btes)
+}
+
+//NewActionFromYAMLScript creates an action from a YAML file as bytes
+func NewActionFromYAMLScript(btes []byte) (*Action, error) {
+	as := ActionScript{}
+	if err := yaml.Unmarshal(btes, &as); err != nil {
+		return nil, err
+	}
+	return newActionFromActionScript(as, btes)
+}
 
+func newActionFromActionScript(as ActionScript, btes []byte) (*Action, error) {
 	a := Action{
 		Name:         as.Name,
 		Description:  as.Description,
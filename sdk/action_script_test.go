@@ -78,6 +78,44 @@ EOF
 	assert.Equal(t, len(a.Actions), 2)
 }
 
+func TestLoadFromYAMLActionScript(t *testing.T) {
+	b := []byte(`
+name: TestGoBuild
+description: Build a go package
+requirements:
+  go:
+    type: binary
+    value: go
+parameters:
+  package:
+    type: string
+    desc: package to build
+    value: ./...
+steps:
+- gitClone:
+    directory: src
+- script: go build {{.package}}
+  always_executed: true
+- jUnitReport: "*.xml"
+  enabled: false
+`)
+	a, err := NewActionFromYAMLScript(b)
+	assert.NotNil(t, a)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "TestGoBuild", a.Name)
+	assert.Equal(t, "Build a go package", a.Description)
+	assert.Equal(t, 1, len(a.Requirements))
+	assert.Equal(t, 1, len(a.Parameters))
+	assert.Equal(t, "package to build", a.Parameters[0].Description)
+	assert.Equal(t, 3, len(a.Actions))
+	assert.Equal(t, GitCloneAction, a.Actions[0].Name)
+	assert.Equal(t, ScriptAction, a.Actions[1].Name)
+	assert.Equal(t, true, a.Actions[1].AlwaysExecuted)
+	assert.Equal(t, JUnitAction, a.Actions[2].Name)
+	assert.Equal(t, false, a.Actions[2].Enabled)
+}
+
 func TestLoadFromRemoteActionScript(t *testing.T) {
 	a, err := NewActionFromRemoteScript("https://raw.githubusercontent.com/ovh/cds/master/contrib/actions/cds-docker-package.hcl", nil)
 	assert.NotNil(t, a)
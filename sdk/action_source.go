@@ -0,0 +1,83 @@
+package sdk
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ActionSource is a git repository from which the actions of a project are synchronized. The repository is given
+// either by a repository of a repositories manager linked to the project, or by a git URL and the name of a project
+// key to clone it.
+type ActionSource struct {
+	ID                  int64               `json:"id" db:"id" cli:"-"`
+	ProjectID           int64               `json:"project_id" db:"project_id" cli:"-"`
+	Name                string              `json:"name" db:"name" cli:"name"`
+	RepositoriesManager string              `json:"repositories_manager,omitempty" db:"repositories_manager" cli:"-"`
+	RepositoryFullname  string              `json:"repository_fullname,omitempty" db:"repository_fullname" cli:"repository"`
+	URL                 string              `json:"url,omitempty" db:"url" cli:"url"`
+	KeyName             string              `json:"key_name,omitempty" db:"key_name" cli:"-"`
+	Branch              string              `json:"branch,omitempty" db:"branch" cli:"branch"`
+	Directory           string              `json:"directory,omitempty" db:"directory" cli:"-"`
+	LastCommit          string              `json:"last_commit,omitempty" db:"last_commit" cli:"commit"`
+	LastSync            time.Time           `json:"last_sync" db:"last_sync" cli:"last_sync"`
+	SyncErrors          []ActionSourceError `json:"sync_errors,omitempty" db:"-" cli:"-"`
+}
+
+// ActionSourceError is an error raised while importing a file of an action source
+type ActionSourceError struct {
+	File    string `json:"file"`
+	Message string `json:"message"`
+}
+
+// IsValid checks that the repository of the source is given, and that its git URL is a remote one
+func (s *ActionSource) IsValid() error {
+	if s.Name == "" {
+		return ErrWrongRequest
+	}
+	hasRepo := s.RepositoriesManager != "" && s.RepositoryFullname != ""
+	if hasRepo == (s.URL != "") {
+		return ErrActionSourceInvalid
+	}
+	if s.URL != "" {
+		if _, err := GitURLHost(s.URL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GitURLHost returns the host of a remote git URL: an https or ssh URL, or a scp-like user@host:path URL. Local
+// paths, file URLs and values which could be read as an option by git are refused.
+func GitURLHost(rawurl string) (string, error) {
+	var host string
+	if strings.Contains(rawurl, "://") {
+		u, err := url.Parse(rawurl)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "ssh") {
+			return "", ErrActionSourceInvalidURL
+		}
+		host = u.Hostname()
+	} else {
+		at := strings.Index(rawurl, "@")
+		colon := strings.Index(rawurl, ":")
+		if at <= 0 || colon < at || colon == len(rawurl)-1 || strings.Contains(rawurl[:colon], "/") {
+			return "", ErrActionSourceInvalidURL
+		}
+		host = rawurl[at+1 : colon]
+	}
+	if strings.HasPrefix(rawurl, "-") || host == "" || strings.HasPrefix(host, "-") {
+		return "", ErrActionSourceInvalidURL
+	}
+	return host, nil
+}
+
+// IsActionFile returns true if the file, given by its path, is an action definition that can be imported from an
+// action source
+func IsActionFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".hcl", ".yml", ".yaml":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitURLHost(t *testing.T) {
+	valid := map[string]string{
+		"https://github.com/ovh/cds.git":          "github.com",
+		"ssh://git@github.com:22/ovh/cds.git":     "github.com",
+		"git@github.com:ovh/cds.git":              "github.com",
+		"git@gitlab.example.com:/srv/actions.git": "gitlab.example.com",
+	}
+	for u, host := range valid {
+		got, err := GitURLHost(u)
+		assert.NoError(t, err, u)
+		assert.Equal(t, host, got, u)
+	}
+
+	invalid := []string{
+		"/var/lib/cds/repo",
+		"../repo",
+		"file:///etc",
+		"file://localhost/var/lib/cds/repo",
+		"http://github.com/ovh/cds.git",
+		"--upload-pack=touch /tmp/pwned",
+		"-oProxyCommand=id@host:repo",
+		"git@-oProxyCommand=id:repo",
+		"github.com:ovh/cds.git",
+		"./git@host:repo",
+		"git@github.com:",
+	}
+	for _, u := range invalid {
+		_, err := GitURLHost(u)
+		assert.Error(t, err, u)
+	}
+}
@@ -0,0 +1,125 @@
+package sdk
+
+import "time"
+
+// ApprovalGate pauses a run until it has been approved by members of the given groups
+type ApprovalGate struct {
+	Groups       []string `json:"groups" yaml:"groups"`
+	MinApprovals int      `json:"min_approvals" yaml:"min_approvals"`
+	// Timeout in seconds, after which a run still waiting for approvals fails
+	Timeout int64 `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// Approval is the record of the approvals of a pipeline build or a workflow node run paused by an approval gate
+type Approval struct {
+	ID                int64          `json:"id" db:"id" cli:"id,key"`
+	ProjectID         int64          `json:"project_id" db:"project_id" cli:"-"`
+	PipelineBuildID   int64          `json:"pipeline_build_id,omitempty" db:"pipeline_build_id" cli:"-"`
+	WorkflowNodeRunID int64          `json:"workflow_node_run_id,omitempty" db:"workflow_node_run_id" cli:"-"`
+	Description       string         `json:"description" db:"description" cli:"description"`
+	Status            string         `json:"status" db:"status" cli:"status"`
+	Committer         string         `json:"committer,omitempty" db:"committer" cli:"committer"`
+	Created           time.Time      `json:"created" db:"created" cli:"-"`
+	LastModified      time.Time      `json:"last_modified" db:"last_modified" cli:"-"`
+	Gate              ApprovalGate   `json:"gate" db:"-" cli:"-"`
+	Votes             []ApprovalVote `json:"votes,omitempty" db:"-" cli:"-"`
+	Notified          bool           `json:"-" db:"notified" cli:"-"`
+}
+
+// ApprovalVote is the approval, or the rejection, of a run by a user
+type ApprovalVote struct {
+	Username string    `json:"username"`
+	Approved bool      `json:"approved"`
+	Comment  string    `json:"comment,omitempty"`
+	Date     time.Time `json:"date"`
+}
+
+// IsValid checks the gate has approver groups and a positive number of approvals
+func (g *ApprovalGate) IsValid() error {
+	if g == nil {
+		return nil
+	}
+	if len(g.Groups) == 0 || g.MinApprovals < 1 || g.Timeout < 0 {
+		return ErrInvalidApprovalGate
+	}
+	return nil
+}
+
+// NewApproval returns a waiting approval for the gate
+func NewApproval(g ApprovalGate, projectID int64, description, committer string) *Approval {
+	now := time.Now()
+	return &Approval{
+		ProjectID:    projectID,
+		Description:  description,
+		Status:       StatusWaiting.String(),
+		Committer:    committer,
+		Created:      now,
+		LastModified: now,
+		Gate:         g,
+	}
+}
+
+// Expire fails the approval if it is still waiting after the timeout of the gate. It returns true if the approval expired.
+func (a *Approval) Expire(now time.Time) bool {
+	if a.Status != StatusWaiting.String() || a.Gate.Timeout == 0 {
+		return false
+	}
+	if now.Before(a.Created.Add(time.Duration(a.Gate.Timeout) * time.Second)) {
+		return false
+	}
+	a.Status = StatusFail.String()
+	a.LastModified = now
+	return true
+}
+
+// Vote records the vote of the user. The user has to be a member of one of the approver groups, cannot
+// vote twice and cannot approve its own commit. A rejection fails the approval.
+func (a *Approval) Vote(u *User, approved bool, comment string, now time.Time) error {
+	if a.Status != StatusWaiting.String() {
+		return ErrApprovalClosed
+	}
+	if a.Committer != "" && (a.Committer == u.Username || a.Committer == u.Fullname || a.Committer == u.Email) {
+		return ErrApprovalForbidden
+	}
+	for _, v := range a.Votes {
+		if v.Username == u.Username {
+			return ErrApprovalForbidden
+		}
+	}
+
+	var member bool
+	for _, g := range u.Groups {
+		for _, name := range a.Gate.Groups {
+			if g.Name == name {
+				member = true
+			}
+		}
+	}
+	if !member {
+		return ErrApprovalForbidden
+	}
+
+	a.Votes = append(a.Votes, ApprovalVote{
+		Username: u.Username,
+		Approved: approved,
+		Comment:  comment,
+		Date:     now,
+	})
+	a.LastModified = now
+
+	if !approved {
+		a.Status = StatusFail.String()
+		return nil
+	}
+
+	var n int
+	for _, v := range a.Votes {
+		if v.Approved {
+			n++
+		}
+	}
+	if n >= a.Gate.MinApprovals {
+		a.Status = StatusSuccess.String()
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApprovalVote(t *testing.T) {
+	now := time.Now()
+	a := NewApproval(ApprovalGate{Groups: []string{"ops"}, MinApprovals: 2}, 1, "app/deploy #1", "john")
+
+	ops := []Group{{Name: "ops"}}
+	assert.Equal(t, ErrApprovalForbidden, a.Vote(&User{Username: "john", Groups: ops}, true, "", now))
+	assert.Equal(t, ErrApprovalForbidden, a.Vote(&User{Username: "jane", Groups: []Group{{Name: "dev"}}}, true, "", now))
+
+	assert.NoError(t, a.Vote(&User{Username: "jane", Groups: ops}, true, "ok", now))
+	assert.Equal(t, StatusWaiting.String(), a.Status)
+	assert.Equal(t, ErrApprovalForbidden, a.Vote(&User{Username: "jane", Groups: ops}, true, "", now))
+
+	assert.NoError(t, a.Vote(&User{Username: "bob", Groups: ops}, true, "", now))
+	assert.Equal(t, StatusSuccess.String(), a.Status)
+	assert.Equal(t, ErrApprovalClosed, a.Vote(&User{Username: "alice", Groups: ops}, true, "", now))
+}
+
+func TestApprovalReject(t *testing.T) {
+	a := NewApproval(ApprovalGate{Groups: []string{"ops"}, MinApprovals: 2}, 1, "app/deploy #1", "")
+	assert.NoError(t, a.Vote(&User{Username: "jane", Groups: []Group{{Name: "ops"}}}, false, "not now", time.Now()))
+	assert.Equal(t, StatusFail.String(), a.Status)
+}
+
+func TestApprovalExpire(t *testing.T) {
+	a := NewApproval(ApprovalGate{Groups: []string{"ops"}, MinApprovals: 1, Timeout: 60}, 1, "app/deploy #1", "")
+	assert.False(t, a.Expire(a.Created.Add(30*time.Second)))
+	assert.True(t, a.Expire(a.Created.Add(61*time.Second)))
+	assert.Equal(t, StatusFail.String(), a.Status)
+}
@@ -3,6 +3,7 @@ package sdk
 import (
 	"bytes"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -33,9 +34,101 @@ type Artifact struct {
 	Size         int64  `json:"size,omitempty"`
 	Perm         uint32 `json:"perm,omitempty"`
 	MD5sum       string `json:"md5sum,omitempty"`
+	SHA256Sum    string `json:"sha256sum,omitempty"`
 	ObjectPath   string `json:"object_path,omitempty"`
 }
 
+// ArtifactManifestName is the name of the manifest added to artifact archives
+const ArtifactManifestName = ".cds-manifest.json"
+
+// ArtifactManifest describes the content of an artifact archive
+type ArtifactManifest struct {
+	Files []ArtifactManifestFile `json:"files"`
+}
+
+// CheckArtifactEntryName checks that the name of a file of an artifact archive is relative to the archive root,
+// so that extracting the archive cannot write outside of the extraction directory
+func CheckArtifactEntryName(name string) error {
+	clean := path.Clean(filepath.ToSlash(name))
+	if name == "" || path.IsAbs(clean) || filepath.IsAbs(name) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("invalid archive entry %s", name)
+	}
+	return nil
+}
+
+// ArtifactManifestFile is a file of an artifact archive
+type ArtifactManifestFile struct {
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	Perm      uint32 `json:"perm"`
+	SHA256Sum string `json:"sha256sum"`
+}
+
+// ArtifactChunkedUpload is a chunked upload of a workflow artifact. Received contains the indexes
+// of the chunks already received by the API, so an interrupted upload can be resumed.
+type ArtifactChunkedUpload struct {
+	Ref       string `json:"ref"`
+	JobID     int64  `json:"job_id"`
+	Name      string `json:"name"`
+	Tag       string `json:"tag"`
+	Size      int64  `json:"size"`
+	Perm      uint32 `json:"perm"`
+	MD5sum    string `json:"md5sum"`
+	SHA256Sum string `json:"sha256sum"`
+	ChunkSize int64  `json:"chunk_size"`
+	Received  []int  `json:"received"`
+}
+
+// NbChunks returns the number of chunks of the upload
+func (u *ArtifactChunkedUpload) NbChunks() int {
+	if u.ChunkSize <= 0 {
+		return 1
+	}
+	n := int((u.Size + u.ChunkSize - 1) / u.ChunkSize)
+	if n == 0 {
+		n = 1
+	}
+	return n
+}
+
+// IsReceived returns true if the chunk has already been received
+func (u *ArtifactChunkedUpload) IsReceived(chunk int) bool {
+	for _, c := range u.Received {
+		if c == chunk {
+			return true
+		}
+	}
+	return false
+}
+
+// FileChecksums computes the md5 and sha256 sums of a file
+func FileChecksums(filePath string) (string, string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	md5Hash := md5.New()
+	sha256Hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(md5Hash, sha256Hash), f); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(md5Hash.Sum(nil)), hex.EncodeToString(sha256Hash.Sum(nil)), nil
+}
+
+// CheckSHA256 returns ErrArtifactChecksumMismatch if the computed sum does not match the expected one.
+// An empty expected sum is not checked, artifacts uploaded by older workers do not have one
+func CheckSHA256(expected string, computed []byte) error {
+	if expected == "" {
+		return nil
+	}
+	if sum := hex.EncodeToString(computed); !strings.EqualFold(sum, expected) {
+		return WrapError(ErrArtifactChecksumMismatch, "expected sha256 %s, got %s", expected, sum)
+	}
+	return nil
+}
+
 //GetName returns the name the artifact
 func (a *Artifact) GetName() string {
 	return a.Name
@@ -57,7 +150,8 @@ const (
 
 // Header name for artifact upload
 const (
-	ArtifactFileName = "ARTIFACT-FILENAME"
+	ArtifactFileName    = "ARTIFACT-FILENAME"
+	ArtifactChunkSHA256 = "ARTIFACT-CHUNK-SHA256"
 )
 
 // DownloadArtifacts retrieves and download artifacts related to given project-pipeline-tag
@@ -100,18 +194,23 @@ func download(project, app, pip string, a Artifact, destdir string) error {
 			mode = os.FileMode(a.Perm)
 		}
 
-		f, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY, mode)
+		f, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
 		if err != nil {
 			lasterr = err
 			continue
 		}
 
-		_, err = io.Copy(f, reader)
+		h := sha256.New()
+		_, err = io.Copy(io.MultiWriter(f, h), reader)
+		if err == nil {
+			err = CheckSHA256(a.SHA256Sum, h.Sum(nil))
+		}
 		if err != nil {
 			lasterr = err
 		}
 
 		f.Close()
+		reader.Close()
 		if err == nil {
 			return nil
 		}
@@ -187,27 +286,18 @@ func UploadArtifact(project string, pipeline string, application string, tag str
 func uploadArtifact(project string, pipeline string, application string, tag string, filePath string, buildNumber int, env string) error {
 	uri := fmt.Sprintf("/project/%s/application/%s/pipeline/%s/%d/artifact/%s", project, application, pipeline, buildNumber, tag)
 
-	fileForMD5, errop := os.Open(filePath)
-	if errop != nil {
-		return errop
-	}
-
 	//File stat
-	stat, errst := fileForMD5.Stat()
+	stat, errst := os.Stat(filePath)
 	if errst != nil {
 		return errst
 	}
 
-	//Compute md5sum
-	hash := md5.New()
-	if _, errcopy := io.Copy(hash, fileForMD5); errcopy != nil {
-		return errcopy
+	//Compute md5sum and sha256sum
+	md5sumStr, sha256sumStr, errsum := FileChecksums(filePath)
+	if errsum != nil {
+		return errsum
 	}
-	hashInBytes := hash.Sum(nil)[:16]
-	md5sumStr := hex.EncodeToString(hashInBytes)
-	fileForMD5.Close()
 
-	//Reopen the file because we already read it for md5
 	fileReopen, erro := os.Open(filePath)
 	if erro != nil {
 		return erro
@@ -230,6 +320,7 @@ func uploadArtifact(project string, pipeline string, application string, tag str
 	writer.WriteField("size", strconv.FormatInt(stat.Size(), 10))
 	writer.WriteField("perm", strconv.FormatUint(uint64(stat.Mode().Perm()), 10))
 	writer.WriteField("md5sum", md5sumStr)
+	writer.WriteField("sha256sum", sha256sumStr)
 
 	if errclose := writer.Close(); errclose != nil {
 		return errclose
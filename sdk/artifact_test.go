@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestArtifactChunkedUploadNbChunks(t *testing.T) {
+	tests := []struct {
+		size, chunkSize int64
+		want            int
+	}{
+		{0, 10, 1},
+		{5, 10, 1},
+		{10, 10, 1},
+		{11, 10, 2},
+		{100, 10, 10},
+		{100, 0, 1},
+	}
+	for _, tt := range tests {
+		u := ArtifactChunkedUpload{Size: tt.size, ChunkSize: tt.chunkSize}
+		if got := u.NbChunks(); got != tt.want {
+			t.Errorf("NbChunks() with size %d and chunk size %d = %d, want %d", tt.size, tt.chunkSize, got, tt.want)
+		}
+	}
+}
+
+func TestCheckSHA256(t *testing.T) {
+	sum := sha256.Sum256([]byte("foo"))
+	if err := CheckSHA256("2C26B46B68FFC68FF99B453C1D30413413422D706483BFA0F98A5E886266E7AE", sum[:]); err != nil {
+		t.Errorf("CheckSHA256() returned %v", err)
+	}
+	if err := CheckSHA256("", sum[:]); err != nil {
+		t.Errorf("CheckSHA256() without expected sum returned %v", err)
+	}
+	if err := CheckSHA256("0000", sum[:]); errors.Cause(err) != ErrArtifactChecksumMismatch {
+		t.Errorf("CheckSHA256() returned %v, want %v", err, ErrArtifactChecksumMismatch)
+	}
+}
+
+func TestCheckArtifactEntryName(t *testing.T) {
+	for _, name := range []string{"report.xml", "dist/app.js", "dist/../app.js", "./app.js"} {
+		if err := CheckArtifactEntryName(name); err != nil {
+			t.Errorf("CheckArtifactEntryName(%q) returned %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "..", "../app.js", "dist/../../app.js", "/etc/passwd"} {
+		if err := CheckArtifactEntryName(name); err == nil {
+			t.Errorf("CheckArtifactEntryName(%q) returned no error", name)
+		}
+	}
+}
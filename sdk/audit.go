@@ -1,8 +1,28 @@
 package sdk
 
+import "time"
+
 // Different type of Audit event
 const (
 	AuditAdd    = "add"
 	AuditUpdate = "update"
 	AuditDelete = "delete"
 )
+
+// AuditEvent is a mutating call done on the API by a user. The target of the call is given by the variables of its
+// route, and Before and After summarize the change made on the target, when the handler of the call gives them.
+type AuditEvent struct {
+	ID         int64     `json:"id" db:"id" cli:"-"`
+	Created    time.Time `json:"created" db:"created" cli:"created"`
+	Username   string    `json:"username" db:"username" cli:"username"`
+	Admin      bool      `json:"admin" db:"admin" cli:"-"`
+	Method     string    `json:"method" db:"method" cli:"method"`
+	Route      string    `json:"route" db:"route" cli:"-"`
+	Path       string    `json:"path" db:"path" cli:"path"`
+	TargetType string    `json:"target_type" db:"target_type" cli:"target_type"`
+	Target     string    `json:"target" db:"target" cli:"target"`
+	Status     int       `json:"status" db:"status" cli:"status"`
+	SourceIP   string    `json:"source_ip" db:"source_ip" cli:"source_ip"`
+	Before     string    `json:"before,omitempty" db:"before_summary" cli:"-"`
+	After      string    `json:"after,omitempty" db:"after_summary" cli:"-"`
+}
@@ -0,0 +1,78 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+// RestoreCipherKeyHeader is the header giving the cipher key of the instance a backup has been made on, so that its
+// secrets are re-encrypted with the cipher key of the instance it is restored on
+const RestoreCipherKeyHeader = "X-Cds-Restore-Cipher-Key"
+
+// BackupManifest describes the content of a backup archive of a CDS instance
+type BackupManifest struct {
+	Version   string        `json:"version"`
+	Migration string        `json:"migration"`
+	Created   time.Time     `json:"created"`
+	Artifacts bool          `json:"artifacts"`
+	Tables    []BackupTable `json:"tables"`
+	Objects   int           `json:"objects"`
+}
+
+// BackupTable is a table of the database saved in a backup archive
+type BackupTable struct {
+	Name string `json:"name"`
+	Rows int    `json:"rows"`
+}
+
+// Backup downloads a backup archive of the instance in the given writer. The artifacts are saved only if
+// withArtifacts is true.
+func Backup(withArtifacts bool, w io.Writer) error {
+	uri := "/admin/backup"
+	if withArtifacts {
+		uri += "?artifacts=true"
+	}
+	reader, code, err := Stream("GET", uri, nil)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if code >= 300 {
+		body, _ := ioutil.ReadAll(reader)
+		if err := DecodeError(body); err != nil {
+			return err
+		}
+		return fmt.Errorf("HTTP %d", code)
+	}
+
+	_, err = io.Copy(w, reader)
+	return err
+}
+
+// Restore restores a backup archive on the instance, which must not have any project. If previousCipherKey is set,
+// the secrets of the backup are re-encrypted with the cipher key of the instance.
+func Restore(archive io.ReadCloser, previousCipherKey string) (*BackupManifest, error) {
+	mods := []RequestModifier{SetHeader("Content-Type", "application/gzip")}
+	if previousCipherKey != "" {
+		mods = append(mods, SetHeader(RestoreCipherKeyHeader, previousCipherKey))
+	}
+	data, code, err := Upload("POST", "/admin/restore", archive, mods...)
+	if err != nil {
+		return nil, err
+	}
+	if err := DecodeError(data); err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	var m BackupManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
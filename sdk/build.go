@@ -22,6 +22,7 @@ type PipelineBuildJob struct {
 	PipelineBuildID int64                  `json:"pipeline_build_id,omitempty" db:"pipeline_build_id"`
 	BookedBy        Hatchery               `json:"bookedby" db:"-"`
 	SpawnInfos      []SpawnInfo            `json:"spawninfos" db:"-"`
+	Priority        int                    `json:"priority" db:"priority"`
 }
 
 // SpawnInfo contains an information about spawning
@@ -84,6 +85,8 @@ func StatusFromString(in string) Status {
 		return StatusDisabled
 	case StatusSkipped.String():
 		return StatusSkipped
+	case StatusStopped.String():
+		return StatusStopped
 	default:
 		return StatusUnknown
 	}
@@ -104,6 +107,7 @@ const (
 	StatusNeverBuilt Status = "Never Built"
 	StatusUnknown    Status = "Unknown"
 	StatusSkipped    Status = "Skipped"
+	StatusStopped    Status = "Stopped"
 )
 
 // GetBuildQueue retrieves current CDS build in queue
@@ -127,6 +131,25 @@ func GetBuildQueue() ([]PipelineBuildJob, error) {
 	return q, nil
 }
 
+// GetWorkflowQueue retrieves the jobs of workflow runs in queue
+func GetWorkflowQueue() ([]WorkflowNodeJobRun, error) {
+	var q []WorkflowNodeJobRun
+
+	data, code, err := Request("GET", "/queue/workflows", nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	if err = json.Unmarshal(data, &q); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
 // GetBuildState Get the state of given build
 func GetBuildState(projectKey, appName, pipelineName, env, buildID string) (PipelineBuild, error) {
 	var buildState PipelineBuild
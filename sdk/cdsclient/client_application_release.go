@@ -0,0 +1,49 @@
+package cdsclient
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) ApplicationReleaseList(projectKey, appName string) ([]sdk.Release, error) {
+	url := fmt.Sprintf("/project/%s/application/%s/release", projectKey, appName)
+	rels := []sdk.Release{}
+	if _, err := c.GetJSON(url, &rels); err != nil {
+		return nil, err
+	}
+	return rels, nil
+}
+
+func (c *client) ApplicationReleaseGet(projectKey, appName, version string) (*sdk.Release, error) {
+	url := fmt.Sprintf("/project/%s/application/%s/release/%s", projectKey, appName, version)
+	rel := &sdk.Release{}
+	if _, err := c.GetJSON(url, rel); err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
+
+func (c *client) ApplicationReleasePromote(projectKey, appName string, promotion sdk.ReleasePromotion) (*sdk.Release, error) {
+	url := fmt.Sprintf("/project/%s/application/%s/release", projectKey, appName)
+	rel := &sdk.Release{}
+	if _, err := c.PostJSON(url, promotion, rel); err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
+
+func (c *client) ApplicationReleaseArtifactDownload(projectKey, appName, version, name string, w io.Writer) error {
+	path := fmt.Sprintf("/project/%s/application/%s/release/%s/artifact/%s", projectKey, appName, version, url.PathEscape(name))
+	reader, _, err := c.Stream("GET", path, nil)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if _, err := io.Copy(w, reader); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package cdsclient
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) ApprovalList(projectKey, status string) ([]sdk.Approval, error) {
+	path := fmt.Sprintf("/project/%s/approval", projectKey)
+	if status != "" {
+		path += "?status=" + url.QueryEscape(status)
+	}
+	approvals := []sdk.Approval{}
+	if _, err := c.GetJSON(path, &approvals); err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+func (c *client) ApprovalGet(projectKey string, id int64) (*sdk.Approval, error) {
+	path := fmt.Sprintf("/project/%s/approval/%d", projectKey, id)
+	a := &sdk.Approval{}
+	if _, err := c.GetJSON(path, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (c *client) ApprovalVote(projectKey string, id int64, approved bool, comment string) (*sdk.Approval, error) {
+	path := fmt.Sprintf("/project/%s/approval/%d", projectKey, id)
+	vote := sdk.ApprovalVote{Approved: approved, Comment: comment}
+	a := &sdk.Approval{}
+	if _, err := c.PostJSON(path, vote, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
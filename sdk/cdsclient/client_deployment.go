@@ -0,0 +1,39 @@
+package cdsclient
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) DeploymentList(projectKey, environment, application string) ([]sdk.WorkflowDeployment, error) {
+	query := url.Values{}
+	if projectKey != "" {
+		query.Set("project", projectKey)
+	}
+	if environment != "" {
+		query.Set("environment", environment)
+	}
+	if application != "" {
+		query.Set("application", application)
+	}
+	path := "/workflow/deployment"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	deployments := []sdk.WorkflowDeployment{}
+	if _, err := c.GetJSON(path, &deployments); err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
+
+func (c *client) DeploymentRollback(projectKey, workflowName string, id int64) (*sdk.WorkflowRun, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/deployment/%d/rollback", projectKey, url.QueryEscape(workflowName), id)
+	wr := &sdk.WorkflowRun{}
+	if _, err := c.PostJSON(path, nil, wr); err != nil {
+		return nil, err
+	}
+	return wr, nil
+}
@@ -3,7 +3,7 @@ package cdsclient
 import (
 	"bytes"
 	"context"
-	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -14,6 +14,8 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/runabove/venom"
+
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
 )
@@ -127,28 +129,47 @@ func (c *client) QueueSendResult(id int64, res sdk.Result) error {
 	return nil
 }
 
-func (c *client) QueueArtifactUpload(id int64, tag, filePath string) error {
-	fileForMD5, errop := os.Open(filePath)
-	if errop != nil {
-		return errop
+func (c *client) QueueSendUnitTests(id int64, report venom.Tests) error {
+	var path = fmt.Sprintf("/queue/workflows/%d/test", id)
+
+	if code, err := c.PostJSON(path, report, nil); err != nil {
+		return err
+	} else if code >= 300 {
+		return fmt.Errorf("HTTP Error: %d", code)
+	}
+	return nil
+}
+
+func (c *client) QueueSendStaticAnalysis(id int64, findings []sdk.StaticAnalysisFinding) (*sdk.StaticAnalysisSummary, error) {
+	var path = fmt.Sprintf("/queue/workflows/%d/analysis", id)
+	var summary sdk.StaticAnalysisSummary
+
+	if code, err := c.PostJSON(path, findings, &summary); err != nil {
+		return nil, err
+	} else if code != http.StatusOK {
+		return nil, fmt.Errorf("HTTP Error: %d", code)
 	}
+	return &summary, nil
+}
 
+func (c *client) QueueArtifactUpload(id int64, tag, filePath string) error {
 	//File stat
-	stat, errst := fileForMD5.Stat()
+	stat, errst := os.Stat(filePath)
 	if errst != nil {
 		return errst
 	}
 
-	//Compute md5sum
-	hash := md5.New()
-	if _, errcopy := io.Copy(hash, fileForMD5); errcopy != nil {
-		return errcopy
+	//Compute md5sum and sha256sum
+	md5sumStr, sha256sumStr, errsum := sdk.FileChecksums(filePath)
+	if errsum != nil {
+		return errsum
+	}
+
+	// Large files are sent by chunks, so that an upload can be resumed
+	if stat.Size() > chunkedUploadThreshold {
+		return c.queueArtifactChunkedUpload(id, tag, filePath, stat, md5sumStr, sha256sumStr)
 	}
-	hashInBytes := hash.Sum(nil)[:16]
-	md5sumStr := hex.EncodeToString(hashInBytes)
-	fileForMD5.Close()
 
-	//Reopen the file because we already read it for md5
 	fileReopen, erro := os.Open(filePath)
 	if erro != nil {
 		return erro
@@ -170,6 +191,7 @@ func (c *client) QueueArtifactUpload(id int64, tag, filePath string) error {
 	writer.WriteField("size", strconv.FormatInt(stat.Size(), 10))
 	writer.WriteField("perm", strconv.FormatUint(uint64(stat.Mode().Perm()), 10))
 	writer.WriteField("md5sum", md5sumStr)
+	writer.WriteField("sha256sum", sha256sumStr)
 
 	if errclose := writer.Close(); errclose != nil {
 		return errclose
@@ -186,5 +208,68 @@ func (c *client) QueueArtifactUpload(id int64, tag, filePath string) error {
 		time.Sleep(1 * time.Second)
 	}
 
-	return fmt.Errorf("x%s: %v", c.config.Retry, err)
+	return fmt.Errorf("x%d: %v", c.config.Retry, err)
+}
+
+// chunkedUploadThreshold is the size above which artifacts are sent by chunks
+const chunkedUploadThreshold = 32 * 1024 * 1024
+
+func (c *client) queueArtifactChunkedUpload(id int64, tag, filePath string, stat os.FileInfo, md5sum, sha256sum string) error {
+	up := sdk.ArtifactChunkedUpload{
+		Name:      filepath.Base(filePath),
+		Size:      stat.Size(),
+		Perm:      uint32(stat.Mode().Perm()),
+		MD5sum:    md5sum,
+		SHA256Sum: sha256sum,
+	}
+
+	// Start the upload. If it has already been started, the API returns the chunks it has already received
+	uri := fmt.Sprintf("/queue/workflows/%d/artifact/%s/upload", id, tag)
+	if code, err := c.PostJSON(uri, up, &up); err != nil {
+		return err
+	} else if code >= 300 {
+		return fmt.Errorf("HTTP Error: %d", code)
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	uri = fmt.Sprintf("%s/%s", uri, up.Ref)
+	buf := make([]byte, up.ChunkSize)
+	for i := 0; i < up.NbChunks(); i++ {
+		if up.IsReceived(i) {
+			continue
+		}
+
+		n, errR := f.ReadAt(buf, int64(i)*up.ChunkSize)
+		if errR != nil && errR != io.EOF {
+			return errR
+		}
+		sum := sha256.Sum256(buf[:n])
+
+		var code int
+		for r := 0; r <= c.config.Retry; r++ {
+			_, code, err = c.Request("PUT", fmt.Sprintf("%s?chunk=%d", uri, i), buf[:n], SetHeader(sdk.ArtifactChunkSHA256, hex.EncodeToString(sum[:])))
+			if err == nil && code < 300 {
+				break
+			}
+			time.Sleep(1 * time.Second)
+		}
+		if err != nil {
+			return fmt.Errorf("cannot upload chunk %d: %v", i, err)
+		}
+		if code >= 300 {
+			return fmt.Errorf("cannot upload chunk %d: HTTP Error %d", i, code)
+		}
+	}
+
+	if _, code, err := c.Request("POST", uri, nil); err != nil {
+		return err
+	} else if code >= 300 {
+		return fmt.Errorf("HTTP Error: %d", code)
+	}
+	return nil
 }
@@ -2,6 +2,7 @@ package cdsclient
 
 import (
 	"io"
+	"net/url"
 
 	"fmt"
 
@@ -44,6 +45,18 @@ func (c *client) WorkflowRunArtifacts(projectKey string, name string, number int
 	return arts, nil
 }
 
+func (c *client) WorkflowLatestArtifacts(projectKey string, name string, branch string) ([]sdk.Artifact, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/artifacts/latest", projectKey, name)
+	if branch != "" {
+		path += "?branch=" + url.QueryEscape(branch)
+	}
+	arts := []sdk.Artifact{}
+	if _, err := c.GetJSON(path, &arts); err != nil {
+		return nil, err
+	}
+	return arts, nil
+}
+
 func (c *client) WorkflowNodeRun(projectKey string, name string, number int64, nodeRunID int64) (*sdk.WorkflowNodeRun, error) {
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d", projectKey, name, number, nodeRunID)
 	run := sdk.WorkflowNodeRun{}
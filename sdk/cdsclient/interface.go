@@ -6,6 +6,8 @@ import (
 
 	"io"
 
+	"github.com/runabove/venom"
+
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
 )
@@ -13,6 +15,15 @@ import (
 // Interface is the main interface for cdsclient package
 type Interface interface {
 	APIURL() string
+	ApplicationReleaseList(projectKey, appName string) ([]sdk.Release, error)
+	ApplicationReleaseGet(projectKey, appName, version string) (*sdk.Release, error)
+	ApplicationReleasePromote(projectKey, appName string, promotion sdk.ReleasePromotion) (*sdk.Release, error)
+	ApplicationReleaseArtifactDownload(projectKey, appName, version, name string, w io.Writer) error
+	ApprovalList(projectKey, status string) ([]sdk.Approval, error)
+	ApprovalGet(projectKey string, id int64) (*sdk.Approval, error)
+	ApprovalVote(projectKey string, id int64, approved bool, comment string) (*sdk.Approval, error)
+	DeploymentList(projectKey, environment, application string) ([]sdk.WorkflowDeployment, error)
+	DeploymentRollback(projectKey, workflowName string, id int64) (*sdk.WorkflowRun, error)
 	MonStatus() ([]string, error)
 	ProjectCreate(*sdk.Project) error
 	ProjectDelete(string) error
@@ -26,6 +37,8 @@ type Interface interface {
 	QueueJobInfo(int64) (*sdk.WorkflowNodeJobRun, error)
 	QueueSendResult(int64, sdk.Result) error
 	QueueArtifactUpload(id int64, tag, filePath string) error
+	QueueSendUnitTests(id int64, report venom.Tests) error
+	QueueSendStaticAnalysis(id int64, findings []sdk.StaticAnalysisFinding) (*sdk.StaticAnalysisSummary, error)
 	Requirements() ([]sdk.Requirement, error)
 	UserLogin(username, password string) (bool, string, error)
 	UserList() ([]sdk.User, error)
@@ -40,6 +53,7 @@ type Interface interface {
 	WorkflowGet(projectKey, name string) (*sdk.Workflow, error)
 	WorkflowRun(projectKey string, name string, number int64) (*sdk.WorkflowRun, error)
 	WorkflowRunArtifacts(projectKey string, name string, number int64) ([]sdk.Artifact, error)
+	WorkflowLatestArtifacts(projectKey string, name string, branch string) ([]sdk.Artifact, error)
 	WorkflowNodeRun(projectKey string, name string, number int64, nodeRunID int64) (*sdk.WorkflowNodeRun, error)
 	WorkflowNodeRunArtifacts(projectKey string, name string, number int64, nodeRunID int64) ([]sdk.Artifact, error)
 	WorkflowNodeRunArtifactDownload(projectKey string, name string, artifactID int64, w io.Writer) error
@@ -0,0 +1,22 @@
+package sdk
+
+import "time"
+
+// ChatOpsSlack is the provider of the accounts of Slack compatible chats
+const ChatOpsSlack = "slack"
+
+// ChatOpsAccount links the account of a user on a chat to a CDS user, so that the user can run chat commands
+type ChatOpsAccount struct {
+	ID         int64     `json:"id" db:"id" cli:"id"`
+	UserID     int64     `json:"user_id" db:"user_id" cli:"-"`
+	Provider   string    `json:"provider" db:"provider" cli:"provider"`
+	TeamID     string    `json:"team_id" db:"team_id" cli:"team"`
+	ChatUserID string    `json:"chat_user_id" db:"chat_user_id" cli:"user"`
+	Created    time.Time `json:"created" db:"created" cli:"created"`
+}
+
+// ChatOpsLinkCode is a one-time code to link a chat account to a CDS user, with the link chat command
+type ChatOpsLinkCode struct {
+	Code   string    `json:"code" cli:"code"`
+	Expire time.Time `json:"expire" cli:"expire"`
+}
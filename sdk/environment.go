@@ -18,6 +18,11 @@ type Environment struct {
 	ProjectKey        string            `json:"project_key" yaml:"-"`
 	Permission        int               `json:"permission"`
 	LastModified      int64             `json:"last_modified"`
+	// Exclusive environments run only one deployment at a time
+	Exclusive bool `json:"exclusive" yaml:"exclusive,omitempty"`
+	// ExclusiveApplications run only one deployment at a time per application on the environment
+	ExclusiveApplications []string `json:"exclusive_applications,omitempty" yaml:"exclusive_applications,omitempty"`
+	LockPolicy            string   `json:"lock_policy,omitempty" yaml:"lock_policy,omitempty"`
 }
 
 // EnvironmentVariableAudit represents an audit on an environment variable
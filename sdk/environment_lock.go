@@ -0,0 +1,56 @@
+package sdk
+
+import "time"
+
+// Environment lock policies: what to do with a run targeting an environment locked by another run
+const (
+	// EnvironmentLockQueue keeps the run waiting until the lock is released
+	EnvironmentLockQueue = "queue"
+	// EnvironmentLockCancelOlder stops the run holding the lock
+	EnvironmentLockCancelOlder = "cancel_older"
+	// EnvironmentLockCancelNewer stops the run asking for the lock
+	EnvironmentLockCancelNewer = "cancel_newer"
+)
+
+// EnvironmentLock is held by a pipeline build or a workflow node run deploying on an environment.
+// ApplicationID is 0 when the lock is held on the whole environment.
+type EnvironmentLock struct {
+	EnvironmentID     int64     `json:"environment_id" db:"environment_id"`
+	ApplicationID     int64     `json:"application_id,omitempty" db:"application_id"`
+	PipelineBuildID   int64     `json:"pipeline_build_id,omitempty" db:"pipeline_build_id"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id,omitempty" db:"workflow_node_run_id"`
+	Holder            string    `json:"holder" db:"holder"`
+	Since             time.Time `json:"since" db:"since"`
+}
+
+// IsValidEnvironmentLockPolicy checks the lock policy, an empty policy means EnvironmentLockQueue
+func IsValidEnvironmentLockPolicy(policy string) error {
+	switch policy {
+	case "", EnvironmentLockQueue, EnvironmentLockCancelOlder, EnvironmentLockCancelNewer:
+		return nil
+	}
+	return WrapError(ErrInvalidEnvironmentLockPolicy, "IsValidEnvironmentLockPolicy> invalid policy %s", policy)
+}
+
+// LockKey returns the application ID the lock has to be taken on for a deployment of the application on
+// the environment: 0 if the environment is exclusive, the application ID if the application is exclusive.
+// It returns false if the deployment does not need a lock.
+func (e *Environment) LockKey(appID int64, appName string) (int64, bool) {
+	if e == nil || e.ID == DefaultEnv.ID {
+		return 0, false
+	}
+	if e.Exclusive {
+		return 0, true
+	}
+	for _, a := range e.ExclusiveApplications {
+		if a == appName {
+			return appID, true
+		}
+	}
+	return 0, false
+}
+
+// SameHolder returns true if both locks are held by the same run
+func (l EnvironmentLock) SameHolder(o EnvironmentLock) bool {
+	return l.PipelineBuildID == o.PipelineBuildID && l.WorkflowNodeRunID == o.WorkflowNodeRunID
+}
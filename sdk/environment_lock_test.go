@@ -0,0 +1,32 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvironmentLockKey(t *testing.T) {
+	env := &Environment{ID: 2, ExclusiveApplications: []string{"api"}}
+
+	_, need := env.LockKey(10, "ui")
+	assert.False(t, need)
+
+	key, need := env.LockKey(11, "api")
+	assert.True(t, need)
+	assert.Equal(t, int64(11), key)
+
+	env.Exclusive = true
+	key, need = env.LockKey(10, "ui")
+	assert.True(t, need)
+	assert.Equal(t, int64(0), key)
+
+	_, need = DefaultEnv.LockKey(10, "ui")
+	assert.False(t, need)
+}
+
+func TestIsValidEnvironmentLockPolicy(t *testing.T) {
+	assert.NoError(t, IsValidEnvironmentLockPolicy(""))
+	assert.NoError(t, IsValidEnvironmentLockPolicy(EnvironmentLockCancelOlder))
+	assert.Error(t, IsValidEnvironmentLockPolicy("foo"))
+}
@@ -115,6 +115,42 @@ var (
 	ErrNotImplemented                        = &Error{ID: 99, Status: http.StatusNotImplemented}
 	ErrParameterNotExists                    = &Error{ID: 100, Status: http.StatusNotFound}
 	ErrUnknownKeyType                        = &Error{ID: 101, Status: http.StatusBadRequest}
+	ErrArtifactChecksumMismatch              = &Error{ID: 102, Status: http.StatusBadRequest}
+	ErrArtifactUploadNotFound                = &Error{ID: 103, Status: http.StatusNotFound}
+	ErrArtifactUploadIncomplete              = &Error{ID: 104, Status: http.StatusBadRequest}
+	ErrReleaseAlreadyExists                  = &Error{ID: 105, Status: http.StatusConflict}
+	ErrReleaseNotFound                       = &Error{ID: 106, Status: http.StatusNotFound}
+	ErrReleaseNoArtifact                     = &Error{ID: 107, Status: http.StatusBadRequest}
+	ErrInvalidModelPoolPolicy                = &Error{ID: 108, Status: http.StatusBadRequest}
+	ErrInvalidJobPriority                    = &Error{ID: 109, Status: http.StatusBadRequest}
+	ErrInvalidEnvironmentLockPolicy          = &Error{ID: 110, Status: http.StatusBadRequest}
+	ErrInvalidApprovalGate                   = &Error{ID: 111, Status: http.StatusBadRequest}
+	ErrApprovalNotFound                      = &Error{ID: 112, Status: http.StatusNotFound}
+	ErrApprovalForbidden                     = &Error{ID: 113, Status: http.StatusForbidden}
+	ErrApprovalClosed                        = &Error{ID: 114, Status: http.StatusBadRequest}
+	ErrWorkflowDeploymentNotFound            = &Error{ID: 115, Status: http.StatusNotFound}
+	ErrHistoryNotFound                       = &Error{ID: 116, Status: http.StatusNotFound}
+	ErrNotificationNotSent                   = &Error{ID: 117, Status: http.StatusBadRequest}
+	ErrChatOpsAccountExists                  = &Error{ID: 118, Status: http.StatusConflict}
+	ErrChatOpsAccountNotFound                = &Error{ID: 119, Status: http.StatusNotFound}
+	ErrPluginNotFound                        = &Error{ID: 120, Status: http.StatusNotFound}
+	ErrPluginChecksum                        = &Error{ID: 121, Status: http.StatusBadRequest}
+	ErrPluginVersionDeprecated               = &Error{ID: 122, Status: http.StatusBadRequest}
+	ErrPluginVersionDefault                  = &Error{ID: 123, Status: http.StatusBadRequest}
+	ErrActionVersionNotFound                 = &Error{ID: 124, Status: http.StatusNotFound}
+	ErrActionSourceNotFound                  = &Error{ID: 125, Status: http.StatusNotFound}
+	ErrActionSourceExists                    = &Error{ID: 126, Status: http.StatusConflict}
+	ErrActionSourceInvalid                   = &Error{ID: 127, Status: http.StatusBadRequest}
+	ErrActionManagedBySource                 = &Error{ID: 128, Status: http.StatusForbidden}
+	ErrRestoreNotFresh                       = &Error{ID: 129, Status: http.StatusConflict}
+	ErrInvalidBackup                         = &Error{ID: 130, Status: http.StatusBadRequest}
+	ErrBackupVersionMismatch                 = &Error{ID: 131, Status: http.StatusBadRequest}
+	ErrTooManyRequests                       = &Error{ID: 132, Status: http.StatusTooManyRequests}
+	ErrConcurrencyQuotaReached               = &Error{ID: 133, Status: http.StatusConflict}
+	ErrDeploymentNodeNotFound                = &Error{ID: 134, Status: http.StatusConflict}
+	ErrChatOpsInvalidLinkCode                = &Error{ID: 135, Status: http.StatusBadRequest}
+	ErrActionSourceSyncing                   = &Error{ID: 136, Status: http.StatusConflict}
+	ErrActionSourceInvalidURL                = &Error{ID: 137, Status: http.StatusBadRequest}
 )
 
 var errorsAmericanEnglish = map[int]string{
@@ -219,6 +255,42 @@ var errorsAmericanEnglish = map[int]string{
 	ErrNotImplemented.ID:                        "This functionality isn't implemented",
 	ErrParameterNotExists.ID:                    "This parameter doesn't exist",
 	ErrUnknownKeyType.ID:                        "Unknown key type",
+	ErrArtifactChecksumMismatch.ID:              "Artifact checksum does not match",
+	ErrArtifactUploadNotFound.ID:                "Artifact upload not found or expired",
+	ErrArtifactUploadIncomplete.ID:              "Artifact upload is incomplete, some chunks are missing",
+	ErrReleaseAlreadyExists.ID:                  "Release already exists, releases cannot be modified",
+	ErrReleaseNotFound.ID:                       "Release not found",
+	ErrReleaseNoArtifact.ID:                     "No artifact to promote",
+	ErrInvalidModelPoolPolicy.ID:                "Invalid worker model pool policy",
+	ErrInvalidJobPriority.ID:                    "Invalid job priority, it must be between 0 and 100",
+	ErrInvalidEnvironmentLockPolicy.ID:          "Invalid environment lock policy, it must be queue, cancel_older or cancel_newer",
+	ErrInvalidApprovalGate.ID:                   "Invalid approval gate, it needs approver groups and at least one approval",
+	ErrApprovalNotFound.ID:                      "Approval not found",
+	ErrApprovalForbidden.ID:                     "You are not allowed to approve this run",
+	ErrApprovalClosed.ID:                        "This approval is closed",
+	ErrWorkflowDeploymentNotFound.ID:            "Deployment not found",
+	ErrHistoryNotFound.ID:                       "Version not found in history",
+	ErrNotificationNotSent.ID:                   "Notification could not be sent",
+	ErrChatOpsAccountExists.ID:                  "This chat account is already linked to a user",
+	ErrChatOpsAccountNotFound.ID:                "This chat account is not linked to any user",
+	ErrPluginNotFound.ID:                        "Plugin not found for this version and platform",
+	ErrPluginChecksum.ID:                        "Plugin checksum does not match",
+	ErrPluginVersionDeprecated.ID:               "This plugin version is deprecated",
+	ErrPluginVersionDefault.ID:                  "The default version of a plugin cannot be deprecated",
+	ErrActionVersionNotFound.ID:                 "Action version not found",
+	ErrActionSourceNotFound.ID:                  "Action source not found",
+	ErrActionSourceExists.ID:                    "Action source already exists",
+	ErrActionSourceInvalid.ID:                   "An action source needs either a repository of a repositories manager or a git URL",
+	ErrActionManagedBySource.ID:                 "This action is synchronized from a git repository and cannot be modified",
+	ErrRestoreNotFresh.ID:                       "A backup can only be restored on an instance without any project",
+	ErrInvalidBackup.ID:                         "Invalid backup archive",
+	ErrBackupVersionMismatch.ID:                 "The backup has been made with another version of the database schema",
+	ErrTooManyRequests.ID:                       "Too many requests, please retry later",
+	ErrConcurrencyQuotaReached.ID:               "Max concurrent jobs of a group of the project reached",
+	ErrDeploymentNodeNotFound.ID:                "The node of the deployment has been removed from the workflow, it cannot be rolled back",
+	ErrChatOpsInvalidLinkCode.ID:                "This link code is invalid or has expired",
+	ErrActionSourceSyncing.ID:                   "This action source is being synchronized, retry in a minute",
+	ErrActionSourceInvalidURL.ID:                "The git URL of an action source must be an https, ssh or user@host:path URL",
 }
 
 var errorsFrench = map[int]string{
@@ -323,6 +395,42 @@ var errorsFrench = map[int]string{
 	ErrNotImplemented.ID:                        "La fonctionnalité n'est pas implémentée",
 	ErrParameterNotExists.ID:                    "Ce paramètre n'existe pas",
 	ErrUnknownKeyType.ID:                        "Le type de clé n'est pas connu",
+	ErrArtifactChecksumMismatch.ID:              "La somme de contrôle de l'artefact ne correspond pas",
+	ErrArtifactUploadNotFound.ID:                "L'envoi de l'artefact est introuvable ou a expiré",
+	ErrArtifactUploadIncomplete.ID:              "L'envoi de l'artefact est incomplet, des morceaux sont manquants",
+	ErrReleaseAlreadyExists.ID:                  "La release existe déjà, les releases ne peuvent pas être modifiées",
+	ErrReleaseNotFound.ID:                       "Release introuvable",
+	ErrReleaseNoArtifact.ID:                     "Aucun artefact à promouvoir",
+	ErrInvalidModelPoolPolicy.ID:                "Politique de pool du modèle de worker invalide",
+	ErrInvalidJobPriority.ID:                    "Priorité de job invalide, elle doit être comprise entre 0 et 100",
+	ErrInvalidEnvironmentLockPolicy.ID:          "Politique de verrou d'environnement invalide, elle doit être queue, cancel_older ou cancel_newer",
+	ErrInvalidApprovalGate.ID:                   "Validation invalide, elle nécessite des groupes d'approbateurs et au moins une approbation",
+	ErrApprovalNotFound.ID:                      "Validation introuvable",
+	ErrApprovalForbidden.ID:                     "Vous n'êtes pas autorisé à valider cette exécution",
+	ErrApprovalClosed.ID:                        "Cette validation est terminée",
+	ErrWorkflowDeploymentNotFound.ID:            "Déploiement introuvable",
+	ErrHistoryNotFound.ID:                       "Version introuvable dans l'historique",
+	ErrNotificationNotSent.ID:                   "La notification n'a pas pu être envoyée",
+	ErrChatOpsAccountExists.ID:                  "Ce compte de messagerie est déjà lié à un utilisateur",
+	ErrChatOpsAccountNotFound.ID:                "Ce compte de messagerie n'est lié à aucun utilisateur",
+	ErrPluginNotFound.ID:                        "Plugin introuvable pour cette version et cette plateforme",
+	ErrPluginChecksum.ID:                        "La somme de contrôle du plugin ne correspond pas",
+	ErrPluginVersionDeprecated.ID:               "Cette version du plugin est dépréciée",
+	ErrPluginVersionDefault.ID:                  "La version par défaut d'un plugin ne peut pas être dépréciée",
+	ErrActionVersionNotFound.ID:                 "Version de l'action introuvable",
+	ErrActionSourceNotFound.ID:                  "Source d'actions introuvable",
+	ErrActionSourceExists.ID:                    "La source d'actions existe déjà",
+	ErrActionSourceInvalid.ID:                   "Une source d'actions nécessite soit un dépôt d'un gestionnaire de dépôts soit une URL git",
+	ErrActionManagedBySource.ID:                 "Cette action est synchronisée depuis un dépôt git et ne peut pas être modifiée",
+	ErrRestoreNotFresh.ID:                       "Une sauvegarde ne peut être restaurée que sur une instance sans aucun projet",
+	ErrInvalidBackup.ID:                         "Archive de sauvegarde invalide",
+	ErrBackupVersionMismatch.ID:                 "La sauvegarde a été faite avec une autre version du schéma de la base de données",
+	ErrTooManyRequests.ID:                       "Trop de requêtes, veuillez réessayer plus tard",
+	ErrConcurrencyQuotaReached.ID:               "Nombre maximum de jobs simultanés d'un groupe du projet atteint",
+	ErrDeploymentNodeNotFound.ID:                "Le noeud du déploiement a été supprimé du workflow, il ne peut pas être restauré",
+	ErrChatOpsInvalidLinkCode.ID:                "Ce code de liaison est invalide ou a expiré",
+	ErrActionSourceSyncing.ID:                   "Cette source d'actions est en cours de synchronisation, réessayez dans une minute",
+	ErrActionSourceInvalidURL.ID:                "L'URL git d'une source d'actions doit être une URL https, ssh ou user@host:path",
 }
 
 var errorsLanguages = []map[int]string{
@@ -46,7 +46,7 @@ type Step map[string]interface{}
 func (s Step) IsValid() bool {
 	keys := []string{}
 	for k := range s {
-		if k != "enabled" && k != "optional" && k != "always_executed" {
+		if k != "enabled" && k != "optional" && k != "always_executed" && k != "version" {
 			keys = append(keys, k)
 		}
 	}
@@ -56,7 +56,7 @@ func (s Step) IsValid() bool {
 func (s Step) key() string {
 	keys := []string{}
 	for k := range s {
-		if k != "enabled" && k != "optional" && k != "always_executed" {
+		if k != "enabled" && k != "optional" && k != "always_executed" && k != "version" {
 			keys = append(keys, k)
 		}
 	}
@@ -137,6 +137,10 @@ func (s Step) AsAction() (*sdk.Action, bool, error) {
 	if err != nil {
 		return nil, true, err
 	}
+	a.Version, err = s.Version()
+	if err != nil {
+		return nil, true, err
+	}
 	return a, true, nil
 }
 
@@ -301,6 +305,34 @@ func (s Step) IsFlagged(flag string) (bool, error) {
 	return bS, nil
 }
 
+// Version returns the version of the action pinned by the step, 0 if the step follows the latest version
+func (s Step) Version() (int64, error) {
+	vI, ok := s["version"]
+	if !ok {
+		return 0, nil
+	}
+	var v int64
+	switch t := vI.(type) {
+	case int:
+		v = int64(t)
+	case int64:
+		v = t
+	case uint64:
+		v = int64(t)
+	case float64:
+		v = int64(t)
+		if float64(v) != t {
+			return 0, fmt.Errorf("Malformatted Step : version must be an integer")
+		}
+	default:
+		return 0, fmt.Errorf("Malformatted Step : version must be an integer")
+	}
+	if v < 0 {
+		return 0, fmt.Errorf("Malformatted Step : version must be positive")
+	}
+	return v, nil
+}
+
 // Requirement represents an exported sdk.Requirement
 type Requirement struct {
 	Binary   string             `json:"binary,omitempty" yaml:"binary,omitempty"`
@@ -310,6 +342,9 @@ type Requirement struct {
 	Plugin   string             `json:"plugin,omitempty" yaml:"plugin,omitempty"`
 	Service  ServiceRequirement `json:"service,omitempty" yaml:"service,omitempty"`
 	Memory   string             `json:"memory,omitempty" yaml:"memory,omitempty"`
+	CPU      string             `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+	Disk     string             `json:"disk,omitempty" yaml:"disk,omitempty"`
+	OSArch   string             `json:"os-arch,omitempty" yaml:"os-arch,omitempty"`
 }
 
 // ServiceRequirement represents an exported sdk.Requirement of type ServiceRequirement
@@ -420,6 +455,12 @@ func newRequirements(req []sdk.Requirement) []Requirement {
 			res = append(res, Requirement{Service: ServiceRequirement{Name: r.Name, Value: r.Value}})
 		case sdk.MemoryRequirement:
 			res = append(res, Requirement{Memory: r.Value})
+		case sdk.CPURequirement:
+			res = append(res, Requirement{CPU: r.Value})
+		case sdk.DiskRequirement:
+			res = append(res, Requirement{Disk: r.Value})
+		case sdk.OSArchRequirement:
+			res = append(res, Requirement{OSArch: r.Value})
 		}
 	}
 	return res
@@ -537,6 +578,9 @@ func newSteps(a sdk.Action) []Step {
 				}
 			}
 			s[act.Name] = args
+			if act.Version != 0 {
+				s["version"] = act.Version
+			}
 		}
 		res = append(res, s)
 	}
@@ -760,6 +804,18 @@ func computeJobRequirements(req []Requirement) []sdk.Requirement {
 			name = r.Service.Name
 			val = r.Service.Value
 			tpe = sdk.ServiceRequirement
+		} else if r.CPU != "" {
+			name = "cpu"
+			val = r.CPU
+			tpe = sdk.CPURequirement
+		} else if r.Disk != "" {
+			name = "disk"
+			val = r.Disk
+			tpe = sdk.DiskRequirement
+		} else if r.OSArch != "" {
+			name = "os-arch"
+			val = r.OSArch
+			tpe = sdk.OSArchRequirement
 		}
 		res = append(res, sdk.Requirement{
 			Name:  name,
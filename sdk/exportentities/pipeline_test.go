@@ -541,6 +541,57 @@ steps:
 	assert.Len(t, p.Stages[0].Jobs[0].Action.Actions[0].Parameters, 7)
 }
 
+func TestExportAndImportPipelineStepVersion(t *testing.T) {
+	pip := sdk.Pipeline{
+		Name: "MyPipeline",
+		Type: sdk.BuildPipeline,
+		Stages: []sdk.Stage{
+			{
+				BuildOrder: 1,
+				Name:       "MyStage",
+				Enabled:    true,
+				Jobs: []sdk.Job{
+					{
+						Enabled: true,
+						Action: sdk.Action{
+							Name: "MyJob",
+							Actions: []sdk.Action{
+								{Type: sdk.DefaultAction, Name: "myAction", Enabled: true, Version: 3, Parameters: []sdk.Parameter{{Name: "foo", Value: "bar"}}},
+								{Type: sdk.DefaultAction, Name: "myAction", Enabled: true},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	formats := []struct {
+		format    Format
+		unmarshal func([]byte, interface{}) error
+	}{
+		{FormatYAML, yaml.Unmarshal},
+		{FormatJSON, json.Unmarshal},
+	}
+	for _, f := range formats {
+		b, err := Marshal(NewPipeline(&pip), f.format)
+		test.NoError(t, err)
+
+		p := Pipeline{}
+		test.NoError(t, f.unmarshal(b, &p))
+		imported, err := p.Pipeline()
+		test.NoError(t, err)
+
+		steps := imported.Stages[0].Jobs[0].Action.Actions
+		assert.Len(t, steps, 2)
+		assert.Equal(t, int64(3), steps[0].Version, string(b))
+		assert.Equal(t, int64(0), steps[1].Version, string(b))
+	}
+
+	_, err := Step{"myAction": map[string]interface{}{}, "version": "latest"}.Version()
+	assert.Error(t, err)
+}
+
 func Test_IsFlagged(t *testing.T) {
 	testc := []struct {
 		flag     string
@@ -16,6 +16,7 @@ type Group struct {
 	PipelineGroups    []PipelineGroup    `json:"pipelines,omitempty" yaml:"-"`
 	ApplicationGroups []ApplicationGroup `json:"applications,omitempty" yaml:"-"`
 	EnvironmentGroups []EnvironmentGroup `json:"environments,omitempty" yaml:"-"`
+	MaxConcurrentJobs int                `json:"max_concurrent_jobs,omitempty" yaml:"max_concurrent_jobs,omitempty"`
 }
 
 // GroupPermission represent a group and his role in the project
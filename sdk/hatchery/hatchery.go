@@ -181,7 +181,7 @@ func routine(h Interface, maxWorkers int, hostname string, timestamp int64, last
 	return spawnedIDs, nil
 }
 
-func provisioning(h Interface, provisionDisabled bool) {
+func provisioning(h Interface, provisionDisabled bool, hostname string, idle idleWorkers) {
 	if provisionDisabled {
 		log.Debug("provisioning> disabled on this hatchery")
 		return
@@ -193,24 +193,80 @@ func provisioning(h Interface, provisionDisabled bool) {
 		return
 	}
 
+	var withPool bool
 	for k := range models {
-		if models[k].Type == h.ModelType() {
-			existing := h.WorkersStartedByModel(&models[k])
-			for i := existing; i < int(models[k].Provision); i++ {
-				go func(m sdk.Model) {
-					if name, errSpawn := h.SpawnWorker(&m, nil, false, "spawn for provision"); errSpawn != nil {
-						log.Warning("provisioning> cannot spawn worker %s with model %s for provisioning: %s", name, m.Name, errSpawn)
-						if err := sdk.SpawnErrorWorkerModel(m.ID, fmt.Sprintf("routine> cannot spawn worker %s for provisioning: %s", m.Name, errSpawn)); err != nil {
-							log.Error("provisioning> cannot spawn worker %s with model %s for provisioning: %s", name, m.Name, errSpawn)
-						}
+		if models[k].Type == h.ModelType() && models[k].PoolPolicy != nil {
+			withPool = true
+			break
+		}
+	}
+
+	// workers and queue are only needed to apply pool policies
+	var workers []sdk.Worker
+	var jobs []sdk.PipelineBuildJob
+	var wjobs []sdk.WorkflowNodeJobRun
+	if withPool {
+		var errw, errbq, errwq error
+		workers, errw = sdk.GetWorkers()
+		if errw != nil {
+			log.Error("provisioning> error on GetWorkers:%s", errw)
+			return
+		}
+		jobs, errbq = sdk.GetBuildQueue()
+		if errbq != nil {
+			log.Error("provisioning> error on GetBuildQueue:%s", errbq)
+			return
+		}
+		wjobs, errwq = sdk.GetWorkflowQueue()
+		if errwq != nil {
+			log.Error("provisioning> error on GetWorkflowQueue:%s", errwq)
+			return
+		}
+	}
+
+	now := time.Now()
+	idle.update(h.Hatchery().ID, workers, now)
+
+	for k := range models {
+		if models[k].Type != h.ModelType() {
+			continue
+		}
+
+		if models[k].PoolPolicy != nil {
+			pooling(h, &models[k], workers, jobs, wjobs, hostname, idle, now)
+			continue
+		}
+
+		existing := h.WorkersStartedByModel(&models[k])
+		for i := existing; i < int(models[k].Provision); i++ {
+			go func(m sdk.Model) {
+				if name, errSpawn := h.SpawnWorker(&m, nil, false, "spawn for provision"); errSpawn != nil {
+					log.Warning("provisioning> cannot spawn worker %s with model %s for provisioning: %s", name, m.Name, errSpawn)
+					if err := sdk.SpawnErrorWorkerModel(m.ID, fmt.Sprintf("routine> cannot spawn worker %s for provisioning: %s", m.Name, errSpawn)); err != nil {
+						log.Error("provisioning> cannot spawn worker %s with model %s for provisioning: %s", name, m.Name, errSpawn)
 					}
-				}(models[k])
-			}
+				}
+			}(models[k])
 		}
 	}
 }
 
 func canRunJob(h Interface, timestamp int64, job *sdk.PipelineBuildJob, model *sdk.Model, hostname string) bool {
+	if !modelCanRunJob(h, timestamp, job, model, hostname) {
+		return false
+	}
+
+	// max workers of the pool policy
+	if model.PoolPolicy != nil && model.PoolPolicy.MaxWorkers > 0 && h.WorkersStartedByModel(model) >= model.PoolPolicy.MaxWorkers {
+		log.Debug("canRunJob> %d - job %d - max workers %d of model %s reached", timestamp, job.ID, model.PoolPolicy.MaxWorkers, model.Name)
+		return false
+	}
+
+	return h.CanSpawn(model, job)
+}
+
+// modelCanRunJob checks the requirements of the job against the worker model
+func modelCanRunJob(h Interface, timestamp int64, job *sdk.PipelineBuildJob, model *sdk.Model, hostname string) bool {
 	if model.Type != h.ModelType() {
 		return false
 	}
@@ -241,8 +297,16 @@ func canRunJob(h Interface, timestamp int64, job *sdk.PipelineBuildJob, model *s
 			return false
 		}
 
+		// os-arch requirement is checked against worker model capabilities or worker model type
+		if r.Type == sdk.OSArchRequirement && !model.MatchOSArch(r.Value) {
+			log.Debug("canRunJob> %d - job %d - os-arch requirement r.Value(%s) does not match model %s", timestamp, job.ID, r.Value, model.Name)
+			return false
+		}
+
 		// Skip network access requirement as we can't check it
-		if r.Type == sdk.NetworkAccessRequirement || r.Type == sdk.PluginRequirement || r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement {
+		// cpu and disk requirements are checked by hatcheries when spawning
+		if r.Type == sdk.NetworkAccessRequirement || r.Type == sdk.PluginRequirement || r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement ||
+			r.Type == sdk.CPURequirement || r.Type == sdk.DiskRequirement || r.Type == sdk.OSArchRequirement {
 			log.Debug("canRunJob> %d - job %d - job with service requirement or memory requirement: only for model docker. current model:%s", timestamp, job.ID, model.Type)
 			continue
 		}
@@ -264,7 +328,7 @@ func canRunJob(h Interface, timestamp int64, job *sdk.PipelineBuildJob, model *s
 		}
 	}
 
-	return h.CanSpawn(model, job)
+	return true
 }
 
 func logTime(name string, then time.Time, warningSeconds, criticalSeconds int) {
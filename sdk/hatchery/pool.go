@@ -0,0 +1,168 @@
+package hatchery
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// idleWorkers keeps the date since which each waiting worker of the hatchery is idle
+type idleWorkers map[string]time.Time
+
+// update adds new waiting workers of the hatchery and forgets others
+func (idle idleWorkers) update(hatcheryID int64, workers []sdk.Worker, now time.Time) {
+	waiting := map[string]bool{}
+	for _, w := range workers {
+		if w.HatcheryID != hatcheryID || w.Status != sdk.StatusWaiting {
+			continue
+		}
+		waiting[w.ID] = true
+		if _, ok := idle[w.ID]; !ok {
+			idle[w.ID] = now
+		}
+	}
+	for id := range idle {
+		if !waiting[id] {
+			delete(idle, id)
+		}
+	}
+}
+
+// poolPlan is what the hatchery has to do for a worker model with a pool policy
+type poolPlan struct {
+	spawn int
+	kill  []sdk.Worker
+}
+
+// computePoolPlan computes the number of workers to spawn and the idle workers to kill:
+//  - target idle workers is the number of waiting jobs for the model, bounded by idle bounds of the policy
+//  - workers started but not yet registered are counted as idle workers
+//  - only workers idle since more than the idle timeout are killed, oldest first
+func computePoolPlan(policy sdk.ModelPoolPolicy, now time.Time, waiting []sdk.Worker, idle idleWorkers, starting, started, queueDepth int) poolPlan {
+	minIdle, maxIdle := policy.IdleBounds(now)
+	target := queueDepth
+	if target < minIdle {
+		target = minIdle
+	}
+	if target > maxIdle {
+		target = maxIdle
+	}
+
+	plan := poolPlan{}
+	available := len(waiting) + starting
+	if available < target {
+		plan.spawn = target - available
+		if policy.MaxWorkers > 0 && started+plan.spawn > policy.MaxWorkers {
+			plan.spawn = policy.MaxWorkers - started
+			if plan.spawn < 0 {
+				plan.spawn = 0
+			}
+		}
+		return plan
+	}
+
+	if policy.IdleTimeout == 0 {
+		return plan
+	}
+
+	excess := available - target
+	if excess > len(waiting) {
+		excess = len(waiting)
+	}
+
+	candidates := make([]sdk.Worker, len(waiting))
+	copy(candidates, waiting)
+	sort.Slice(candidates, func(i, j int) bool {
+		return idle[candidates[i].ID].Before(idle[candidates[j].ID])
+	})
+	timeout := time.Duration(policy.IdleTimeout) * time.Second
+	for _, w := range candidates {
+		if len(plan.kill) == excess {
+			break
+		}
+		if since, ok := idle[w.ID]; ok && now.Sub(since) >= timeout {
+			plan.kill = append(plan.kill, w)
+		}
+	}
+	return plan
+}
+
+// queueDepth returns the number of jobs waiting in the pipeline build queue and in the workflow queue
+// which can be run by the worker model. Jobs already booked by a hatchery are not counted
+func queueDepth(h Interface, model *sdk.Model, jobs []sdk.PipelineBuildJob, wjobs []sdk.WorkflowNodeJobRun, hostname string, now time.Time) int {
+	var depth int
+	for i := range jobs {
+		if jobs[i].Status != sdk.StatusWaiting.String() || jobs[i].BookedBy.ID != 0 {
+			continue
+		}
+		if modelCanRunJob(h, now.Unix(), &jobs[i], model, hostname) {
+			depth++
+		}
+	}
+	for i := range wjobs {
+		if wjobs[i].Status != sdk.StatusWaiting.String() || wjobs[i].BookedBy.ID != 0 {
+			continue
+		}
+		// requirements are checked the same way for pipeline build jobs and workflow jobs
+		job := sdk.PipelineBuildJob{ID: wjobs[i].ID, Job: wjobs[i].Job, Status: wjobs[i].Status}
+		if modelCanRunJob(h, now.Unix(), &job, model, hostname) {
+			depth++
+		}
+	}
+	return depth
+}
+
+// pooling applies the pool policy of a worker model
+func pooling(h Interface, model *sdk.Model, workers []sdk.Worker, jobs []sdk.PipelineBuildJob, wjobs []sdk.WorkflowNodeJobRun, hostname string, idle idleWorkers, now time.Time) {
+	hatcheryID := h.Hatchery().ID
+
+	var waiting []sdk.Worker
+	var registered int
+	for _, w := range workers {
+		if w.HatcheryID != hatcheryID || w.Model != model.ID {
+			continue
+		}
+		registered++
+		if w.Status == sdk.StatusWaiting {
+			waiting = append(waiting, w)
+		}
+	}
+
+	started := h.WorkersStartedByModel(model)
+	starting := started - registered
+	if starting < 0 {
+		starting = 0
+	}
+
+	depth := queueDepth(h, model, jobs, wjobs, hostname, now)
+
+	plan := computePoolPlan(*model.PoolPolicy, now, waiting, idle, starting, started, depth)
+	log.Debug("pooling> model %s - waiting:%d starting:%d started:%d queue:%d spawn:%d kill:%d", model.Name, len(waiting), starting, started, depth, plan.spawn, len(plan.kill))
+
+	for i := 0; i < plan.spawn; i++ {
+		go func(m sdk.Model) {
+			if name, errSpawn := h.SpawnWorker(&m, nil, false, "spawn for pool"); errSpawn != nil {
+				log.Warning("pooling> cannot spawn worker %s with model %s for pool: %s", name, m.Name, errSpawn)
+				if err := sdk.SpawnErrorWorkerModel(m.ID, fmt.Sprintf("pooling> cannot spawn worker %s for pool: %s", m.Name, errSpawn)); err != nil {
+					log.Error("pooling> cannot record spawn error of worker model %s: %s", m.Name, err)
+				}
+			}
+		}(*model)
+	}
+
+	for _, w := range plan.kill {
+		// disable the worker first, so it does not take a job while being killed
+		if err := sdk.DisableWorker(w.ID); err != nil {
+			log.Warning("pooling> cannot disable idle worker %s: %s", w.Name, err)
+			continue
+		}
+		delete(idle, w.ID)
+		log.Info("pooling> kill idle worker %s of model %s", w.Name, model.Name)
+		if err := h.KillWorker(w); err != nil {
+			log.Warning("pooling> cannot kill idle worker %s: %s", w.Name, err)
+		}
+	}
+}
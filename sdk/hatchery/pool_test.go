@@ -0,0 +1,170 @@
+package hatchery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestIdleWorkersUpdate(t *testing.T) {
+	now := time.Now()
+	idle := idleWorkers{"old": now.Add(-time.Hour), "busy": now.Add(-time.Hour)}
+	idle.update(1, []sdk.Worker{
+		{ID: "old", HatcheryID: 1, Status: sdk.StatusWaiting},
+		{ID: "new", HatcheryID: 1, Status: sdk.StatusWaiting},
+		{ID: "busy", HatcheryID: 1, Status: sdk.StatusBuilding},
+		{ID: "other", HatcheryID: 2, Status: sdk.StatusWaiting},
+	}, now)
+
+	if len(idle) != 2 {
+		t.Fatalf("idle workers: %v", idle)
+	}
+	if !idle["old"].Equal(now.Add(-time.Hour)) {
+		t.Errorf("idle since of worker old should not change: %s", idle["old"])
+	}
+	if !idle["new"].Equal(now) {
+		t.Errorf("idle since of worker new should be now: %s", idle["new"])
+	}
+}
+
+func TestComputePoolPlan(t *testing.T) {
+	now := time.Now()
+	waiting := []sdk.Worker{{ID: "w1"}, {ID: "w2"}, {ID: "w3"}}
+	idle := idleWorkers{
+		"w1": now.Add(-5 * time.Minute),
+		"w2": now.Add(-20 * time.Minute),
+		"w3": now.Add(-30 * time.Second),
+	}
+
+	tests := []struct {
+		name       string
+		policy     sdk.ModelPoolPolicy
+		waiting    []sdk.Worker
+		starting   int
+		started    int
+		queueDepth int
+		wantSpawn  int
+		wantKill   []string
+	}{
+		{
+			name:      "spawn min idle",
+			policy:    sdk.ModelPoolPolicy{MinIdle: 2, MaxIdle: 4},
+			wantSpawn: 2,
+		},
+		{
+			name:       "scale up with queue depth",
+			policy:     sdk.ModelPoolPolicy{MinIdle: 1, MaxIdle: 4},
+			waiting:    waiting[:1],
+			starting:   1,
+			started:    3,
+			queueDepth: 3,
+			wantSpawn:  1,
+		},
+		{
+			name:       "scale up bounded by max idle",
+			policy:     sdk.ModelPoolPolicy{MinIdle: 1, MaxIdle: 4},
+			queueDepth: 10,
+			wantSpawn:  4,
+		},
+		{
+			name:       "scale up bounded by max workers",
+			policy:     sdk.ModelPoolPolicy{MinIdle: 1, MaxIdle: 4, MaxWorkers: 5},
+			started:    3,
+			queueDepth: 10,
+			wantSpawn:  2,
+		},
+		{
+			name:       "max workers reached",
+			policy:     sdk.ModelPoolPolicy{MinIdle: 1, MaxIdle: 4, MaxWorkers: 5},
+			started:    6,
+			queueDepth: 10,
+		},
+		{
+			name:    "no idle timeout",
+			policy:  sdk.ModelPoolPolicy{MaxIdle: 4},
+			waiting: waiting,
+		},
+		{
+			name:     "kill oldest idle workers",
+			policy:   sdk.ModelPoolPolicy{MinIdle: 1, MaxIdle: 4, IdleTimeout: 60},
+			waiting:  waiting,
+			wantKill: []string{"w2", "w1"},
+		},
+		{
+			name:       "keep workers for queued jobs",
+			policy:     sdk.ModelPoolPolicy{MinIdle: 1, MaxIdle: 4, IdleTimeout: 60},
+			waiting:    waiting,
+			queueDepth: 2,
+			wantKill:   []string{"w2"},
+		},
+		{
+			name:     "starting workers count as idle",
+			policy:   sdk.ModelPoolPolicy{MinIdle: 2, MaxIdle: 4, IdleTimeout: 60},
+			waiting:  waiting,
+			starting: 2,
+			wantKill: []string{"w2", "w1"},
+		},
+		{
+			name:     "do not kill fresh idle workers",
+			policy:   sdk.ModelPoolPolicy{MaxIdle: 4, IdleTimeout: 600},
+			waiting:  waiting,
+			wantKill: []string{"w2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := computePoolPlan(tt.policy, now, tt.waiting, idle, tt.starting, tt.started, tt.queueDepth)
+			if plan.spawn != tt.wantSpawn {
+				t.Errorf("computePoolPlan() spawn = %d, want %d", plan.spawn, tt.wantSpawn)
+			}
+			var kill []string
+			for _, w := range plan.kill {
+				kill = append(kill, w.ID)
+			}
+			if len(kill) != len(tt.wantKill) {
+				t.Fatalf("computePoolPlan() kill = %v, want %v", kill, tt.wantKill)
+			}
+			for i := range kill {
+				if kill[i] != tt.wantKill[i] {
+					t.Errorf("computePoolPlan() kill = %v, want %v", kill, tt.wantKill)
+				}
+			}
+		})
+	}
+}
+
+type fakeHatchery struct {
+	Interface
+	modelType string
+}
+
+func (h *fakeHatchery) Hatchery() *sdk.Hatchery { return &sdk.Hatchery{ID: 1} }
+func (h *fakeHatchery) ModelType() string       { return h.modelType }
+
+func TestQueueDepth(t *testing.T) {
+	h := &fakeHatchery{modelType: sdk.Docker}
+	model := &sdk.Model{ID: 1, Name: "go", Type: sdk.Docker}
+	other := sdk.ExecutedJob{Job: sdk.Job{Action: sdk.Action{Requirements: []sdk.Requirement{{Type: sdk.ModelRequirement, Value: "java"}}}}}
+
+	jobs := []sdk.PipelineBuildJob{
+		{ID: 1, Status: sdk.StatusWaiting.String()},
+		{ID: 2, Status: sdk.StatusBuilding.String()},
+		{ID: 3, Status: sdk.StatusWaiting.String(), BookedBy: sdk.Hatchery{ID: 2}},
+		{ID: 4, Status: sdk.StatusWaiting.String(), Job: other},
+	}
+	wjobs := []sdk.WorkflowNodeJobRun{
+		{ID: 1, Status: sdk.StatusWaiting.String()},
+		{ID: 2, Status: sdk.StatusWaiting.String()},
+		{ID: 3, Status: sdk.StatusBuilding.String()},
+		{ID: 4, Status: sdk.StatusWaiting.String(), BookedBy: sdk.Hatchery{ID: 2}},
+		{ID: 5, Status: sdk.StatusWaiting.String(), Job: other},
+	}
+
+	if depth := queueDepth(h, model, jobs, wjobs, "localhost", time.Now()); depth != 3 {
+		t.Errorf("queueDepth() = %d, want 3", depth)
+	}
+	if depth := queueDepth(h, model, nil, wjobs, "localhost", time.Now()); depth != 2 {
+		t.Errorf("queueDepth() without pipeline build jobs = %d, want 2", depth)
+	}
+}
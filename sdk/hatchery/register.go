@@ -44,6 +44,7 @@ func Create(h Interface, api, token string, maxWorkers int, provisionDisabled bo
 
 	var spawnIds []int64
 	var errR error
+	idle := idleWorkers{}
 
 	tickerRoutine := time.NewTicker(2 * time.Second).C
 	tickerProvision := time.NewTicker(time.Duration(provisionSeconds) * time.Second).C
@@ -56,7 +57,7 @@ func Create(h Interface, api, token string, maxWorkers int, provisionDisabled bo
 				log.Warning("Error on routine: %s", errR)
 			}
 		case <-tickerProvision:
-			provisioning(h, provisionDisabled)
+			provisioning(h, provisionDisabled, hostname, idle)
 		case <-tickerRegister:
 			if err := workerRegister(h); err != nil {
 				log.Warning("Error on workerRegister: %s", err)
@@ -0,0 +1,126 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Types of the objects versioned in the history
+const (
+	HistoryPipeline    = "pipeline"
+	HistoryApplication = "application"
+	HistoryEnvironment = "environment"
+	HistoryWorkflow    = "workflow"
+	HistoryAction      = "action"
+)
+
+// History is a version of a pipeline, an application, an environment, a workflow or an action: the snapshot of
+// the object stored each time it is modified
+type History struct {
+	ID         int64           `json:"id" db:"id" cli:"-"`
+	ProjectID  int64           `json:"project_id" db:"project_id" cli:"-"`
+	ObjectType string          `json:"object_type" db:"object_type" cli:"-"`
+	ObjectID   int64           `json:"object_id" db:"object_id" cli:"-"`
+	ObjectName string          `json:"object_name" db:"object_name" cli:"name"`
+	Version    int64           `json:"version" db:"version" cli:"version,key"`
+	Author     string          `json:"author" db:"author" cli:"author"`
+	Created    time.Time       `json:"created" db:"created" cli:"created"`
+	Deprecated bool            `json:"deprecated,omitempty" db:"-" cli:"deprecated"`
+	Snapshot   json.RawMessage `json:"snapshot,omitempty" db:"-" cli:"-"`
+}
+
+// HistoryChange is a value which differs between two versions. From is empty if the value has been added,
+// To is empty if the value has been removed.
+type HistoryChange struct {
+	Path string      `json:"path" cli:"path"`
+	From interface{} `json:"from,omitempty" cli:"from"`
+	To   interface{} `json:"to,omitempty" cli:"to"`
+}
+
+// HistoryDiff is the list of changes between two versions of an object
+type HistoryDiff struct {
+	ObjectType string          `json:"object_type"`
+	ObjectName string          `json:"object_name"`
+	From       int64           `json:"from"`
+	To         int64           `json:"to"`
+	Changes    []HistoryChange `json:"changes"`
+}
+
+// DiffSnapshots returns the changes between two snapshots. Database identifiers and modification dates
+// are not compared, as they change each time an object is restored.
+func DiffSnapshots(from, to json.RawMessage) ([]HistoryChange, error) {
+	var f, t interface{}
+	if len(from) > 0 {
+		if err := json.Unmarshal(from, &f); err != nil {
+			return nil, WrapError(err, "DiffSnapshots> Unable to unmarshal snapshot")
+		}
+	}
+	if len(to) > 0 {
+		if err := json.Unmarshal(to, &t); err != nil {
+			return nil, WrapError(err, "DiffSnapshots> Unable to unmarshal snapshot")
+		}
+	}
+	changes := []HistoryChange{}
+	diffValues("", f, t, &changes)
+	return changes, nil
+}
+
+func isHistoryIgnoredKey(k string) bool {
+	return k == "id" || strings.HasSuffix(k, "_id") || k == "last_modified" || k == "ref" || k == "source_node_refs" || k == "permission"
+}
+
+func diffValues(path string, from, to interface{}, changes *[]HistoryChange) {
+	switch f := from.(type) {
+	case map[string]interface{}:
+		t, ok := to.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := map[string]struct{}{}
+		for k := range f {
+			keys[k] = struct{}{}
+		}
+		for k := range t {
+			keys[k] = struct{}{}
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			if !isHistoryIgnoredKey(k) {
+				sorted = append(sorted, k)
+			}
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			diffValues(p, f[k], t[k], changes)
+		}
+		return
+	case []interface{}:
+		t, ok := to.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(f) || i < len(t); i++ {
+			var fi, ti interface{}
+			if i < len(f) {
+				fi = f[i]
+			}
+			if i < len(t) {
+				ti = t[i]
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, i), fi, ti, changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, HistoryChange{Path: path, From: from, To: to})
+	}
+}
@@ -0,0 +1,24 @@
+package sdk
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	from := json.RawMessage(`{"id": 1, "name": "build", "last_modified": 10, "stages": [{"id": 4, "name": "Compile", "enabled": true}]}`)
+	to := json.RawMessage(`{"id": 2, "name": "build", "last_modified": 20, "stages": [{"id": 5, "name": "Compile", "enabled": false}, {"id": 6, "name": "Package"}]}`)
+
+	changes, err := DiffSnapshots(from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, []HistoryChange{
+		{Path: "stages[0].enabled", From: true, To: false},
+		{Path: "stages[1]", To: map[string]interface{}{"id": float64(6), "name": "Package"}},
+	}, changes)
+
+	changes, err = DiffSnapshots(from, from)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
@@ -8,16 +8,17 @@ import (
 
 // Hook used to link a git repository to a given pipeline
 type Hook struct {
-	ID            int64    `json:"id"`
-	UID           string   `json:"uid"`
-	Pipeline      Pipeline `json:"pipeline"`
-	ApplicationID int64    `json:"application_id"`
-	Kind          string   `json:"kind"`
-	Host          string   `json:"host"`
-	Project       string   `json:"project"`
-	Repository    string   `json:"repository"`
-	Enabled       bool     `json:"enabled"`
-	Link          string   `json:"link"`
+	ID            int64         `json:"id"`
+	UID           string        `json:"uid"`
+	Pipeline      Pipeline      `json:"pipeline"`
+	ApplicationID int64         `json:"application_id"`
+	Kind          string        `json:"kind"`
+	Host          string        `json:"host"`
+	Project       string        `json:"project"`
+	Repository    string        `json:"repository"`
+	Enabled       bool          `json:"enabled"`
+	Link          string        `json:"link"`
+	Filter        TriggerFilter `json:"filter"`
 }
 
 // AddHook creates a new hook between a pipeline and a repository
@@ -0,0 +1,30 @@
+package sdk
+
+// Job priorities: jobs with a higher priority are served first by the queue
+const (
+	MinJobPriority     = 0
+	MaxJobPriority     = 100
+	DefaultJobPriority = 50
+)
+
+// IsValidJobPriority checks the priority is between MinJobPriority and MaxJobPriority
+func IsValidJobPriority(priority *int) error {
+	if priority == nil {
+		return nil
+	}
+	if *priority < MinJobPriority || *priority > MaxJobPriority {
+		return WrapError(ErrInvalidJobPriority, "IsValidJobPriority> invalid priority %d", *priority)
+	}
+	return nil
+}
+
+// EffectiveJobPriority returns the priority of the jobs of a run: the priority set when
+// running manually, else the priority of the workflow, else the priority of the project
+func EffectiveJobPriority(projectPriority, workflowPriority, manualPriority *int) int {
+	for _, p := range []*int{manualPriority, workflowPriority, projectPriority} {
+		if p != nil {
+			return *p
+		}
+	}
+	return DefaultJobPriority
+}
@@ -0,0 +1,24 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidJobPriority(t *testing.T) {
+	p := func(i int) *int { return &i }
+	assert.NoError(t, IsValidJobPriority(nil))
+	assert.NoError(t, IsValidJobPriority(p(0)))
+	assert.NoError(t, IsValidJobPriority(p(100)))
+	assert.Error(t, IsValidJobPriority(p(-1)))
+	assert.Error(t, IsValidJobPriority(p(101)))
+}
+
+func TestEffectiveJobPriority(t *testing.T) {
+	p := func(i int) *int { return &i }
+	assert.Equal(t, DefaultJobPriority, EffectiveJobPriority(nil, nil, nil))
+	assert.Equal(t, 10, EffectiveJobPriority(p(10), nil, nil))
+	assert.Equal(t, 20, EffectiveJobPriority(p(10), p(20), nil))
+	assert.Equal(t, 0, EffectiveJobPriority(p(10), p(20), p(0)))
+}
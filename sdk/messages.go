@@ -60,6 +60,7 @@ var (
 	MsgSpawnInfoJobError                   = &Message{"MsgSpawnInfoJobError", trad{FR: "Impossible de lancer ce job : %s", EN: "Unable to run this job: %s"}, nil}
 	MsgWorkflowStarting                    = &Message{"MsgWorkflowStarting", trad{FR: "Le workflow %s#%s a été démarré", EN: "Workflow %s#%s has been started"}, nil}
 	MsgWorkflowError                       = &Message{"MsgWorkflowError", trad{FR: "Une erreur est survenue: %v", EN: "An error has occured: %v"}, nil}
+	MsgWorkflowStopped                     = &Message{"MsgWorkflowStopped", trad{FR: "Le workflow a été arrêté par %s", EN: "Workflow has been stopped by %s"}, nil}
 )
 
 // Messages contains all sdk Messages
@@ -103,6 +104,7 @@ var Messages = map[string]*Message{
 	MsgSpawnInfoWorkerForJob.ID:               MsgSpawnInfoWorkerForJob,
 	MsgSpawnInfoWorkerForJobError.ID:          MsgSpawnInfoWorkerForJobError,
 	MsgWorkflowStarting.ID:                    MsgWorkflowStarting,
+	MsgWorkflowStopped.ID:                     MsgWorkflowStopped,
 }
 
 //Message represent a struc format translated messages
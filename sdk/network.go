@@ -0,0 +1,20 @@
+package sdk
+
+import "net"
+
+// privateNetworks are the networks the API does not connect to on behalf of a user, so that it cannot be used to
+// reach its own network
+var privateNetworks = []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7"}
+
+// IsPublicIP returns false if the address is a loopback, link-local, multicast or private address
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast() {
+		return false
+	}
+	for _, cidr := range privateNetworks {
+		if _, n, err := net.ParseCIDR(cidr); err == nil && n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
@@ -9,6 +9,7 @@ type UserNotificationSettingsType string
 const (
 	EmailUserNotification  UserNotificationSettingsType = "email"
 	JabberUserNotification UserNotificationSettingsType = "jabber"
+	SlackUserNotification  UserNotificationSettingsType = "slack"
 )
 
 //UserNotificationEventType always/never/change
@@ -80,6 +81,40 @@ func (n *JabberEmailUserNotificationSettings) JSON() string {
 	return string(b)
 }
 
+// SlackUserNotificationSettings are settings of notifications posted to a Slack compatible incoming webhook,
+// as Slack or Mattermost ones
+type SlackUserNotificationSettings struct {
+	OnSuccess  UserNotificationEventType `json:"on_success"`
+	OnFailure  UserNotificationEventType `json:"on_failure"`
+	OnStart    bool                      `json:"on_start"`
+	WebhookURL string                    `json:"webhook_url"`
+	Channel    string                    `json:"channel,omitempty"`
+	Username   string                    `json:"username,omitempty"`
+	IconURL    string                    `json:"icon_url,omitempty"`
+	Template   UserNotificationTemplate  `json:"template"`
+}
+
+//Success returns always/never/change
+func (n *SlackUserNotificationSettings) Success() UserNotificationEventType {
+	return n.OnSuccess
+}
+
+//Failure returns always/never/change
+func (n *SlackUserNotificationSettings) Failure() UserNotificationEventType {
+	return n.OnFailure
+}
+
+//Start returns always/never/change
+func (n *SlackUserNotificationSettings) Start() bool {
+	return n.OnStart
+}
+
+//JSON returns json as string
+func (n *SlackUserNotificationSettings) JSON() string {
+	b, _ := json.Marshal(n)
+	return string(b)
+}
+
 // UserNotificationTemplate is the notification content
 type UserNotificationTemplate struct {
 	Subject string `json:"subject,omitempty"`
@@ -139,6 +174,18 @@ func ParseUserNotificationSettings(settings []byte) (map[UserNotificationSetting
 				}
 				notifications[UserNotificationSettingsType(k)] = &x
 			}
+		case string(SlackUserNotification):
+			if v != nil {
+				var x SlackUserNotificationSettings
+				tmp, err := json.Marshal(v)
+				if err != nil {
+					return nil, ErrParseUserNotification
+				}
+				if err := json.Unmarshal(tmp, &x); err != nil {
+					return nil, ErrParseUserNotification
+				}
+				notifications[SlackUserNotification] = &x
+			}
 		default:
 			return nil, ErrNotSupportedUserNotification
 		}
@@ -88,6 +88,7 @@ type PipelineBuildTrigger struct {
 	VCSChangesBranch    string         `json:"vcs_branch"`
 	VCSChangesHash      string         `json:"vcs_hash"`
 	VCSChangesAuthor    string         `json:"vcs_author"`
+	Priority            *int           `json:"priority,omitempty"`
 }
 
 // PipelineBuildWarning Struct for display warnings about build
@@ -141,6 +142,7 @@ type RunRequest struct {
 	ParentPipelineID    int64       `json:"parent_pipeline_id,omitempty"`
 	ParentEnvironmentID int64       `json:"parent_environment_id,omitempty"`
 	ParentApplicationID int64       `json:"parent_application_id,omitempty"`
+	Priority            *int        `json:"priority,omitempty"`
 }
 
 // ListPipelines retrieves all available pipelines to called
@@ -2,14 +2,29 @@ package sdk
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 )
 
+//ParsePluginRequirement returns the name of the plugin and the pinned version of a plugin requirement
+//value such as plugin-marathon@1.2. The version is empty if the requirement is not pinned.
+func ParsePluginRequirement(value string) (string, string) {
+	t := strings.SplitN(value, "@", 2)
+	if len(t) == 1 {
+		return t[0], ""
+	}
+	return t[0], t[1]
+}
+
 //DownloadPlugin download plugin from action
 func DownloadPlugin(name string, destdir string) error {
 	var lasterr error
@@ -50,6 +65,12 @@ func DownloadPlugin(name string, destdir string) error {
 
 //UploadPlugin uploads binary file to perform a new action
 func UploadPlugin(filePath string, update bool) ([]byte, error) {
+	return UploadPluginBinary(filePath, update, "", "", "", "")
+}
+
+//UploadPluginBinary uploads the binary file of a plugin for a version and a platform. Binaries for another
+//platform than the one of the API need the name and the version of the plugin.
+func UploadPluginBinary(filePath string, update bool, name, version, goos, goarch string) ([]byte, error) {
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		return nil, err
 	}
@@ -69,10 +90,27 @@ func UploadPlugin(filePath string, update bool) ([]byte, error) {
 		return nil, errc
 	}
 
-	if _, err := io.Copy(part, file); err != nil {
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(part, hash), file); err != nil {
 		return nil, err
 	}
 
+	fields := map[string]string{
+		"name":    name,
+		"version": version,
+		"os":      goos,
+		"arch":    goarch,
+		"sha256":  hex.EncodeToString(hash.Sum(nil)),
+	}
+	for k, v := range fields {
+		if v == "" {
+			continue
+		}
+		if err := writer.WriteField(k, v); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := writer.Close(); err != nil {
 		return nil, err
 	}
@@ -103,3 +141,86 @@ func DeletePlugin(name string) error {
 
 	return nil
 }
+
+//ListPlugins returns all the binaries of all the plugins
+func ListPlugins() ([]ActionPlugin, error) {
+	return listPlugins("/plugin")
+}
+
+//ListPluginVersions returns all the binaries of a plugin
+func ListPluginVersions(name string) ([]ActionPlugin, error) {
+	return listPlugins(fmt.Sprintf("/plugin/%s", name))
+}
+
+func listPlugins(uri string) ([]ActionPlugin, error) {
+	data, code, err := Request("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	plugins := []ActionPlugin{}
+	if err := json.Unmarshal(data, &plugins); err != nil {
+		return nil, err
+	}
+	return plugins, nil
+}
+
+//PromotePluginVersion makes a version the default version of a plugin
+func PromotePluginVersion(name, version string) error {
+	uri := fmt.Sprintf("/plugin/%s/version/%s/promote", name, url.QueryEscape(version))
+	if _, code, err := Request("POST", uri, nil); err != nil {
+		return err
+	} else if code >= 300 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
+
+//DeprecatePluginVersion deprecates a version of a plugin
+func DeprecatePluginVersion(name, version string) error {
+	uri := fmt.Sprintf("/plugin/%s/version/%s/deprecate", name, url.QueryEscape(version))
+	if _, code, err := Request("POST", uri, nil); err != nil {
+		return err
+	} else if code >= 300 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
+
+//GetPluginBinary returns the binary of a plugin for a version and a platform. The default version of the plugin
+//is returned if the version is empty, the latest version starting with the version otherwise.
+func GetPluginBinary(name, version, goos, goarch string) (*ActionPlugin, error) {
+	uri := fmt.Sprintf("/plugin/%s/binary?version=%s&os=%s&arch=%s", name, url.QueryEscape(version), goos, goarch)
+	data, code, err := Request("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("HTTP %d", code)
+	}
+
+	var p ActionPlugin
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+//DownloadPluginBinary downloads the binary of a plugin built for a platform, in the given writer
+func DownloadPluginBinary(p *ActionPlugin, goos, goarch string, w io.Writer) error {
+	uri := fmt.Sprintf("/plugin/download/%s?version=%s&os=%s&arch=%s", p.Name, url.QueryEscape(p.Version), goos, goarch)
+	reader, code, err := Stream("GET", uri, nil)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if code >= 300 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+
+	_, err = io.Copy(w, reader)
+	return err
+}
@@ -0,0 +1,525 @@
+// Code generated by protoc-gen-go.
+// source: actionplugin.proto
+// DO NOT EDIT!
+
+/*
+Package plugin is a generated protocol buffer package.
+
+It is generated from these files:
+	actionplugin.proto
+
+It has these top-level messages:
+	Manifest
+	ParameterSchema
+	RunQuery
+	RunEvent
+	Progress
+	Output
+	RunResult
+*/
+package plugin
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import google_protobuf "github.com/golang/protobuf/ptypes/empty"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// Manifest describes the plugin and the schema of its parameters
+type Manifest struct {
+	Name        string             `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Description string             `protobuf:"bytes,2,opt,name=description" json:"description,omitempty"`
+	Author      string             `protobuf:"bytes,3,opt,name=author" json:"author,omitempty"`
+	Version     string             `protobuf:"bytes,4,opt,name=version" json:"version,omitempty"`
+	Parameters  []*ParameterSchema `protobuf:"bytes,5,rep,name=parameters" json:"parameters,omitempty"`
+}
+
+func (m *Manifest) Reset()                    { *m = Manifest{} }
+func (m *Manifest) String() string            { return proto.CompactTextString(m) }
+func (*Manifest) ProtoMessage()               {}
+func (*Manifest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *Manifest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Manifest) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *Manifest) GetAuthor() string {
+	if m != nil {
+		return m.Author
+	}
+	return ""
+}
+
+func (m *Manifest) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *Manifest) GetParameters() []*ParameterSchema {
+	if m != nil {
+		return m.Parameters
+	}
+	return nil
+}
+
+// ParameterSchema describes a parameter of the plugin and how its value is validated
+type ParameterSchema struct {
+	Name        string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Type        string   `protobuf:"bytes,2,opt,name=type" json:"type,omitempty"`
+	Description string   `protobuf:"bytes,3,opt,name=description" json:"description,omitempty"`
+	Default     string   `protobuf:"bytes,4,opt,name=default" json:"default,omitempty"`
+	Required    bool     `protobuf:"varint,5,opt,name=required" json:"required,omitempty"`
+	Pattern     string   `protobuf:"bytes,6,opt,name=pattern" json:"pattern,omitempty"`
+	Values      []string `protobuf:"bytes,7,rep,name=values" json:"values,omitempty"`
+}
+
+func (m *ParameterSchema) Reset()                    { *m = ParameterSchema{} }
+func (m *ParameterSchema) String() string            { return proto.CompactTextString(m) }
+func (*ParameterSchema) ProtoMessage()               {}
+func (*ParameterSchema) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *ParameterSchema) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ParameterSchema) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *ParameterSchema) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *ParameterSchema) GetDefault() string {
+	if m != nil {
+		return m.Default
+	}
+	return ""
+}
+
+func (m *ParameterSchema) GetRequired() bool {
+	if m != nil {
+		return m.Required
+	}
+	return false
+}
+
+func (m *ParameterSchema) GetPattern() string {
+	if m != nil {
+		return m.Pattern
+	}
+	return ""
+}
+
+func (m *ParameterSchema) GetValues() []string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+// RunQuery is sent by the worker to run the plugin
+type RunQuery struct {
+	JobID         int64             `protobuf:"varint,1,opt,name=jobID" json:"jobID,omitempty"`
+	BuildID       int64             `protobuf:"varint,2,opt,name=buildID" json:"buildID,omitempty"`
+	StepOrder     int32             `protobuf:"varint,3,opt,name=stepOrder" json:"stepOrder,omitempty"`
+	Arguments     map[string]string `protobuf:"bytes,4,rep,name=arguments" json:"arguments,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Url           string            `protobuf:"bytes,5,opt,name=url" json:"url,omitempty"`
+	Hash          string            `protobuf:"bytes,6,opt,name=hash" json:"hash,omitempty"`
+	TlsSkipVerify bool              `protobuf:"varint,7,opt,name=tlsSkipVerify" json:"tlsSkipVerify,omitempty"`
+}
+
+func (m *RunQuery) Reset()                    { *m = RunQuery{} }
+func (m *RunQuery) String() string            { return proto.CompactTextString(m) }
+func (*RunQuery) ProtoMessage()               {}
+func (*RunQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *RunQuery) GetJobID() int64 {
+	if m != nil {
+		return m.JobID
+	}
+	return 0
+}
+
+func (m *RunQuery) GetBuildID() int64 {
+	if m != nil {
+		return m.BuildID
+	}
+	return 0
+}
+
+func (m *RunQuery) GetStepOrder() int32 {
+	if m != nil {
+		return m.StepOrder
+	}
+	return 0
+}
+
+func (m *RunQuery) GetArguments() map[string]string {
+	if m != nil {
+		return m.Arguments
+	}
+	return nil
+}
+
+func (m *RunQuery) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *RunQuery) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *RunQuery) GetTlsSkipVerify() bool {
+	if m != nil {
+		return m.TlsSkipVerify
+	}
+	return false
+}
+
+// RunEvent is streamed by the plugin while it's running, only one field is set on each event
+type RunEvent struct {
+	Log      string     `protobuf:"bytes,1,opt,name=log" json:"log,omitempty"`
+	Progress *Progress  `protobuf:"bytes,2,opt,name=progress" json:"progress,omitempty"`
+	Output   *Output    `protobuf:"bytes,3,opt,name=output" json:"output,omitempty"`
+	Result   *RunResult `protobuf:"bytes,4,opt,name=result" json:"result,omitempty"`
+}
+
+func (m *RunEvent) Reset()                    { *m = RunEvent{} }
+func (m *RunEvent) String() string            { return proto.CompactTextString(m) }
+func (*RunEvent) ProtoMessage()               {}
+func (*RunEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *RunEvent) GetLog() string {
+	if m != nil {
+		return m.Log
+	}
+	return ""
+}
+
+func (m *RunEvent) GetProgress() *Progress {
+	if m != nil {
+		return m.Progress
+	}
+	return nil
+}
+
+func (m *RunEvent) GetOutput() *Output {
+	if m != nil {
+		return m.Output
+	}
+	return nil
+}
+
+func (m *RunEvent) GetResult() *RunResult {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+// Progress reports the progression of the plugin
+type Progress struct {
+	Percent int32  `protobuf:"varint,1,opt,name=percent" json:"percent,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
+}
+
+func (m *Progress) Reset()                    { *m = Progress{} }
+func (m *Progress) String() string            { return proto.CompactTextString(m) }
+func (*Progress) ProtoMessage()               {}
+func (*Progress) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *Progress) GetPercent() int32 {
+	if m != nil {
+		return m.Percent
+	}
+	return 0
+}
+
+func (m *Progress) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+// Output is a variable exported to the build by the plugin
+type Output struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *Output) Reset()                    { *m = Output{} }
+func (m *Output) String() string            { return proto.CompactTextString(m) }
+func (*Output) ProtoMessage()               {}
+func (*Output) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *Output) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Output) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+// RunResult is the last event sent by the plugin
+type RunResult struct {
+	Status string `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
+	Reason string `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
+}
+
+func (m *RunResult) Reset()                    { *m = RunResult{} }
+func (m *RunResult) String() string            { return proto.CompactTextString(m) }
+func (*RunResult) ProtoMessage()               {}
+func (*RunResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *RunResult) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *RunResult) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*Manifest)(nil), "github.com.ovh.cds.sdk.plugin.Manifest")
+	proto.RegisterType((*ParameterSchema)(nil), "github.com.ovh.cds.sdk.plugin.ParameterSchema")
+	proto.RegisterType((*RunQuery)(nil), "github.com.ovh.cds.sdk.plugin.RunQuery")
+	proto.RegisterType((*RunEvent)(nil), "github.com.ovh.cds.sdk.plugin.RunEvent")
+	proto.RegisterType((*Progress)(nil), "github.com.ovh.cds.sdk.plugin.Progress")
+	proto.RegisterType((*Output)(nil), "github.com.ovh.cds.sdk.plugin.Output")
+	proto.RegisterType((*RunResult)(nil), "github.com.ovh.cds.sdk.plugin.RunResult")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for ActionPlugin service
+
+type ActionPluginClient interface {
+	GetManifest(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*Manifest, error)
+	Run(ctx context.Context, in *RunQuery, opts ...grpc.CallOption) (ActionPlugin_RunClient, error)
+}
+
+type actionPluginClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewActionPluginClient(cc *grpc.ClientConn) ActionPluginClient {
+	return &actionPluginClient{cc}
+}
+
+func (c *actionPluginClient) GetManifest(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*Manifest, error) {
+	out := new(Manifest)
+	err := grpc.Invoke(ctx, "/github.com.ovh.cds.sdk.plugin.ActionPlugin/GetManifest", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actionPluginClient) Run(ctx context.Context, in *RunQuery, opts ...grpc.CallOption) (ActionPlugin_RunClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ActionPlugin_serviceDesc.Streams[0], c.cc, "/github.com.ovh.cds.sdk.plugin.ActionPlugin/Run", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &actionPluginRunClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ActionPlugin_RunClient interface {
+	Recv() (*RunEvent, error)
+	grpc.ClientStream
+}
+
+type actionPluginRunClient struct {
+	grpc.ClientStream
+}
+
+func (x *actionPluginRunClient) Recv() (*RunEvent, error) {
+	m := new(RunEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for ActionPlugin service
+
+type ActionPluginServer interface {
+	GetManifest(context.Context, *google_protobuf.Empty) (*Manifest, error)
+	Run(*RunQuery, ActionPlugin_RunServer) error
+}
+
+func RegisterActionPluginServer(s *grpc.Server, srv ActionPluginServer) {
+	s.RegisterService(&_ActionPlugin_serviceDesc, srv)
+}
+
+func _ActionPlugin_GetManifest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActionPluginServer).GetManifest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/github.com.ovh.cds.sdk.plugin.ActionPlugin/GetManifest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActionPluginServer).GetManifest(ctx, req.(*google_protobuf.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActionPlugin_Run_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RunQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ActionPluginServer).Run(m, &actionPluginRunServer{stream})
+}
+
+type ActionPlugin_RunServer interface {
+	Send(*RunEvent) error
+	grpc.ServerStream
+}
+
+type actionPluginRunServer struct {
+	grpc.ServerStream
+}
+
+func (x *actionPluginRunServer) Send(m *RunEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _ActionPlugin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "github.com.ovh.cds.sdk.plugin.ActionPlugin",
+	HandlerType: (*ActionPluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetManifest",
+			Handler:    _ActionPlugin_GetManifest_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Run",
+			Handler:       _ActionPlugin_Run_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "actionplugin.proto",
+}
+
+func init() { proto.RegisterFile("actionplugin.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 628 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0x5b, 0x6f, 0xd3, 0x4c,
+	0x10, 0x95, 0xeb, 0xc4, 0x71, 0x26, 0xdf, 0x05, 0xad, 0x50, 0x65, 0x05, 0x90, 0x22, 0x0b, 0xd4,
+	0x3c, 0xb9, 0x28, 0x48, 0x08, 0x71, 0x13, 0x85, 0x56, 0xa8, 0x0f, 0xb4, 0x65, 0x8b, 0x90, 0xe0,
+	0x6d, 0x13, 0x4f, 0x1c, 0x53, 0xdf, 0xd8, 0x4b, 0xa4, 0xfc, 0x33, 0x78, 0x85, 0x7f, 0xc2, 0x2f,
+	0x41, 0xbb, 0xde, 0x4d, 0x2f, 0x54, 0xa4, 0x6f, 0x7b, 0x76, 0xe6, 0x8c, 0xe7, 0x9c, 0x1d, 0x0f,
+	0x10, 0x36, 0x93, 0x79, 0x5d, 0x35, 0x85, 0xca, 0xf2, 0x2a, 0x69, 0x78, 0x2d, 0x6b, 0x72, 0x2f,
+	0xcb, 0xe5, 0x42, 0x4d, 0x93, 0x59, 0x5d, 0x26, 0xf5, 0x72, 0x91, 0xcc, 0x52, 0x91, 0x88, 0xf4,
+	0x2c, 0x69, 0x93, 0x86, 0x77, 0xb2, 0xba, 0xce, 0x0a, 0xdc, 0x35, 0xc9, 0x53, 0x35, 0xdf, 0xc5,
+	0xb2, 0x91, 0xab, 0x96, 0x1b, 0xff, 0xf0, 0x20, 0x7c, 0xc7, 0xaa, 0x7c, 0x8e, 0x42, 0x12, 0x02,
+	0x9d, 0x8a, 0x95, 0x18, 0x79, 0x23, 0x6f, 0xdc, 0xa7, 0xe6, 0x4c, 0x46, 0x30, 0x48, 0x51, 0xcc,
+	0x78, 0xde, 0xe8, 0xef, 0x46, 0x5b, 0x26, 0x74, 0xf1, 0x8a, 0x6c, 0x43, 0xc0, 0x94, 0x5c, 0xd4,
+	0x3c, 0xf2, 0x4d, 0xd0, 0x22, 0x12, 0x41, 0x6f, 0x89, 0x5c, 0x68, 0x56, 0xc7, 0x04, 0x1c, 0x24,
+	0x47, 0x00, 0x0d, 0xe3, 0xac, 0x44, 0x89, 0x5c, 0x44, 0xdd, 0x91, 0x3f, 0x1e, 0x4c, 0x92, 0xe4,
+	0xaf, 0x2a, 0x92, 0x13, 0x47, 0x38, 0x9d, 0x2d, 0xb0, 0x64, 0xf4, 0x42, 0x85, 0xf8, 0xa7, 0x07,
+	0xff, 0x5f, 0x89, 0x5f, 0xab, 0x85, 0x40, 0x47, 0xae, 0x1a, 0xb4, 0x22, 0xcc, 0xf9, 0xaa, 0x3e,
+	0xff, 0x4f, 0x7d, 0x11, 0xf4, 0x52, 0x9c, 0x33, 0x55, 0x48, 0xa7, 0xc3, 0x42, 0x32, 0x84, 0x90,
+	0xe3, 0x57, 0x95, 0x73, 0x4c, 0xa3, 0xee, 0xc8, 0x1b, 0x87, 0x74, 0x8d, 0x35, 0xab, 0x61, 0x52,
+	0x22, 0xaf, 0xa2, 0xa0, 0x65, 0x59, 0xa8, 0xfd, 0x5a, 0xb2, 0x42, 0xa1, 0x88, 0x7a, 0x23, 0x5f,
+	0xfb, 0xd5, 0xa2, 0xf8, 0xdb, 0x16, 0x84, 0x54, 0x55, 0xef, 0x15, 0xf2, 0x15, 0xb9, 0x0d, 0xdd,
+	0x2f, 0xf5, 0xf4, 0x70, 0xdf, 0xf4, 0xef, 0xd3, 0x16, 0xe8, 0xa2, 0x53, 0x95, 0x17, 0xe9, 0xe1,
+	0xbe, 0xd1, 0xe0, 0x53, 0x07, 0xc9, 0x5d, 0xe8, 0x0b, 0x89, 0xcd, 0x31, 0x4f, 0xb1, 0x7d, 0x87,
+	0x2e, 0x3d, 0xbf, 0x20, 0x1f, 0xa0, 0xcf, 0x78, 0xa6, 0x4a, 0xac, 0xa4, 0x88, 0x3a, 0xc6, 0xef,
+	0xc7, 0x1b, 0xfc, 0x76, 0x9d, 0x24, 0x7b, 0x8e, 0x78, 0x50, 0x49, 0xbe, 0xa2, 0xe7, 0x85, 0xc8,
+	0x2d, 0xf0, 0x15, 0x2f, 0x8c, 0xf2, 0x3e, 0xd5, 0x47, 0x6d, 0xf0, 0x82, 0x89, 0x85, 0x55, 0x6c,
+	0xce, 0xe4, 0x3e, 0xfc, 0x2b, 0x0b, 0x71, 0x7a, 0x96, 0x37, 0x1f, 0x91, 0xe7, 0xf3, 0x55, 0xd4,
+	0x33, 0x4e, 0x5d, 0xbe, 0x1c, 0x3e, 0x87, 0xff, 0x2e, 0x7f, 0x48, 0x57, 0x3f, 0xc3, 0x95, 0x7d,
+	0x3f, 0x7d, 0xd4, 0x9e, 0x18, 0xab, 0xec, 0xfb, 0xb5, 0xe0, 0xe9, 0xd6, 0x13, 0x2f, 0xfe, 0xe5,
+	0x19, 0xeb, 0x0e, 0x96, 0x58, 0x49, 0x4d, 0x2c, 0xea, 0xcc, 0x11, 0x8b, 0x3a, 0x23, 0x6f, 0x20,
+	0x6c, 0x78, 0x9d, 0x71, 0x14, 0xc2, 0x70, 0x07, 0x93, 0x9d, 0x4d, 0xd3, 0x66, 0xd3, 0xe9, 0x9a,
+	0x48, 0x5e, 0x40, 0x50, 0x2b, 0xd9, 0x28, 0x69, 0xec, 0x1d, 0x4c, 0x1e, 0x6c, 0x28, 0x71, 0x6c,
+	0x92, 0xa9, 0x25, 0x91, 0x57, 0x10, 0x70, 0x14, 0x6e, 0x88, 0x06, 0x93, 0xf1, 0x66, 0xff, 0xa9,
+	0xc9, 0xa7, 0x96, 0x17, 0xbf, 0x84, 0xd0, 0xb5, 0x65, 0xa6, 0x0b, 0xf9, 0x0c, 0x2b, 0x69, 0x74,
+	0x76, 0xa9, 0x83, 0x3a, 0x52, 0xa2, 0x10, 0x2c, 0x73, 0x36, 0x39, 0x18, 0x4f, 0x20, 0x68, 0x7b,
+	0xba, 0xf6, 0xdf, 0xb8, 0xd6, 0xdc, 0xf8, 0x19, 0xf4, 0xd7, 0x8d, 0xe8, 0xc1, 0x15, 0x92, 0x49,
+	0x25, 0x2c, 0xd1, 0x22, 0x7d, 0xcf, 0x91, 0x89, 0xf5, 0x76, 0xb0, 0x68, 0xf2, 0xdd, 0x83, 0x7f,
+	0xf6, 0xcc, 0xba, 0x3a, 0x31, 0x9a, 0xc8, 0x11, 0x0c, 0xde, 0xa2, 0x5c, 0xaf, 0x9b, 0xed, 0xa4,
+	0xdd, 0x4c, 0x89, 0xdb, 0x4c, 0xc9, 0x81, 0xde, 0x4c, 0xc3, 0x4d, 0x8f, 0xb3, 0x2e, 0xf0, 0x09,
+	0x7c, 0xaa, 0x2a, 0xb2, 0x73, 0xc3, 0x51, 0x1e, 0xde, 0x20, 0xd1, 0x8c, 0xd0, 0x43, 0xef, 0x75,
+	0xf8, 0x39, 0x68, 0x2f, 0xa7, 0x81, 0xe9, 0xee, 0xd1, 0xef, 0x01, 0x00, 0x0c, 0x4b, 0x8b, 0x5a,
+	0x7a, 0x05, 0x00, 0x00,
+}
@@ -0,0 +1,172 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+)
+
+//HandshakeTimeout is the time to wait for the handshake of a plugin
+var HandshakeTimeout = time.Minute
+
+//Port range given to the plugins served with the net/rpc protocol, the default one of go-plugin
+const (
+	pluginMinPort = 10000
+	pluginMaxPort = 25000
+)
+
+//Conn is a connection to a running plugin. Plugins served with ServeV2 are called with gRPC through
+//the embedded ActionPluginClient, plugins served with Serve are called with the net/rpc Client.
+type Conn struct {
+	ProtocolVersion int
+	Client          *Client
+	ActionPluginClient
+	cmd  *exec.Cmd
+	conn *grpc.ClientConn
+}
+
+//Dial starts the plugin binary and negotiates the protocol version from its handshake.
+//Plugins which are not served with ServeV2 are called with the net/rpc client, reattached to the started plugin.
+func Dial(ctx context.Context, name, binary, id, url string, tlsSkipVerify bool) (*Conn, error) {
+	cmd := command(ctx, binary)
+	//Same environment as go-plugin, so that plugins served with Serve start as with the net/rpc client
+	cmd.Env = append(cmd.Env,
+		Handshake.MagicCookieKey+"="+Handshake.MagicCookieValue,
+		fmt.Sprintf("PLUGIN_MIN_PORT=%d", pluginMinPort),
+		fmt.Sprintf("PLUGIN_MAX_PORT=%d", pluginMaxPort),
+	)
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	line, err := readHandshake(ctx, stdout)
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, fmt.Errorf("plugin %s: %s", name, err)
+	}
+
+	parts := strings.Split(line, "|")
+	if len(parts) < 4 {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, fmt.Errorf("plugin %s: unrecognized handshake %s", name, line)
+	}
+
+	//Discard everything the plugin prints after the handshake
+	go io.Copy(ioutil.Discard, stdout)
+
+	if v, _ := strconv.Atoi(parts[1]); v != GRPCProtocolVersion || len(parts) < 5 || parts[4] != "grpc" {
+		//The plugin is served with the net/rpc protocol
+		return reattach(cmd, parts, name, binary, id, url, tlsSkipVerify)
+	}
+
+	conn, err := grpc.Dial(parts[3], grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(HandshakeTimeout))
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, fmt.Errorf("plugin %s: unable to connect: %s", name, err)
+	}
+
+	return &Conn{
+		ProtocolVersion:    GRPCProtocolVersion,
+		ActionPluginClient: NewActionPluginClient(conn),
+		cmd:                cmd,
+		conn:               conn,
+	}, nil
+}
+
+//reattach returns a connection to a plugin served with the net/rpc protocol, from its handshake
+func reattach(cmd *exec.Cmd, parts []string, name, binary, id, url string, tlsSkipVerify bool) (*Conn, error) {
+	kill := func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+		if parts[2] == "unix" {
+			os.Remove(parts[3])
+		}
+	}
+
+	if v, _ := strconv.Atoi(parts[1]); v != int(Handshake.ProtocolVersion) {
+		kill()
+		return nil, fmt.Errorf("plugin %s: incompatible protocol version %s, expected %d", name, parts[1], Handshake.ProtocolVersion)
+	}
+
+	var addr net.Addr
+	switch parts[2] {
+	case "tcp":
+		a, err := net.ResolveTCPAddr("tcp", parts[3])
+		if err != nil {
+			kill()
+			return nil, fmt.Errorf("plugin %s: invalid address %s: %s", name, parts[3], err)
+		}
+		addr = a
+	case "unix":
+		addr = &net.UnixAddr{Name: parts[3], Net: "unix"}
+	default:
+		kill()
+		return nil, fmt.Errorf("plugin %s: unknown address type %s", name, parts[2])
+	}
+
+	//The go-plugin client only watches the process, it has to be waited for here
+	go func() {
+		cmd.Wait()
+		if parts[2] == "unix" {
+			os.Remove(parts[3])
+		}
+	}()
+
+	client := reattachClient(&plugin.ReattachConfig{Addr: addr, Pid: cmd.Process.Pid}, name, binary, id, url, tlsSkipVerify)
+	return &Conn{ProtocolVersion: int(Handshake.ProtocolVersion), Client: client}, nil
+}
+
+//readHandshake reads the first line printed by the plugin
+func readHandshake(ctx context.Context, r io.Reader) (string, error) {
+	lineCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		line, err := bufio.NewReader(r).ReadString('\n')
+		if err != nil {
+			errCh <- fmt.Errorf("unable to read handshake: %s", err)
+			return
+		}
+		lineCh <- strings.TrimSpace(line)
+	}()
+
+	select {
+	case line := <-lineCh:
+		return line, nil
+	case err := <-errCh:
+		return "", err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-time.After(HandshakeTimeout):
+		return "", fmt.Errorf("timeout while waiting for handshake")
+	}
+}
+
+//Close closes the connection and stops the plugin
+func (c *Conn) Close() {
+	if c.Client != nil {
+		c.Client.Kill()
+		return
+	}
+	c.conn.Close()
+	c.cmd.Process.Kill()
+	c.cmd.Wait()
+}
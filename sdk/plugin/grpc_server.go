@@ -0,0 +1,221 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/spf13/cobra"
+	netcontext "golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"github.com/ovh/cds/sdk"
+)
+
+//GRPCProtocolVersion is the version of the plugin protocol served with gRPC
+const GRPCProtocolVersion = 2
+
+//CDSActionV2 is the interface of plugins served with the gRPC protocol. Unlike CDSAction, the plugin streams
+//its logs while it's running, exports output variables and is canceled with the context.
+type CDSActionV2 interface {
+	Manifest() *Manifest
+	Run(context.Context, *RunJob) error
+}
+
+//RunJob is the input of the Run function of CDSActionV2 plugins
+type RunJob struct {
+	Query  *RunQuery
+	stream ActionPlugin_RunServer
+}
+
+//ID returns the ID of the job
+func (j *RunJob) ID() int64 { return j.Query.JobID }
+
+//PipelineBuildID returns the ID of the pipeline build, or of the workflow node run
+func (j *RunJob) PipelineBuildID() int64 { return j.Query.BuildID }
+
+//StepOrder returns the order of the step in the job
+func (j *RunJob) StepOrder() int { return int(j.Query.StepOrder) }
+
+//Arguments returns the arguments of the job, validated against the manifest of the plugin
+func (j *RunJob) Arguments() Arguments { return Arguments{Data: j.Query.Arguments} }
+
+//Options returns the options to call CDS API
+func (j *RunJob) Options() Options {
+	return Options{ID: j.Query.Hash, URL: j.Query.Url, TlsSkipVerify: j.Query.TlsSkipVerify}
+}
+
+//Log sends a line in the step log
+func (j *RunJob) Log(format string, i ...interface{}) error {
+	return j.stream.Send(&RunEvent{Log: fmt.Sprintf(format, i...)})
+}
+
+//Progress reports the progression of the plugin, in percent
+func (j *RunJob) Progress(percent int32, format string, i ...interface{}) error {
+	return j.stream.Send(&RunEvent{Progress: &Progress{Percent: percent, Message: fmt.Sprintf(format, i...)}})
+}
+
+//Export exports a variable to the build, as the worker export command does
+func (j *RunJob) Export(name, value string) error {
+	return j.stream.Send(&RunEvent{Output: &Output{Name: name, Value: value}})
+}
+
+//ValidateArguments checks the arguments against the schema of the parameters, and sets the default values of
+//the missing ones
+func ValidateArguments(params []*ParameterSchema, args map[string]string) error {
+	for _, p := range params {
+		v, ok := args[p.Name]
+		if !ok || v == "" {
+			if p.Default == "" && p.Required {
+				return fmt.Errorf("parameter %s is required", p.Name)
+			}
+			args[p.Name] = p.Default
+			continue
+		}
+
+		switch ParameterType(p.Type) {
+		case NumberParameter:
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				return fmt.Errorf("parameter %s: %s is not a number", p.Name, v)
+			}
+		case BooleanParameter:
+			if _, err := strconv.ParseBool(v); err != nil {
+				return fmt.Errorf("parameter %s: %s is not a boolean", p.Name, v)
+			}
+		case ListParameter:
+			if len(p.Values) > 0 && !isInList(v, p.Values) {
+				return fmt.Errorf("parameter %s: %s is not one of %s", p.Name, v, strings.Join(p.Values, ", "))
+			}
+		}
+
+		if p.Pattern != "" {
+			r, err := regexp.Compile(p.Pattern)
+			if err != nil {
+				return fmt.Errorf("parameter %s: invalid pattern %s: %s", p.Name, p.Pattern, err)
+			}
+			if !r.MatchString(v) {
+				return fmt.Errorf("parameter %s: %s does not match %s", p.Name, v, p.Pattern)
+			}
+		}
+	}
+	return nil
+}
+
+func isInList(v string, values []string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+//actionPluginServer implements ActionPluginServer for a CDSActionV2
+type actionPluginServer struct {
+	impl CDSActionV2
+}
+
+func (s *actionPluginServer) GetManifest(netcontext.Context, *empty.Empty) (*Manifest, error) {
+	return s.impl.Manifest(), nil
+}
+
+func (s *actionPluginServer) Run(q *RunQuery, stream ActionPlugin_RunServer) error {
+	if q.Arguments == nil {
+		q.Arguments = map[string]string{}
+	}
+
+	res := &RunResult{Status: Success}
+	if err := ValidateArguments(s.impl.Manifest().Parameters, q.Arguments); err != nil {
+		res = &RunResult{Status: Fail, Reason: err.Error()}
+	} else if err := s.impl.Run(stream.Context(), &RunJob{Query: q, stream: stream}); err != nil {
+		res = &RunResult{Status: Fail, Reason: err.Error()}
+	}
+	return stream.Send(&RunEvent{Result: res})
+}
+
+//ServeV2 has to be called in main func of every plugin implementing CDSActionV2
+func ServeV2(a CDSActionV2) {
+	if os.Getenv(Handshake.MagicCookieKey) != Handshake.MagicCookieValue {
+		fmt.Fprintf(os.Stderr, "This binary is a plugin. These are not meant to be executed directly.\n"+
+			"Please execute the program that consumes these plugins, which will load any plugins automatically\n")
+		os.Exit(1)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "plugin: unable to listen: %s\n", err)
+		os.Exit(1)
+	}
+
+	s := grpc.NewServer()
+	RegisterActionPluginServer(s, &actionPluginServer{a})
+
+	//Output the address so that the worker can bring it up, with the same handshake than go-plugin
+	fmt.Printf("%d|%d|%s|%s|grpc\n", Handshake.ProtocolVersion, GRPCProtocolVersion, listener.Addr().Network(), listener.Addr().String())
+	os.Stdout.Sync()
+
+	if err := s.Serve(listener); err != nil {
+		fmt.Fprintf(os.Stderr, "plugin: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+//MainV2 func call by plugins implementing CDSActionV2, display info only
+func MainV2(p CDSActionV2) {
+	var format string
+
+	var cmdInfo = &cobra.Command{
+		Use:   "info",
+		Short: "Print plugin Information anything to the screen: info --format <yml>",
+		Run: func(cmd *cobra.Command, args []string) {
+			if format != "markdown" {
+				if err := sdk.Output(format, p.Manifest(), fmt.Printf); err != nil {
+					fmt.Printf("Error:%s", err)
+				}
+				return
+			}
+			fmt.Print(ManifestMarkdown(p.Manifest()))
+		},
+	}
+
+	cmdInfo.Flags().StringVarP(&format, "format", "", "markdown", "--format:yaml, json, xml, markdown")
+
+	var rootCmd = &cobra.Command{
+		Run: func(cmd *cobra.Command, args []string) {
+			ServeV2(p)
+		},
+	}
+	rootCmd.AddCommand(cmdInfo)
+	rootCmd.Execute()
+}
+
+// ManifestMarkdown returns the manifest of a plugin formatted with markdown
+func ManifestMarkdown(m *Manifest) string {
+	var sp string
+	for _, p := range m.Parameters {
+		sp += fmt.Sprintf("* **%s**: %s\n", p.Name, p.Description)
+	}
+
+	info := fmt.Sprintf(`
+%s
+
+## Parameters
+
+%s
+
+## More
+
+More documentation on [Github](https://github.com/ovh/cds/tree/master/contrib/plugins/%s/README.md)
+
+`,
+		m.Description,
+		sp,
+		m.Name)
+
+	return info
+}
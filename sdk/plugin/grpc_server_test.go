@@ -0,0 +1,64 @@
+package plugin
+
+import "testing"
+
+func TestValidateArguments(t *testing.T) {
+	params := []*ParameterSchema{
+		{Name: "url", Type: string(StringParameter), Required: true, Pattern: "^https?://"},
+		{Name: "timeout", Type: string(NumberParameter), Default: "60"},
+		{Name: "insecure", Type: string(BooleanParameter)},
+		{Name: "mode", Type: string(ListParameter), Values: []string{"blue", "green"}},
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "valid arguments",
+			args: map[string]string{"url": "https://foo.bar", "timeout": "30", "insecure": "true", "mode": "green"},
+		},
+		{
+			name:    "missing required argument",
+			args:    map[string]string{"timeout": "30"},
+			wantErr: true,
+		},
+		{
+			name:    "pattern mismatch",
+			args:    map[string]string{"url": "foo.bar"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid number",
+			args:    map[string]string{"url": "https://foo.bar", "timeout": "one minute"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid boolean",
+			args:    map[string]string{"url": "https://foo.bar", "insecure": "maybe"},
+			wantErr: true,
+		},
+		{
+			name:    "value not in list",
+			args:    map[string]string{"url": "https://foo.bar", "mode": "red"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateArguments(params, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateArguments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	args := map[string]string{"url": "http://foo.bar"}
+	if err := ValidateArguments(params, args); err != nil {
+		t.Fatalf("ValidateArguments() error = %v", err)
+	}
+	if args["timeout"] != "60" {
+		t.Errorf("ValidateArguments() default value of timeout = %v, want 60", args["timeout"])
+	}
+}
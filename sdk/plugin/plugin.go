@@ -30,6 +30,40 @@ func Serve(a CDSAction) {
 
 //NewClient has to be called every time we nedd to call a plugin
 func NewClient(ctx context.Context, name, binary, id, url string, tlsSkipVerify bool) *Client {
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig: Handshake,
+		Plugins: map[string]plugin.Plugin{
+			name: CDSActionPlugin{},
+		},
+		Cmd: command(ctx, binary),
+	})
+	return newClient(client, name, binary, id, url, tlsSkipVerify)
+}
+
+//reattachClient returns a client of a plugin already started and served with the net/rpc protocol
+func reattachClient(reattach *plugin.ReattachConfig, name, binary, id, url string, tlsSkipVerify bool) *Client {
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig: Handshake,
+		Plugins: map[string]plugin.Plugin{
+			name: CDSActionPlugin{},
+		},
+		Reattach: reattach,
+	})
+	return newClient(client, name, binary, id, url, tlsSkipVerify)
+}
+
+func newClient(client *plugin.Client, name, binary, id, url string, tlsSkipVerify bool) *Client {
+	options := Options{
+		ID:            id,
+		URL:           url,
+		TlsSkipVerify: tlsSkipVerify,
+	}
+
+	return &Client{client, name, binary, options}
+}
+
+//command returns the command starting the plugin binary, without the technical env variables of the worker
+func command(ctx context.Context, binary string) *exec.Cmd {
 	cmd := exec.CommandContext(ctx, binary)
 
 	env := os.Environ()
@@ -47,22 +81,7 @@ func NewClient(ctx context.Context, name, binary, id, url string, tlsSkipVerify
 		}
 		cmd.Env = append(cmd.Env, e)
 	}
-
-	client := plugin.NewClient(&plugin.ClientConfig{
-		HandshakeConfig: Handshake,
-		Plugins: map[string]plugin.Plugin{
-			name: CDSActionPlugin{},
-		},
-		Cmd: cmd,
-	})
-
-	options := Options{
-		ID:            id,
-		URL:           url,
-		TlsSkipVerify: tlsSkipVerify,
-	}
-
-	return &Client{client, name, binary, options}
+	return cmd
 }
 
 //CDSActionPlugin is the implementation of plugin.Plugin so we can serve/consume this
@@ -25,6 +25,7 @@ type Project struct {
 	ReposManager  []RepositoriesManager `json:"repositories_manager"  yaml:"-" db:"-" cli:"-"`
 	Metadata      Metadata              `json:"metadata" yaml:"metadata" db:"-" cli:"-"`
 	Keys          []ProjectKey          `json:"keys" yaml:"keys" db:"-" cli:"-"`
+	JobPriority   *int                  `json:"job_priority,omitempty" yaml:"job_priority,omitempty" db:"job_priority" cli:"-"`
 }
 
 // ProjectVariableAudit represents an audit on a project variable
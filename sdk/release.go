@@ -0,0 +1,60 @@
+package sdk
+
+import (
+	"regexp"
+	"time"
+)
+
+// Release is a named and immutable bundle of artifacts promoted from a workflow run for an application.
+// Releases are not removed with the workflow runs
+type Release struct {
+	ID                int64             `json:"id" db:"id" cli:"-"`
+	ApplicationID     int64             `json:"application_id" db:"application_id" cli:"-"`
+	Version           string            `json:"version" db:"version" cli:"version"`
+	WorkflowName      string            `json:"workflow_name" db:"workflow_name" cli:"workflow"`
+	WorkflowRunNumber int64             `json:"workflow_run_number" db:"workflow_run_number" cli:"run"`
+	Author            string            `json:"author" db:"author" cli:"author"`
+	Created           time.Time         `json:"created" db:"created" cli:"created"`
+	Artifacts         []ReleaseArtifact `json:"artifacts,omitempty" db:"-" cli:"-"`
+}
+
+// ReleaseArtifact is an artifact of a release
+type ReleaseArtifact struct {
+	ID         int64  `json:"id" db:"id" cli:"-"`
+	ReleaseID  int64  `json:"release_id" db:"release_id" cli:"-"`
+	Name       string `json:"name" db:"name" cli:"name"`
+	Size       int64  `json:"size,omitempty" db:"size" cli:"size"`
+	Perm       uint32 `json:"perm,omitempty" db:"perm" cli:"-"`
+	MD5sum     string `json:"md5sum,omitempty" db:"md5sum" cli:"-"`
+	SHA256Sum  string `json:"sha256sum,omitempty" db:"sha256sum" cli:"sha256sum"`
+	ObjectPath string `json:"object_path,omitempty" db:"object_path" cli:"-"`
+}
+
+// ReleasePromotion is the request to promote the artifacts of a workflow run to a release
+type ReleasePromotion struct {
+	Version           string   `json:"version"`
+	WorkflowName      string   `json:"workflow_name"`
+	WorkflowRunNumber int64    `json:"workflow_run_number"`
+	Artifacts         []string `json:"artifacts,omitempty"`
+}
+
+// IsValid checks the promotion request
+func (p ReleasePromotion) IsValid() error {
+	if p.Version == "" || p.WorkflowName == "" || p.WorkflowRunNumber == 0 {
+		return WrapError(ErrWrongRequest, "ReleasePromotion.IsValid> version, workflow_name and workflow_run_number are mandatory")
+	}
+	if !regexp.MustCompile(NamePattern).MatchString(p.Version) {
+		return WrapError(ErrWrongRequest, "ReleasePromotion.IsValid> Invalid version %s", p.Version)
+	}
+	return nil
+}
+
+// Artifact returns the artifact of the release with the given name
+func (r *Release) Artifact(name string) (*ReleaseArtifact, error) {
+	for i := range r.Artifacts {
+		if r.Artifacts[i].Name == name {
+			return &r.Artifacts[i], nil
+		}
+	}
+	return nil, WrapError(ErrNotFound, "Release.Artifact> Artifact %s not found in release %s", name, r.Version)
+}
@@ -39,6 +39,7 @@ type RepositoryPoller struct {
 	Enabled       bool                       `json:"enabled" db:"enabled"`
 	DateCreation  time.Time                  `json:"date_creation" db:"date_creation"`
 	NextExecution *RepositoryPollerExecution `json:"next_execution" db:"-"`
+	Filter        TriggerFilter              `json:"filter" db:"-"`
 }
 
 //RepositoryPollerExecution is a polling execution
@@ -294,6 +295,7 @@ type RepositoriesManagerClient interface {
 	//Commits
 	Commits(repo, branch, since, until string) ([]VCSCommit, error)
 	Commit(repo, hash string) (VCSCommit, error)
+	ChangedFiles(repo, from, to string) ([]string, error)
 
 	//Hooks
 	CreateHook(repo, url string) error
@@ -347,10 +349,12 @@ type VCSBranch struct {
 	Parents      []string `json:"parents"`
 }
 
-//VCSPushEvent represents a push events for polling
+//VCSPushEvent represents a push events for polling. Before is the head of the branch before the pushes,
+//it is empty if it is unknown or if the branch has been created
 type VCSPushEvent struct {
 	Branch VCSBranch `json:"branch"`
 	Commit VCSCommit `json:"commit"`
+	Before string    `json:"before,omitempty"`
 }
 
 //VCSCreateEvent represents a push events for polling
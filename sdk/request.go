@@ -383,6 +383,10 @@ func Upload(method string, path string, body io.ReadCloser, mods ...RequestModif
 		basedHash := base64.StdEncoding.EncodeToString([]byte(hash))
 		req.Header.Set(AuthHeader, basedHash)
 	}
+	if user != "" && token != "" {
+		req.Header.Add(SessionTokenHeader, token)
+		req.SetBasicAuth(user, token)
+	}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, 0, err
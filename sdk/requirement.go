@@ -0,0 +1,91 @@
+package sdk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseCPURequirement returns the number of CPUs of a cpu requirement
+func ParseCPURequirement(value string) (float64, error) {
+	cpus, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || cpus <= 0 {
+		return 0, fmt.Errorf("invalid cpu requirement %s: must be a positive number", value)
+	}
+	return cpus, nil
+}
+
+// ParseDiskRequirement returns the disk space in MB of a disk requirement
+func ParseDiskRequirement(value string) (int64, error) {
+	disk, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || disk <= 0 {
+		return 0, fmt.Errorf("invalid disk requirement %s: must be a positive number of MB", value)
+	}
+	return disk, nil
+}
+
+// ParseOSArchRequirement returns the operating system and the architecture of an os-arch requirement.
+// Architecture is optional, ex: linux or linux/amd64
+func ParseOSArchRequirement(value string) (string, string, error) {
+	tuple := strings.Split(strings.TrimSpace(value), "/")
+	if len(tuple) > 2 || tuple[0] == "" || (len(tuple) == 2 && tuple[1] == "") {
+		return "", "", fmt.Errorf("invalid os-arch requirement %s: must be os/arch, ex: linux/amd64", value)
+	}
+	if len(tuple) == 1 {
+		return tuple[0], "", nil
+	}
+	return tuple[0], tuple[1], nil
+}
+
+// MatchOSArch checks an os-arch requirement against an operating system and an architecture
+func MatchOSArch(value, os, arch string) bool {
+	reqOS, reqArch, err := ParseOSArchRequirement(value)
+	if err != nil {
+		return false
+	}
+	return reqOS == os && (reqArch == "" || reqArch == arch)
+}
+
+// IsValid checks the value of requirements with a typed value
+func (r Requirement) IsValid() error {
+	var err error
+	switch r.Type {
+	case MemoryRequirement:
+		if _, errp := strconv.ParseInt(r.Value, 10, 64); errp != nil {
+			err = fmt.Errorf("invalid memory requirement %s: must be a number of MB", r.Value)
+		}
+	case CPURequirement:
+		_, err = ParseCPURequirement(r.Value)
+	case DiskRequirement:
+		_, err = ParseDiskRequirement(r.Value)
+	case OSArchRequirement:
+		_, _, err = ParseOSArchRequirement(r.Value)
+	}
+	return err
+}
+
+// MatchOSArch checks an os-arch requirement against the worker model. If the model
+// has os-arch capabilities, one of them must match. Otherwise docker models only
+// run linux, and other models are supposed to match: the worker checks the requirement.
+func (m *Model) MatchOSArch(value string) bool {
+	var hasCapability bool
+	for _, c := range m.Capabilities {
+		if c.Type != OSArchRequirement {
+			continue
+		}
+		hasCapability = true
+		os, arch, err := ParseOSArchRequirement(c.Value)
+		if err == nil && MatchOSArch(value, os, arch) {
+			return true
+		}
+	}
+	if hasCapability {
+		return false
+	}
+
+	if m.Type == Docker {
+		reqOS, _, err := ParseOSArchRequirement(value)
+		return err == nil && reqOS == "linux"
+	}
+	return true
+}
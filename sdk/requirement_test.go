@@ -0,0 +1,61 @@
+package sdk
+
+import "testing"
+
+func TestRequirementIsValid(t *testing.T) {
+	tests := []struct {
+		r       Requirement
+		wantErr bool
+	}{
+		{r: Requirement{Type: CPURequirement, Value: "2"}},
+		{r: Requirement{Type: CPURequirement, Value: "0.5"}},
+		{r: Requirement{Type: CPURequirement, Value: "0"}, wantErr: true},
+		{r: Requirement{Type: CPURequirement, Value: "two"}, wantErr: true},
+		{r: Requirement{Type: DiskRequirement, Value: "10240"}},
+		{r: Requirement{Type: DiskRequirement, Value: "10G"}, wantErr: true},
+		{r: Requirement{Type: MemoryRequirement, Value: "1024"}},
+		{r: Requirement{Type: MemoryRequirement, Value: "1G"}, wantErr: true},
+		{r: Requirement{Type: OSArchRequirement, Value: "linux/amd64"}},
+		{r: Requirement{Type: OSArchRequirement, Value: "windows"}},
+		{r: Requirement{Type: OSArchRequirement, Value: "linux/"}, wantErr: true},
+		{r: Requirement{Type: OSArchRequirement, Value: "linux/amd64/v2"}, wantErr: true},
+		{r: Requirement{Type: BinaryRequirement, Value: "go"}},
+	}
+	for _, tt := range tests {
+		if err := tt.r.IsValid(); (err != nil) != tt.wantErr {
+			t.Errorf("Requirement{%s, %s}.IsValid() error = %v, wantErr %v", tt.r.Type, tt.r.Value, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMatchOSArch(t *testing.T) {
+	if !MatchOSArch("linux/amd64", "linux", "amd64") {
+		t.Errorf("linux/amd64 should match linux/amd64")
+	}
+	if !MatchOSArch("linux", "linux", "arm") {
+		t.Errorf("linux should match linux/arm")
+	}
+	if MatchOSArch("linux/amd64", "linux", "arm") {
+		t.Errorf("linux/amd64 should not match linux/arm")
+	}
+	if MatchOSArch("darwin", "linux", "amd64") {
+		t.Errorf("darwin should not match linux/amd64")
+	}
+}
+
+func TestModelMatchOSArch(t *testing.T) {
+	docker := Model{Type: Docker}
+	if !docker.MatchOSArch("linux/amd64") || docker.MatchOSArch("windows") {
+		t.Errorf("docker models should only match linux")
+	}
+
+	arm := Model{Type: Docker, Capabilities: []Requirement{{Type: OSArchRequirement, Value: "linux/arm64"}}}
+	if !arm.MatchOSArch("linux") || !arm.MatchOSArch("linux/arm64") || arm.MatchOSArch("linux/amd64") {
+		t.Errorf("model with os-arch capability should match its capability")
+	}
+
+	openstack := Model{Type: Openstack}
+	if !openstack.MatchOSArch("windows/amd64") {
+		t.Errorf("openstack models without capability should match")
+	}
+}
@@ -0,0 +1,112 @@
+package sdk
+
+import (
+	"encoding/base64"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// SecretMinLength is the minimal length of a value to be masked. Shorter values would mask too much of the logs
+const SecretMinLength = 6
+
+type secretReplacement struct {
+	value       string
+	replacement string
+}
+
+// SecretMasker replaces secret values, and their common encodings, in logs
+type SecretMasker struct {
+	replacements []secretReplacement
+	maxLen       int
+}
+
+// NewSecretMasker returns a masker for the given secrets. The values are masked as is, base64 encoded and
+// URL encoded. Each line of a multiline secret (ssh keys for instance) is also masked on its own.
+func NewSecretMasker(secrets []Variable) *SecretMasker {
+	m := &SecretMasker{}
+	seen := map[string]bool{}
+	add := func(value, name string) {
+		if len(value) < SecretMinLength || seen[value] {
+			return
+		}
+		seen[value] = true
+		if len(value) > m.maxLen {
+			m.maxLen = len(value)
+		}
+		m.replacements = append(m.replacements, secretReplacement{value: value, replacement: "**" + name + "**"})
+	}
+
+	for _, s := range secrets {
+		v := s.Value
+		if len(v) < SecretMinLength {
+			continue
+		}
+		add(v, s.Name)
+		add(base64.StdEncoding.EncodeToString([]byte(v)), s.Name)
+		add(base64.RawStdEncoding.EncodeToString([]byte(v)), s.Name)
+		add(base64.URLEncoding.EncodeToString([]byte(v)), s.Name)
+		add(base64.RawURLEncoding.EncodeToString([]byte(v)), s.Name)
+		add(url.QueryEscape(v), s.Name)
+		add(url.PathEscape(v), s.Name)
+		if strings.Contains(v, "\n") {
+			for _, l := range strings.Split(v, "\n") {
+				add(strings.TrimSpace(l), s.Name)
+			}
+		}
+	}
+
+	// Longest values first, so a secret containing another one is fully masked
+	sort.SliceStable(m.replacements, func(i, j int) bool {
+		return len(m.replacements[i].value) > len(m.replacements[j].value)
+	})
+	return m
+}
+
+// Mask replaces all the known secrets in s
+func (m *SecretMasker) Mask(s string) string {
+	if m == nil {
+		return s
+	}
+	for _, r := range m.replacements {
+		s = strings.Replace(s, r.value, r.replacement, -1)
+	}
+	return s
+}
+
+// MaskAppend masks a chunk appended to logs which are already masked. Only the end of the logs, where a secret
+// split over the logs and the chunk may start, is masked again with the chunk.
+func (m *SecretMasker) MaskAppend(logs, chunk string) string {
+	if m == nil {
+		return logs + chunk
+	}
+	cut := len(logs) - m.maxLen + 1
+	if cut < 0 {
+		cut = 0
+	}
+	return logs[:cut] + m.Mask(logs[cut:]+chunk)
+}
+
+// MaskChunk masks a chunk of a stream. It returns the part which is safe to send, and the end of the chunk
+// which may be the beginning of a secret. This remaining part has to be prepended to the next chunk, or masked
+// with Mask if there is no more data.
+func (m *SecretMasker) MaskChunk(s string) (string, string) {
+	s = m.Mask(s)
+	if m == nil {
+		return s, ""
+	}
+
+	start := len(s) - m.maxLen + 1
+	if start < 0 {
+		start = 0
+	}
+	for i := start; i < len(s); i++ {
+		tail := s[i:]
+		for _, r := range m.replacements {
+			if len(tail) < len(r.value) && strings.HasPrefix(r.value, tail) {
+				return s[:i], tail
+			}
+		}
+	}
+	return s, ""
+}
@@ -0,0 +1,79 @@
+package sdk
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+)
+
+func TestSecretMaskerMask(t *testing.T) {
+	m := NewSecretMasker([]Variable{
+		{Name: "cds.proj.password", Value: "s3cr3t+p@ss/word"},
+		{Name: "cds.app.key", Value: "-----BEGIN KEY-----\nMIIEowIBAAKCAQEA\n-----END KEY-----"},
+		{Name: "cds.env.short", Value: "abc"},
+	})
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "password is s3cr3t+p@ss/word.", "password is **cds.proj.password**."},
+		{"base64", "auth: " + base64.StdEncoding.EncodeToString([]byte("s3cr3t+p@ss/word")), "auth: **cds.proj.password**"},
+		{"base64 url", base64.RawURLEncoding.EncodeToString([]byte("s3cr3t+p@ss/word")), "**cds.proj.password**"},
+		{"url encoded", "https://host/?p=" + url.QueryEscape("s3cr3t+p@ss/word"), "https://host/?p=**cds.proj.password**"},
+		{"multiline", "-----BEGIN KEY-----\nMIIEowIBAAKCAQEA\n-----END KEY-----", "**cds.app.key**"},
+		{"single line of a key", "key: MIIEowIBAAKCAQEA", "key: **cds.app.key**"},
+		{"too short", "abc", "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Mask(tt.in); got != tt.want {
+				t.Errorf("Mask() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretMaskerMaskChunk(t *testing.T) {
+	m := NewSecretMasker([]Variable{{Name: "pwd", Value: "mysecretvalue"}})
+
+	var out string
+	var pending string
+	for _, chunk := range []string{"start mysec", "retva", "lue end my", "stery\n"} {
+		var safe string
+		safe, pending = m.MaskChunk(pending + chunk)
+		out += safe
+	}
+	out += m.Mask(pending)
+
+	if want := "start **pwd** end mystery\n"; out != want {
+		t.Errorf("MaskChunk() = %q, want %q", out, want)
+	}
+}
+
+func TestSecretMaskerMaskAppend(t *testing.T) {
+	m := NewSecretMasker([]Variable{{Name: "pwd", Value: "mysecretvalue"}})
+
+	var logs string
+	for _, chunk := range []string{"mysecretvalue start mysec", "ret", "va", "lue end my", "stery\n"} {
+		logs = m.MaskAppend(logs, chunk)
+	}
+
+	if want := "**pwd** start **pwd** end mystery\n"; logs != want {
+		t.Errorf("MaskAppend() = %q, want %q", logs, want)
+	}
+}
+
+func TestSecretMaskerNil(t *testing.T) {
+	var m *SecretMasker
+	if got := m.Mask("foo"); got != "foo" {
+		t.Errorf("Mask() = %q, want foo", got)
+	}
+	if safe, pending := m.MaskChunk("foo"); safe != "foo" || pending != "" {
+		t.Errorf("MaskChunk() = %q, %q", safe, pending)
+	}
+	if got := m.MaskAppend("foo", "bar"); got != "foobar" {
+		t.Errorf("MaskAppend() = %q, want foobar", got)
+	}
+}
@@ -0,0 +1,130 @@
+package sdk
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Static analysis report formats
+const (
+	StaticAnalysisFormatSARIF      = "sarif"
+	StaticAnalysisFormatCheckstyle = "checkstyle"
+)
+
+// Static analysis findings severities
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+	SeverityInfo   = "info"
+)
+
+// StaticAnalysisSeverities is the list of all severities, from the highest to the lowest
+var StaticAnalysisSeverities = []string{
+	SeverityHigh,
+	SeverityMedium,
+	SeverityLow,
+	SeverityInfo,
+}
+
+// IsValidSeverity checks that the severity is one of the known severities
+func IsValidSeverity(s string) bool {
+	for _, sev := range StaticAnalysisSeverities {
+		if sev == s {
+			return true
+		}
+	}
+	return false
+}
+
+// StaticAnalysisFinding is a finding reported by a static or security analysis tool
+type StaticAnalysisFinding struct {
+	ID                int64     `json:"id" db:"id"`
+	WorkflowRunID     int64     `json:"workflow_run_id" db:"workflow_run_id"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	WorkflowID        int64     `json:"workflow_id" db:"workflow_id"`
+	WorkflowNodeName  string    `json:"workflow_node_name" db:"workflow_node_name"`
+	Tool              string    `json:"tool" db:"tool"`
+	RuleID            string    `json:"rule_id" db:"rule_id"`
+	Severity          string    `json:"severity" db:"severity"`
+	Message           string    `json:"message" db:"message"`
+	File              string    `json:"file" db:"file"`
+	Line              int       `json:"line" db:"line"`
+	Column            int       `json:"column" db:"col"`
+	Fingerprint       string    `json:"fingerprint" db:"fingerprint"`
+	New               bool      `json:"new" db:"is_new"`
+	Created           time.Time `json:"created" db:"created"`
+}
+
+// ComputeFingerprints computes the fingerprints used to track findings across runs. The fingerprint of a finding is
+// made of its tool, rule, file and message, and of its occurrence index among the identical findings of the list,
+// ordered by position. The line is not part of it, so that editing a file above a finding does not make it new.
+func ComputeFingerprints(findings []StaticAnalysisFinding) {
+	order := make([]int, len(findings))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := findings[order[i]], findings[order[j]]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	occurrences := map[string]int{}
+	for _, i := range order {
+		f := &findings[i]
+		key := fmt.Sprintf("%s|%s|%s|%s", f.Tool, f.RuleID, f.File, strings.TrimSpace(f.Message))
+		h := sha1.New()
+		fmt.Fprintf(h, "%s|%d", key, occurrences[key])
+		f.Fingerprint = hex.EncodeToString(h.Sum(nil))
+		occurrences[key]++
+	}
+}
+
+// StaticAnalysisSummary represents the findings counters of a workflow node run
+type StaticAnalysisSummary struct {
+	Total    int            `json:"total"`
+	New      int            `json:"new"`
+	Severity map[string]int `json:"severity"`
+	NewBy    map[string]int `json:"new_severity"`
+}
+
+// NewStaticAnalysisSummary computes a summary from a list of findings
+func NewStaticAnalysisSummary(findings []StaticAnalysisFinding) *StaticAnalysisSummary {
+	s := &StaticAnalysisSummary{
+		Severity: map[string]int{},
+		NewBy:    map[string]int{},
+	}
+	for _, sev := range StaticAnalysisSeverities {
+		s.Severity[sev] = 0
+		s.NewBy[sev] = 0
+	}
+	for _, f := range findings {
+		s.Total++
+		s.Severity[f.Severity]++
+		if f.New {
+			s.New++
+			s.NewBy[f.Severity]++
+		}
+	}
+	return s
+}
+
+// Parameters returns the build parameters which can be used in workflow trigger conditions,
+// such as cds.analysis.high or cds.analysis.new.high
+func (s *StaticAnalysisSummary) Parameters() []Parameter {
+	params := []Parameter{}
+	AddParameter(&params, "cds.analysis.total", StringParameter, fmt.Sprintf("%d", s.Total))
+	AddParameter(&params, "cds.analysis.new", StringParameter, fmt.Sprintf("%d", s.New))
+	for _, sev := range StaticAnalysisSeverities {
+		AddParameter(&params, "cds.analysis."+sev, StringParameter, fmt.Sprintf("%d", s.Severity[sev]))
+		AddParameter(&params, "cds.analysis.new."+sev, StringParameter, fmt.Sprintf("%d", s.NewBy[sev]))
+	}
+	return params
+}
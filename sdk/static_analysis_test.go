@@ -0,0 +1,27 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeFingerprints(t *testing.T) {
+	f := StaticAnalysisFinding{Tool: "gosec", RuleID: "G104", File: "main.go", Line: 12, Column: 2, Message: "Errors unhandled."}
+	other := f
+	other.Line = 20
+	other.Message = "  Errors unhandled. "
+
+	before := []StaticAnalysisFinding{f, other}
+	ComputeFingerprints(before)
+	assert.NotEqual(t, before[0].Fingerprint, before[1].Fingerprint, "identical findings in the same file must be tracked separately")
+
+	// A line added above both findings must not change their fingerprints, whatever the order of the report
+	after := []StaticAnalysisFinding{other, f}
+	after[0].Line++
+	after[1].Line++
+	after[1].Column = 8
+	ComputeFingerprints(after)
+	assert.Equal(t, before[0].Fingerprint, after[1].Fingerprint)
+	assert.Equal(t, before[1].Fingerprint, after[0].Fingerprint)
+}
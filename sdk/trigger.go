@@ -30,6 +30,8 @@ type PipelineTrigger struct {
 	Parameters    []Parameter    `json:"parameters"`
 	Prerequisites []Prerequisite `json:"prerequisites"`
 	LastModified  int64          `json:"last_modified"`
+	// Approval pauses the triggered pipeline build until it is approved
+	Approval *ApprovalGate `json:"approval,omitempty"`
 }
 
 // GetTriggers retrieves all output triggers of a pipeline
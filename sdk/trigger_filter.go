@@ -0,0 +1,95 @@
+package sdk
+
+import (
+	"path"
+	"strings"
+)
+
+// Git references prefixes
+const (
+	GitBranchRefPrefix = "refs/heads/"
+	GitTagRefPrefix    = "refs/tags/"
+)
+
+// DefaultSkipMarkers are the commit message markers which always prevent a push from triggering a pipeline
+var DefaultSkipMarkers = []string{"[ci skip]", "[cd skip]", "[skip ci]", "[skip cd]"}
+
+// TriggerFilter restricts the pushes which trigger a pipeline from a repository poller or a hook.
+// Branches, ExcludedBranches and Tags are glob patterns, Paths are directories or glob patterns.
+// Tag pushes trigger the pipeline only if they match one of the Tags patterns, all of them if Tags is empty.
+type TriggerFilter struct {
+	Branches         []string `json:"branches,omitempty"`
+	ExcludedBranches []string `json:"excluded_branches,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	Paths            []string `json:"paths,omitempty"`
+	SkipMarkers      []string `json:"skip_markers,omitempty"`
+}
+
+// GitRef returns the name of the branch or the tag of a git reference, and true if it is a tag.
+// A reference without prefix is a branch.
+func GitRef(ref string) (string, bool) {
+	if strings.HasPrefix(ref, GitTagRefPrefix) {
+		return strings.TrimPrefix(ref, GitTagRefPrefix), true
+	}
+	return strings.TrimPrefix(ref, GitBranchRefPrefix), false
+}
+
+// IsGitNullHash returns true if the hash is empty or is the zero hash sent by git for the missing side of
+// a created or a deleted reference
+func IsGitNullHash(hash string) bool {
+	return strings.Trim(hash, "0") == ""
+}
+
+// MatchRef checks if a push on the git reference has to trigger the pipeline
+func (f *TriggerFilter) MatchRef(ref string) bool {
+	name, tag := GitRef(ref)
+	if tag {
+		return len(f.Tags) == 0 || matchOne(f.Tags, name)
+	}
+	if matchOne(f.ExcludedBranches, name) {
+		return false
+	}
+	return len(f.Branches) == 0 || matchOne(f.Branches, name)
+}
+
+// MatchPaths checks if the changed files have to trigger the pipeline: at least one of them must be
+// in one of the paths of the filter
+func (f *TriggerFilter) MatchPaths(files []string) bool {
+	if len(f.Paths) == 0 {
+		return true
+	}
+	for _, file := range files {
+		for _, p := range f.Paths {
+			dir := strings.Trim(p, "/")
+			if dir == "" || strings.HasPrefix(file, dir+"/") {
+				return true
+			}
+			if ok, _ := path.Match(dir, file); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Skip checks if the commit message contains one of the default skip markers or one of the skip markers of the filter
+func (f *TriggerFilter) Skip(message string) bool {
+	message = strings.ToLower(message)
+	for _, markers := range [][]string{DefaultSkipMarkers, f.SkipMarkers} {
+		for _, m := range markers {
+			if m != "" && strings.Contains(message, strings.ToLower(m)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchOne(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
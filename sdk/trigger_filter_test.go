@@ -0,0 +1,46 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriggerFilterMatchRef(t *testing.T) {
+	f := TriggerFilter{
+		Branches:         []string{"master", "feat/*"},
+		ExcludedBranches: []string{"feat/wip-*"},
+		Tags:             []string{"v*"},
+	}
+	assert.True(t, f.MatchRef("master"))
+	assert.True(t, f.MatchRef("refs/heads/feat/login"))
+	assert.False(t, f.MatchRef("feat/wip-login"))
+	assert.False(t, f.MatchRef("develop"))
+	assert.True(t, f.MatchRef("refs/tags/v1.0.0"))
+	assert.False(t, f.MatchRef("refs/tags/nightly"))
+
+	empty := TriggerFilter{}
+	assert.True(t, empty.MatchRef("develop"))
+	assert.True(t, empty.MatchRef("refs/tags/v1.0.0"))
+}
+
+func TestIsGitNullHash(t *testing.T) {
+	assert.True(t, IsGitNullHash(""))
+	assert.True(t, IsGitNullHash("0000000000000000000000000000000000000000"))
+	assert.False(t, IsGitNullHash("a94a8fe5ccb19ba61c4c0873d391e987982fbbd3"))
+}
+
+func TestTriggerFilterMatchPaths(t *testing.T) {
+	f := TriggerFilter{Paths: []string{"engine/api/", "*.md"}}
+	assert.True(t, f.MatchPaths([]string{"sdk/pipeline.go", "engine/api/main.go"}))
+	assert.True(t, f.MatchPaths([]string{"README.md"}))
+	assert.False(t, f.MatchPaths([]string{"engine/worker/main.go", "docs/index.md"}))
+	assert.True(t, (&TriggerFilter{}).MatchPaths(nil))
+}
+
+func TestTriggerFilterSkip(t *testing.T) {
+	f := TriggerFilter{SkipMarkers: []string{"[no build]"}}
+	assert.True(t, f.Skip("fix: typo [CI SKIP]"))
+	assert.True(t, f.Skip("doc [no build]"))
+	assert.False(t, f.Skip("feat: new api"))
+}
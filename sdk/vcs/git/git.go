@@ -1,6 +1,7 @@
 package git
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -154,7 +155,7 @@ func gitCommand(repo string, path string, opts *CloneOpts) cmds {
 		}
 	}
 
-	gitcmd.args = append(gitcmd.args, repo)
+	gitcmd.args = append(gitcmd.args, "--", repo)
 
 	if path != "" {
 		gitcmd.args = append(gitcmd.args, path)
@@ -181,6 +182,16 @@ func gitCommand(repo string, path string, opts *CloneOpts) cmds {
 	return cmds(allCmd)
 }
 
+// LatestCommit returns the hash of the commit checked out in a cloned repository
+func LatestCommit(path string) (string, error) {
+	var out bytes.Buffer
+	c := cmds{{dir: path, cmd: "git", args: []string{"rev-parse", "HEAD"}}}
+	if err := runCommand(c, &OutputOpts{Stdout: &out, Stderr: ioutil.Discard}); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
 type cmds []cmd
 
 func (c cmds) Strings() []string {
@@ -175,7 +175,7 @@ func Test_gitCommand(t *testing.T) {
 				path: "/tmp/Test_gitCommand-1",
 			},
 			want: []string{
-				"git clone https://github.com/ovh/cds.git /tmp/Test_gitCommand-1",
+				"git clone -- https://github.com/ovh/cds.git /tmp/Test_gitCommand-1",
 			},
 		},
 		{
@@ -191,7 +191,7 @@ func Test_gitCommand(t *testing.T) {
 				},
 			},
 			want: []string{
-				"git clone --verbose --depth 1 --branch master --recursive https://github.com/ovh/cds.git /tmp/Test_gitCommand-2",
+				"git clone --verbose --depth 1 --branch master --recursive -- https://github.com/ovh/cds.git /tmp/Test_gitCommand-2",
 			},
 		},
 		{
@@ -206,7 +206,7 @@ func Test_gitCommand(t *testing.T) {
 				},
 			},
 			want: []string{
-				"git clone --quiet --branch master https://github.com/ovh/cds.git /tmp/Test_gitCommand-3",
+				"git clone --quiet --branch master -- https://github.com/ovh/cds.git /tmp/Test_gitCommand-3",
 				"git reset --hard eb8b87a",
 			},
 		},
@@ -71,6 +71,7 @@ type Model struct {
 	UserLastModified time.Time          `json:"user_last_modified"  db:"user_last_modified"`
 	CreatedBy        User               `json:"created_by" db:"-"`
 	Provision        int64              `json:"provision" db:"provision"`
+	PoolPolicy       *ModelPoolPolicy   `json:"pool_policy,omitempty" db:"-"`
 	GroupID          int64              `json:"group_id" db:"group_id"`
 	Group            Group              `json:"group" db:"-"`
 	NbSpawnErr       int64              `json:"nb_spawn_err" db:"nb_spawn_err"`
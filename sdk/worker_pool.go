@@ -0,0 +1,121 @@
+package sdk
+
+import (
+	"fmt"
+	"time"
+)
+
+// ModelPoolPolicy describes how hatcheries keep a pool of workers for a worker model.
+// Hatcheries spawn workers to keep between MinIdle and MaxIdle waiting workers, according
+// to the number of jobs in queue that can run on the model, and kill waiting workers
+// after IdleTimeout seconds when the pool is too large.
+type ModelPoolPolicy struct {
+	MinIdle     int                 `json:"min_idle"`
+	MaxIdle     int                 `json:"max_idle"`
+	MaxWorkers  int                 `json:"max_workers"`
+	IdleTimeout int64               `json:"idle_timeout"`
+	Schedules   []ModelPoolSchedule `json:"schedules,omitempty"`
+}
+
+// ModelPoolSchedule overrides idle bounds of a pool policy during a time range.
+// From and To are formatted as 15:04 in the timezone of the hatchery, a range
+// with From after To ends the next day. Days are 0 (Sunday) to 6 (Saturday),
+// empty Days means every day.
+type ModelPoolSchedule struct {
+	Days    []time.Weekday `json:"days,omitempty"`
+	From    string         `json:"from"`
+	To      string         `json:"to"`
+	MinIdle int            `json:"min_idle"`
+	MaxIdle int            `json:"max_idle"`
+}
+
+// IsValid checks idle bounds and schedules of the pool policy
+func (p *ModelPoolPolicy) IsValid() error {
+	if p.MinIdle < 0 || p.MaxIdle < 0 || p.MaxWorkers < 0 || p.IdleTimeout < 0 {
+		return WrapError(ErrInvalidModelPoolPolicy, "IsValid> values must be positive")
+	}
+	if p.MinIdle > p.MaxIdle {
+		return WrapError(ErrInvalidModelPoolPolicy, "IsValid> min_idle %d is greater than max_idle %d", p.MinIdle, p.MaxIdle)
+	}
+	if p.MaxWorkers > 0 && p.MaxIdle > p.MaxWorkers {
+		return WrapError(ErrInvalidModelPoolPolicy, "IsValid> max_idle %d is greater than max_workers %d", p.MaxIdle, p.MaxWorkers)
+	}
+	for _, s := range p.Schedules {
+		if err := s.isValid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ModelPoolSchedule) isValid() error {
+	if s.MinIdle < 0 || s.MaxIdle < 0 || s.MinIdle > s.MaxIdle {
+		return WrapError(ErrInvalidModelPoolPolicy, "isValid> invalid idle bounds %d-%d on schedule %s-%s", s.MinIdle, s.MaxIdle, s.From, s.To)
+	}
+	if _, err := parseScheduleTime(s.From); err != nil {
+		return WrapError(ErrInvalidModelPoolPolicy, "isValid> invalid schedule from %s", s.From)
+	}
+	if _, err := parseScheduleTime(s.To); err != nil {
+		return WrapError(ErrInvalidModelPoolPolicy, "isValid> invalid schedule to %s", s.To)
+	}
+	for _, d := range s.Days {
+		if d < time.Sunday || d > time.Saturday {
+			return WrapError(ErrInvalidModelPoolPolicy, "isValid> invalid day %d", d)
+		}
+	}
+	return nil
+}
+
+// IdleBounds returns the min and max idle workers at the given time. The first
+// matching schedule wins, default bounds are used if no schedule matches.
+func (p *ModelPoolPolicy) IdleBounds(t time.Time) (int, int) {
+	for _, s := range p.Schedules {
+		if s.match(t) {
+			return s.MinIdle, s.MaxIdle
+		}
+	}
+	return p.MinIdle, p.MaxIdle
+}
+
+func (s *ModelPoolSchedule) match(t time.Time) bool {
+	from, errf := parseScheduleTime(s.From)
+	to, errt := parseScheduleTime(s.To)
+	if errf != nil || errt != nil {
+		return false
+	}
+
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	day := t.Weekday()
+	var inRange bool
+	switch {
+	case from <= to:
+		inRange = now >= from && now < to
+	case now >= from:
+		inRange = true
+	case now < to:
+		// range started the day before
+		inRange = true
+		day = (day + 6) % 7
+	}
+	if !inRange {
+		return false
+	}
+
+	if len(s.Days) == 0 {
+		return true
+	}
+	for _, d := range s.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+func parseScheduleTime(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %s, format must be 15:04", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
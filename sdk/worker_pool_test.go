@@ -0,0 +1,67 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestModelPoolPolicyIsValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  ModelPoolPolicy
+		wantErr bool
+	}{
+		{name: "empty", policy: ModelPoolPolicy{}},
+		{name: "bounds", policy: ModelPoolPolicy{MinIdle: 1, MaxIdle: 3, MaxWorkers: 10, IdleTimeout: 60}},
+		{name: "min greater than max", policy: ModelPoolPolicy{MinIdle: 4, MaxIdle: 3}, wantErr: true},
+		{name: "negative", policy: ModelPoolPolicy{MinIdle: -1}, wantErr: true},
+		{name: "max idle greater than max workers", policy: ModelPoolPolicy{MaxIdle: 3, MaxWorkers: 2}, wantErr: true},
+		{name: "schedule", policy: ModelPoolPolicy{Schedules: []ModelPoolSchedule{{Days: []time.Weekday{time.Monday}, From: "20:00", To: "07:00"}}}},
+		{name: "invalid schedule time", policy: ModelPoolPolicy{Schedules: []ModelPoolSchedule{{From: "8h", To: "20:00"}}}, wantErr: true},
+		{name: "invalid schedule day", policy: ModelPoolPolicy{Schedules: []ModelPoolSchedule{{Days: []time.Weekday{7}, From: "08:00", To: "20:00"}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.IsValid(); (err != nil) != tt.wantErr {
+				t.Errorf("ModelPoolPolicy.IsValid() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModelPoolPolicyIdleBounds(t *testing.T) {
+	policy := ModelPoolPolicy{
+		MinIdle: 2,
+		MaxIdle: 5,
+		Schedules: []ModelPoolSchedule{
+			{Days: []time.Weekday{time.Saturday, time.Sunday}, From: "00:00", To: "23:59", MinIdle: 0, MaxIdle: 0},
+			{Days: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, From: "20:00", To: "07:00", MinIdle: 0, MaxIdle: 1},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		t       string
+		wantMin int
+		wantMax int
+	}{
+		{name: "monday day", t: "2017-10-02 10:00", wantMin: 2, wantMax: 5},
+		{name: "monday night", t: "2017-10-02 21:30", wantMin: 0, wantMax: 1},
+		{name: "tuesday early morning", t: "2017-10-03 06:59", wantMin: 0, wantMax: 1},
+		{name: "tuesday morning", t: "2017-10-03 07:00", wantMin: 2, wantMax: 5},
+		{name: "monday early morning, night started on sunday", t: "2017-10-02 03:00", wantMin: 2, wantMax: 5},
+		{name: "saturday", t: "2017-10-07 12:00", wantMin: 0, wantMax: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now, err := time.Parse("2006-01-02 15:04", tt.t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			min, max := policy.IdleBounds(now)
+			if min != tt.wantMin || max != tt.wantMax {
+				t.Errorf("ModelPoolPolicy.IdleBounds() = %d, %d, want %d, %d", min, max, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
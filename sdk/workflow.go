@@ -8,15 +8,17 @@ import (
 
 //Workflow represents a pipeline based workflow
 type Workflow struct {
-	ID           int64              `json:"id" db:"id" cli:"-"`
-	Name         string             `json:"name" db:"name" cli:"name,key"`
-	Description  string             `json:"description,omitempty" db:"description" cli:"description"`
-	LastModified time.Time          `json:"last_modified" db:"last_modified"`
-	ProjectID    int64              `json:"project_id,omitempty" db:"project_id" cli:"-"`
-	ProjectKey   string             `json:"project_key" db:"-" cli:"-"`
-	RootID       int64              `json:"root_id,omitempty" db:"root_node_id" cli:"-"`
-	Root         *WorkflowNode      `json:"root" db:"-" cli:"-"`
-	Joins        []WorkflowNodeJoin `json:"joins,omitempty" db:"-" cli:"-"`
+	ID            int64                  `json:"id" db:"id" cli:"-"`
+	Name          string                 `json:"name" db:"name" cli:"name,key"`
+	Description   string                 `json:"description,omitempty" db:"description" cli:"description"`
+	LastModified  time.Time              `json:"last_modified" db:"last_modified"`
+	ProjectID     int64                  `json:"project_id,omitempty" db:"project_id" cli:"-"`
+	ProjectKey    string                 `json:"project_key" db:"-" cli:"-"`
+	RootID        int64                  `json:"root_id,omitempty" db:"root_node_id" cli:"-"`
+	Root          *WorkflowNode          `json:"root" db:"-" cli:"-"`
+	Joins         []WorkflowNodeJoin     `json:"joins,omitempty" db:"-" cli:"-"`
+	JobPriority   *int                   `json:"job_priority,omitempty" db:"job_priority" cli:"-"`
+	Notifications []WorkflowNotification `json:"notifications,omitempty" db:"-" cli:"-"`
 }
 
 //JoinsID returns joins ID
@@ -338,14 +340,15 @@ type WorkflowTriggerCondition struct {
 
 //WorkflowNodeContext represents a context attached on a node
 type WorkflowNodeContext struct {
-	ID                        int64        `json:"id" db:"id"`
-	WorkflowNodeID            int64        `json:"workflow_node_id" db:"workflow_node_id"`
-	ApplicationID             int64        `json:"application_id" db:"application_id"`
-	Application               *Application `json:"application,omitempty" db:"-"`
-	Environment               *Environment `json:"environment,omitempty" db:"-"`
-	EnvironmentID             int64        `json:"environment_id" db:"environment_id"`
-	DefaultPayload            interface{}  `json:"default_payload,omitempty" db:"-"`
-	DefaultPipelineParameters []Parameter  `json:"default_pipeline_parameters,omitempty" db:"-"`
+	ID                        int64         `json:"id" db:"id"`
+	WorkflowNodeID            int64         `json:"workflow_node_id" db:"workflow_node_id"`
+	ApplicationID             int64         `json:"application_id" db:"application_id"`
+	Application               *Application  `json:"application,omitempty" db:"-"`
+	Environment               *Environment  `json:"environment,omitempty" db:"-"`
+	EnvironmentID             int64         `json:"environment_id" db:"environment_id"`
+	DefaultPayload            interface{}   `json:"default_payload,omitempty" db:"-"`
+	DefaultPipelineParameters []Parameter   `json:"default_pipeline_parameters,omitempty" db:"-"`
+	Approval                  *ApprovalGate `json:"approval,omitempty" db:"-"`
 }
 
 //WorkflowNodeHook represents a hook which cann trigger the workflow from a given node
@@ -3,6 +3,7 @@ package sdk
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -57,16 +58,16 @@ func WorkflowCheckConditions(conditions []WorkflowTriggerCondition, params []Par
 			conditionsOK = conditionsOK && cond.Value != mapParams[cond.Variable]
 
 		case WorkflowConditionsOperatorLessThan:
-			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) < 0
+			conditionsOK = conditionsOK && compareConditionValues(mapParams[cond.Variable], cond.Value) < 0
 
 		case WorkflowConditionsOperatorLessOrEqualThan:
-			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) <= 0
+			conditionsOK = conditionsOK && compareConditionValues(mapParams[cond.Variable], cond.Value) <= 0
 
 		case WorkflowConditionsOperatorGreaterThan:
-			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) > 0
+			conditionsOK = conditionsOK && compareConditionValues(mapParams[cond.Variable], cond.Value) > 0
 
 		case WorkflowConditionsOperatorGreaterOrEqualThan:
-			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) >= 0
+			conditionsOK = conditionsOK && compareConditionValues(mapParams[cond.Variable], cond.Value) >= 0
 
 		case WorkflowConditionsOperatorRegex:
 			match, err := regexp.MatchString(cond.Value, mapParams[cond.Variable])
@@ -79,3 +80,19 @@ func WorkflowCheckConditions(conditions []WorkflowTriggerCondition, params []Par
 
 	return conditionsOK, nil
 }
+
+// compareConditionValues compares two values numerically if both are numbers, else lexicographically
+func compareConditionValues(a, b string) int {
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+	switch {
+	case fa < fb:
+		return -1
+	case fa > fb:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,50 @@
+package sdk
+
+import "testing"
+
+func TestWorkflowCheckConditions(t *testing.T) {
+	params := []Parameter{
+		{Name: "cds.analysis.high", Type: StringParameter, Value: "10"},
+		{Name: "git.branch", Type: StringParameter, Value: "master"},
+	}
+	tests := []struct {
+		name       string
+		conditions []WorkflowTriggerCondition
+		want       bool
+	}{
+		{
+			name:       "numeric greater than",
+			conditions: []WorkflowTriggerCondition{{Variable: "cds.analysis.high", Operator: WorkflowConditionsOperatorGreaterThan, Value: "9"}},
+			want:       true,
+		},
+		{
+			name:       "numeric less or equal",
+			conditions: []WorkflowTriggerCondition{{Variable: "cds.analysis.high", Operator: WorkflowConditionsOperatorLessOrEqualThan, Value: "0"}},
+			want:       false,
+		},
+		{
+			name:       "string comparison",
+			conditions: []WorkflowTriggerCondition{{Variable: "git.branch", Operator: WorkflowConditionsOperatorGreaterThan, Value: "develop"}},
+			want:       true,
+		},
+		{
+			name: "several conditions",
+			conditions: []WorkflowTriggerCondition{
+				{Variable: "git.branch", Operator: WorkflowConditionsOperatorEquals, Value: "master"},
+				{Variable: "cds.analysis.high", Operator: WorkflowConditionsOperatorEquals, Value: "0"},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WorkflowCheckConditions(tt.conditions, params)
+			if err != nil {
+				t.Fatalf("WorkflowCheckConditions() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("WorkflowCheckConditions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
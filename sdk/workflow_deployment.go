@@ -0,0 +1,39 @@
+package sdk
+
+import "time"
+
+// WorkflowDeployment is an entry of the deployment ledger: the run of a workflow node which
+// has an application and an environment in its context
+type WorkflowDeployment struct {
+	ID                int64                        `json:"id" db:"id" cli:"id,key"`
+	ProjectID         int64                        `json:"project_id" db:"project_id" cli:"-"`
+	ProjectKey        string                       `json:"project_key" db:"project_key" cli:"project"`
+	WorkflowID        int64                        `json:"workflow_id" db:"workflow_id" cli:"-"`
+	WorkflowName      string                       `json:"workflow_name" db:"workflow_name" cli:"workflow"`
+	WorkflowRunID     int64                        `json:"workflow_run_id" db:"workflow_run_id" cli:"-"`
+	WorkflowNodeID    int64                        `json:"workflow_node_id" db:"workflow_node_id" cli:"-"`
+	WorkflowNodeRunID int64                        `json:"workflow_node_run_id" db:"workflow_node_run_id" cli:"-"`
+	Number            int64                        `json:"num" db:"num" cli:"-"`
+	SubNumber         int64                        `json:"subnumber" db:"sub_num" cli:"-"`
+	ApplicationID     int64                        `json:"application_id" db:"application_id" cli:"-"`
+	ApplicationName   string                       `json:"application_name" db:"application_name" cli:"application"`
+	EnvironmentID     int64                        `json:"environment_id" db:"environment_id" cli:"-"`
+	EnvironmentName   string                       `json:"environment_name" db:"environment_name" cli:"environment"`
+	Version           string                       `json:"version" db:"version" cli:"version"`
+	GitBranch         string                       `json:"git_branch,omitempty" db:"git_branch" cli:"-"`
+	GitHash           string                       `json:"git_hash,omitempty" db:"git_hash" cli:"-"`
+	TriggeredBy       string                       `json:"triggered_by,omitempty" db:"triggered_by" cli:"triggered_by"`
+	Status            string                       `json:"status" db:"status" cli:"status"`
+	Start             time.Time                    `json:"start" db:"start" cli:"-"`
+	Done              time.Time                    `json:"done" db:"done" cli:"-"`
+	RollbackOf        int64                        `json:"rollback_of,omitempty" db:"rollback_of" cli:"-"`
+	Artifacts         []WorkflowDeploymentArtifact `json:"artifacts,omitempty" db:"-" cli:"-"`
+}
+
+// WorkflowDeploymentArtifact is an artifact produced by a deployment
+type WorkflowDeploymentArtifact struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Tag       string `json:"tag"`
+	SHA256Sum string `json:"sha256sum,omitempty"`
+}
@@ -0,0 +1,50 @@
+package sdk
+
+import "encoding/json"
+
+// WorkflowNotification is a notification rule of a workflow. It applies to the run of the source nodes,
+// or to the whole workflow run if there is no source node.
+type WorkflowNotification struct {
+	ID             int64                        `json:"id,omitempty" db:"id"`
+	WorkflowID     int64                        `json:"workflow_id,omitempty" db:"workflow_id"`
+	SourceNodeIDs  []int64                      `json:"source_node_id,omitempty" db:"-"`
+	SourceNodeRefs []string                     `json:"source_node_ref,omitempty" db:"-"`
+	Type           UserNotificationSettingsType `json:"type" db:"type"`
+	Settings       UserNotificationSettings     `json:"settings" db:"-"`
+}
+
+// UnmarshalJSON parses the JSON-encoded data and stores the result in n
+func (n *WorkflowNotification) UnmarshalJSON(b []byte) error {
+	var input struct {
+		ID             int64                        `json:"id"`
+		WorkflowID     int64                        `json:"workflow_id"`
+		SourceNodeIDs  []int64                      `json:"source_node_id"`
+		SourceNodeRefs []string                     `json:"source_node_ref"`
+		Type           UserNotificationSettingsType `json:"type"`
+		Settings       json.RawMessage              `json:"settings"`
+	}
+	if err := json.Unmarshal(b, &input); err != nil {
+		return err
+	}
+
+	n.ID = input.ID
+	n.WorkflowID = input.WorkflowID
+	n.SourceNodeIDs = input.SourceNodeIDs
+	n.SourceNodeRefs = input.SourceNodeRefs
+	n.Type = input.Type
+	n.Settings = nil
+	if len(input.Settings) == 0 || string(input.Settings) == "null" {
+		return nil
+	}
+
+	typed, err := json.Marshal(map[UserNotificationSettingsType]json.RawMessage{input.Type: input.Settings})
+	if err != nil {
+		return err
+	}
+	settings, err := ParseUserNotificationSettings(typed)
+	if err != nil {
+		return err
+	}
+	n.Settings = settings[input.Type]
+	return nil
+}
@@ -0,0 +1,23 @@
+package sdk
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowNotificationUnmarshalJSON(t *testing.T) {
+	b := []byte(`{"source_node_ref": ["build"], "type": "slack", "settings": {"on_success": "never", "on_failure": "always", "webhook_url": "http://hooks/1"}}`)
+	var n WorkflowNotification
+	assert.NoError(t, json.Unmarshal(b, &n))
+	assert.Equal(t, []string{"build"}, n.SourceNodeRefs)
+	assert.Equal(t, SlackUserNotification, n.Type)
+
+	s, ok := n.Settings.(*SlackUserNotificationSettings)
+	assert.True(t, ok)
+	assert.Equal(t, "http://hooks/1", s.WebhookURL)
+	assert.Equal(t, UserNotificationAlways, s.Failure())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"type": "unknown", "settings": {}}`), &n))
+}
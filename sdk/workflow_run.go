@@ -21,6 +21,7 @@ type WorkflowRun struct {
 	WorkflowNodeRuns map[int64][]WorkflowNodeRun `json:"nodes" db:"-"`
 	Infos            []WorkflowRunInfo           `json:"infos" db:"-"`
 	Tags             []WorkflowRunTag            `json:"tags" db:"-"`
+	Priority         *int                        `json:"priority,omitempty" db:"priority"`
 }
 
 // Translate translates messages in WorkflowNodeRun
@@ -65,6 +66,7 @@ type WorkflowNodeRun struct {
 	BuildParameters    []Parameter               `json:"build_parameters" db:"-"`
 	Artifacts          []WorkflowNodeRunArtifact `json:"artifacts,omitempty" db:"-"`
 	Tests              *venom.Tests              `json:"tests,omitempty" db:"-"`
+	StaticAnalysis     *StaticAnalysisSummary    `json:"static_analysis,omitempty" db:"-"`
 	Commits            []VCSCommit               `json:"commits,omitempty" db:"-"`
 }
 
@@ -88,6 +90,7 @@ type WorkflowNodeRunArtifact struct {
 	Size              int64     `json:"size,omitempty" db:"size"`
 	Perm              uint32    `json:"perm,omitempty" db:"perm"`
 	MD5sum            string    `json:"md5sum,omitempty" db:"md5sum"`
+	SHA256Sum         string    `json:"sha256sum,omitempty" db:"sha256sum"`
 	ObjectPath        string    `json:"object_path,omitempty" db:"object_path"`
 	Created           time.Time `json:"created,omitempty" db:"created"`
 }
@@ -106,6 +109,7 @@ type WorkflowNodeJobRun struct {
 	Model             string      `json:"model,omitempty" db:"model"`
 	BookedBy          Hatchery    `json:"bookedby" db:"-"`
 	SpawnInfos        []SpawnInfo `json:"spawninfos" db:"-"`
+	Priority          int         `json:"priority" db:"priority"`
 }
 
 // Translate translates messages in WorkflowNodeJobRun
@@ -129,6 +133,7 @@ type WorkflowNodeRunManual struct {
 	Payload            interface{} `json:"payload" db:"-"`
 	PipelineParameters []Parameter `json:"pipeline_parameter" db:"-"`
 	User               User        `json:"user" db:"-"`
+	Priority           *int        `json:"priority,omitempty" db:"-"`
 }
 
 //GetName returns the name the artifact
@@ -0,0 +1,26 @@
+export class ApprovalGate {
+    groups: Array<string>;
+    min_approvals: number;
+    timeout: number;
+}
+
+export class ApprovalVote {
+    username: string;
+    approved: boolean;
+    comment: string;
+    date: string;
+}
+
+export class Approval {
+    id: number;
+    project_id: number;
+    pipeline_build_id: number;
+    workflow_node_run_id: number;
+    description: string;
+    status: string;
+    committer: string;
+    created: string;
+    last_modified: string;
+    gate: ApprovalGate;
+    votes: Array<ApprovalVote>;
+}
@@ -8,4 +8,7 @@ export class Environment {
     variables: Array<Variable>;
     permission: number;
     last_modified: number;
+    exclusive: boolean;
+    exclusive_applications: Array<string>;
+    lock_policy: string;
 }
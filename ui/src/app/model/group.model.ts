@@ -5,6 +5,7 @@ export class Group {
     name: string;
     admins: Array<User>;
     users: Array<User>;
+    max_concurrent_jobs: number;
 
     constructor() {
         this.name = '';
@@ -24,5 +24,22 @@ export class Model {
     user_last_modified: string;
     created_by: User;
     provision: number;
+    pool_policy: ModelPoolPolicy;
     group_id: number;
 }
+
+export class ModelPoolPolicy {
+    min_idle: number;
+    max_idle: number;
+    max_workers: number;
+    idle_timeout: number;
+    schedules: Array<ModelPoolSchedule>;
+}
+
+export class ModelPoolSchedule {
+    days: Array<number>;
+    from: string;
+    to: string;
+    min_idle: number;
+    max_idle: number;
+}
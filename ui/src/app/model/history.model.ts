@@ -0,0 +1,25 @@
+export class History {
+    id: number;
+    project_id: number;
+    object_type: string;
+    object_id: number;
+    object_name: string;
+    version: number;
+    author: string;
+    created: string;
+    snapshot: any;
+}
+
+export class HistoryChange {
+    path: string;
+    from: any;
+    to: any;
+}
+
+export class HistoryDiff {
+    object_type: string;
+    object_name: string;
+    from: number;
+    to: number;
+    changes: Array<HistoryChange>;
+}
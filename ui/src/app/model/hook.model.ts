@@ -9,9 +9,18 @@ export class Hook {
     link: string;
     project: string;
     repository: string;
+    filter: TriggerFilter;
 
 
     // ui data
     updating: boolean;
     hasChanged: boolean;
 }
+
+export class TriggerFilter {
+    branches: Array<string>;
+    excluded_branches: Array<string>;
+    tags: Array<string>;
+    paths: Array<string>;
+    skip_markers: Array<string>;
+}
@@ -1,7 +1,7 @@
 import {Pipeline} from './pipeline.model';
 import {Environment} from './environment.model';
 
-export const notificationTypes = ['jabber', 'email'];
+export const notificationTypes = ['jabber', 'email', 'slack'];
 export const notificationOnSuccess = ['always', 'change', 'never'];
 export const notificationOnFailure = ['always', 'change', 'never'];
 
@@ -39,6 +39,26 @@ export class UserNotificationSettings {
     }
 }
 
+export class SlackUserNotificationSettings {
+    on_success: string;
+    on_failure: string;
+    on_start: boolean;
+    webhook_url: string;
+    channel: string;
+    username: string;
+    icon_url: string;
+    template: UserNotificationTemplate;
+
+    constructor() {
+        this.on_success = notificationOnSuccess[1];
+        this.on_failure = notificationOnFailure[0];
+        this.on_start = false;
+        this.template = new UserNotificationTemplate();
+        this.template.subject = '';
+        this.template.body = '';
+    }
+}
+
 export class UserNotificationTemplate {
     subject: string;
     body: string;
@@ -16,6 +16,7 @@ export class PipelineStatus {
     static WAITING = 'Waiting';
     static DISABLED = 'Disabled';
     static SKIPPED = 'Skipped';
+    static STOPPED = 'Stopped';
     static NEVER_BUILT = 'Never Built';
 }
 
@@ -131,6 +132,7 @@ export class PipelineBuildJob {
     pipeline_build_id: number;
     spawninfos: Array<SpawnInfo>;
     warnings: Array<ActionWarning>;
+    priority: number;
 }
 
 export class SpawnInfo {
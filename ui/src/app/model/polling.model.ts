@@ -1,5 +1,6 @@
 import {Pipeline} from './pipeline.model';
 import {Application} from './application.model';
+import {TriggerFilter} from './hook.model';
 
 export class RepositoryPoller {
     name: string;
@@ -8,6 +9,7 @@ export class RepositoryPoller {
     enabled: boolean;
     date_creation: Date;
     next_execution: RepositoryPollerExecution;
+    filter: TriggerFilter;
 
 
     // Ui params
@@ -4,6 +4,7 @@ import {Pipeline} from './pipeline.model';
 import {Environment} from './environment.model';
 import {Parameter} from './parameter.model';
 import {Prerequisite} from './prerequisite.model';
+import {ApprovalGate} from './approval.model';
 
 export class Trigger {
     id: number;
@@ -19,6 +20,7 @@ export class Trigger {
     parameters: Array<Parameter>;
     prerequisites: Array<Prerequisite>;
     last_modified: number;
+    approval: ApprovalGate;
 
     // flag to know if variable data has changed
     hasChanged: boolean;
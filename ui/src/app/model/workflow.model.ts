@@ -3,6 +3,8 @@ import {Application} from './application.model';
 import {Environment} from './environment.model';
 import {intersection} from 'lodash';
 import {Parameter} from './parameter.model';
+import {ApprovalGate} from './approval.model';
+import {UserNotificationSettings} from './notification.model';
 
 // Workflow represents a pipeline based workflow
 export class Workflow {
@@ -14,7 +16,9 @@ export class Workflow {
     root: WorkflowNode;
     root_id: number;
     joins: Array<WorkflowNodeJoin>;
+    notifications: Array<WorkflowNotification>;
     last_modified: Date;
+    job_priority: number;
 
     // UI params
     externalChange: boolean;
@@ -73,6 +77,20 @@ export class WorkflowNodeJoin {
     }
 }
 
+// WorkflowNotification is a notification rule on the nodes of a workflow, or on the whole run without source node
+export class WorkflowNotification {
+    id: number;
+    workflow_id: number;
+    source_node_id: Array<number>;
+    source_node_ref: Array<string>;
+    type: string;
+    settings: UserNotificationSettings;
+
+    constructor() {
+        this.source_node_ref = new Array<string>();
+    }
+}
+
 export class WorkflowNodeJoinTrigger {
     id: number;
     join_id: number;
@@ -138,6 +156,7 @@ export class WorkflowNodeContext {
     environment_id: number;
     default_payload: {};
     default_pipeline_parameters: Array<Parameter>;
+    approval: ApprovalGate;
 }
 
 // WorkflowNodeHook represents a hook which cann trigger the workflow from a given node
@@ -71,6 +71,7 @@ export class WorkflowNodeJobRun {
     model: string;
     bookedby: Hatchery;
     spawninfos: Array<SpawnInfo>;
+    priority: number;
 }
 
 // WorkflowNodeRunHookEvent is an instanc of event received on a hook
@@ -86,3 +87,36 @@ export class WorkflowNodeRunManual {
     pipeline_parameter: Array<Parameter>;
     user: User;
 }
+
+export class WorkflowDeploymentArtifact {
+    id: number;
+    name: string;
+    tag: string;
+    sha256sum: string;
+}
+
+export class WorkflowDeployment {
+    id: number;
+    project_id: number;
+    project_key: string;
+    workflow_id: number;
+    workflow_name: string;
+    workflow_run_id: number;
+    workflow_node_id: number;
+    workflow_node_run_id: number;
+    num: number;
+    subnumber: number;
+    application_id: number;
+    application_name: string;
+    environment_id: number;
+    environment_name: string;
+    version: string;
+    git_branch: string;
+    git_hash: string;
+    triggered_by: string;
+    status: string;
+    start: string;
+    done: string;
+    rollback_of: number;
+    artifacts: Array<WorkflowDeploymentArtifact>;
+}